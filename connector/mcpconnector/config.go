@@ -4,17 +4,53 @@
 package mcpconnector
 
 import (
+	"errors"
+	"fmt"
+
 	"go.opentelemetry.io/collector/component"
 )
 
-// Config defines configuration for the MCP connector
+var (
+	errInvalidResourceAttribute = errors.New("add_resource_attributes keys must be non-empty")
+	errInvalidAllowlist         = errors.New("attribute_allowlist entries must be non-empty")
+)
+
+// Config defines configuration for the MCP connector. The connector finds the
+// MCP extension on its own, so no configuration is required.
 type Config struct {
-	// No configuration needed - the connector automatically finds the MCP extension
+	// AddResourceAttributes are static resource attributes (e.g. environment or
+	// tenant) set on every buffered batch, overwriting existing values. They
+	// are only written to the clone the connector buffers, never to the batch
+	// forwarded downstream. The connector clones every batch it buffers anyway,
+	// so enrichment adds one attribute write per resource, not another copy.
+	AddResourceAttributes map[string]string `mapstructure:"add_resource_attributes"`
+
+	// AttributeAllowlist are the attribute keys kept on buffered telemetry.
+	// When set, every other resource, span, span event, span link, log record
+	// and data point attribute is removed from the clone the connector buffers,
+	// which lowers buffer memory and keeps PII out of the buffer at the price
+	// of no longer being able to query the dropped attributes. service.name and
+	// the connector's own resource attributes are always kept. Empty keeps all
+	// attributes.
+	AttributeAllowlist []string `mapstructure:"attribute_allowlist"`
 }
 
 var _ component.Config = (*Config)(nil)
 
 // Validate checks if the connector configuration is valid
-func (*Config) Validate() error {
+func (cfg *Config) Validate() error {
+	for key := range cfg.AddResourceAttributes {
+		if key == "" {
+			return errInvalidResourceAttribute
+		}
+		if key == connectorIDAttribute {
+			return fmt.Errorf("add_resource_attributes must not set %s, it is always the connector ID", connectorIDAttribute)
+		}
+	}
+	for _, key := range cfg.AttributeAllowlist {
+		if key == "" {
+			return errInvalidAllowlist
+		}
+	}
 	return nil
 }
@@ -5,10 +5,12 @@ package mcpconnector
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -18,6 +20,13 @@ import (
 const (
 	// mcpExtensionType is the type string used to identify the MCP extension
 	mcpExtensionType = "mcp"
+
+	// spanCountMetricName is the metric emitted by the traces->metrics variant
+	spanCountMetricName = "mcp.connector.spans"
+
+	// connectorIDAttribute is the resource attribute recording which connector
+	// instance buffered a batch. This must match the attribute the query tools filter on.
+	connectorIDAttribute = "mcp.connector.id"
 )
 
 // TelemetryBuffer is the interface the connector uses to store telemetry
@@ -32,6 +41,7 @@ type TelemetryBuffer interface {
 type mcpConnector struct {
 	logger *zap.Logger
 	set    connector.Settings
+	config *Config
 
 	// Next consumers in the pipeline
 	nextTraces  consumer.Traces
@@ -40,6 +50,10 @@ type mcpConnector struct {
 
 	// Reference to MCP extension's buffer
 	buffer TelemetryBuffer
+
+	// allowedAttributes is the set of attribute_allowlist keys, nil when all
+	// attributes are kept
+	allowedAttributes map[string]bool
 }
 
 var (
@@ -50,17 +64,26 @@ var (
 
 func newConnector(
 	set connector.Settings,
+	cfg *Config,
 	nextTraces consumer.Traces,
 	nextMetrics consumer.Metrics,
 	nextLogs consumer.Logs,
 ) *mcpConnector {
-	return &mcpConnector{
+	c := &mcpConnector{
 		logger:      set.Logger,
 		set:         set,
+		config:      cfg,
 		nextTraces:  nextTraces,
 		nextMetrics: nextMetrics,
 		nextLogs:    nextLogs,
 	}
+	if len(cfg.AttributeAllowlist) > 0 {
+		c.allowedAttributes = map[string]bool{"service.name": true}
+		for _, key := range cfg.AttributeAllowlist {
+			c.allowedAttributes[key] = true
+		}
+	}
+	return c
 }
 
 //nolint:revive // ctx unused but kept for interface compatibility
@@ -93,12 +116,23 @@ func (*mcpConnector) Capabilities() consumer.Capabilities {
 }
 
 // ConsumeTraces buffers traces and passes them through
+//
+// Buffered batches are tagged with the connector ID so telemetry from connectors
+// in different pipelines can be told apart. The tag, the configured
+// add_resource_attributes and the attribute_allowlist filtering are only ever
+// applied to the clone owned by the buffer: the batch forwarded downstream is
+// shared with other consumers and must stay untouched, which keeps MutatesData
+// false.
 func (c *mcpConnector) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
 	// Always clone before buffering to prevent upstream mutations
 	// Upstream collectors may reuse or mutate the data after this call returns
 	if c.buffer != nil {
 		tdClone := ptrace.NewTraces()
 		td.CopyTo(tdClone)
+		c.filterTraceAttributes(tdClone)
+		for i := 0; i < tdClone.ResourceSpans().Len(); i++ {
+			c.tagResource(tdClone.ResourceSpans().At(i).Resource())
+		}
 		c.buffer.AddTraces(tdClone)
 	}
 
@@ -106,6 +140,11 @@ func (c *mcpConnector) ConsumeTraces(ctx context.Context, td ptrace.Traces) erro
 	if c.nextTraces != nil {
 		return c.nextTraces.ConsumeTraces(ctx, td)
 	}
+
+	// Traces->metrics variant forwards span counts derived from the batch
+	if c.nextMetrics != nil {
+		return c.nextMetrics.ConsumeMetrics(ctx, spanCountMetrics(td))
+	}
 	return nil
 }
 
@@ -116,6 +155,10 @@ func (c *mcpConnector) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) e
 	if c.buffer != nil {
 		mdClone := pmetric.NewMetrics()
 		md.CopyTo(mdClone)
+		c.filterMetricAttributes(mdClone)
+		for i := 0; i < mdClone.ResourceMetrics().Len(); i++ {
+			c.tagResource(mdClone.ResourceMetrics().At(i).Resource())
+		}
 		c.buffer.AddMetrics(mdClone)
 	}
 
@@ -133,6 +176,10 @@ func (c *mcpConnector) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	if c.buffer != nil {
 		ldClone := plog.NewLogs()
 		ld.CopyTo(ldClone)
+		c.filterLogAttributes(ldClone)
+		for i := 0; i < ldClone.ResourceLogs().Len(); i++ {
+			c.tagResource(ldClone.ResourceLogs().At(i).Resource())
+		}
 		c.buffer.AddLogs(ldClone)
 	}
 
@@ -142,3 +189,139 @@ func (c *mcpConnector) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	}
 	return nil
 }
+
+// tagResource records the configured resource attributes and the connector ID
+// on a buffered resource
+func (c *mcpConnector) tagResource(res pcommon.Resource) {
+	for key, value := range c.config.AddResourceAttributes {
+		res.Attributes().PutStr(key, value)
+	}
+	res.Attributes().PutStr(connectorIDAttribute, c.set.ID.String())
+}
+
+// filterAttributes removes the attributes not in attribute_allowlist. It is a
+// no-op when no allowlist is configured.
+func (c *mcpConnector) filterAttributes(attrs pcommon.Map) {
+	if c.allowedAttributes == nil {
+		return
+	}
+	attrs.RemoveIf(func(key string, _ pcommon.Value) bool {
+		return !c.allowedAttributes[key]
+	})
+}
+
+// filterTraceAttributes applies attribute_allowlist to the resources, spans,
+// span events and span links of a buffered clone
+func (c *mcpConnector) filterTraceAttributes(td ptrace.Traces) {
+	if c.allowedAttributes == nil {
+		return
+	}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		c.filterAttributes(rs.Resource().Attributes())
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				c.filterAttributes(span.Attributes())
+				for l := 0; l < span.Events().Len(); l++ {
+					c.filterAttributes(span.Events().At(l).Attributes())
+				}
+				for l := 0; l < span.Links().Len(); l++ {
+					c.filterAttributes(span.Links().At(l).Attributes())
+				}
+			}
+		}
+	}
+}
+
+// filterMetricAttributes applies attribute_allowlist to the resources and data
+// points of a buffered clone
+func (c *mcpConnector) filterMetricAttributes(md pmetric.Metrics) {
+	if c.allowedAttributes == nil {
+		return
+	}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		c.filterAttributes(rm.Resource().Attributes())
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					for l := 0; l < metric.Gauge().DataPoints().Len(); l++ {
+						c.filterAttributes(metric.Gauge().DataPoints().At(l).Attributes())
+					}
+				case pmetric.MetricTypeSum:
+					for l := 0; l < metric.Sum().DataPoints().Len(); l++ {
+						c.filterAttributes(metric.Sum().DataPoints().At(l).Attributes())
+					}
+				case pmetric.MetricTypeHistogram:
+					for l := 0; l < metric.Histogram().DataPoints().Len(); l++ {
+						c.filterAttributes(metric.Histogram().DataPoints().At(l).Attributes())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					for l := 0; l < metric.ExponentialHistogram().DataPoints().Len(); l++ {
+						c.filterAttributes(metric.ExponentialHistogram().DataPoints().At(l).Attributes())
+					}
+				case pmetric.MetricTypeSummary:
+					for l := 0; l < metric.Summary().DataPoints().Len(); l++ {
+						c.filterAttributes(metric.Summary().DataPoints().At(l).Attributes())
+					}
+				}
+			}
+		}
+	}
+}
+
+// filterLogAttributes applies attribute_allowlist to the resources and log
+// records of a buffered clone
+func (c *mcpConnector) filterLogAttributes(ld plog.Logs) {
+	if c.allowedAttributes == nil {
+		return
+	}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		c.filterAttributes(rl.Resource().Attributes())
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				c.filterAttributes(records.At(k).Attributes())
+			}
+		}
+	}
+}
+
+// spanCountMetrics derives a delta span count per resource from a traces batch
+func spanCountMetrics(td ptrace.Traces) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	now := pcommon.NewTimestampFromTime(time.Now())
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+
+		spanCount := 0
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spanCount += rs.ScopeSpans().At(j).Spans().Len()
+		}
+
+		rm := md.ResourceMetrics().AppendEmpty()
+		rs.Resource().CopyTo(rm.Resource())
+
+		metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName(spanCountMetricName)
+		metric.SetDescription("Number of spans buffered by the MCP connector")
+		metric.SetUnit("{span}")
+
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		sum.SetIsMonotonic(true)
+
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetTimestamp(now)
+		dp.SetIntValue(int64(spanCount))
+	}
+
+	return md
+}
@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/connector/connectortest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -73,7 +74,7 @@ func TestMCPConnectorTraces(t *testing.T) {
 	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
 
 	tracesSink := new(consumertest.TracesSink)
-	conn := newConnector(set, tracesSink, nil, nil)
+	conn := newConnector(set, &Config{}, tracesSink, nil, nil)
 	require.NotNil(t, conn)
 
 	buffer := &mockBuffer{}
@@ -106,7 +107,7 @@ func TestMCPConnectorMetrics(t *testing.T) {
 	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
 
 	metricsSink := new(consumertest.MetricsSink)
-	conn := newConnector(set, nil, metricsSink, nil)
+	conn := newConnector(set, &Config{}, nil, metricsSink, nil)
 	require.NotNil(t, conn)
 
 	buffer := &mockBuffer{}
@@ -140,7 +141,7 @@ func TestMCPConnectorLogs(t *testing.T) {
 	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
 
 	logsSink := new(consumertest.LogsSink)
-	conn := newConnector(set, nil, nil, logsSink)
+	conn := newConnector(set, &Config{}, nil, nil, logsSink)
 	require.NotNil(t, conn)
 
 	buffer := &mockBuffer{}
@@ -170,12 +171,55 @@ func TestMCPConnectorLogs(t *testing.T) {
 	assert.Len(t, buffer.logs, 3)
 }
 
+func TestMCPConnectorTracesToMetrics(t *testing.T) {
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
+
+	metricsSink := new(consumertest.MetricsSink)
+	conn := newConnector(set, &Config{}, nil, metricsSink, nil)
+	require.NotNil(t, conn)
+
+	buffer := &mockBuffer{}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		extension: &mockExtension{
+			buffer: buffer,
+		},
+	}
+
+	require.NoError(t, conn.Start(ctx, host))
+	t.Cleanup(func() { require.NoError(t, conn.Shutdown(ctx)) })
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "test-service")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Spans().AppendEmpty()
+	ss.Spans().AppendEmpty()
+
+	require.NoError(t, conn.ConsumeTraces(ctx, td))
+
+	// Verify input traces were buffered
+	assert.Len(t, buffer.traces, 1)
+
+	// Verify a span count metric was forwarded
+	require.Len(t, metricsSink.AllMetrics(), 1)
+	rm := metricsSink.AllMetrics()[0].ResourceMetrics().At(0)
+	sn, ok := rm.Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "test-service", sn.Str())
+
+	metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, spanCountMetricName, metric.Name())
+	assert.Equal(t, int64(2), metric.Sum().DataPoints().At(0).IntValue())
+}
+
 func TestMCPConnectorWithoutExtension(t *testing.T) {
 	ctx := context.Background()
 	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
 
 	tracesSink := new(consumertest.TracesSink)
-	conn := newConnector(set, tracesSink, nil, nil)
+	conn := newConnector(set, &Config{}, tracesSink, nil, nil)
 	require.NotNil(t, conn)
 
 	// Start without MCP extension
@@ -193,7 +237,7 @@ func TestMCPConnectorWithoutExtension(t *testing.T) {
 
 func TestMCPConnectorCapabilities(t *testing.T) {
 	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
-	conn := newConnector(set, nil, nil, nil)
+	conn := newConnector(set, &Config{}, nil, nil, nil)
 
 	caps := conn.Capabilities()
 	assert.False(t, caps.MutatesData)
@@ -205,7 +249,7 @@ func TestMCPConnectorCloningOptimization(t *testing.T) {
 
 	// Create a consumer that does NOT mutate data
 	nonMutatingConsumer := &nonMutatingTracesConsumer{}
-	conn := newConnector(set, nonMutatingConsumer, nil, nil)
+	conn := newConnector(set, &Config{}, nonMutatingConsumer, nil, nil)
 	require.NotNil(t, conn)
 
 	buffer := &mockBuffer{}
@@ -233,7 +277,7 @@ func TestMCPConnectorWithMutatingConsumer(t *testing.T) {
 
 	// Create a consumer that DOES mutate data
 	mutatingConsumer := &mutatingTracesConsumer{}
-	conn := newConnector(set, mutatingConsumer, nil, nil)
+	conn := newConnector(set, &Config{}, mutatingConsumer, nil, nil)
 	require.NotNil(t, conn)
 
 	buffer := &mockBuffer{}
@@ -255,6 +299,183 @@ func TestMCPConnectorWithMutatingConsumer(t *testing.T) {
 	assert.Len(t, buffer.traces, 1)
 }
 
+func TestMCPConnectorTagsBufferedTelemetry(t *testing.T) {
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
+	set.ID = component.MustNewIDWithName("mcp", "prod")
+
+	logsSink := new(consumertest.LogsSink)
+	conn := newConnector(set, &Config{}, nil, nil, logsSink)
+
+	buffer := &mockBuffer{}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		extension: &mockExtension{
+			buffer: buffer,
+		},
+	}
+
+	require.NoError(t, conn.Start(ctx, host))
+	t.Cleanup(func() { require.NoError(t, conn.Shutdown(ctx)) })
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("service.name", "test-service")
+	require.NoError(t, conn.ConsumeLogs(ctx, ld))
+
+	// Buffered clone carries the connector ID
+	require.Len(t, buffer.logs, 1)
+	v, ok := buffer.logs[0].ResourceLogs().At(0).Resource().Attributes().Get(connectorIDAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "mcp/prod", v.AsString())
+
+	// Forwarded batch is not mutated
+	require.Len(t, logsSink.AllLogs(), 1)
+	_, ok = logsSink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Get(connectorIDAttribute)
+	assert.False(t, ok)
+}
+
+func TestMCPConnectorAddsResourceAttributes(t *testing.T) {
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
+
+	tracesSink := new(consumertest.TracesSink)
+	cfg := &Config{AddResourceAttributes: map[string]string{
+		"deployment.environment": "staging",
+		"tenant":                 "acme",
+	}}
+	conn := newConnector(set, cfg, tracesSink, nil, nil)
+
+	buffer := &mockBuffer{}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		extension: &mockExtension{
+			buffer: buffer,
+		},
+	}
+
+	require.NoError(t, conn.Start(ctx, host))
+	t.Cleanup(func() { require.NoError(t, conn.Shutdown(ctx)) })
+
+	td := ptrace.NewTraces()
+	attrs := td.ResourceSpans().AppendEmpty().Resource().Attributes()
+	attrs.PutStr("service.name", "test-service")
+	attrs.PutStr("tenant", "unknown")
+	require.NoError(t, conn.ConsumeTraces(ctx, td))
+
+	// Buffered clone carries the configured attributes, overwriting existing values
+	require.Len(t, buffer.traces, 1)
+	buffered := buffer.traces[0].ResourceSpans().At(0).Resource().Attributes().AsRaw()
+	assert.Equal(t, "staging", buffered["deployment.environment"])
+	assert.Equal(t, "acme", buffered["tenant"])
+	assert.Equal(t, "test-service", buffered["service.name"])
+
+	// Forwarded batch is not mutated
+	require.Len(t, tracesSink.AllTraces(), 1)
+	forwarded := tracesSink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().AsRaw()
+	assert.Equal(t, map[string]any{"service.name": "test-service", "tenant": "unknown"}, forwarded)
+}
+
+func TestMCPConnectorAttributeAllowlist(t *testing.T) {
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(component.MustNewType("mcp"))
+
+	tracesSink := new(consumertest.TracesSink)
+	cfg := &Config{
+		AddResourceAttributes: map[string]string{"tenant": "acme"},
+		AttributeAllowlist:    []string{"http.route", "host.name"},
+	}
+	conn := newConnector(set, cfg, tracesSink, nil, nil)
+	metricsConn := newConnector(set, cfg, nil, consumertest.NewNop(), nil)
+	logsConn := newConnector(set, cfg, nil, nil, consumertest.NewNop())
+
+	buffer := &mockBuffer{}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		extension: &mockExtension{
+			buffer: buffer,
+		},
+	}
+	for _, c := range []*mcpConnector{conn, metricsConn, logsConn} {
+		require.NoError(t, c.Start(ctx, host))
+	}
+
+	setAttributes := func(attrs pcommon.Map) {
+		attrs.PutStr("http.route", "/users/{id}")
+		attrs.PutStr("enduser.id", "alice")
+	}
+	resource := func(res pcommon.Resource) {
+		res.Attributes().PutStr("service.name", "test-service")
+		res.Attributes().PutStr("host.name", "node-1")
+		res.Attributes().PutStr("process.command_line", "app --password=secret")
+	}
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	resource(rs.Resource())
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	setAttributes(span.Attributes())
+	setAttributes(span.Events().AppendEmpty().Attributes())
+	setAttributes(span.Links().AppendEmpty().Attributes())
+	require.NoError(t, conn.ConsumeTraces(ctx, td))
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	resource(rm.Resource())
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	setAttributes(metrics.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty().Attributes())
+	setAttributes(metrics.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().Attributes())
+	require.NoError(t, metricsConn.ConsumeMetrics(ctx, md))
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	resource(rl.Resource())
+	setAttributes(rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes())
+	require.NoError(t, logsConn.ConsumeLogs(ctx, ld))
+
+	wantResource := map[string]any{
+		"service.name":       "test-service",
+		"host.name":          "node-1",
+		"tenant":             "acme",
+		connectorIDAttribute: set.ID.String(),
+	}
+	wantAttributes := map[string]any{"http.route": "/users/{id}"}
+
+	require.Len(t, buffer.traces, 1)
+	bufferedRS := buffer.traces[0].ResourceSpans().At(0)
+	assert.Equal(t, wantResource, bufferedRS.Resource().Attributes().AsRaw())
+	bufferedSpan := bufferedRS.ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, wantAttributes, bufferedSpan.Attributes().AsRaw())
+	assert.Equal(t, wantAttributes, bufferedSpan.Events().At(0).Attributes().AsRaw())
+	assert.Equal(t, wantAttributes, bufferedSpan.Links().At(0).Attributes().AsRaw())
+
+	require.Len(t, buffer.metrics, 1)
+	bufferedRM := buffer.metrics[0].ResourceMetrics().At(0)
+	assert.Equal(t, wantResource, bufferedRM.Resource().Attributes().AsRaw())
+	bufferedMetrics := bufferedRM.ScopeMetrics().At(0).Metrics()
+	assert.Equal(t, wantAttributes, bufferedMetrics.At(0).Sum().DataPoints().At(0).Attributes().AsRaw())
+	assert.Equal(t, wantAttributes, bufferedMetrics.At(1).Histogram().DataPoints().At(0).Attributes().AsRaw())
+
+	require.Len(t, buffer.logs, 1)
+	bufferedRL := buffer.logs[0].ResourceLogs().At(0)
+	assert.Equal(t, wantResource, bufferedRL.Resource().Attributes().AsRaw())
+	assert.Equal(t, wantAttributes, bufferedRL.ScopeLogs().At(0).LogRecords().At(0).Attributes().AsRaw())
+
+	// Forwarded batch keeps every attribute
+	require.Len(t, tracesSink.AllTraces(), 1)
+	forwarded := tracesSink.AllTraces()[0].ResourceSpans().At(0)
+	assert.Equal(t, 3, forwarded.Resource().Attributes().Len())
+	assert.Equal(t, 2, forwarded.ScopeSpans().At(0).Spans().At(0).Attributes().Len())
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, (&Config{}).Validate())
+	assert.NoError(t, (&Config{AddResourceAttributes: map[string]string{"tenant": "acme"}}).Validate())
+	assert.ErrorIs(t, (&Config{AddResourceAttributes: map[string]string{"": "acme"}}).Validate(), errInvalidResourceAttribute)
+	assert.Error(t, (&Config{AddResourceAttributes: map[string]string{connectorIDAttribute: "other"}}).Validate())
+	assert.NoError(t, (&Config{AttributeAllowlist: []string{"http.route"}}).Validate())
+	assert.ErrorIs(t, (&Config{AttributeAllowlist: []string{"http.route", ""}}).Validate(), errInvalidAllowlist)
+}
+
 // Test consumers
 type nonMutatingTracesConsumer struct{}
 
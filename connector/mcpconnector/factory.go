@@ -17,11 +17,19 @@ const (
 )
 
 // NewFactory creates a factory for the MCP connector
+//
+// Besides the same-signal pass-through variants, traces->metrics is supported so
+// the connector can sit where a spanmetrics-style connector would: input traces
+// are buffered and a per-resource span count is forwarded downstream. Other
+// cross-signal combinations are not offered: there is nothing meaningful to
+// derive for metrics->traces or logs->traces, and buffering only needs the
+// input side of the pipeline.
 func NewFactory() connector.Factory {
 	return connector.NewFactory(
 		component.MustNewType(typeStr),
 		createDefaultConfig,
 		connector.WithTracesToTraces(createTracesToTraces, stability),
+		connector.WithTracesToMetrics(createTracesToMetrics, stability),
 		connector.WithMetricsToMetrics(createMetricsToMetrics, stability),
 		connector.WithLogsToLogs(createLogsToLogs, stability),
 	)
@@ -34,26 +42,35 @@ func createDefaultConfig() component.Config {
 func createTracesToTraces(
 	_ context.Context,
 	set connector.Settings,
-	_ component.Config,
+	cfg component.Config,
 	next consumer.Traces,
 ) (connector.Traces, error) {
-	return newConnector(set, next, nil, nil), nil
+	return newConnector(set, cfg.(*Config), next, nil, nil), nil
+}
+
+func createTracesToMetrics(
+	_ context.Context,
+	set connector.Settings,
+	cfg component.Config,
+	next consumer.Metrics,
+) (connector.Traces, error) {
+	return newConnector(set, cfg.(*Config), nil, next, nil), nil
 }
 
 func createMetricsToMetrics(
 	_ context.Context,
 	set connector.Settings,
-	_ component.Config,
+	cfg component.Config,
 	next consumer.Metrics,
 ) (connector.Metrics, error) {
-	return newConnector(set, nil, next, nil), nil
+	return newConnector(set, cfg.(*Config), nil, next, nil), nil
 }
 
 func createLogsToLogs(
 	_ context.Context,
 	set connector.Settings,
-	_ component.Config,
+	cfg component.Config,
 	next consumer.Logs,
 ) (connector.Logs, error) {
-	return newConnector(set, nil, nil, next), nil
+	return newConnector(set, cfg.(*Config), nil, nil, next), nil
 }
@@ -57,6 +57,28 @@ func TestCreateTracesToTraces(t *testing.T) {
 	assert.Nil(t, mcpConn.nextLogs)
 }
 
+func TestCreateTracesToMetrics(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	next := new(consumertest.MetricsSink)
+	conn, err := factory.CreateTracesToMetrics(
+		context.Background(),
+		connectortest.NewNopSettings(component.MustNewType("mcp")),
+		cfg,
+		next,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, conn)
+
+	mcpConn, ok := conn.(*mcpConnector)
+	require.True(t, ok)
+	assert.Nil(t, mcpConn.nextTraces)
+	assert.NotNil(t, mcpConn.nextMetrics)
+	assert.Nil(t, mcpConn.nextLogs)
+}
+
 func TestCreateMetricsToMetrics(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
@@ -108,6 +130,7 @@ func TestFactoryStability(t *testing.T) {
 
 	// Verify all factory methods return development stability
 	assert.Equal(t, component.StabilityLevelDevelopment, factory.TracesToTracesStability())
+	assert.Equal(t, component.StabilityLevelDevelopment, factory.TracesToMetricsStability())
 	assert.Equal(t, component.StabilityLevelDevelopment, factory.MetricsToMetricsStability())
 	assert.Equal(t, component.StabilityLevelDevelopment, factory.LogsToLogsStability())
 }
@@ -0,0 +1,78 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpextension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+)
+
+// toolCallLoggingMiddleware logs every tool invocation with the tool name, the
+// argument keys, the duration and the outcome. Argument values are never logged
+// since they may contain secrets.
+func toolCallLoggingMiddleware(logger *zap.Logger) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok {
+				return next(ctx, method, req)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, method, req)
+
+			fields := []zap.Field{
+				zap.String("tool", params.Name),
+				zap.Strings("argument_keys", argumentKeys(params.Arguments)),
+				zap.Duration("duration", time.Since(start)),
+			}
+			callErr := err
+			if callErr == nil {
+				callErr = toolResultError(result)
+			}
+			if callErr != nil {
+				logger.Warn("MCP tool call failed", append(fields, zap.Error(callErr))...)
+			} else {
+				logger.Info("MCP tool call", fields...)
+			}
+
+			return result, err
+		}
+	}
+}
+
+// argumentKeys returns the sorted top-level keys of raw tool arguments
+func argumentKeys(raw json.RawMessage) []string {
+	var args map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toolResultError returns the error a tool handler reported through an
+// IsError result, or nil if the call succeeded
+func toolResultError(result mcp.Result) error {
+	res, ok := result.(*mcp.CallToolResult)
+	if !ok || !res.IsError {
+		return nil
+	}
+	for _, content := range res.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			return errors.New(text.Text)
+		}
+	}
+	return errors.New("tool returned an error")
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpextension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type echoInput struct {
+	Message string `json:"message,omitempty"`
+	Token   string `json:"token,omitempty"`
+	Fail    bool   `json:"fail,omitempty"`
+}
+
+func TestToolCallLoggingMiddleware(t *testing.T) {
+	ctx := context.Background()
+	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
+	server.AddReceivingMiddleware(toolCallLoggingMiddleware(zap.New(core)))
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(_ context.Context, _ *mcp.CallToolRequest, input echoInput) (*mcp.CallToolResult, any, error) {
+		if input.Fail {
+			return nil, nil, errors.New("echo failed")
+		}
+		return nil, map[string]any{"message": input.Message}, nil
+	})
+
+	_, err := server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	t.Run("success", func(t *testing.T) {
+		_, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "echo",
+			Arguments: map[string]any{"message": "hi", "token": "s3cr3t"},
+		})
+		require.NoError(t, err)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "MCP tool call", entries[0].Message)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "echo", fields["tool"])
+		assert.Equal(t, []any{"message", "token"}, fields["argument_keys"])
+		assert.Contains(t, fields, "duration")
+		assert.NotContains(t, fmt.Sprint(fields), "s3cr3t")
+	})
+
+	t.Run("tool_error", func(t *testing.T) {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "echo",
+			Arguments: map[string]any{"fail": true},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, "echo failed", entries[0].ContextMap()["error"])
+	})
+
+	t.Run("other_methods_not_logged", func(t *testing.T) {
+		_, err := session.ListTools(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, logs.TakeAll())
+	})
+}
@@ -5,39 +5,263 @@ package mcpextension
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	_ "time/tzdata" // timezone must resolve in images without a zoneinfo database
 
 	"go.opentelemetry.io/collector/component"
+
+	"github.com/pavolloffay/otel-mcp/internal/buffer"
 )
 
-var errInvalidBufferSize = errors.New("buffer size must be positive")
+var (
+	errInvalidBufferSize = errors.New("buffer size must not be negative")
+	errNoCORSOrigins     = errors.New("cors requires at least one allowed origin")
+	errInvalidPath       = errors.New("path must start with \"/\" and be a valid http.ServeMux pattern")
+	errNegativeHTTPLimit = errors.New("http timeouts and size limits must not be negative")
+	errInvalidQueryLimit = errors.New("max query limit must be positive")
+	errInvalidMaxSize    = errors.New("max response bytes must not be negative")
+	errInvalidTraceSpans = errors.New("max trace spans must not be negative")
+	errInvalidDefault    = errors.New("default limits must be positive")
+	errInvalidTimeout    = errors.New("query timeout must not be negative")
+	errInvalidEviction   = errors.New("eviction policy must be one of drop_oldest, drop_newest, reject_new")
+	errInvalidDropSample = errors.New("dropped batch samples must not be negative")
+	errInvalidEndpoint   = errors.New("additional endpoints must be non-empty and distinct from each other and the endpoint")
+	errUnknownTool       = errors.New("enabled_tools and disabled_tools entries must be a tool name or one of the groups config, discovery, telemetry")
+	errInvalidTimezone   = errors.New("timezone must be an IANA time zone name such as UTC or Europe/Berlin")
+	errInvalidTraceCache = errors.New("trace_cache ttl and max_traces must be positive")
+	errEmptyRedactKey    = errors.New("redact_attributes entries must not be empty")
+)
 
 // Config defines configuration for the MCP extension
 type Config struct {
 	// Endpoint for the MCP HTTP server (e.g., "localhost:9999")
 	Endpoint string `mapstructure:"endpoint"`
 
-	// TracesBufferSize is the number of recent trace batches to keep in memory
+	// AdditionalEndpoints are further addresses the same MCP server is served on
+	// (e.g. a loopback port for local clients next to a public one)
+	AdditionalEndpoints []string `mapstructure:"additional_endpoints"`
+
+	// Path the MCP handler is served on (e.g., "/mcp")
+	Path string `mapstructure:"path"`
+
+	// TracesBufferSize is the number of recent trace batches to keep in memory.
+	// Zero disables buffering for the signal.
 	TracesBufferSize int `mapstructure:"traces_buffer_size"`
 
-	// MetricsBufferSize is the number of recent metric batches to keep in memory
+	// MetricsBufferSize is the number of recent metric batches to keep in memory.
+	// Zero disables buffering for the signal.
 	MetricsBufferSize int `mapstructure:"metrics_buffer_size"`
 
-	// LogsBufferSize is the number of recent log batches to keep in memory
+	// LogsBufferSize is the number of recent log batches to keep in memory.
+	// Zero disables buffering for the signal.
 	LogsBufferSize int `mapstructure:"logs_buffer_size"`
+
+	// EvictionPolicy controls what happens when a buffer is full: "drop_oldest"
+	// evicts the oldest batch, "drop_newest" evicts the most recent batch and
+	// "reject_new" discards incoming batches.
+	EvictionPolicy string `mapstructure:"eviction_policy"`
+
+	// DroppedBatchSamples is the number of summaries of rejected batches kept
+	// per signal. Rejected batches are always counted; zero disables the summaries.
+	DroppedBatchSamples int `mapstructure:"dropped_batch_samples"`
+
+	// MaxQueryLimit caps the limit a client can request from query tools.
+	// Larger limits are clamped to this value.
+	MaxQueryLimit int `mapstructure:"max_query_limit"`
+
+	// MaxResponseBytes caps the size of the markdown and CSV rendered by the
+	// query_* tools. Longer output is cut with a truncation marker, and calls
+	// can only lower the limit. Zero means no limit.
+	MaxResponseBytes int `mapstructure:"max_response_bytes"`
+
+	// MaxTraceSpans caps the spans get_trace_by_id assembles for one trace.
+	// Spans past the limit are counted but not rendered. Zero means no limit.
+	MaxTraceSpans int `mapstructure:"max_trace_spans"`
+
+	// DefaultQueryLimit is the limit used by the query, search and facet tools
+	// when a call omits it. It is clamped to MaxQueryLimit.
+	DefaultQueryLimit int `mapstructure:"default_query_limit"`
+
+	// DefaultRecentLimit is the limit used by the get_recent_* tools when a call
+	// omits it
+	DefaultRecentLimit int `mapstructure:"default_recent_limit"`
+
+	// QueryTimeout bounds how long a tool call may run. Scan tools stop at the
+	// deadline and return what they collected with truncated set. Zero means no
+	// limit.
+	QueryTimeout time.Duration `mapstructure:"query_timeout"`
+
+	// Timezone is the IANA time zone (e.g. "Europe/Berlin") timestamps are
+	// rendered in by the telemetry tools. Empty means UTC.
+	Timezone string `mapstructure:"timezone"`
+
+	// RedactAttributes lists attribute key patterns whose values are replaced
+	// by [REDACTED] wherever the telemetry tools render attributes. A key
+	// matches when it contains a pattern, ignoring case, so "authorization"
+	// also matches http.request.header.authorization. An empty list disables
+	// redaction.
+	RedactAttributes []string `mapstructure:"redact_attributes"`
+
+	// ReplayDir is a directory of OTLP files (JSON or length-prefixed proto, as
+	// written by export_buffer) loaded into the buffer on Start, to use the
+	// tools without a live pipeline. Empty disables replay.
+	ReplayDir string `mapstructure:"replay_dir"`
+
+	// DumpDir is the directory export_buffer writes dumps to and import_buffer
+	// reads them from. Tool calls name a subdirectory relative to it. Empty
+	// disables file dumps, leaving base64 encoded dumps only.
+	DumpDir string `mapstructure:"dump_dir"`
+
+	// TraceCache assembles the spans of each trace independently of the traces
+	// buffer, so get_trace_by_id can return traces whose early batches were
+	// already evicted. When nil, or when traces_buffer_size is 0, no traces are
+	// cached.
+	TraceCache *TraceCacheConfig `mapstructure:"trace_cache"`
+
+	// ReadTimeout is the maximum duration for reading an entire request.
+	// Zero means no timeout.
+	ReadTimeout time.Duration `mapstructure:"read_timeout"`
+
+	// WriteTimeout is the maximum duration before timing out writes of a response.
+	// Zero means no timeout.
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+
+	// IdleTimeout is the maximum time to wait for the next request on a keep-alive
+	// connection. Zero falls back to ReadTimeout.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+
+	// MaxHeaderBytes caps the size of request headers. Zero uses the Go default (1 MiB).
+	MaxHeaderBytes int `mapstructure:"max_header_bytes"`
+
+	// MaxRequestBodySize caps the size of request bodies in bytes. Zero means no limit.
+	MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
+
+	// LogToolCalls logs every tool invocation with its name, argument keys,
+	// duration and outcome. Argument values are not logged.
+	LogToolCalls bool `mapstructure:"log_tool_calls"`
+
+	// ReadOnly only registers read-only telemetry and inspection tools, skipping
+	// the tools listed in mutatingTools: the config modification and validation
+	// tools, evict_trace, import_buffer and export_buffer
+	ReadOnly bool `mapstructure:"read_only"`
+
+	// EnabledTools restricts the registered tools to the listed tool names or
+	// groups ("config", "discovery", "telemetry"). Empty enables all tools.
+	EnabledTools []string `mapstructure:"enabled_tools"`
+
+	// DisabledTools lists tool names or groups that are never registered, even
+	// if they are matched by EnabledTools
+	DisabledTools []string `mapstructure:"disabled_tools"`
+
+	// CORS enables cross-origin requests from browser-based MCP clients.
+	// When nil, no CORS headers are sent.
+	CORS *CORSConfig `mapstructure:"cors"`
+}
+
+// CORSConfig defines cross-origin resource sharing settings for the MCP endpoint
+type CORSConfig struct {
+	// AllowedOrigins lists origins allowed to call the endpoint. "*" allows any origin.
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+
+	// AllowedHeaders lists request headers allowed in addition to the ones
+	// required by the MCP streamable HTTP transport
+	AllowedHeaders []string `mapstructure:"allowed_headers"`
+}
+
+// TraceCacheConfig defines the trace assembly cache
+type TraceCacheConfig struct {
+	// TTL is how long a trace is kept after its last span arrived
+	TTL time.Duration `mapstructure:"ttl"`
+
+	// MaxTraces caps the number of cached traces. When full, the trace idle
+	// the longest is dropped to make room.
+	MaxTraces int `mapstructure:"max_traces"`
 }
 
 var _ component.Config = (*Config)(nil)
 
 // Validate checks if the extension configuration is valid
 func (cfg *Config) Validate() error {
-	if cfg.TracesBufferSize <= 0 {
+	if !strings.HasPrefix(cfg.Path, "/") || !validPathPattern(cfg.Path) {
+		return errInvalidPath
+	}
+	seen := map[string]bool{cfg.Endpoint: true}
+	for _, endpoint := range cfg.AdditionalEndpoints {
+		if endpoint == "" || seen[endpoint] {
+			return errInvalidEndpoint
+		}
+		seen[endpoint] = true
+	}
+	if cfg.TracesBufferSize < 0 {
 		return errInvalidBufferSize
 	}
-	if cfg.MetricsBufferSize <= 0 {
+	if cfg.MetricsBufferSize < 0 {
 		return errInvalidBufferSize
 	}
-	if cfg.LogsBufferSize <= 0 {
+	if cfg.LogsBufferSize < 0 {
 		return errInvalidBufferSize
 	}
+	switch buffer.EvictionPolicy(cfg.EvictionPolicy) {
+	case buffer.DropOldest, buffer.DropNewest, buffer.RejectNew:
+	default:
+		return errInvalidEviction
+	}
+	if cfg.DroppedBatchSamples < 0 {
+		return errInvalidDropSample
+	}
+	if cfg.MaxQueryLimit <= 0 {
+		return errInvalidQueryLimit
+	}
+	if cfg.MaxResponseBytes < 0 {
+		return errInvalidMaxSize
+	}
+	if cfg.MaxTraceSpans < 0 {
+		return errInvalidTraceSpans
+	}
+	if cfg.DefaultQueryLimit <= 0 || cfg.DefaultRecentLimit <= 0 {
+		return errInvalidDefault
+	}
+	if cfg.QueryTimeout < 0 {
+		return errInvalidTimeout
+	}
+	if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+		return fmt.Errorf("%w: %q", errInvalidTimezone, cfg.Timezone)
+	}
+	for _, pattern := range cfg.RedactAttributes {
+		if strings.TrimSpace(pattern) == "" {
+			return errEmptyRedactKey
+		}
+	}
+	if cfg.ReadTimeout < 0 || cfg.WriteTimeout < 0 || cfg.IdleTimeout < 0 ||
+		cfg.MaxHeaderBytes < 0 || cfg.MaxRequestBodySize < 0 {
+		return errNegativeHTTPLimit
+	}
+	for _, entry := range append(append([]string{}, cfg.EnabledTools...), cfg.DisabledTools...) {
+		if !isKnownTool(entry) {
+			return fmt.Errorf("%w: %q", errUnknownTool, entry)
+		}
+	}
+	if cfg.TraceCache != nil && (cfg.TraceCache.TTL <= 0 || cfg.TraceCache.MaxTraces <= 0) {
+		return errInvalidTraceCache
+	}
+	if cfg.CORS != nil && len(cfg.CORS.AllowedOrigins) == 0 {
+		return errNoCORSOrigins
+	}
 	return nil
 }
+
+// validPathPattern reports whether http.ServeMux accepts path as a pattern.
+// ServeMux.Handle panics on patterns it cannot parse, such as an unclosed "{"
+// or a space, which would otherwise only surface when the extension starts
+func validPathPattern(path string) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	http.NewServeMux().Handle(path, http.NotFoundHandler())
+	return true
+}
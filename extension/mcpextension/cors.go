@@ -0,0 +1,79 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpextension
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// corsDefaultHeaders are always allowed since the MCP streamable HTTP transport relies on them
+var corsDefaultHeaders = []string{"Accept", "Content-Type", "Last-Event-ID", "Mcp-Protocol-Version", "Mcp-Session-Id"}
+
+const corsAllowedMethods = "GET, POST, DELETE, OPTIONS"
+
+// corsHandler wraps an http.Handler and adds CORS headers for allowed origins
+type corsHandler struct {
+	next           http.Handler
+	allowedOrigins []string
+	allowedHeaders string
+}
+
+func newCORSHandler(cfg *CORSConfig, next http.Handler) http.Handler {
+	headers := slices.Clone(corsDefaultHeaders)
+	for _, h := range cfg.AllowedHeaders {
+		h = http.CanonicalHeaderKey(h)
+		if !slices.Contains(headers, h) {
+			headers = append(headers, h)
+		}
+	}
+
+	return &corsHandler{
+		next:           next,
+		allowedOrigins: cfg.AllowedOrigins,
+		allowedHeaders: strings.Join(headers, ", "),
+	}
+}
+
+func (h *corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+	if origin == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Origin")
+	if !h.originAllowed(origin) {
+		if preflight {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		// Let the request through without CORS headers; the browser blocks the response
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if preflight {
+		w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+		w.Header().Set("Access-Control-Allow-Headers", h.allowedHeaders)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+	h.next.ServeHTTP(w, r)
+}
+
+func (h *corsHandler) originAllowed(origin string) bool {
+	for _, allowed := range h.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
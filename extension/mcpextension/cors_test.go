@@ -0,0 +1,90 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpextension
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func sendPreflight(t *testing.T, url, origin string) *http.Response {
+	req, err := http.NewRequest(http.MethodOptions, url, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "content-type,x-custom")
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestMCPExtensionCORS(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
+	cfg.CORS = &CORSConfig{
+		AllowedOrigins: []string{"http://localhost:3000"},
+		AllowedHeaders: []string{"X-Custom"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ext.Shutdown(context.Background())) })
+
+	url := "http://" + cfg.Endpoint + "/mcp"
+
+	t.Run("preflight_allowed_origin", func(t *testing.T) {
+		resp := sendPreflight(t, url, "http://localhost:3000")
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "http://localhost:3000", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, resp.Header.Get("Access-Control-Allow-Methods"), http.MethodPost)
+		assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "Mcp-Session-Id")
+		assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "X-Custom")
+	})
+
+	t.Run("preflight_disallowed_origin", func(t *testing.T) {
+		resp := sendPreflight(t, url, "http://evil.example.com")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Methods"))
+	})
+}
+
+func TestMCPExtensionWithoutCORS(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ext.Shutdown(context.Background())) })
+
+	resp := sendPreflight(t, "http://"+cfg.Endpoint+"/mcp", "http://localhost:3000")
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.CORS = &CORSConfig{}
+	require.ErrorIs(t, cfg.Validate(), errNoCORSOrigins)
+
+	cfg.CORS.AllowedOrigins = []string{"*"}
+	require.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpextension
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// shutdownDrainTimeout bounds how long Shutdown waits for in-flight tool calls
+// to return after their contexts were cancelled
+const shutdownDrainTimeout = 5 * time.Second
+
+var errShuttingDown = errors.New("MCP extension is shutting down")
+
+// callTracker counts in-flight tool calls so Shutdown can wait for them. Once
+// closed it rejects new calls, so the wait group is never added to while
+// Shutdown waits on it.
+type callTracker struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// start registers a tool call and reports false when the tracker is closed
+func (c *callTracker) start() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	c.wg.Add(1)
+	return true
+}
+
+func (c *callTracker) done() {
+	c.wg.Done()
+}
+
+// closeAndWait rejects new calls and waits until the in-flight ones returned or
+// ctx is done
+func (c *callTracker) closeAndWait(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// toolCallDrainMiddleware tracks tool calls in calls and cancels their context
+// when serverCtx is cancelled, so scans in progress stop at Shutdown
+func toolCallDrainMiddleware(serverCtx context.Context, calls *callTracker) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if _, ok := req.GetParams().(*mcp.CallToolParamsRaw); !ok {
+				return next(ctx, method, req)
+			}
+			if !calls.start() {
+				return nil, errShuttingDown
+			}
+			defer calls.done()
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			stop := context.AfterFunc(serverCtx, cancel)
+			defer stop()
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// toolCallTimeoutMiddleware bounds the context of tool calls by timeout, so
+// scans over large buffers stop and return partial results. Zero disables it.
+func toolCallTimeoutMiddleware(timeout time.Duration) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if _, ok := req.GetParams().(*mcp.CallToolParamsRaw); !ok || timeout <= 0 {
+				return next(ctx, method, req)
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, method, req)
+		}
+	}
+}
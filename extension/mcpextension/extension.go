@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -35,15 +36,20 @@ var (
 )
 
 type mcpExtension struct {
+	id        component.ID
 	config    *Config
 	logger    *zap.Logger
 	telemetry component.TelemetrySettings
 
 	// MCP server
-	server     *mcp.Server
-	mu         sync.Mutex
-	httpServer *http.Server
-	cancelFunc context.CancelFunc
+	server      *mcp.Server
+	mu          sync.Mutex
+	httpServers []*http.Server
+	cancelFunc  context.CancelFunc // cancels the context of in-flight tool calls
+	calls       *callTracker
+
+	// Tools registered on server, listed once by registerTools
+	registeredTools []*mcp.Tool
 
 	// Configuration from collector - uses atomic.Value for lock-free reads
 	collectorConf atomic.Value // stores *confmap.Conf
@@ -51,21 +57,56 @@ type mcpExtension struct {
 	// Telemetry buffer
 	buffer buffer.TelemetryBuffer
 
+	// Trace assembly cache, nil when disabled
+	traceCache *buffer.TraceCache
+
+	// Time zone rendered timestamps are shown in
+	location *time.Location
+
+	// Lower-cased redact_attributes patterns
+	redactPatterns []string
+
+	// First config notification and first telemetry per signal, in unix nanos (0 until received)
+	firstConfigAt  atomic.Int64
+	firstTracesAt  atomic.Int64
+	firstMetricsAt atomic.Int64
+	firstLogsAt    atomic.Int64
+
 	// Component host for introspection
 	host component.Host
 
 	// Host capabilities (optional)
 	moduleInfos      atomic.Value // stores service.ModuleInfos
-	componentFactory hostcapabilities.ComponentFactory
+	componentFactory atomic.Value // stores componentFactoryHolder
+}
+
+// componentFactoryHolder wraps the host's ComponentFactory so atomic.Value always
+// stores the same concrete type, whatever type the host is
+type componentFactoryHolder struct {
+	factory hostcapabilities.ComponentFactory
 }
 
 func newMCPExtension(cfg *Config, set extension.Settings) *mcpExtension {
-	return &mcpExtension{
+	// The timezone was checked by Config.Validate
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	e := &mcpExtension{
+		id:        set.ID,
 		config:    cfg,
 		logger:    set.Logger,
 		telemetry: set.TelemetrySettings,
-		buffer:    buffer.New(cfg.TracesBufferSize, cfg.MetricsBufferSize, cfg.LogsBufferSize),
+		buffer:    buffer.NewWithPolicy(cfg.TracesBufferSize, cfg.MetricsBufferSize, cfg.LogsBufferSize, buffer.EvictionPolicy(cfg.EvictionPolicy), cfg.DroppedBatchSamples),
+		location:  location,
+	}
+	for _, pattern := range cfg.RedactAttributes {
+		e.redactPatterns = append(e.redactPatterns, strings.ToLower(strings.TrimSpace(pattern)))
 	}
+	if cfg.TraceCache != nil {
+		e.traceCache = buffer.NewTraceCache(cfg.TraceCache.TTL, cfg.TraceCache.MaxTraces)
+	}
+	return e
 }
 
 func (e *mcpExtension) Start(_ context.Context, host component.Host) error {
@@ -88,23 +129,36 @@ func (e *mcpExtension) Start(_ context.Context, host component.Host) error {
 	}
 
 	if cf, ok := host.(hostcapabilities.ComponentFactory); ok {
-		e.componentFactory = cf
+		e.componentFactory.Store(componentFactoryHolder{factory: cf})
 		e.logger.Info("Host provides ComponentFactory capability")
 	} else {
 		e.logger.Warn("Host does not provide ComponentFactory capability - factory inspection will be limited")
 	}
 
-	// Create MCP server
-	serverInfo := &mcp.Implementation{
-		Name:    "otel-collector-mcp",
-		Version: "0.1.0",
+	if e.config.ReplayDir != "" {
+		stats, err := e.replayDir(e.config.ReplayDir)
+		if err != nil {
+			return err
+		}
+		e.logger.Info("Replayed telemetry into the buffer",
+			zap.String("replay_dir", e.config.ReplayDir),
+			zap.Int("trace_batches", stats.traces),
+			zap.Int("metric_batches", stats.metrics),
+			zap.Int("log_batches", stats.logs),
+		)
 	}
 
-	server := mcp.NewServer(serverInfo, nil)
+	// Create MCP server. Tool calls run with a context cancelled on Shutdown or
+	// after the query timeout.
+	serverCtx, cancel := context.WithCancel(context.Background())
+	calls := &callTracker{}
+	server := newServer()
+	server.AddReceivingMiddleware(toolCallDrainMiddleware(serverCtx, calls), toolCallTimeoutMiddleware(e.config.QueryTimeout))
 	e.server = server
 
 	// Register all MCP tools
 	if err := e.registerTools(); err != nil {
+		cancel()
 		return err
 	}
 
@@ -118,78 +172,177 @@ func (e *mcpExtension) Start(_ context.Context, host component.Host) error {
 
 	// Create HTTP server
 	mux := http.NewServeMux()
-	mux.Handle("/mcp", handler)
+	mux.Handle(e.config.Path, handler)
 
-	// Create listener to verify binding before returning from Start
-	listener, err := net.Listen("tcp", e.config.Endpoint)
-	if err != nil {
-		return fmt.Errorf("failed to bind MCP HTTP server to %s: %w", e.config.Endpoint, err)
+	var httpHandler = gzipHandler(mux)
+	if e.config.MaxRequestBodySize > 0 {
+		httpHandler = maxBodySizeHandler(httpHandler, e.config.MaxRequestBodySize)
+	}
+	if e.config.CORS != nil {
+		httpHandler = newCORSHandler(e.config.CORS, httpHandler)
+	}
+
+	// Create all listeners to verify binding before returning from Start.
+	// If any endpoint fails to bind, the ones already bound are released.
+	endpoints := append([]string{e.config.Endpoint}, e.config.AdditionalEndpoints...)
+	listeners := make([]net.Listener, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		listener, err := net.Listen("tcp", endpoint)
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			cancel()
+			return fmt.Errorf("failed to bind MCP HTTP server to %s: %w", endpoint, err)
+		}
+		listeners = append(listeners, listener)
 	}
 
-	// Protect httpServer and cancelFunc with mutex
+	// Protect httpServers and cancelFunc with mutex
 	e.mu.Lock()
-	e.httpServer = &http.Server{
-		Addr:              e.config.Endpoint,
-		Handler:           mux,
-		ReadHeaderTimeout: 10 * time.Second,
+	e.httpServers = make([]*http.Server, len(endpoints))
+	for i, endpoint := range endpoints {
+		e.httpServers[i] = &http.Server{
+			Addr:              endpoint,
+			Handler:           httpHandler,
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       e.config.ReadTimeout,
+			WriteTimeout:      e.config.WriteTimeout,
+			IdleTimeout:       e.config.IdleTimeout,
+			MaxHeaderBytes:    e.config.MaxHeaderBytes,
+		}
 	}
 
-	// Start HTTP server in background
-	_, cancel := context.WithCancel(context.Background())
 	e.cancelFunc = cancel
-	httpServer := e.httpServer
+	e.calls = calls
+	httpServers := e.httpServers
 	e.mu.Unlock()
 
-	go func() {
-		e.logger.Info("Starting MCP HTTP server", zap.String("endpoint", e.config.Endpoint))
-		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
-			e.logger.Error("MCP HTTP server error", zap.Error(err))
-		}
-	}()
+	// Start HTTP servers in background
+	for i, httpServer := range httpServers {
+		listener := listeners[i]
+		go func() {
+			e.logger.Info("Starting MCP HTTP server", zap.String("endpoint", httpServer.Addr))
+			if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+				e.logger.Error("MCP HTTP server error", zap.String("endpoint", httpServer.Addr), zap.Error(err))
+			}
+		}()
+	}
 
-	e.logger.Info("MCP extension started successfully", zap.String("endpoint", e.config.Endpoint))
+	e.logger.Info("MCP extension started successfully", zap.Strings("endpoints", endpoints), zap.String("path", e.config.Path))
 	return nil
 }
 
 func (e *mcpExtension) Shutdown(ctx context.Context) error {
 	e.logger.Info("Shutting down MCP extension")
 
-	// Get httpServer and cancelFunc under lock
+	// Get httpServers, cancelFunc and calls under lock
 	e.mu.Lock()
-	httpServer := e.httpServer
+	httpServers := e.httpServers
 	cancelFunc := e.cancelFunc
+	calls := e.calls
 	e.mu.Unlock()
 
-	// Stop HTTP server gracefully
-	if httpServer != nil {
-		if err := httpServer.Shutdown(ctx); err != nil {
-			e.logger.Error("Error shutting down MCP HTTP server", zap.Error(err))
+	// Abort in-flight tool calls and wait for their handlers to return, so no
+	// scan is still reading the buffers once Shutdown returns
+	if cancelFunc != nil {
+		cancelFunc()
+	}
+	if calls != nil {
+		drainCtx, cancel := context.WithTimeout(ctx, shutdownDrainTimeout)
+		err := calls.closeAndWait(drainCtx)
+		cancel()
+		if err != nil {
+			e.logger.Warn("Timed out waiting for in-flight MCP tool calls", zap.Error(err))
 		}
 	}
 
-	if cancelFunc != nil {
-		cancelFunc()
+	// Stop HTTP servers gracefully
+	for _, httpServer := range httpServers {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			e.logger.Error("Error shutting down MCP HTTP server", zap.String("endpoint", httpServer.Addr), zap.Error(err))
+		}
 	}
+
 	return nil
 }
 
+// newServer creates the MCP server. Resource subscriptions are not supported:
+// the stateless HTTP handler keeps no session to send update notifications on,
+// so clients re-read the config resources instead.
+func newServer() *mcp.Server {
+	serverInfo := &mcp.Implementation{
+		Name:    "otel-collector-mcp",
+		Version: "0.1.0",
+	}
+
+	return mcp.NewServer(serverInfo, nil)
+}
+
 // NotifyConfig implements extensioncapabilities.ConfigWatcher
 func (e *mcpExtension) NotifyConfig(_ context.Context, conf *confmap.Conf) error {
 	e.collectorConf.Store(conf)
+	markFirst(&e.firstConfigAt)
 	e.logger.Info("Received collector configuration update")
+	e.applyBufferSizes(conf)
 	return nil
 }
 
+// applyBufferSizes resizes the telemetry buffers when a config reload changed
+// the extension's own buffer sizes. Invalid extension configs are ignored since
+// the collector rejects them before they take effect.
+func (e *mcpExtension) applyBufferSizes(conf *confmap.Conf) {
+	key := "extensions::" + e.id.String()
+	if !conf.IsSet(key) {
+		return
+	}
+	sub, err := conf.Sub(key)
+	if err != nil {
+		e.logger.Warn("Failed to read MCP extension configuration", zap.Error(err))
+		return
+	}
+
+	updated := createDefaultConfig().(*Config)
+	if err := sub.Unmarshal(updated); err != nil {
+		e.logger.Warn("Failed to parse MCP extension configuration", zap.Error(err))
+		return
+	}
+	if err := updated.Validate(); err != nil {
+		e.logger.Warn("Ignoring invalid MCP extension configuration", zap.Error(err))
+		return
+	}
+
+	stats := e.buffer.GetStats()
+	if stats.TracesCapacity == updated.TracesBufferSize &&
+		stats.MetricsCapacity == updated.MetricsBufferSize &&
+		stats.LogsCapacity == updated.LogsBufferSize {
+		return
+	}
+
+	e.buffer.Resize(updated.TracesBufferSize, updated.MetricsBufferSize, updated.LogsBufferSize)
+	e.logger.Info("Resized telemetry buffers",
+		zap.Int("traces_buffer_size", updated.TracesBufferSize),
+		zap.Int("metrics_buffer_size", updated.MetricsBufferSize),
+		zap.Int("logs_buffer_size", updated.LogsBufferSize),
+	)
+}
+
 // TelemetryBuffer interface implementation - delegates to internal buffer
 func (e *mcpExtension) AddTraces(td ptrace.Traces) {
+	markFirst(&e.firstTracesAt)
+	if e.traceCacheEnabled() {
+		e.traceCache.Add(td)
+	}
 	e.buffer.AddTraces(td)
 }
 
 func (e *mcpExtension) AddMetrics(md pmetric.Metrics) {
+	markFirst(&e.firstMetricsAt)
 	e.buffer.AddMetrics(md)
 }
 
 func (e *mcpExtension) AddLogs(ld plog.Logs) {
+	markFirst(&e.firstLogsAt)
 	e.buffer.AddLogs(ld)
 }
 
@@ -205,10 +358,38 @@ func (e *mcpExtension) GetRecentLogs(limit, offset int) []plog.Logs {
 	return e.buffer.GetRecentLogs(limit, offset)
 }
 
+func (e *mcpExtension) GetRecentTraceBatches(limit, offset int) []buffer.Batch[ptrace.Traces] {
+	return e.buffer.GetRecentTraceBatches(limit, offset)
+}
+
+func (e *mcpExtension) GetRecentMetricBatches(limit, offset int) []buffer.Batch[pmetric.Metrics] {
+	return e.buffer.GetRecentMetricBatches(limit, offset)
+}
+
+func (e *mcpExtension) GetRecentLogBatches(limit, offset int) []buffer.Batch[plog.Logs] {
+	return e.buffer.GetRecentLogBatches(limit, offset)
+}
+
+func (e *mcpExtension) EvictTrace(traceID string) int {
+	traceID = strings.ToLower(traceID)
+	if e.traceCache != nil {
+		e.traceCache.Evict(traceID)
+	}
+	return e.buffer.EvictTrace(traceID)
+}
+
+func (e *mcpExtension) Resize(tracesCapacity, metricsCapacity, logsCapacity int) {
+	e.buffer.Resize(tracesCapacity, metricsCapacity, logsCapacity)
+}
+
 func (e *mcpExtension) GetStats() buffer.BufferStats {
 	return e.buffer.GetStats()
 }
 
+func (e *mcpExtension) GetDropStats() buffer.DropStats {
+	return e.buffer.GetDropStats()
+}
+
 // ExtensionContext interface implementation for tools
 func (e *mcpExtension) GetCollectorConf() *confmap.Conf {
 	val := e.collectorConf.Load()
@@ -238,6 +419,121 @@ func (e *mcpExtension) GetBufferStats() tools.BufferStats {
 	}
 }
 
+func (e *mcpExtension) GetLogBatches(limit, offset int) []tools.LogBatch {
+	batches := e.buffer.GetRecentLogBatches(limit, offset)
+	result := make([]tools.LogBatch, len(batches))
+	for i, batch := range batches {
+		result[i] = tools.LogBatch{Seq: batch.Seq, Logs: batch.Data}
+	}
+	return result
+}
+
+// GetBatchInfos returns the metadata of the buffered batches of signal
+// ("traces", "metrics" or "logs"), sizing each batch as OTLP protobuf
+func (e *mcpExtension) GetBatchInfos(signal string, limit, offset int) []tools.BatchInfo {
+	return e.batchInfos(signal, limit, offset, true)
+}
+
+// GetBatchTimes returns the metadata of the buffered batches of signal like
+// GetBatchInfos, without the cost of sizing them: SizeBytes is left 0
+func (e *mcpExtension) GetBatchTimes(signal string, limit, offset int) []tools.BatchInfo {
+	return e.batchInfos(signal, limit, offset, false)
+}
+
+func (e *mcpExtension) batchInfos(signal string, limit, offset int, sized bool) []tools.BatchInfo {
+	var result []tools.BatchInfo
+	switch signal {
+	case "traces":
+		sizer := &ptrace.ProtoMarshaler{}
+		for _, batch := range e.buffer.GetRecentTraceBatches(limit, offset) {
+			size := 0
+			if sized {
+				size = sizer.TracesSize(batch.Data)
+			}
+			result = append(result, batchInfo(batch, size))
+		}
+	case "metrics":
+		sizer := &pmetric.ProtoMarshaler{}
+		for _, batch := range e.buffer.GetRecentMetricBatches(limit, offset) {
+			size := 0
+			if sized {
+				size = sizer.MetricsSize(batch.Data)
+			}
+			result = append(result, batchInfo(batch, size))
+		}
+	case "logs":
+		sizer := &plog.ProtoMarshaler{}
+		for _, batch := range e.buffer.GetRecentLogBatches(limit, offset) {
+			size := 0
+			if sized {
+				size = sizer.LogsSize(batch.Data)
+			}
+			result = append(result, batchInfo(batch, size))
+		}
+	}
+	return result
+}
+
+func batchInfo[T any](batch buffer.Batch[T], size int) tools.BatchInfo {
+	return tools.BatchInfo{
+		Seq:        batch.Seq,
+		ReceivedAt: batch.ReceivedAt,
+		Items:      batch.Items,
+		SizeBytes:  size,
+	}
+}
+
+// GetSignalDrops returns the batches the buffer of signal ("traces", "metrics"
+// or "logs") rejected
+func (e *mcpExtension) GetSignalDrops(signal string) tools.SignalDrops {
+	stats := e.buffer.GetDropStats()
+	var drops buffer.SignalDrops
+	switch signal {
+	case "traces":
+		drops = stats.Traces
+	case "metrics":
+		drops = stats.Metrics
+	case "logs":
+		drops = stats.Logs
+	}
+
+	result := tools.SignalDrops{
+		Batches: drops.Batches,
+		Items:   drops.Items,
+		Recent:  make([]tools.DroppedBatch, len(drops.Recent)),
+	}
+	for i, dropped := range drops.Recent {
+		result.Recent[i] = tools.DroppedBatch{
+			DroppedAt: dropped.DroppedAt,
+			Items:     dropped.Items,
+			Services:  dropped.Services,
+		}
+	}
+	return result
+}
+
+func (e *mcpExtension) GetReadiness() tools.Readiness {
+	return tools.Readiness{
+		FirstConfigAt:  loadTime(&e.firstConfigAt),
+		FirstTracesAt:  loadTime(&e.firstTracesAt),
+		FirstMetricsAt: loadTime(&e.firstMetricsAt),
+		FirstLogsAt:    loadTime(&e.firstLogsAt),
+	}
+}
+
+// markFirst records the current time unless a time was already recorded
+func markFirst(v *atomic.Int64) {
+	v.CompareAndSwap(0, time.Now().UnixNano())
+}
+
+func loadTime(v *atomic.Int64) time.Time {
+	nanos := v.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
 func (e *mcpExtension) GetModuleInfos() *service.ModuleInfos {
 	val := e.moduleInfos.Load()
 	if val == nil {
@@ -248,5 +544,73 @@ func (e *mcpExtension) GetModuleInfos() *service.ModuleInfos {
 }
 
 func (e *mcpExtension) GetComponentFactory() hostcapabilities.ComponentFactory {
-	return e.componentFactory
+	val := e.componentFactory.Load()
+	if val == nil {
+		return nil
+	}
+	return val.(componentFactoryHolder).factory
+}
+
+func (e *mcpExtension) GetMaxQueryLimit() int {
+	return e.config.MaxQueryLimit
+}
+
+func (e *mcpExtension) GetMaxResponseBytes() int {
+	return e.config.MaxResponseBytes
+}
+
+func (e *mcpExtension) GetMaxTraceSpans() int {
+	return e.config.MaxTraceSpans
+}
+
+func (e *mcpExtension) GetDefaultQueryLimit() int {
+	return e.config.DefaultQueryLimit
+}
+
+func (e *mcpExtension) GetDefaultRecentLimit() int {
+	return e.config.DefaultRecentLimit
+}
+
+func (e *mcpExtension) GetCachedTrace(traceID string) (ptrace.Traces, bool) {
+	if !e.traceCacheEnabled() {
+		return ptrace.Traces{}, false
+	}
+	return e.traceCache.Get(strings.ToLower(traceID))
+}
+
+// traceCacheEnabled reports whether the trace assembly cache is configured and
+// trace buffering is enabled; traces_buffer_size: 0 turns the cache off too
+func (e *mcpExtension) traceCacheEnabled() bool {
+	return e.traceCache != nil && e.buffer.GetStats().TracesCapacity > 0
+}
+
+func (e *mcpExtension) GetLocation() *time.Location {
+	return e.location
+}
+
+func (e *mcpExtension) GetDumpDir() string {
+	return e.config.DumpDir
+}
+
+func (e *mcpExtension) IsRedactedAttribute(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range e.redactPatterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxBodySizeHandler rejects requests whose declared body exceeds limit and caps
+// the bytes read from bodies of unknown length
+func maxBodySizeHandler(next http.Handler, limit int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
 }
@@ -5,30 +5,38 @@ package mcpextension
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/pavolloffay/otel-mcp/internal/tools"
 )
 
 func TestMCPExtensionUsage(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
@@ -58,12 +66,11 @@ func TestMCPExtensionPortAlreadyInUse(t *testing.T) {
 	require.NoError(t, err)
 	defer ln.Close()
 
-	cfg := &Config{
-		Endpoint:          endpoint,
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
@@ -75,12 +82,11 @@ func TestMCPExtensionPortAlreadyInUse(t *testing.T) {
 }
 
 func TestMCPExtensionMultipleStarts(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
@@ -95,12 +101,11 @@ func TestMCPExtensionMultipleStarts(t *testing.T) {
 }
 
 func TestMCPExtensionMultipleShutdowns(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
@@ -111,12 +116,11 @@ func TestMCPExtensionMultipleShutdowns(t *testing.T) {
 }
 
 func TestMCPExtensionShutdownWithoutStart(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
@@ -125,12 +129,11 @@ func TestMCPExtensionShutdownWithoutStart(t *testing.T) {
 }
 
 func TestMCPExtensionConfigWatcher(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
@@ -153,14 +156,57 @@ func TestMCPExtensionConfigWatcher(t *testing.T) {
 	assert.Equal(t, testConf.ToStringMap(), storedConf.ToStringMap())
 }
 
-func TestMCPExtensionBufferOperations(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  5,
-		MetricsBufferSize: 5,
-		LogsBufferSize:    5,
+func TestMCPExtensionConfigWatcherResizesBuffers(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TracesBufferSize = 5
+	cfg.MetricsBufferSize = 5
+	cfg.LogsBufferSize = 5
+
+	set := extensiontest.NewNopSettings(component.MustNewType("mcp"))
+	set.ID = component.MustNewID("mcp")
+	ext := newMCPExtension(cfg, set)
+	for i := 0; i < 5; i++ {
+		ext.AddLogs(plog.NewLogs())
 	}
 
+	// Config without the extension leaves the buffers alone
+	require.NoError(t, ext.NotifyConfig(context.Background(), confmap.New()))
+	assert.Equal(t, 5, ext.GetStats().LogsCapacity)
+
+	reloaded := confmap.NewFromStringMap(map[string]any{
+		"extensions": map[string]any{
+			"mcp": map[string]any{
+				"traces_buffer_size":  5,
+				"metrics_buffer_size": 8,
+				"logs_buffer_size":    2,
+			},
+		},
+	})
+	require.NoError(t, ext.NotifyConfig(context.Background(), reloaded))
+
+	stats := ext.GetStats()
+	assert.Equal(t, 5, stats.TracesCapacity)
+	assert.Equal(t, 8, stats.MetricsCapacity)
+	assert.Equal(t, 2, stats.LogsCapacity)
+	assert.Equal(t, 2, stats.LogsCount)
+
+	// Invalid sizes are ignored
+	invalid := confmap.NewFromStringMap(map[string]any{
+		"extensions": map[string]any{
+			"mcp": map[string]any{"logs_buffer_size": -1},
+		},
+	})
+	require.NoError(t, ext.NotifyConfig(context.Background(), invalid))
+	assert.Equal(t, 2, ext.GetStats().LogsCapacity)
+}
+
+func TestMCPExtensionBufferOperations(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 5
+	cfg.MetricsBufferSize = 5
+	cfg.LogsBufferSize = 5
+
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
 
@@ -198,13 +244,79 @@ func TestMCPExtensionBufferOperations(t *testing.T) {
 	assert.Equal(t, 5, stats.LogsCapacity)
 }
 
+func TestMCPExtensionReadiness(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 5
+	cfg.MetricsBufferSize = 5
+	cfg.LogsBufferSize = 5
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NotNil(t, ext)
+
+	readiness := ext.GetReadiness()
+	assert.True(t, readiness.FirstConfigAt.IsZero())
+	assert.True(t, readiness.FirstTracesAt.IsZero())
+	assert.True(t, readiness.FirstMetricsAt.IsZero())
+	assert.True(t, readiness.FirstLogsAt.IsZero())
+
+	require.NoError(t, ext.NotifyConfig(context.Background(), confmap.New()))
+	ext.AddLogs(plog.NewLogs())
+
+	readiness = ext.GetReadiness()
+	firstConfigAt, firstLogsAt := readiness.FirstConfigAt, readiness.FirstLogsAt
+	assert.False(t, firstConfigAt.IsZero())
+	assert.False(t, firstLogsAt.IsZero())
+	assert.True(t, readiness.FirstTracesAt.IsZero())
+	assert.True(t, readiness.FirstMetricsAt.IsZero())
+
+	// Later notifications and telemetry do not move the first-received times
+	time.Sleep(time.Millisecond)
+	require.NoError(t, ext.NotifyConfig(context.Background(), confmap.New()))
+	ext.AddLogs(plog.NewLogs())
+
+	readiness = ext.GetReadiness()
+	assert.True(t, firstConfigAt.Equal(readiness.FirstConfigAt))
+	assert.True(t, firstLogsAt.Equal(readiness.FirstLogsAt))
+}
+
+func TestMCPExtensionBatchInfos(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NotNil(t, ext)
+
+	before := time.Now()
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetName("a")
+	spans.AppendEmpty().SetName("b")
+	ext.AddTraces(td)
+	ext.AddTraces(ptrace.NewTraces())
+
+	infos := ext.GetBatchInfos("traces", 10, 0)
+	require.Len(t, infos, 2)
+	assert.Equal(t, uint64(0), infos[0].Seq)
+	assert.Equal(t, 2, infos[0].Items)
+	assert.Equal(t, (&ptrace.ProtoMarshaler{}).TracesSize(td), infos[0].SizeBytes)
+	assert.False(t, infos[0].ReceivedAt.Before(before))
+	assert.Equal(t, 0, infos[1].Items)
+
+	times := ext.GetBatchTimes("traces", 10, 0)
+	require.Len(t, times, 2)
+	assert.Equal(t, infos[0].ReceivedAt, times[0].ReceivedAt)
+	assert.Equal(t, 2, times[0].Items)
+	assert.Equal(t, 0, times[0].SizeBytes)
+
+	assert.Empty(t, ext.GetBatchInfos("logs", 10, 0))
+	assert.Empty(t, ext.GetBatchInfos("profiles", 10, 0))
+}
+
 func TestMCPExtensionBufferCapacity(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  3,
-		MetricsBufferSize: 3,
-		LogsBufferSize:    3,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 3
+	cfg.MetricsBufferSize = 3
+	cfg.LogsBufferSize = 3
 
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 	require.NotNil(t, ext)
@@ -229,3 +341,573 @@ func getAvailableLocalAddress(t *testing.T) string {
 	defer ln.Close()
 	return ln.Addr().String()
 }
+
+func TestMCPExtensionHTTPLimits(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
+	cfg.ReadTimeout = 30 * time.Second
+	cfg.WriteTimeout = 60 * time.Second
+	cfg.IdleTimeout = 90 * time.Second
+	cfg.MaxHeaderBytes = 64 << 10
+	cfg.MaxRequestBodySize = 256
+	require.NoError(t, cfg.Validate())
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ext.Shutdown(context.Background())) })
+
+	ext.mu.Lock()
+	require.Len(t, ext.httpServers, 1)
+	httpServer := ext.httpServers[0]
+	ext.mu.Unlock()
+	assert.Equal(t, 30*time.Second, httpServer.ReadTimeout)
+	assert.Equal(t, 60*time.Second, httpServer.WriteTimeout)
+	assert.Equal(t, 90*time.Second, httpServer.IdleTimeout)
+	assert.Equal(t, 64<<10, httpServer.MaxHeaderBytes)
+
+	// A body over the limit is rejected before reaching the MCP handler
+	body := `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"padding":"` + strings.Repeat("x", 512) + `"}}`
+	req, err := http.NewRequest(http.MethodPost, "http://"+cfg.Endpoint+cfg.Path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestConfigValidateHTTPLimits(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.WriteTimeout = -time.Second
+	require.ErrorIs(t, cfg.Validate(), errNegativeHTTPLimit)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MaxRequestBodySize = -1
+	require.ErrorIs(t, cfg.Validate(), errNegativeHTTPLimit)
+}
+
+func TestConfigValidateEvictionPolicy(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	for _, policy := range []string{"drop_newest", "reject_new"} {
+		cfg.EvictionPolicy = policy
+		require.NoError(t, cfg.Validate())
+	}
+
+	cfg.EvictionPolicy = "drop_random"
+	require.ErrorIs(t, cfg.Validate(), errInvalidEviction)
+}
+
+func TestConfigValidateBufferSizes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TracesBufferSize = 0
+	require.NoError(t, cfg.Validate())
+
+	cfg.MetricsBufferSize = -1
+	require.ErrorIs(t, cfg.Validate(), errInvalidBufferSize)
+}
+
+func TestConfigValidateDroppedBatchSamples(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DroppedBatchSamples = 0
+	require.NoError(t, cfg.Validate())
+
+	cfg.DroppedBatchSamples = -1
+	require.ErrorIs(t, cfg.Validate(), errInvalidDropSample)
+}
+
+func TestConfigValidateTimezone(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Timezone = "Asia/Tokyo"
+	require.NoError(t, cfg.Validate())
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	assert.Equal(t, "Asia/Tokyo", ext.GetLocation().String())
+
+	cfg.Timezone = "Mars/Olympus_Mons"
+	require.ErrorIs(t, cfg.Validate(), errInvalidTimezone)
+}
+
+func TestConfigValidateMaxResponseBytes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxResponseBytes = 64 * 1024
+	require.NoError(t, cfg.Validate())
+
+	cfg.MaxResponseBytes = -1
+	require.ErrorIs(t, cfg.Validate(), errInvalidMaxSize)
+}
+
+func TestConfigValidateMaxTraceSpans(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, defaultTraceSpans, cfg.MaxTraceSpans)
+
+	cfg.MaxTraceSpans = 0
+	require.NoError(t, cfg.Validate())
+
+	cfg.MaxTraceSpans = -1
+	require.ErrorIs(t, cfg.Validate(), errInvalidTraceSpans)
+}
+
+func TestConfigValidateRedactAttributes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	assert.True(t, ext.IsRedactedAttribute("http.request.header.Authorization"))
+	assert.True(t, ext.IsRedactedAttribute("http.response.header.set-cookie"))
+	assert.True(t, ext.IsRedactedAttribute("db.password"))
+	assert.False(t, ext.IsRedactedAttribute("http.method"))
+
+	cfg.RedactAttributes = nil
+	ext = newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	assert.False(t, ext.IsRedactedAttribute("authorization"))
+
+	cfg.RedactAttributes = []string{"token", " "}
+	require.ErrorIs(t, cfg.Validate(), errEmptyRedactKey)
+}
+
+func TestConfigValidateDefaultLimits(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DefaultQueryLimit = 500
+	cfg.DefaultRecentLimit = 50
+	require.NoError(t, cfg.Validate())
+
+	cfg.DefaultQueryLimit = 0
+	require.ErrorIs(t, cfg.Validate(), errInvalidDefault)
+
+	// Defaults above max_query_limit are clamped when a tool resolves its limit
+	cfg.DefaultQueryLimit = cfg.MaxQueryLimit + 1
+	require.NoError(t, cfg.Validate())
+
+	cfg.DefaultQueryLimit = 100
+	cfg.DefaultRecentLimit = -1
+	require.ErrorIs(t, cfg.Validate(), errInvalidDefault)
+}
+
+func TestConfigValidateQueryTimeout(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, 30*time.Second, cfg.QueryTimeout)
+
+	cfg.QueryTimeout = 0
+	require.NoError(t, cfg.Validate())
+
+	cfg.QueryTimeout = -time.Second
+	require.ErrorIs(t, cfg.Validate(), errInvalidTimeout)
+}
+
+func TestConfigValidateTools(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+
+	cfg.EnabledTools = []string{"telemetry", "get_config"}
+	cfg.DisabledTools = []string{"evict_trace"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.EnabledTools = []string{"no_such_tool"}
+	require.ErrorIs(t, cfg.Validate(), errUnknownTool)
+
+	cfg.EnabledTools = nil
+	cfg.DisabledTools = []string{"writes"}
+	require.ErrorIs(t, cfg.Validate(), errUnknownTool)
+}
+
+func TestRegisterToolsFiltering(t *testing.T) {
+	tests := []struct {
+		name          string
+		readOnly      bool
+		enabledTools  []string
+		disabledTools []string
+		want          []string
+		notWant       []string
+	}{
+		{
+			name: "all_enabled_by_default",
+			want: []string{"get_config", "list_available_components", "add_component", "query_logs", "get_collector_info"},
+		},
+		{
+			name:         "enabled_group_and_tool",
+			enabledTools: []string{"telemetry", "get_config"},
+			want:         []string{"query_logs", "evict_trace", "get_config"},
+			notWant:      []string{"get_pipeline_config", "add_component", "list_available_components"},
+		},
+		{
+			name:          "disabled_group",
+			disabledTools: []string{"config"},
+			want:          []string{"query_logs", "list_available_components", "get_collector_info"},
+			notWant:       []string{"get_config", "add_component", "validate_ottl"},
+		},
+		{
+			name:          "disabled_wins_over_enabled",
+			enabledTools:  []string{"telemetry"},
+			disabledTools: []string{"evict_trace"},
+			want:          []string{"query_traces"},
+			notWant:       []string{"evict_trace", "get_config"},
+		},
+		{
+			name:     "read_only",
+			readOnly: true,
+			want:     []string{"get_config", "get_pipeline_config", "list_available_components", "query_logs", "get_collector_info"},
+			notWant:  []string{"validate_config_section", "add_component", "remove_component", "validate_config", "update_pipeline", "validate_ottl", "evict_trace", "import_buffer", "export_buffer"},
+		},
+		{
+			name:         "read_only_with_enabled_tools",
+			readOnly:     true,
+			enabledTools: []string{"config"},
+			want:         []string{"get_config", "list_configured_components"},
+			notWant:      []string{"add_component", "validate_ottl", "query_logs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.ReadOnly = tt.readOnly
+			cfg.EnabledTools = tt.enabledTools
+			cfg.DisabledTools = tt.disabledTools
+			require.NoError(t, cfg.Validate())
+
+			names := registeredToolNames(t, cfg)
+			for _, name := range tt.want {
+				assert.Contains(t, names, name)
+			}
+			for _, name := range tt.notWant {
+				assert.NotContains(t, names, name)
+			}
+		})
+	}
+}
+
+// registeredToolNames registers the tools allowed by cfg on a fresh server and
+// lists them over an in-memory transport
+func registeredToolNames(t *testing.T, cfg *Config) []string {
+	ctx := context.Background()
+	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	ext.server = mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
+	require.NoError(t, ext.registerTools())
+
+	_, err := ext.server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestRegisteredToolsCatalog(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DisabledTools = []string{"config"}
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	ext.server = newServer()
+	require.NoError(t, ext.registerTools())
+
+	names := make([]string, 0, len(ext.GetRegisteredTools()))
+	for _, tool := range ext.GetRegisteredTools() {
+		names = append(names, tool.Name)
+		assert.NotNil(t, tool.InputSchema, tool.Name)
+	}
+	assert.ElementsMatch(t, registeredToolNames(t, cfg), names)
+	assert.NotContains(t, names, "get_config")
+}
+
+func TestConfigResources(t *testing.T) {
+	ctx := context.Background()
+	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
+
+	ext := newMCPExtension(createDefaultConfig().(*Config), extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	ext.server = newServer()
+	require.NoError(t, ext.registerTools())
+
+	_, err := ext.server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	// Subscriptions are not advertised since the stateless HTTP handler cannot
+	// deliver update notifications
+	capabilities := session.InitializeResult().Capabilities
+	require.NotNil(t, capabilities.Resources)
+	assert.False(t, capabilities.Resources.Subscribe)
+
+	listed, err := session.ListResources(ctx, nil)
+	require.NoError(t, err)
+	uris := make([]string, 0, len(listed.Resources))
+	for _, resource := range listed.Resources {
+		uris = append(uris, resource.URI)
+	}
+	assert.ElementsMatch(t, []string{tools.ConfigYAMLResourceURI, tools.ConfigJSONResourceURI}, uris)
+
+	// No config has been received yet
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tools.ConfigYAMLResourceURI})
+	require.Error(t, err)
+
+	require.Error(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: tools.ConfigYAMLResourceURI}))
+	require.NoError(t, ext.NotifyConfig(ctx, confmap.NewFromStringMap(map[string]any{
+		"receivers": map[string]any{
+			"otlp": map[string]any{"protocols": map[string]any{"grpc": nil}},
+		},
+	})))
+
+	yamlResult, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tools.ConfigYAMLResourceURI})
+	require.NoError(t, err)
+	require.Len(t, yamlResult.Contents, 1)
+	assert.Equal(t, "application/yaml", yamlResult.Contents[0].MIMEType)
+	assert.Contains(t, yamlResult.Contents[0].Text, "receivers:\n    otlp:")
+
+	jsonResult, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tools.ConfigJSONResourceURI})
+	require.NoError(t, err)
+	require.Len(t, jsonResult.Contents, 1)
+	assert.Equal(t, "application/json", jsonResult.Contents[0].MIMEType)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(jsonResult.Contents[0].Text), &decoded))
+	assert.Contains(t, decoded["receivers"], "otlp")
+
+	// Disabling get_config also hides the resources
+	cfg := createDefaultConfig().(*Config)
+	cfg.DisabledTools = []string{"get_config"}
+	hidden := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	hidden.server = newServer()
+	require.NoError(t, hidden.registerTools())
+
+	hct, hst := mcp.NewInMemoryTransports()
+	_, err = hidden.server.Connect(ctx, hst, nil)
+	require.NoError(t, err)
+	hiddenSession, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil).Connect(ctx, hct, nil)
+	require.NoError(t, err)
+	defer hiddenSession.Close()
+
+	_, err = hiddenSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: tools.ConfigYAMLResourceURI})
+	assert.Error(t, err)
+}
+
+func TestShutdownCancelsInFlightToolCalls(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	// A scan that only stops when its context is cancelled
+	scanning := make(chan struct{})
+	var scanErr error
+	var scanReturned bool
+	mcp.AddTool(ext.server, &mcp.Tool{Name: "long_scan"}, func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, struct{}, error) {
+		close(scanning)
+		for ctx.Err() == nil {
+			time.Sleep(time.Millisecond)
+		}
+		// Shutdown must wait for the handler to finish cleaning up
+		time.Sleep(50 * time.Millisecond)
+		scanErr = ctx.Err()
+		scanReturned = true
+		return nil, struct{}{}, scanErr
+	})
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	_, err := ext.server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	go func() {
+		_, _ = session.CallTool(ctx, &mcp.CallToolParams{Name: "long_scan", Arguments: map[string]any{}})
+	}()
+	select {
+	case <-scanning:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool call did not start")
+	}
+
+	start := time.Now()
+	require.NoError(t, ext.Shutdown(ctx))
+	assert.Less(t, time.Since(start), shutdownDrainTimeout)
+	assert.True(t, scanReturned, "Shutdown returned before the in-flight tool call finished")
+	assert.ErrorIs(t, scanErr, context.Canceled)
+
+	// New tool calls are rejected once Shutdown started
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "get_telemetry_summary", Arguments: map[string]any{}})
+	if err == nil {
+		assert.True(t, result.IsError, "tool call after Shutdown should fail")
+	}
+}
+
+func TestReplayDir(t *testing.T) {
+	newConfig := func(dir string) *Config {
+		cfg := createDefaultConfig().(*Config)
+		cfg.Endpoint = getAvailableLocalAddress(t)
+		cfg.TracesBufferSize = 10
+		cfg.MetricsBufferSize = 10
+		cfg.LogsBufferSize = 10
+		cfg.ReplayDir = dir
+		return cfg
+	}
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("GET /cart")
+	traceJSON, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("cart loaded")
+	logJSON, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("queue.size")
+	metricProto, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	require.NoError(t, err)
+
+	t.Run("mixed_files", func(t *testing.T) {
+		dir := t.TempDir()
+		// One JSON Lines file holding two signals, a single-document JSON file
+		// and an export_buffer proto dump
+		mixed := append(append(append(traceJSON, '\n'), logJSON...), '\n')
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "capture.jsonl"), mixed, 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "single.json"), traceJSON, 0o600))
+		dump := binary.BigEndian.AppendUint32(nil, uint32(len(metricProto)))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "metrics.pb"), append(dump, metricProto...), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("not telemetry"), 0o600))
+
+		ext := newMCPExtension(newConfig(dir), extensiontest.NewNopSettings(component.MustNewType("mcp")))
+		require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+		t.Cleanup(func() { require.NoError(t, ext.Shutdown(context.Background())) })
+
+		stats := ext.GetStats()
+		assert.Equal(t, 2, stats.TracesCount)
+		assert.Equal(t, 1, stats.MetricsCount)
+		assert.Equal(t, 1, stats.LogsCount)
+		assert.Equal(t, "queue.size", ext.GetRecentMetrics(1, 0)[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+	})
+
+	t.Run("invalid_file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"unknown": []}`), 0o600))
+
+		ext := newMCPExtension(newConfig(dir), extensiontest.NewNopSettings(component.MustNewType("mcp")))
+		err := ext.Start(context.Background(), componenttest.NewNopHost())
+		require.ErrorContains(t, err, "broken.json")
+	})
+
+	t.Run("missing_dir", func(t *testing.T) {
+		ext := newMCPExtension(newConfig(filepath.Join(t.TempDir(), "missing")), extensiontest.NewNopSettings(component.MustNewType("mcp")))
+		require.Error(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	})
+}
+
+func TestConfigValidateTraceCache(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TraceCache = &TraceCacheConfig{TTL: time.Minute, MaxTraces: 1000}
+	require.NoError(t, cfg.Validate())
+
+	cfg.TraceCache = &TraceCacheConfig{MaxTraces: 1000}
+	require.ErrorIs(t, cfg.Validate(), errInvalidTraceCache)
+
+	cfg.TraceCache = &TraceCacheConfig{TTL: time.Minute}
+	require.ErrorIs(t, cfg.Validate(), errInvalidTraceCache)
+}
+
+func TestMCPExtensionTraceCache(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 1
+	cfg.MetricsBufferSize = 1
+	cfg.LogsBufferSize = 1
+	cfg.TraceCache = &TraceCacheConfig{TTL: time.Minute, MaxTraces: 100}
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ext.Shutdown(context.Background())) })
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	addSpan := func(spanID byte, name string) {
+		td := ptrace.NewTraces()
+		span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(pcommon.SpanID([8]byte{spanID}))
+		span.SetName(name)
+		ext.AddTraces(td)
+	}
+	addSpan(1, "GET /checkout")
+	// Evicts the first batch from the one-batch buffer
+	addSpan(2, "charge card")
+	require.Len(t, ext.GetRecentTraces(10, 0), 1)
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	_, err := ext.server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	getTrace := func() tools.GetTraceByIDOutput {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "get_trace_by_id",
+			Arguments: map[string]any{"trace_id": traceID.String()},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		raw, err := json.Marshal(result.StructuredContent)
+		require.NoError(t, err)
+		var out tools.GetTraceByIDOutput
+		require.NoError(t, json.Unmarshal(raw, &out))
+		return out
+	}
+
+	out := getTrace()
+	assert.True(t, out.FromCache)
+	assert.Equal(t, 2, out.SpanCount)
+	assert.Contains(t, out.Markdown, "GET /checkout")
+	assert.Contains(t, out.Markdown, "charge card")
+
+	// Evicting the trace also removes it from the cache, whatever the case of
+	// the ID
+	ext.EvictTrace(strings.ToUpper(traceID.String()))
+	_, ok := ext.GetCachedTrace(traceID.String())
+	assert.False(t, ok)
+}
+
+func TestMCPExtensionTraceCacheTracesDisabled(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 0
+	cfg.TraceCache = &TraceCacheConfig{TTL: time.Minute, MaxTraces: 100}
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	require.NotNil(t, ext)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(traceID)
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	ext.AddTraces(td)
+
+	// Disabling trace buffering keeps spans out of the cache as well
+	_, ok := ext.GetCachedTrace(traceID.String())
+	assert.False(t, ok)
+}
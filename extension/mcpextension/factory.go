@@ -5,6 +5,7 @@ package mcpextension
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/extension"
@@ -15,6 +16,17 @@ const (
 	stability         = component.StabilityLevelDevelopment
 	defaultBufferSize = 1000
 	defaultEndpoint   = "localhost:9999"
+	defaultPath       = "/mcp"
+	defaultQueryLimit = 1000
+	defaultTraceSpans = 10000
+
+	defaultQueryToolLimit  = 100
+	defaultRecentToolLimit = 10
+	defaultQueryTimeout    = 30 * time.Second
+
+	defaultEvictionPolicy = "drop_oldest"
+	defaultDropSamples    = 10
+	defaultTimezone       = "UTC"
 )
 
 // NewFactory creates a factory for the MCP extension
@@ -29,10 +41,20 @@ func NewFactory() extension.Factory {
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		Endpoint:          defaultEndpoint,
-		TracesBufferSize:  defaultBufferSize,
-		MetricsBufferSize: defaultBufferSize,
-		LogsBufferSize:    defaultBufferSize,
+		Endpoint:            defaultEndpoint,
+		Path:                defaultPath,
+		TracesBufferSize:    defaultBufferSize,
+		MetricsBufferSize:   defaultBufferSize,
+		LogsBufferSize:      defaultBufferSize,
+		MaxQueryLimit:       defaultQueryLimit,
+		MaxTraceSpans:       defaultTraceSpans,
+		DefaultQueryLimit:   defaultQueryToolLimit,
+		DefaultRecentLimit:  defaultRecentToolLimit,
+		QueryTimeout:        defaultQueryTimeout,
+		EvictionPolicy:      defaultEvictionPolicy,
+		DroppedBatchSamples: defaultDropSamples,
+		Timezone:            defaultTimezone,
+		RedactAttributes:    []string{"authorization", "cookie", "set-cookie", "password"},
 	}
 }
 
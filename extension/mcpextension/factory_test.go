@@ -30,9 +30,14 @@ func TestCreateDefaultConfig(t *testing.T) {
 	require.True(t, ok)
 
 	assert.Equal(t, "localhost:9999", mcpCfg.Endpoint)
+	assert.Equal(t, "/mcp", mcpCfg.Path)
 	assert.Equal(t, 1000, mcpCfg.TracesBufferSize)
 	assert.Equal(t, 1000, mcpCfg.MetricsBufferSize)
 	assert.Equal(t, 1000, mcpCfg.LogsBufferSize)
+	assert.Equal(t, 1000, mcpCfg.MaxQueryLimit)
+	assert.Equal(t, 100, mcpCfg.DefaultQueryLimit)
+	assert.Equal(t, 10, mcpCfg.DefaultRecentLimit)
+	assert.Equal(t, "drop_oldest", mcpCfg.EvictionPolicy)
 
 	// Verify config validation passes
 	require.NoError(t, componenttest.CheckConfigStruct(cfg))
@@ -61,12 +66,11 @@ func TestCreateExtension(t *testing.T) {
 }
 
 func TestCreateExtensionWithCustomConfig(t *testing.T) {
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  100,
-		MetricsBufferSize: 200,
-		LogsBufferSize:    300,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 100
+	cfg.MetricsBufferSize = 200
+	cfg.LogsBufferSize = 300
 
 	ext, err := createExtension(
 		context.Background(),
@@ -225,12 +225,11 @@ func TestConcurrentBufferOperations(t *testing.T) {
 func TestConcurrentHTTPSessions(t *testing.T) {
 	ctx := context.Background()
 
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 
 	require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
@@ -286,3 +285,60 @@ func TestConcurrentHTTPSessions(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// factoryHost is a host providing the ComponentFactory capability
+type factoryHost struct {
+	component.Host
+}
+
+func (factoryHost) GetFactory(component.Kind, component.Type) component.Factory {
+	return nil
+}
+
+func TestConcurrentComponentFactoryAccess(t *testing.T) {
+	ctx := context.Background()
+	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+
+	// Serve the factory tool from a separate server so calls can run while Start
+	// stores the host capabilities
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
+	tools.RegisterGetFactoryInfo(server, ext)
+
+	_, err := server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				_, _ = session.CallTool(ctx, &mcp.CallToolParams{
+					Name: "get_factory_info",
+					Arguments: map[string]any{
+						"kind":           "receiver",
+						"component_type": "otlp",
+					},
+				})
+			}
+		}()
+	}
+
+	require.NoError(t, ext.Start(ctx, factoryHost{Host: componenttest.NewNopHost()}))
+	t.Cleanup(func() { require.NoError(t, ext.Shutdown(ctx)) })
+
+	wg.Wait()
+	assert.NotNil(t, ext.GetComponentFactory())
+}
@@ -5,6 +5,7 @@ package mcpextension
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"runtime"
 	"testing"
@@ -23,12 +24,11 @@ func TestMCPHTTPEndpoint(t *testing.T) {
 	ctx := context.Background()
 
 	// Create extension with dynamic port
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 
 	require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
@@ -75,12 +75,11 @@ func TestMCPHTTPEndpoint(t *testing.T) {
 func TestMCPHTTPEndpointWithConfig(t *testing.T) {
 	ctx := context.Background()
 
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 
 	require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
@@ -135,12 +134,11 @@ func TestMCPHTTPEndpointWithConfig(t *testing.T) {
 func TestMCPHTTPMultipleClients(t *testing.T) {
 	ctx := context.Background()
 
-	cfg := &Config{
-		Endpoint:          getAvailableLocalAddress(t),
-		TracesBufferSize:  10,
-		MetricsBufferSize: 10,
-		LogsBufferSize:    10,
-	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
 	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
 
 	require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
@@ -168,3 +166,142 @@ func TestMCPHTTPMultipleClients(t *testing.T) {
 		session.Close()
 	}
 }
+
+func TestMCPHTTPCustomPath(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.Path = "/otel/collector/mcp"
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
+	require.NoError(t, cfg.Validate())
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+
+	require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ext.Shutdown(ctx)) })
+
+	runtime.Gosched()
+	time.Sleep(100 * time.Millisecond)
+
+	transport := &mcp.StreamableClientTransport{
+		Endpoint:   "http://" + cfg.Endpoint + cfg.Path,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, transport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	require.NoError(t, session.Ping(ctx, nil))
+
+	// The default path is no longer served
+	resp, err := http.Post("http://"+cfg.Endpoint+"/mcp", "application/json", http.NoBody)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestConfigValidatePath(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Path = "mcp"
+	require.ErrorIs(t, cfg.Validate(), errInvalidPath)
+
+	cfg.Path = ""
+	require.ErrorIs(t, cfg.Validate(), errInvalidPath)
+
+	// Patterns http.ServeMux.Handle would panic on at Start
+	for _, path := range []string{"/mcp/{id", "/mcp path", "/{a}/{a}", "/mcp/{bad-name}"} {
+		cfg.Path = path
+		require.ErrorIs(t, cfg.Validate(), errInvalidPath, path)
+	}
+}
+
+func TestMCPHTTPAdditionalEndpoints(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.AdditionalEndpoints = []string{getAvailableLocalAddress(t)}
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
+	require.NoError(t, cfg.Validate())
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+
+	require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
+
+	runtime.Gosched()
+	time.Sleep(100 * time.Millisecond)
+
+	// Both endpoints serve the same MCP server
+	for _, endpoint := range []string{cfg.Endpoint, cfg.AdditionalEndpoints[0]} {
+		transport := &mcp.StreamableClientTransport{
+			Endpoint:   "http://" + endpoint + cfg.Path,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		}
+
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+		session, err := client.Connect(ctx, transport, nil)
+		require.NoError(t, err, "endpoint %s", endpoint)
+		require.NoError(t, session.Ping(ctx, nil))
+		session.Close()
+	}
+
+	// Shutdown releases every endpoint
+	require.NoError(t, ext.Shutdown(ctx))
+	for _, endpoint := range []string{cfg.Endpoint, cfg.AdditionalEndpoints[0]} {
+		ln, err := net.Listen("tcp", endpoint)
+		require.NoError(t, err, "endpoint %s still bound", endpoint)
+		require.NoError(t, ln.Close())
+	}
+}
+
+func TestMCPHTTPAdditionalEndpointBindFailure(t *testing.T) {
+	ctx := context.Background()
+
+	// Occupy the additional endpoint so binding it fails
+	occupied, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer occupied.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = getAvailableLocalAddress(t)
+	cfg.AdditionalEndpoints = []string{occupied.Addr().String()}
+	cfg.TracesBufferSize = 10
+	cfg.MetricsBufferSize = 10
+	cfg.LogsBufferSize = 10
+	require.NoError(t, cfg.Validate())
+
+	ext := newMCPExtension(cfg, extensiontest.NewNopSettings(component.MustNewType("mcp")))
+	err = ext.Start(ctx, componenttest.NewNopHost())
+	require.ErrorContains(t, err, occupied.Addr().String())
+
+	// The primary endpoint bound before the failure is released again
+	ln, err := net.Listen("tcp", cfg.Endpoint)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+	require.NoError(t, ext.Shutdown(ctx))
+}
+
+func TestConfigValidateAdditionalEndpoints(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+
+	cfg.AdditionalEndpoints = []string{"localhost:9998"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.AdditionalEndpoints = []string{cfg.Endpoint}
+	require.ErrorIs(t, cfg.Validate(), errInvalidEndpoint)
+
+	cfg.AdditionalEndpoints = []string{"localhost:9998", "localhost:9998"}
+	require.ErrorIs(t, cfg.Validate(), errInvalidEndpoint)
+
+	cfg.AdditionalEndpoints = []string{""}
+	require.ErrorIs(t, cfg.Validate(), errInvalidEndpoint)
+}
@@ -5,20 +5,34 @@ package mcpextension
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/service"
 	"go.opentelemetry.io/collector/service/hostcapabilities"
 	"go.uber.org/zap"
+	"go.yaml.in/yaml/v3"
 
 	"github.com/pavolloffay/otel-mcp/internal/tools"
 )
@@ -30,11 +44,28 @@ type mockExtensionContext struct {
 	moduleInfos      *service.ModuleInfos
 	componentFactory hostcapabilities.ComponentFactory
 	bufferStats      tools.BufferStats
+	readiness        tools.Readiness
+	maxQueryLimit    int
+	maxResponseBytes int
+	maxTraceSpans    int
+	defaultQuery     int
+	defaultRecent    int
+	location         *time.Location
+	redactKeys       []string
+	redactDelay      time.Duration // slows each redaction check to simulate a long scan
+	dumpDir          string
 	recentTraces     []ptrace.Traces
 	recentMetrics    []pmetric.Metrics
 	recentLogs       []plog.Logs
-	logger           *zap.Logger
-	host             component.Host
+	cachedTraces     map[string]ptrace.Traces
+	// recentTracesCalls counts the GetRecentTraces calls
+	recentTracesCalls atomic.Int64
+	batchInfos        map[string][]tools.BatchInfo
+	signalDrops       map[string]tools.SignalDrops
+	toolGroups        map[string]string
+	registeredTools   []*mcp.Tool
+	logger            *zap.Logger
+	host              component.Host
 }
 
 func (m *mockExtensionContext) GetCollectorConf() *confmap.Conf {
@@ -55,6 +86,15 @@ func (m *mockExtensionContext) GetBufferStats() tools.BufferStats {
 	return m.bufferStats
 }
 
+func (m *mockExtensionContext) GetReadiness() tools.Readiness {
+	return m.readiness
+}
+
+func (m *mockExtensionContext) GetCachedTrace(traceID string) (ptrace.Traces, bool) {
+	td, ok := m.cachedTraces[traceID]
+	return td, ok
+}
+
 func (m *mockExtensionContext) GetModuleInfos() *service.ModuleInfos {
 	return m.moduleInfos
 }
@@ -63,7 +103,79 @@ func (m *mockExtensionContext) GetComponentFactory() hostcapabilities.ComponentF
 	return m.componentFactory
 }
 
+func (m *mockExtensionContext) GetMaxQueryLimit() int {
+	return m.maxQueryLimit
+}
+
+func (m *mockExtensionContext) GetMaxResponseBytes() int {
+	return m.maxResponseBytes
+}
+
+func (m *mockExtensionContext) GetMaxTraceSpans() int {
+	return m.maxTraceSpans
+}
+
+func (m *mockExtensionContext) GetDefaultQueryLimit() int {
+	return m.defaultQuery
+}
+
+func (m *mockExtensionContext) GetDefaultRecentLimit() int {
+	return m.defaultRecent
+}
+
+func (m *mockExtensionContext) GetLocation() *time.Location {
+	if m.location == nil {
+		return time.UTC
+	}
+	return m.location
+}
+
+func (m *mockExtensionContext) GetDumpDir() string {
+	return m.dumpDir
+}
+
+func (m *mockExtensionContext) IsRedactedAttribute(key string) bool {
+	time.Sleep(m.redactDelay)
+	for _, pattern := range m.redactKeys {
+		if strings.Contains(strings.ToLower(key), pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockExtensionContext) GetToolGroups() map[string]string {
+	return m.toolGroups
+}
+
+func (m *mockExtensionContext) GetRegisteredTools() []*mcp.Tool {
+	return m.registeredTools
+}
+
+func (m *mockExtensionContext) GetBatchInfos(signal string, limit, offset int) []tools.BatchInfo {
+	infos := m.batchInfos[signal]
+	if offset >= len(infos) {
+		return nil
+	}
+	return infos[offset:min(offset+limit, len(infos))]
+}
+
+func (m *mockExtensionContext) GetBatchTimes(signal string, limit, offset int) []tools.BatchInfo {
+	infos := m.GetBatchInfos(signal, limit, offset)
+	result := make([]tools.BatchInfo, len(infos))
+	for i, info := range infos {
+		info.SizeBytes = 0
+		result[i] = info
+	}
+	return result
+}
+
+func (m *mockExtensionContext) GetSignalDrops(signal string) tools.SignalDrops {
+	return m.signalDrops[signal]
+}
+
 func (m *mockExtensionContext) GetRecentTraces(limit, offset int) []ptrace.Traces {
+	m.recentTracesCalls.Add(1)
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	if offset >= len(m.recentTraces) {
@@ -100,6 +212,45 @@ func (m *mockExtensionContext) GetRecentLogs(limit, offset int) []plog.Logs {
 	return m.recentLogs[offset:end]
 }
 
+// GetLogBatches numbers the mock log batches by their position
+func (m *mockExtensionContext) GetLogBatches(limit, offset int) []tools.LogBatch {
+	logs := m.GetRecentLogs(limit, offset)
+	batches := make([]tools.LogBatch, len(logs))
+	for i, ld := range logs {
+		batches[i] = tools.LogBatch{Seq: uint64(offset + i), Logs: ld}
+	}
+	return batches
+}
+
+// EvictTrace drops whole batches containing the trace; partial batch rewrites
+// are covered by the buffer tests
+func (m *mockExtensionContext) EvictTrace(traceID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed := 0
+	kept := m.recentTraces[:0]
+	for _, td := range m.recentTraces {
+		matched := 0
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			rs := td.ResourceSpans().At(i)
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				spans := rs.ScopeSpans().At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if spans.At(k).TraceID().String() == traceID {
+						matched++
+					}
+				}
+			}
+		}
+		if matched == 0 {
+			kept = append(kept, td)
+		}
+		removed += matched
+	}
+	m.recentTraces = kept
+	return removed
+}
+
 // Helper methods for thread-safe writes in concurrent tests
 func (m *mockExtensionContext) SetConf(conf *confmap.Conf) {
 	m.mu.Lock()
@@ -113,6 +264,22 @@ func (m *mockExtensionContext) AddTrace(td ptrace.Traces) {
 	m.recentTraces = append(m.recentTraces, td)
 }
 
+func (m *mockExtensionContext) AddTraces(td ptrace.Traces) {
+	m.AddTrace(td)
+}
+
+func (m *mockExtensionContext) AddMetrics(md pmetric.Metrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recentMetrics = append(m.recentMetrics, md)
+}
+
+func (m *mockExtensionContext) AddLogs(ld plog.Logs) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recentLogs = append(m.recentLogs, ld)
+}
+
 func newMockExtensionContext() *mockExtensionContext {
 	logger, _ := zap.NewDevelopment()
 	return &mockExtensionContext{
@@ -133,6 +300,14 @@ func newMockExtensionContext() *mockExtensionContext {
 				"debug": map[string]any{},
 			},
 			"service": map[string]any{
+				"telemetry": map[string]any{
+					"logs": map[string]any{
+						"level": "debug",
+					},
+					"metrics": map[string]any{
+						"level": "detailed",
+					},
+				},
 				"pipelines": map[string]any{
 					"traces": map[string]any{
 						"receivers":  []any{"otlp"},
@@ -153,6 +328,8 @@ func newMockExtensionContext() *mockExtensionContext {
 				component.MustNewType("debug"): {BuilderRef: "go.opentelemetry.io/collector/exporter/debugexporter v0.136.0"},
 			},
 		},
+		defaultQuery:  100,
+		defaultRecent: 10,
 		bufferStats: tools.BufferStats{
 			TracesCount:     5,
 			TracesCapacity:  100,
@@ -165,6 +342,50 @@ func newMockExtensionContext() *mockExtensionContext {
 	}
 }
 
+// connectTestClient registers tools on a new MCP server backed by ext and
+// returns a client session connected to it over in-memory transports
+func connectTestClient(t *testing.T, ext tools.ExtensionContext, register ...func(*mcp.Server, tools.ExtensionContext)) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
+	for _, r := range register {
+		r(server, ext)
+	}
+	_, err := server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// callTool calls a tool with args
+func callTool(session *mcp.ClientSession, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	return session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      name,
+		Arguments: args,
+	})
+}
+
+// callToolOutput calls a tool and decodes its structured output into T
+func callToolOutput[T any](t *testing.T, session *mcp.ClientSession, name string, args map[string]any) T {
+	t.Helper()
+	result, err := callTool(session, name, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, "tool %s returned error: %v", name, result.Content)
+
+	raw, err := json.Marshal(result.StructuredContent)
+	require.NoError(t, err)
+
+	var out T
+	require.NoError(t, json.Unmarshal(raw, &out))
+	return out
+}
+
 func TestMCPToolsWithInMemoryTransport(t *testing.T) {
 	ctx := context.Background()
 	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
@@ -183,6 +404,8 @@ func TestMCPToolsWithInMemoryTransport(t *testing.T) {
 	tools.RegisterListAvailableComponents(server, mockCtx)
 	tools.RegisterGetComponentSchema(server, mockCtx)
 	tools.RegisterGetFactoryInfo(server, mockCtx)
+	tools.RegisterInspectComponent(server, mockCtx)
+	tools.RegisterGetCollectorInfo(server, mockCtx)
 	tools.RegisterGetRecentTraces(server, mockCtx)
 	tools.RegisterGetRecentMetrics(server, mockCtx)
 	tools.RegisterGetRecentLogs(server, mockCtx)
@@ -217,29 +440,20 @@ func TestMCPToolsWithInMemoryTransport(t *testing.T) {
 	})
 
 	t.Run("get_config_full", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "get_config",
-			Arguments: map[string]any{},
-		})
+		result, err := callTool(session, "get_config", map[string]any{})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 		assert.NotEmpty(t, result.Content)
 	})
 
 	t.Run("get_config_section", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "get_config",
-			Arguments: map[string]any{"section": "receivers"},
-		})
+		result, err := callTool(session, "get_config", map[string]any{"section": "receivers"})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("get_config_invalid_section", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "get_config",
-			Arguments: map[string]any{"section": "invalid"},
-		})
+		result, err := callTool(session, "get_config", map[string]any{"section": "invalid"})
 
 		// Should return error (either protocol error or IsError=true)
 		if err == nil {
@@ -248,92 +462,118 @@ func TestMCPToolsWithInMemoryTransport(t *testing.T) {
 	})
 
 	t.Run("list_configured_components", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "list_configured_components",
-			Arguments: map[string]any{},
-		})
+		result, err := callTool(session, "list_configured_components", map[string]any{})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("list_configured_components_filtered", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "list_configured_components",
-			Arguments: map[string]any{"kind": "receiver"},
-		})
+		result, err := callTool(session, "list_configured_components", map[string]any{"kind": "receiver"})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("get_component_config", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name: "get_component_config",
-			Arguments: map[string]any{
-				"component_id": "otlp",
-				"kind":         "receiver",
-			},
+		result, err := callTool(session, "get_component_config", map[string]any{
+			"component_id": "otlp",
+			"kind":         "receiver",
 		})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
+	t.Run("get_component_config_service_telemetry", func(t *testing.T) {
+		out := callToolOutput[map[string]any](t, session, "get_component_config", map[string]any{
+			"component_id": "telemetry",
+			"kind":         "service",
+		})
+		assert.Contains(t, out, "logs")
+		assert.Contains(t, out, "metrics")
+
+		logs := callToolOutput[map[string]any](t, session, "get_component_config", map[string]any{
+			"component_id": "telemetry::logs",
+			"kind":         "service",
+		})
+		assert.Equal(t, "debug", logs["level"])
+	})
+
+	t.Run("get_component_config_service_missing", func(t *testing.T) {
+		result, err := callTool(session, "get_component_config", map[string]any{
+			"component_id": "telemetry::traces",
+			"kind":         "service",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+
 	t.Run("get_pipeline_config", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name: "get_pipeline_config",
-			Arguments: map[string]any{
-				"pipeline_id": "traces",
-			},
+		result, err := callTool(session, "get_pipeline_config", map[string]any{
+			"pipeline_id": "traces",
 		})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("list_available_components", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name: "list_available_components",
-			Arguments: map[string]any{
-				"kind": "",
-			},
+		result, err := callTool(session, "list_available_components", map[string]any{
+			"kind": "",
 		})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("get_telemetry_summary", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "get_telemetry_summary",
-			Arguments: map[string]any{},
-		})
+		result, err := callTool(session, "get_telemetry_summary", map[string]any{})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
+
+	t.Run("inspect_component_partial", func(t *testing.T) {
+		// Mock provides module info but no component factory
+		out := callToolOutput[tools.InspectComponentOutput](t, session, "inspect_component", map[string]any{
+			"kind":         "receiver",
+			"component_id": "otlp",
+		})
+		assert.Equal(t, "otlp", out.ComponentType)
+		assert.Contains(t, out.Config, "protocols")
+		require.NotNil(t, out.FactoryInfo)
+		assert.Contains(t, out.FactoryInfo.Version, "otlpreceiver")
+		require.NotNil(t, out.Status)
+		assert.Equal(t, "running", out.Status.Status)
+		assert.Nil(t, out.Schema)
+		assert.Contains(t, out.Unavailable, "schema")
+	})
+
+	t.Run("inspect_component_not_configured", func(t *testing.T) {
+		out := callToolOutput[tools.InspectComponentOutput](t, session, "inspect_component", map[string]any{
+			"kind":         "exporter",
+			"component_id": "otlp/missing",
+		})
+		assert.Equal(t, "otlp", out.ComponentType)
+		assert.Nil(t, out.Config)
+		assert.Contains(t, out.Unavailable, "config")
+		assert.Contains(t, out.Unavailable, "status")
+	})
+
+	t.Run("get_collector_info", func(t *testing.T) {
+		out := callToolOutput[tools.GetCollectorInfoOutput](t, session, "get_collector_info", map[string]any{})
+		assert.Equal(t, runtime.Version(), out.GoVersion)
+		assert.Equal(t, runtime.GOOS, out.OS)
+		assert.Equal(t, runtime.GOARCH, out.Arch)
+		require.NotNil(t, out.Components)
+		assert.Equal(t, tools.ComponentCounts{Receivers: 1, Processors: 1, Exporters: 1}, *out.Components)
+	})
 }
 
 func TestMCPToolsWithoutConfig(t *testing.T) {
-	ctx := context.Background()
-	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
-
 	// Create mock context without config
 	mockCtx := newMockExtensionContext()
 	mockCtx.conf = nil
 
-	// Create MCP server
-	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
-	tools.RegisterGetConfig(server, mockCtx)
-
-	_, err := server.Connect(ctx, st, nil)
-	require.NoError(t, err)
-
-	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
-	session, err := client.Connect(ctx, ct, nil)
-	require.NoError(t, err)
-	defer session.Close()
+	session := connectTestClient(t, mockCtx, tools.RegisterGetConfig)
 
 	t.Run("get_config_without_config", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "get_config",
-			Arguments: map[string]any{},
-		})
+		result, err := callTool(session, "get_config", map[string]any{})
 
 		// Should error or return IsError=true
 		if err == nil {
@@ -343,32 +583,16 @@ func TestMCPToolsWithoutConfig(t *testing.T) {
 }
 
 func TestMCPToolsWithoutHostCapabilities(t *testing.T) {
-	ctx := context.Background()
-	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
-
 	// Create mock context without host capabilities
 	mockCtx := newMockExtensionContext()
 	mockCtx.moduleInfos = nil
 	mockCtx.componentFactory = nil
 
-	// Create MCP server
-	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
-	tools.RegisterListAvailableComponents(server, mockCtx)
-
-	_, err := server.Connect(ctx, st, nil)
-	require.NoError(t, err)
-
-	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
-	session, err := client.Connect(ctx, ct, nil)
-	require.NoError(t, err)
-	defer session.Close()
+	session := connectTestClient(t, mockCtx, tools.RegisterListAvailableComponents, tools.RegisterGetCollectorInfo)
 
 	t.Run("list_available_components_without_module_info", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name: "list_available_components",
-			Arguments: map[string]any{
-				"kind": "",
-			},
+		result, err := callTool(session, "list_available_components", map[string]any{
+			"kind": "",
 		})
 
 		// Should error when ModuleInfo capability is missing
@@ -376,12 +600,15 @@ func TestMCPToolsWithoutHostCapabilities(t *testing.T) {
 			assert.True(t, result.IsError, "should error when ModuleInfo not available")
 		}
 	})
+
+	t.Run("get_collector_info_without_module_info", func(t *testing.T) {
+		out := callToolOutput[tools.GetCollectorInfoOutput](t, session, "get_collector_info", map[string]any{})
+		assert.NotEmpty(t, out.GoVersion)
+		assert.Nil(t, out.Components)
+	})
 }
 
 func TestMCPTelemetryTools(t *testing.T) {
-	ctx := context.Background()
-	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
-
 	// Create mock context with telemetry
 	mockCtx := newMockExtensionContext()
 
@@ -406,60 +633,5101 @@ func TestMCPTelemetryTools(t *testing.T) {
 	rl.Resource().Attributes().PutStr("service.name", "test-service")
 	mockCtx.recentLogs = []plog.Logs{ld}
 
-	// Create MCP server
-	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
-	tools.RegisterGetRecentTraces(server, mockCtx)
-	tools.RegisterGetRecentMetrics(server, mockCtx)
-	tools.RegisterGetRecentLogs(server, mockCtx)
-	tools.RegisterGetTelemetrySummary(server, mockCtx)
-
-	_, err := server.Connect(ctx, st, nil)
-	require.NoError(t, err)
-
-	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
-	session, err := client.Connect(ctx, ct, nil)
-	require.NoError(t, err)
-	defer session.Close()
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterGetRecentTraces,
+		tools.RegisterGetRecentMetrics,
+		tools.RegisterGetRecentLogs,
+		tools.RegisterGetTelemetrySummary,
+	)
 
 	t.Run("get_recent_traces", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name: "get_recent_traces",
-			Arguments: map[string]any{
-				"limit": 10,
-			},
+		result, err := callTool(session, "get_recent_traces", map[string]any{
+			"limit": 10,
 		})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("get_recent_metrics", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name: "get_recent_metrics",
-			Arguments: map[string]any{
-				"limit": 10,
-			},
+		result, err := callTool(session, "get_recent_metrics", map[string]any{
+			"limit": 10,
 		})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("get_recent_logs", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name: "get_recent_logs",
-			Arguments: map[string]any{
-				"limit": 10,
-			},
+		result, err := callTool(session, "get_recent_logs", map[string]any{
+			"limit": 10,
 		})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
 
 	t.Run("get_telemetry_summary", func(t *testing.T) {
-		result, err := session.CallTool(ctx, &mcp.CallToolParams{
-			Name:      "get_telemetry_summary",
-			Arguments: map[string]any{},
-		})
+		result, err := callTool(session, "get_telemetry_summary", map[string]any{})
 		require.NoError(t, err)
 		assert.False(t, result.IsError)
 	})
+	t.Run("get_telemetry_summary_without_distributions", func(t *testing.T) {
+		out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{})
+		assert.Nil(t, out.LogsBySeverity)
+		assert.Nil(t, out.SpansByStatus)
+	})
+}
+
+func TestTelemetrySummaryReadiness(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTelemetrySummary)
+
+	t.Run("cold", func(t *testing.T) {
+		out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{})
+		assert.Equal(t, "cold", out.BufferStatus)
+		assert.False(t, out.ConfigReceived)
+		assert.Empty(t, out.FirstConfigAt)
+		assert.Empty(t, out.Traces.FirstReceivedAt)
+		assert.Empty(t, out.Metrics.FirstReceivedAt)
+		assert.Empty(t, out.Logs.FirstReceivedAt)
+	})
+
+	t.Run("warm", func(t *testing.T) {
+		configAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		metricsAt := configAt.Add(time.Second)
+		mockCtx.readiness = tools.Readiness{FirstConfigAt: configAt, FirstMetricsAt: metricsAt}
+
+		out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{})
+		assert.Equal(t, "warm", out.BufferStatus)
+		assert.True(t, out.ConfigReceived)
+		assert.Equal(t, "2025-01-02T03:04:05Z", out.FirstConfigAt)
+		assert.Equal(t, "2025-01-02T03:04:06Z", out.Metrics.FirstReceivedAt)
+		assert.Empty(t, out.Traces.FirstReceivedAt)
+		assert.Empty(t, out.Logs.FirstReceivedAt)
+	})
+}
+
+func TestTelemetrySummaryDistributions(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	ss.Spans().AppendEmpty().Status().SetCode(ptrace.StatusCodeOk)
+	ss.Spans().AppendEmpty().Status().SetCode(ptrace.StatusCodeError)
+	ss.Spans().AppendEmpty().Status().SetCode(ptrace.StatusCodeError)
+	ss.Spans().AppendEmpty()
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	sl.LogRecords().AppendEmpty().SetSeverityNumber(plog.SeverityNumberInfo)
+	sl.LogRecords().AppendEmpty().SetSeverityNumber(plog.SeverityNumberInfo2)
+	sl.LogRecords().AppendEmpty().SetSeverityNumber(plog.SeverityNumberError)
+	sl.LogRecords().AppendEmpty().SetSeverityNumber(plog.SeverityNumberFatal4)
+	sl.LogRecords().AppendEmpty().SetSeverityText("warn")
+	sl.LogRecords().AppendEmpty().SetSeverityText("Warning")
+	// Unknown severity texts must not each open their own bucket
+	sl.LogRecords().AppendEmpty().SetSeverityText("notice")
+	sl.LogRecords().AppendEmpty().SetSeverityText("x-custom")
+	sl.LogRecords().AppendEmpty()
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTelemetrySummary)
+
+	out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{
+		"include_distributions": true,
+	})
+
+	assert.Equal(t, map[string]int{"Ok": 1, "Error": 2, "Unset": 1}, out.SpansByStatus)
+	assert.Equal(t, map[string]int{"INFO": 2, "ERROR": 1, "FATAL": 1, "WARN": 2, "UNSPECIFIED": 3}, out.LogsBySeverity)
+}
+
+func TestQueryLogsAttributeTruncation(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	longValue := strings.Repeat("x", 100)
+	ld := plog.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Body().SetStr("hello")
+	lr.Attributes().PutStr("key", longValue)
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs)
+
+	t.Run("default", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{})
+		assert.NotContains(t, out.Markdown, longValue)
+		assert.Contains(t, out.Markdown, "...")
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"max_attr_length": 10,
+		})
+		assert.Contains(t, out.Markdown, "key="+strings.Repeat("x", 6)+"...")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"max_attr_length": 0,
+		})
+		assert.Contains(t, out.Markdown, "key="+longValue)
+	})
+}
+
+func TestQueryLogsPlainFormat(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	first := sl.LogRecords().AppendEmpty()
+	first.SetTimestamp(pcommon.Timestamp(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).UnixNano()))
+	first.SetSeverityText("ERROR")
+	first.Body().SetStr("connection refused\nretrying")
+	second := sl.LogRecords().AppendEmpty()
+	second.SetSeverityText("INFO")
+	second.Body().SetStr("connected")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs)
+
+	t.Run("bodies_only", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"format": "plain",
+		})
+		assert.Equal(t, 2, out.LogCount)
+		assert.Equal(t, "connection refused retrying\nconnected\n", out.Markdown)
+	})
+
+	t.Run("with_prefix", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"format":       "plain",
+			"plain_prefix": true,
+		})
+		assert.Contains(t, out.Markdown, "[ERROR] 2025-01-02T03:04:05Z: connection refused retrying\n")
+		assert.Contains(t, out.Markdown, "[INFO] ")
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		result, err := callTool(session, "query_logs", map[string]any{"format": "csv"})
+		if err == nil {
+			assert.True(t, result.IsError, "should return error for invalid format")
+		}
+	})
+}
+
+func TestValidateOTTL(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	session := connectTestClient(t, mockCtx, tools.RegisterValidateOTTL)
+
+	t.Run("valid_statement", func(t *testing.T) {
+		out := callToolOutput[tools.ValidateOTTLOutput](t, session, "validate_ottl", map[string]any{
+			"context":   "span",
+			"statement": `set(attributes["env"], "prod") where name == "GET /"`,
+		})
+		assert.True(t, out.Valid)
+		assert.Empty(t, out.Error)
+	})
+
+	t.Run("valid_condition", func(t *testing.T) {
+		out := callToolOutput[tools.ValidateOTTLOutput](t, session, "validate_ottl", map[string]any{
+			"context":   "log",
+			"statement": `severity_number < SEVERITY_NUMBER_WARN`,
+			"condition": true,
+		})
+		assert.True(t, out.Valid)
+	})
+
+	t.Run("syntax_error", func(t *testing.T) {
+		out := callToolOutput[tools.ValidateOTTLOutput](t, session, "validate_ottl", map[string]any{
+			"context":   "span",
+			"statement": `set(attributes["env"], "prod"`,
+		})
+		assert.False(t, out.Valid)
+		assert.NotEmpty(t, out.Error)
+		require.NotNil(t, out.Position)
+		assert.Equal(t, 1, out.Position.Line)
+	})
+
+	t.Run("unknown_function", func(t *testing.T) {
+		out := callToolOutput[tools.ValidateOTTLOutput](t, session, "validate_ottl", map[string]any{
+			"context":   "datapoint",
+			"statement": `not_a_function(attributes["x"])`,
+		})
+		assert.False(t, out.Valid)
+		assert.Contains(t, out.Error, "not_a_function")
+	})
+
+	t.Run("invalid_context", func(t *testing.T) {
+		result, err := callTool(session, "validate_ottl", map[string]any{
+			"context":   "profile",
+			"statement": `set(attributes["x"], 1)`,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestGetLogsForTrace(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	otherTraceID := pcommon.TraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	sl := rl.ScopeLogs().AppendEmpty()
+
+	addLog := func(body string, ts int64, sev plog.SeverityNumber, tid pcommon.TraceID) {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Body().SetStr(body)
+		lr.SetTimestamp(pcommon.Timestamp(ts))
+		lr.SetSeverityNumber(sev)
+		lr.SetSeverityText(sev.String())
+		lr.SetTraceID(tid)
+	}
+	addLog("second", 2000, plog.SeverityNumberError, traceID)
+	addLog("first", 1000, plog.SeverityNumberInfo, traceID)
+	addLog("unrelated", 1500, plog.SeverityNumberError, otherTraceID)
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetLogsForTrace)
+
+	t.Run("sorted_by_timestamp", func(t *testing.T) {
+		out := callToolOutput[tools.GetLogsForTraceOutput](t, session, "get_logs_for_trace", map[string]any{
+			"trace_id": traceID.String(),
+		})
+		assert.Equal(t, 2, out.LogCount)
+		assert.NotContains(t, out.Markdown, "unrelated")
+		assert.Less(t, strings.Index(out.Markdown, "first"), strings.Index(out.Markdown, "second"))
+		assert.Contains(t, out.Markdown, "checkout")
+	})
+
+	t.Run("severity_filter", func(t *testing.T) {
+		out := callToolOutput[tools.GetLogsForTraceOutput](t, session, "get_logs_for_trace", map[string]any{
+			"trace_id": traceID.String(),
+			"severity": "error",
+		})
+		assert.Equal(t, 1, out.LogCount)
+		assert.Contains(t, out.Markdown, "second")
+		assert.NotContains(t, out.Markdown, "first")
+	})
+
+	t.Run("no_matches", func(t *testing.T) {
+		out := callToolOutput[tools.GetLogsForTraceOutput](t, session, "get_logs_for_trace", map[string]any{
+			"trace_id": "ffffffffffffffffffffffffffffffff",
+		})
+		assert.Equal(t, 0, out.LogCount)
+	})
+}
+
+func TestQueryLimitBounds(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.maxQueryLimit = 2
+
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	for i := 0; i < 5; i++ {
+		sl.LogRecords().AppendEmpty().Body().SetStr("log")
+	}
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs, tools.RegisterGetRecentLogs)
+
+	t.Run("limit_clamped", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"limit": 1000000,
+		})
+		assert.Equal(t, 2, out.LogCount)
+	})
+
+	t.Run("default_limit_clamped", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{})
+		assert.Equal(t, 2, out.LogCount)
+	})
+
+	for _, tc := range []struct {
+		name string
+		tool string
+		args map[string]any
+		want string
+	}{
+		{"query_logs_negative_offset", "query_logs", map[string]any{"offset": -1}, tools.ErrInvalidOffset.Error()},
+		{"query_logs_negative_limit", "query_logs", map[string]any{"limit": -5}, tools.ErrInvalidLimit.Error()},
+		{"get_recent_logs_negative_offset", "get_recent_logs", map[string]any{"offset": -1}, tools.ErrInvalidOffset.Error()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := callTool(session, tc.tool, tc.args)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			require.NotEmpty(t, result.Content)
+			text, ok := result.Content[0].(*mcp.TextContent)
+			require.True(t, ok)
+			assert.Contains(t, text.Text, tc.want)
+		})
+	}
+}
+
+func TestConfiguredDefaultLimits(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.defaultQuery = 4
+	mockCtx.defaultRecent = 3
+
+	for i := 0; i < 5; i++ {
+		ld := plog.NewLogs()
+		lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.Body().SetStr("log")
+		lr.Attributes().PutInt("n", int64(i))
+		mockCtx.recentLogs = append(mockCtx.recentLogs, ld)
+	}
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterQueryLogs,
+		tools.RegisterGetRecentLogs,
+		tools.RegisterGetAttributeValues,
+	)
+
+	t.Run("query_default", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{})
+		assert.Equal(t, 4, out.LogCount)
+	})
+
+	t.Run("recent_default", func(t *testing.T) {
+		out := callToolOutput[tools.LogsOutput](t, session, "get_recent_logs", map[string]any{})
+		assert.Equal(t, 3, out.Count)
+	})
+
+	t.Run("explicit_limit_wins", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{"limit": 5})
+		assert.Equal(t, 5, out.LogCount)
+	})
+
+	t.Run("facet_default", func(t *testing.T) {
+		out := callToolOutput[tools.GetAttributeValuesOutput](t, session, "get_attribute_values", map[string]any{"signal": "logs", "key": "n"})
+		assert.Equal(t, 5, out.TotalDistinct)
+		assert.Len(t, out.Values, 4)
+	})
+}
+
+func TestQueryMetricsMultipleTypes(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("requests.total")
+	sum.SetEmptySum().DataPoints().AppendEmpty().SetIntValue(10)
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("queue.size")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(3)
+	hist := sm.Metrics().AppendEmpty()
+	hist.SetName("request.duration")
+	hist.SetEmptyHistogram().DataPoints().AppendEmpty().SetCount(4)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics)
+
+	t.Run("sum_and_gauge", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_type": "Sum, gauge",
+		})
+		assert.Equal(t, 2, out.MetricCount)
+		assert.Contains(t, out.Markdown, "requests.total")
+		assert.Contains(t, out.Markdown, "queue.size")
+		assert.NotContains(t, out.Markdown, "request.duration")
+	})
+
+	t.Run("single_type_case_insensitive", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_type": "histogram",
+		})
+		assert.Equal(t, 1, out.MetricCount)
+		assert.Contains(t, out.Markdown, "request.duration")
+	})
+}
+
+func TestQueryTracesAttributeProjection(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /orders")
+	span.Attributes().PutStr("http.method", "GET")
+	span.Attributes().PutInt("http.status_code", 200)
+	span.Attributes().PutStr("http.url", "http://shop/orders")
+	span.Attributes().PutStr("user.id", "42")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	keys := []any{"http.status_code", "http.method"}
+
+	t.Run("summary", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"attribute_keys":  keys,
+			"max_attr_length": 0,
+		})
+		assert.Contains(t, out.Markdown, "http.status_code=200 http.method=GET")
+		assert.NotContains(t, out.Markdown, "http.url")
+		assert.NotContains(t, out.Markdown, "user.id")
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"attribute_keys": keys,
+			"detailed":       true,
+		})
+		assert.Contains(t, out.Markdown, "| http.status_code | 200 |\n| http.method | GET |")
+		assert.NotContains(t, out.Markdown, "user.id")
+	})
+}
+
+func TestGetMetricsByResource(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	addResource := func(md pmetric.Metrics, service, host string, ts int64, value int64) {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", service)
+		rm.Resource().Attributes().PutStr("host.name", host)
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("queue.size")
+		dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.Timestamp(ts))
+		dp.SetIntValue(value)
+	}
+
+	first := pmetric.NewMetrics()
+	addResource(first, "checkout", "host-a", 1000, 5)
+	addResource(first, "cart", "host-a", 500, 1)
+	second := pmetric.NewMetrics()
+	addResource(second, "checkout", "host-b", 2000, 7)
+	mockCtx.recentMetrics = []pmetric.Metrics{first, second}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetMetricsByResource)
+
+	t.Run("default_service_name", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricsByResourceOutput](t, session, "get_metrics_by_resource", map[string]any{})
+		assert.Equal(t, "service.name", out.GroupBy)
+		require.Equal(t, 2, out.GroupCount)
+		assert.Equal(t, "cart", out.Groups[0].Value)
+		assert.Equal(t, "checkout", out.Groups[1].Value)
+
+		require.Len(t, out.Groups[1].Metrics, 1)
+		metric := out.Groups[1].Metrics[0]
+		assert.Equal(t, "queue.size", metric.Name)
+		assert.Equal(t, "7", metric.LatestValue)
+		assert.Equal(t, 2, metric.DataPointCount)
+	})
+
+	t.Run("custom_attribute", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricsByResourceOutput](t, session, "get_metrics_by_resource", map[string]any{
+			"group_by": "host.name",
+		})
+		require.Equal(t, 2, out.GroupCount)
+		assert.Equal(t, "host-a", out.Groups[0].Value)
+		assert.Equal(t, "5", out.Groups[0].Metrics[0].LatestValue)
+	})
+
+	t.Run("missing_attribute", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricsByResourceOutput](t, session, "get_metrics_by_resource", map[string]any{
+			"group_by": "k8s.pod.name",
+		})
+		require.Equal(t, 1, out.GroupCount)
+		assert.Equal(t, "unknown", out.Groups[0].Value)
+	})
+}
+
+func TestQueryLogsConnectorFilter(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	ld := plog.NewLogs()
+	for _, id := range []string{"mcp/prod", "mcp/staging"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("mcp.connector.id", id)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("from " + id)
+	}
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs)
+
+	out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+		"connector_id": "mcp/prod",
+	})
+	assert.Equal(t, 1, out.LogCount)
+	assert.Contains(t, out.Markdown, "from mcp/prod")
+	assert.NotContains(t, out.Markdown, "from mcp/staging")
+}
+
+func TestQueryResourceAttributesFilter(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	ld := plog.NewLogs()
+	md := pmetric.NewMetrics()
+	for _, env := range []string{"staging", "prod"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "checkout")
+		rl.Resource().Attributes().PutStr("deployment.environment", env)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("from " + env)
+
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "checkout")
+		rm.Resource().Attributes().PutStr("deployment.environment", env)
+		metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName("queue.size." + env)
+		metric.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	}
+	mockCtx.recentLogs = []plog.Logs{ld}
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs, tools.RegisterQueryMetrics)
+
+	t.Run("query_logs", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"resource_attributes": map[string]any{"deployment.environment": "staging"},
+		})
+		assert.Equal(t, 1, out.LogCount)
+		assert.Contains(t, out.Markdown, "from staging")
+		assert.NotContains(t, out.Markdown, "from prod")
+	})
+
+	t.Run("query_metrics", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"resource_attributes": map[string]any{"deployment.environment": "staging"},
+		})
+		assert.Equal(t, 1, out.MetricCount)
+		assert.Contains(t, out.Markdown, "queue.size.staging")
+		assert.NotContains(t, out.Markdown, "queue.size.prod")
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"resource_attributes": map[string]any{"deployment.environment": "staging", "service.name": "cart"},
+		})
+		assert.Equal(t, 0, out.LogCount)
+	})
+}
+
+func TestEvictTrace(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	traceID := pcommon.TraceID([16]byte{0xab, 0xcd})
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	for i := 0; i < 2; i++ {
+		ss.Spans().AppendEmpty().SetTraceID(traceID)
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterEvictTrace)
+
+	t.Run("invalid_trace_id", func(t *testing.T) {
+		result, err := callTool(session, "evict_trace", map[string]any{"trace_id": "abc"})
+		if err == nil {
+			assert.True(t, result.IsError, "should return error for invalid trace ID")
+		}
+	})
+
+	t.Run("evicts_spans", func(t *testing.T) {
+		out := callToolOutput[tools.EvictTraceOutput](t, session, "evict_trace", map[string]any{
+			"trace_id": strings.ToUpper(traceID.String()),
+		})
+		assert.Equal(t, traceID.String(), out.TraceID)
+		assert.Equal(t, 2, out.SpansRemoved)
+		assert.True(t, out.Evicted)
+		assert.Empty(t, mockCtx.GetRecentTraces(10, 0))
+	})
+
+	t.Run("already_evicted", func(t *testing.T) {
+		out := callToolOutput[tools.EvictTraceOutput](t, session, "evict_trace", map[string]any{
+			"trace_id": traceID.String(),
+		})
+		assert.Equal(t, 0, out.SpansRemoved)
+		assert.False(t, out.Evicted)
+	})
+}
+
+func TestListSpanNames(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	addSpans := func(service string, names ...string) {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		ss := rs.ScopeSpans().AppendEmpty()
+		for _, name := range names {
+			ss.Spans().AppendEmpty().SetName(name)
+		}
+	}
+	addSpans("checkout", "GET /cart", "GET /cart", "POST /order")
+	addSpans("cart", "GET /cart", "redis GET")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterListSpanNames)
+
+	t.Run("all", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanNamesOutput](t, session, "list_span_names", map[string]any{})
+		assert.Equal(t, 3, out.TotalNames)
+		require.Len(t, out.SpanNames, 3)
+		assert.Equal(t, tools.SpanNameFacet{Name: "GET /cart", Count: 3, Services: []string{"cart", "checkout"}}, out.SpanNames[0])
+		assert.Equal(t, "POST /order", out.SpanNames[1].Name)
+		assert.Equal(t, "redis GET", out.SpanNames[2].Name)
+	})
+
+	t.Run("service_filter", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanNamesOutput](t, session, "list_span_names", map[string]any{
+			"service_name": "cart",
+		})
+		require.Len(t, out.SpanNames, 2)
+		assert.Equal(t, tools.SpanNameFacet{Name: "GET /cart", Count: 1, Services: []string{"cart"}}, out.SpanNames[0])
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanNamesOutput](t, session, "list_span_names", map[string]any{
+			"limit": 1,
+		})
+		assert.Equal(t, 3, out.TotalNames)
+		require.Len(t, out.SpanNames, 1)
+		assert.Equal(t, "GET /cart", out.SpanNames[0].Name)
+	})
+}
+
+func TestSearchAll(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	early := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("POST /order")
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(early))
+	span.Attributes().PutStr("order.id", "ORD-12345")
+	other := rs.ScopeSpans().At(0).Spans().AppendEmpty()
+	other.SetName("GET /health")
+	other.SetStartTimestamp(pcommon.NewTimestampFromTime(early))
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "payment")
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(late))
+	lr.SetSeverityText("ERROR")
+	lr.Body().SetStr("payment declined for ord-12345")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("orders.processed")
+	dp := m.SetEmptySum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(late))
+	dp.Attributes().PutStr("order.id", "ORD-12345")
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterSearchAll)
+
+	t.Run("all_signals", func(t *testing.T) {
+		out := callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{
+			"query": "ord-12345",
+		})
+		assert.Equal(t, 3, out.MatchCount)
+		require.Len(t, out.Traces, 1)
+		assert.Equal(t, "POST /order", out.Traces[0].Name)
+		assert.Equal(t, "attributes.order.id", out.Traces[0].Field)
+		assert.Equal(t, "checkout", out.Traces[0].Service)
+		require.Len(t, out.Logs, 1)
+		assert.Equal(t, "body", out.Logs[0].Field)
+		assert.Equal(t, "payment", out.Logs[0].Service)
+		require.Len(t, out.Metrics, 1)
+		assert.Equal(t, "orders.processed", out.Metrics[0].Name)
+		assert.Equal(t, "unknown", out.Metrics[0].Service)
+	})
+
+	t.Run("signal_filter", func(t *testing.T) {
+		out := callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{
+			"query":   "ord-12345",
+			"signals": []string{"logs"},
+		})
+		assert.Equal(t, 1, out.MatchCount)
+		assert.Len(t, out.Logs, 1)
+		assert.Empty(t, out.Traces)
+		assert.Empty(t, out.Metrics)
+	})
+
+	t.Run("time_range", func(t *testing.T) {
+		out := callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{
+			"query":      "ord-12345",
+			"start_time": early.Add(30 * time.Minute).Format(time.RFC3339),
+		})
+		assert.Equal(t, 2, out.MatchCount)
+		assert.Empty(t, out.Traces)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		out := callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{
+			"query":   "/",
+			"signals": []string{"traces"},
+			"limit":   1,
+		})
+		assert.Len(t, out.Traces, 1)
+	})
+
+	t.Run("invalid_signal", func(t *testing.T) {
+		result, err := callTool(session, "search_all", map[string]any{"query": "x", "signals": []string{"profiles"}})
+		if err == nil {
+			assert.True(t, result.IsError, "should return error for invalid signal")
+		}
+	})
+}
+
+func TestGetLogByID(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	first := plog.NewLogs()
+	first.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("first batch")
+	second := plog.NewLogs()
+	rl := second.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "payment")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Body().SetStr("other record")
+	target := records.AppendEmpty()
+	target.SetSeverityText("ERROR")
+	target.Body().SetStr("card declined")
+	mockCtx.recentLogs = []plog.Logs{first, second}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs, tools.RegisterGetLogByID)
+
+	t.Run("id_in_query_logs", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"body": "declined",
+		})
+		assert.Contains(t, out.Markdown, "| 1-0-0-1 |")
+	})
+
+	t.Run("found", func(t *testing.T) {
+		out := callToolOutput[tools.GetLogByIDOutput](t, session, "get_log_by_id", map[string]any{
+			"log_id": "1-0-0-1",
+		})
+		assert.True(t, out.Found)
+		assert.Contains(t, out.Markdown, "card declined")
+		assert.Contains(t, out.Markdown, "**Service:** payment")
+		assert.Contains(t, out.Markdown, "`1-0-0-1`")
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		out := callToolOutput[tools.GetLogByIDOutput](t, session, "get_log_by_id", map[string]any{
+			"log_id": "1-0-0-5",
+		})
+		assert.False(t, out.Found)
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		result, err := callTool(session, "get_log_by_id", map[string]any{"log_id": "abc"})
+		if err == nil {
+			assert.True(t, result.IsError, "should return error for invalid log ID")
+		}
+	})
+}
+
+func TestQueryMetricsSummaryAverage(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	hist := sm.Metrics().AppendEmpty()
+	hist.SetName("request.duration")
+	hdp := hist.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetCount(4)
+	hdp.SetSum(10)
+	summ := sm.Metrics().AppendEmpty()
+	summ.SetName("gc.pause")
+	sdp := summ.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetCount(3)
+	sdp.SetSum(1.5)
+	empty := sm.Metrics().AppendEmpty()
+	empty.SetName("idle.duration")
+	empty.SetEmptyHistogram().DataPoints().AppendEmpty()
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics)
+
+	out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{})
+	assert.Contains(t, out.Markdown, "count=4 sum=10.00 avg=2.50")
+	assert.Contains(t, out.Markdown, "count=3 sum=1.50 avg=0.50")
+	assert.Contains(t, out.Markdown, "count=0 sum=0.00 |")
+}
+
+func TestGetSpanByID(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	uniqueSpan := pcommon.SpanID([8]byte{1})
+	sharedSpan := pcommon.SpanID([8]byte{2})
+	traceA := pcommon.TraceID([16]byte{0xa})
+	traceB := pcommon.TraceID([16]byte{0xb})
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	span := spans.AppendEmpty()
+	span.SetName("charge card")
+	span.SetTraceID(traceA)
+	span.SetSpanID(uniqueSpan)
+	span.Events().AppendEmpty().SetName("exception")
+	for _, traceID := range []pcommon.TraceID{traceA, traceB} {
+		s := spans.AppendEmpty()
+		s.SetName("shared")
+		s.SetTraceID(traceID)
+		s.SetSpanID(sharedSpan)
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetSpanByID)
+
+	t.Run("found", func(t *testing.T) {
+		out := callToolOutput[tools.GetSpanByIDOutput](t, session, "get_span_by_id", map[string]any{
+			"span_id": uniqueSpan.String(),
+		})
+		assert.True(t, out.Found)
+		assert.Equal(t, traceA.String(), out.TraceID)
+		assert.Contains(t, out.Markdown, "## Span: charge card")
+		assert.Contains(t, out.Markdown, "### Events")
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		out := callToolOutput[tools.GetSpanByIDOutput](t, session, "get_span_by_id", map[string]any{
+			"span_id": sharedSpan.String(),
+		})
+		assert.False(t, out.Found)
+		assert.True(t, out.Ambiguous)
+		assert.Equal(t, []string{traceA.String(), traceB.String()}, out.CandidateTraceIDs)
+	})
+
+	t.Run("scoped_to_trace", func(t *testing.T) {
+		out := callToolOutput[tools.GetSpanByIDOutput](t, session, "get_span_by_id", map[string]any{
+			"span_id":  sharedSpan.String(),
+			"trace_id": traceB.String(),
+		})
+		assert.True(t, out.Found)
+		assert.Equal(t, traceB.String(), out.TraceID)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		out := callToolOutput[tools.GetSpanByIDOutput](t, session, "get_span_by_id", map[string]any{
+			"span_id": "ffffffffffffffff",
+		})
+		assert.False(t, out.Found)
+		assert.Equal(t, "Span not found", out.Markdown)
+	})
+}
+
+func TestListSpanEvents(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("charge card")
+	span.SetTraceID(pcommon.TraceID([16]byte{1}))
+	exception := span.Events().AppendEmpty()
+	exception.SetName("exception")
+	exception.Attributes().PutStr("exception.type", "TimeoutError")
+	exception.Attributes().PutStr("exception.message", "gateway timed out")
+	exception.Attributes().PutStr("exception.stacktrace", strings.Repeat("at frame\n", 100))
+	exception.Attributes().PutBool("exception.escaped", true)
+	span.Events().AppendEmpty().SetName("retry")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterListSpanEvents)
+
+	t.Run("exceptions_by_default", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanEventsOutput](t, session, "list_span_events", map[string]any{})
+		require.Equal(t, 1, out.EventCount)
+		event := out.Events[0]
+		assert.Equal(t, "charge card", event.SpanName)
+		assert.Equal(t, "checkout", event.Service)
+		assert.Equal(t, "TimeoutError", event.ExceptionType)
+		assert.Equal(t, "gateway timed out", event.ExceptionMessage)
+		assert.Len(t, []rune(event.ExceptionStacktrace), 503)
+		assert.Equal(t, "exception.escaped=true", event.Attributes)
+	})
+
+	t.Run("by_name", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanEventsOutput](t, session, "list_span_events", map[string]any{
+			"name": "RETRY",
+		})
+		require.Equal(t, 1, out.EventCount)
+		assert.Equal(t, "retry", out.Events[0].Name)
+	})
+
+	t.Run("all_events", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanEventsOutput](t, session, "list_span_events", map[string]any{
+			"name": "*",
+		})
+		assert.Equal(t, 2, out.EventCount)
+	})
+
+	t.Run("service_filter", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanEventsOutput](t, session, "list_span_events", map[string]any{
+			"service_name": "cart",
+		})
+		assert.Equal(t, 0, out.EventCount)
+	})
+}
+
+func TestQueryMetricsValueFilter(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	cpu := sm.Metrics().AppendEmpty()
+	cpu.SetName("cpu.utilization")
+	cpuPoints := cpu.SetEmptyGauge().DataPoints()
+	for i, v := range []float64{0.25, 0.91, 0.97} {
+		dp := cpuPoints.AppendEmpty()
+		dp.SetDoubleValue(v)
+		dp.Attributes().PutInt("cpu", int64(i))
+	}
+	mem := sm.Metrics().AppendEmpty()
+	mem.SetName("memory.utilization")
+	mem.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(0.4)
+	errorsTotal := sm.Metrics().AppendEmpty()
+	errorsTotal.SetName("errors.total")
+	errorsTotal.SetEmptySum().DataPoints().AppendEmpty().SetIntValue(0)
+	hist := sm.Metrics().AppendEmpty()
+	hist.SetName("request.duration")
+	hdp := hist.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetCount(2)
+	hdp.SetSum(1500)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics)
+
+	t.Run("gauge_above_threshold", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_type": "gauge",
+			"min_value":   0.9,
+			"detailed":    true,
+		})
+		assert.Equal(t, 1, out.MetricCount)
+		assert.Contains(t, out.Markdown, "cpu.utilization")
+		assert.Contains(t, out.Markdown, "| 0.91 | cpu=1 |")
+		assert.Contains(t, out.Markdown, "| 0.97 | cpu=2 |")
+		assert.NotContains(t, out.Markdown, "cpu=0")
+		assert.NotContains(t, out.Markdown, "memory.utilization")
+	})
+
+	t.Run("zero_counters", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_type": "sum",
+			"max_value":   0,
+		})
+		assert.Equal(t, 1, out.MetricCount)
+		assert.Contains(t, out.Markdown, "errors.total")
+	})
+
+	t.Run("histogram_by_field", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_type": "histogram",
+			"min_value":   1000,
+		})
+		assert.Equal(t, 0, out.MetricCount)
+
+		out = callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_type": "histogram",
+			"min_value":   1000,
+			"value_field": "sum",
+		})
+		assert.Equal(t, 1, out.MetricCount)
+	})
+
+	t.Run("buffer_not_modified", func(t *testing.T) {
+		assert.Equal(t, 3, cpu.Gauge().DataPoints().Len())
+	})
+}
+
+func TestCheckCorrelation(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	buffered := pcommon.TraceID([16]byte{1})
+	missing := pcommon.TraceID([16]byte{2})
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	logged := spans.AppendEmpty()
+	logged.SetName("charge card")
+	logged.SetTraceID(buffered)
+	logged.SetSpanID(pcommon.SpanID([8]byte{1}))
+	silent := spans.AppendEmpty()
+	silent.SetName("reserve stock")
+	silent.SetTraceID(buffered)
+	silent.SetSpanID(pcommon.SpanID([8]byte{2}))
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+	correlated := records.AppendEmpty()
+	correlated.SetTraceID(buffered)
+	correlated.SetSpanID(pcommon.SpanID([8]byte{1}))
+	records.AppendEmpty().Body().SetStr("no trace context")
+	for range 2 {
+		orphan := records.AppendEmpty()
+		orphan.SetTraceID(missing)
+		orphan.SetSpanID(pcommon.SpanID([8]byte{3}))
+	}
+	other := ld.ResourceLogs().AppendEmpty()
+	other.Resource().Attributes().PutStr("service.name", "frontend")
+	other.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("frontend log")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterCheckCorrelation)
+
+	t.Run("all_services", func(t *testing.T) {
+		out := callToolOutput[tools.CheckCorrelationOutput](t, session, "check_correlation", map[string]any{})
+		assert.Equal(t, 5, out.LogCount)
+		assert.Equal(t, 3, out.LogsWithTraceContext)
+		assert.Equal(t, 2, out.LogsWithoutTraceContext)
+		assert.Equal(t, 2, out.OrphanedLogs)
+		assert.Equal(t, []string{missing.String()}, out.OrphanedTraceIDs)
+
+		assert.Equal(t, 2, out.SpanCount)
+		assert.Equal(t, 1, out.SpansWithLogs)
+		assert.Equal(t, 1, out.SpansWithoutLogs)
+		require.Len(t, out.UncorrelatedSpans, 1)
+		assert.Equal(t, "reserve stock", out.UncorrelatedSpans[0].Name)
+		assert.Equal(t, "checkout", out.UncorrelatedSpans[0].Service)
+	})
+
+	t.Run("service_filter", func(t *testing.T) {
+		out := callToolOutput[tools.CheckCorrelationOutput](t, session, "check_correlation", map[string]any{"service_name": "frontend"})
+		assert.Equal(t, 1, out.LogCount)
+		assert.Equal(t, 1, out.LogsWithoutTraceContext)
+		assert.Equal(t, 0, out.SpanCount)
+		assert.Empty(t, out.UncorrelatedSpans)
+	})
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for _, d := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"GET /cart", 500 * time.Microsecond},
+		{"GET /cart", 3 * time.Millisecond},
+		{"GET /cart", 4 * time.Millisecond},
+		{"POST /pay", 2 * time.Second},
+		{"POST /pay", time.Minute},
+	} {
+		span := spans.AppendEmpty()
+		span.SetName(d.name)
+		span.SetStartTimestamp(pcommon.Timestamp(1_000_000_000))
+		span.SetEndTimestamp(pcommon.Timestamp(1_000_000_000 + int64(d.duration)))
+	}
+	other := td.ResourceSpans().AppendEmpty()
+	other.Resource().Attributes().PutStr("service.name", "frontend")
+	other.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("GET /cart")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterLatencyHistogram)
+
+	t.Run("default_buckets", func(t *testing.T) {
+		out := callToolOutput[tools.LatencyHistogramOutput](t, session, "latency_histogram", map[string]any{
+			"service_name": "checkout",
+		})
+		assert.Equal(t, 5, out.SpanCount)
+		require.Len(t, out.Buckets, 15)
+		assert.Equal(t, "0s - 1ms", out.Buckets[0].Label)
+		assert.Equal(t, 1, out.Buckets[0].Count)
+		assert.Equal(t, 2, out.Buckets[2].Count)
+		assert.Equal(t, ">= 30s", out.Buckets[14].Label)
+		assert.Nil(t, out.Buckets[14].UpperMs)
+		assert.Equal(t, 1, out.Buckets[14].Count)
+		assert.Contains(t, out.Markdown, "| 2ms - 5ms | 2 | "+strings.Repeat("█", 40)+" |")
+		assert.Contains(t, out.Markdown, "| 0s - 1ms | 1 | "+strings.Repeat("█", 20)+" |")
+	})
+
+	t.Run("custom_buckets_and_span_name", func(t *testing.T) {
+		out := callToolOutput[tools.LatencyHistogramOutput](t, session, "latency_histogram", map[string]any{
+			"service_name": "checkout",
+			"span_name":    "POST /pay",
+			"buckets_ms":   []float64{1000, 5000},
+		})
+		assert.Equal(t, 2, out.SpanCount)
+		require.Len(t, out.Buckets, 3)
+		assert.Equal(t, []int{0, 1, 1}, []int{out.Buckets[0].Count, out.Buckets[1].Count, out.Buckets[2].Count})
+		assert.Equal(t, "1s - 5s", out.Buckets[1].Label)
+	})
+
+	t.Run("unknown_service", func(t *testing.T) {
+		out := callToolOutput[tools.LatencyHistogramOutput](t, session, "latency_histogram", map[string]any{
+			"service_name": "missing",
+		})
+		assert.Equal(t, 0, out.SpanCount)
+		assert.Contains(t, out.Markdown, "No spans found")
+	})
+
+	t.Run("invalid_buckets", func(t *testing.T) {
+		result, err := callTool(session, "latency_histogram", map[string]any{"service_name": "checkout", "buckets_ms": []float64{10, 5}})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for decreasing buckets")
+		}
+	})
+}
+
+func TestExportTraceOTLP(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	target := pcommon.TraceID([16]byte{1, 2, 3})
+	for _, service := range []string{"checkout", "payment"} {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName("instrumentation")
+		span := ss.Spans().AppendEmpty()
+		span.SetName(service + " span")
+		span.SetTraceID(target)
+		span.SetSpanID(pcommon.SpanID([8]byte{byte(len(service))}))
+		span.Attributes().PutInt("attempt", 2)
+		ss.Spans().AppendEmpty().SetTraceID(pcommon.TraceID([16]byte{9}))
+		mockCtx.recentTraces = append(mockCtx.recentTraces, td)
+	}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterExportTraceOTLP)
+
+	t.Run("round_trip", func(t *testing.T) {
+		out := callToolOutput[tools.ExportTraceOTLPOutput](t, session, "export_trace_otlp", map[string]any{
+			"trace_id": strings.ToUpper(target.String()),
+		})
+		assert.Equal(t, target.String(), out.TraceID)
+		assert.Equal(t, 2, out.SpanCount)
+
+		td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(out.OTLPJSON))
+		require.NoError(t, err)
+		require.Equal(t, 2, td.ResourceSpans().Len())
+		assert.Equal(t, 2, td.SpanCount())
+
+		rs := td.ResourceSpans().At(1)
+		service, _ := rs.Resource().Attributes().Get("service.name")
+		assert.Equal(t, "payment", service.Str())
+		assert.Equal(t, "instrumentation", rs.ScopeSpans().At(0).Scope().Name())
+		span := rs.ScopeSpans().At(0).Spans().At(0)
+		assert.Equal(t, "payment span", span.Name())
+		attempt, _ := span.Attributes().Get("attempt")
+		assert.Equal(t, int64(2), attempt.Int())
+	})
+
+	t.Run("not_buffered", func(t *testing.T) {
+		out := callToolOutput[tools.ExportTraceOTLPOutput](t, session, "export_trace_otlp", map[string]any{
+			"trace_id": "ffffffffffffffffffffffffffffffff",
+		})
+		assert.Equal(t, 0, out.SpanCount)
+		assert.Empty(t, out.OTLPJSON)
+	})
+
+	t.Run("invalid_trace_id", func(t *testing.T) {
+		result, err := callTool(session, "export_trace_otlp", map[string]any{"trace_id": "abc"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for invalid trace ID")
+		}
+	})
+}
+
+func TestBufferDisabledSignal(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.bufferStats.TracesCount = 0
+	mockCtx.bufferStats.TracesCapacity = 0
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterQueryTraces,
+		tools.RegisterGetRecentTraces,
+		tools.RegisterQueryLogs,
+		tools.RegisterGetTelemetrySummary,
+	)
+
+	for _, tool := range []string{"query_traces", "get_recent_traces"} {
+		t.Run(tool, func(t *testing.T) {
+			result, err := callTool(session, tool, map[string]any{})
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			text := result.Content[0].(*mcp.TextContent).Text
+			assert.Contains(t, text, tools.ErrBufferDisabled.Error())
+			assert.Contains(t, text, "traces_buffer_size is 0")
+		})
+	}
+
+	t.Run("other_signals_unaffected", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{})
+		assert.Equal(t, 0, out.LogCount)
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{})
+		assert.True(t, out.Traces.Disabled)
+		assert.False(t, out.Logs.Disabled)
+	})
+}
+
+func TestQueryMetricsTemporality(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	requests := metrics.AppendEmpty()
+	requests.SetName("http.requests")
+	requests.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	requests.Sum().SetIsMonotonic(true)
+	requests.Sum().DataPoints().AppendEmpty().SetDoubleValue(10)
+	inflight := metrics.AppendEmpty()
+	inflight.SetName("http.inflight")
+	inflight.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	inflight.Sum().DataPoints().AppendEmpty().SetDoubleValue(2)
+	latency := metrics.AppendEmpty()
+	latency.SetName("http.latency")
+	latency.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	latency.Histogram().DataPoints().AppendEmpty().SetCount(3)
+	memory := metrics.AppendEmpty()
+	memory.SetName("memory.usage")
+	memory.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(512)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics, tools.RegisterGetRecentMetrics)
+
+	t.Run("summary_rows", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{})
+		assert.Equal(t, 4, out.MetricCount)
+		assert.Contains(t, out.Markdown, "| http.requests | Sum (Cumulative, monotonic) |")
+		assert.Contains(t, out.Markdown, "| http.inflight | Sum (Delta) |")
+		assert.Contains(t, out.Markdown, "| http.latency | Histogram (Delta) |")
+		assert.Contains(t, out.Markdown, "| memory.usage | Gauge |")
+	})
+
+	t.Run("temporality_filter", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{"temporality": "delta"})
+		assert.Equal(t, 2, out.MetricCount)
+		assert.Contains(t, out.Markdown, "http.inflight")
+		assert.Contains(t, out.Markdown, "http.latency")
+
+		out = callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{"temporality": "Cumulative"})
+		assert.Equal(t, 1, out.MetricCount)
+		assert.Contains(t, out.Markdown, "http.requests")
+	})
+
+	t.Run("invalid_temporality", func(t *testing.T) {
+		result, err := callTool(session, "query_metrics", map[string]any{"temporality": "sometimes"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for invalid temporality")
+		}
+	})
+
+	t.Run("recent_metrics_summary", func(t *testing.T) {
+		out := callToolOutput[tools.MetricsOutput](t, session, "get_recent_metrics", map[string]any{})
+		summaries := make(map[string]tools.MetricSummary)
+		for _, summary := range out.AvailableMetrics {
+			summaries[summary.Name] = summary
+		}
+		assert.Equal(t, "Cumulative", summaries["http.requests"].Temporality)
+		assert.True(t, summaries["http.requests"].IsMonotonic)
+		assert.Equal(t, "Delta", summaries["http.inflight"].Temporality)
+		assert.False(t, summaries["http.inflight"].IsMonotonic)
+		assert.Empty(t, summaries["memory.usage"].Temporality)
+	})
+}
+
+func TestQueryTracesRootSpanName(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	addSpan := func(spans ptrace.SpanSlice, name string, traceID byte, spanID, parentID byte) {
+		span := spans.AppendEmpty()
+		span.SetName(name)
+		span.SetTraceID(pcommon.TraceID([16]byte{traceID}))
+		span.SetSpanID(pcommon.SpanID([8]byte{spanID}))
+		if parentID != 0 {
+			span.SetParentSpanID(pcommon.SpanID([8]byte{parentID}))
+		}
+	}
+
+	// Root and child spans of a trace arrive in separate batches
+	roots := ptrace.NewTraces()
+	rootSpans := roots.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	addSpan(rootSpans, "GET /checkout", 1, 1, 0)
+	addSpan(rootSpans, "GET /cart", 2, 1, 0)
+	children := ptrace.NewTraces()
+	childSpans := children.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	addSpan(childSpans, "SELECT orders", 1, 2, 1)
+	addSpan(childSpans, "SELECT carts", 2, 2, 1)
+	// The parent of this span is not buffered, so it counts as a root
+	addSpan(childSpans, "GET /checkout/confirm", 3, 5, 4)
+	mockCtx.recentTraces = []ptrace.Traces{roots, children}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	t.Run("whole_traces", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"root_span_name": "get /checkout",
+		})
+		assert.Equal(t, 3, out.SpanCount)
+		assert.Contains(t, out.Markdown, "SELECT orders")
+		assert.Contains(t, out.Markdown, "GET /checkout/confirm")
+		assert.NotContains(t, out.Markdown, "SELECT carts")
+	})
+
+	t.Run("root_only", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"root_span_name": "GET /checkout",
+			"root_only":      true,
+		})
+		assert.Equal(t, 2, out.SpanCount)
+		assert.NotContains(t, out.Markdown, "SELECT orders")
+
+		// root_spans_only is an alias of root_only
+		alias := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"root_span_name":  "GET /checkout",
+			"root_spans_only": true,
+		})
+		assert.Equal(t, out, alias)
+	})
+
+	t.Run("child_name_does_not_match", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"root_span_name": "SELECT",
+		})
+		assert.Equal(t, 0, out.SpanCount)
+	})
+}
+
+func TestTroubleshootPipelinePrompt(t *testing.T) {
+	ctx := context.Background()
+	var ct, st mcp.Transport = mcp.NewInMemoryTransports()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "0.1.0"}, nil)
+	tools.RegisterTroubleshootPipelinePrompt(server)
+
+	_, err := server.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	t.Run("listed", func(t *testing.T) {
+		result, err := session.ListPrompts(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Prompts, 1)
+		assert.Equal(t, "troubleshoot_pipeline", result.Prompts[0].Name)
+		require.Len(t, result.Prompts[0].Arguments, 1)
+		assert.True(t, result.Prompts[0].Arguments[0].Required)
+	})
+
+	t.Run("templated", func(t *testing.T) {
+		result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+			Name:      "troubleshoot_pipeline",
+			Arguments: map[string]string{"pipeline_id": "metrics/prod"},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 1)
+		text := result.Messages[0].Content.(*mcp.TextContent).Text
+		assert.Contains(t, text, "`metrics/prod`")
+		for _, tool := range []string{"get_pipeline_metrics", "get_pipeline_config", "get_component_status", "query_metrics"} {
+			assert.Contains(t, text, tool)
+		}
+	})
+
+	t.Run("missing_pipeline_id", func(t *testing.T) {
+		_, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "troubleshoot_pipeline"})
+		assert.Error(t, err)
+	})
+}
+
+func TestListInstrumentationScopes(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	addSpans := func(service, scope, version string, count int) {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName(scope)
+		ss.Scope().SetVersion(version)
+		for i := 0; i < count; i++ {
+			ss.Spans().AppendEmpty().SetName("span")
+		}
+	}
+	addSpans("checkout", "otelhttp", "0.60.0", 2)
+	addSpans("cart", "otelhttp", "0.60.0", 1)
+	addSpans("cart", "otelhttp", "0.59.0", 1)
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("otelhttp")
+	sl.Scope().SetVersion("0.60.0")
+	sl.LogRecords().AppendEmpty().Body().SetStr("request")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "cart")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("runtime")
+	sm.Metrics().AppendEmpty().SetName("go.goroutines")
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterListInstrumentationScopes)
+
+	t.Run("all", func(t *testing.T) {
+		out := callToolOutput[tools.ListInstrumentationScopesOutput](t, session, "list_instrumentation_scopes", map[string]any{})
+		assert.Equal(t, 3, out.TotalScopes)
+		require.Len(t, out.Scopes, 3)
+		assert.Equal(t, tools.InstrumentationScopeFacet{Name: "otelhttp", Version: "0.60.0", Spans: 3, Logs: 1, Services: []string{"cart", "checkout"}}, out.Scopes[0])
+		assert.Equal(t, tools.InstrumentationScopeFacet{Name: "otelhttp", Version: "0.59.0", Spans: 1, Services: []string{"cart"}}, out.Scopes[1])
+		assert.Equal(t, tools.InstrumentationScopeFacet{Name: "runtime", Metrics: 1, Services: []string{"cart"}}, out.Scopes[2])
+	})
+
+	t.Run("service_filter", func(t *testing.T) {
+		out := callToolOutput[tools.ListInstrumentationScopesOutput](t, session, "list_instrumentation_scopes", map[string]any{
+			"service_name": "checkout",
+		})
+		require.Len(t, out.Scopes, 1)
+		assert.Equal(t, tools.InstrumentationScopeFacet{Name: "otelhttp", Version: "0.60.0", Spans: 2, Logs: 1, Services: []string{"checkout"}}, out.Scopes[0])
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		out := callToolOutput[tools.ListInstrumentationScopesOutput](t, session, "list_instrumentation_scopes", map[string]any{
+			"limit": 1,
+		})
+		assert.Equal(t, 3, out.TotalScopes)
+		require.Len(t, out.Scopes, 1)
+	})
+}
+
+func TestExponentialHistogramMetrics(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("request.duration")
+	dp := metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetCount(6)
+	dp.SetSum(60)
+	dp.SetMin(0)
+	dp.SetMax(20)
+	dp.SetScale(0)
+	dp.SetZeroCount(2)
+	dp.Positive().SetOffset(2)
+	dp.Positive().BucketCounts().FromRaw([]uint64{3, 0, 1})
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics, tools.RegisterGetRecentMetrics)
+
+	t.Run("summary", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{})
+		assert.Contains(t, out.Markdown, "count=6 sum=60.00 avg=10.00 min=0.00 max=20.00")
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"detailed": true,
+		})
+		assert.Contains(t, out.Markdown, "**Count:** 6")
+		assert.Contains(t, out.Markdown, "**Max:** 20.00")
+		assert.Contains(t, out.Markdown, "**Scale:** 0")
+		assert.Contains(t, out.Markdown, "| 0 | 0 | 2 |")
+		assert.Contains(t, out.Markdown, "| 4 | 8 | 3 |")
+		assert.Contains(t, out.Markdown, "| 16 | 32 | 1 |")
+		assert.NotContains(t, out.Markdown, "| 8 | 16 |")
+	})
+
+	t.Run("get_recent_metrics", func(t *testing.T) {
+		out := callToolOutput[tools.MetricsOutput](t, session, "get_recent_metrics", map[string]any{
+			"metric_name": "request.duration",
+		})
+		require.Len(t, out.DataPoints, 1)
+		assert.Equal(t, "count=6,sum=60.00", out.DataPoints[0].Value)
+	})
+}
+
+func TestScanToolsTruncateOnTimeout(t *testing.T) {
+
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("GET /cart")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	// Simulate a request whose deadline passed before the scan started
+	expireDeadline := func(server *mcp.Server, _ tools.ExtensionContext) {
+		server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+			return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				if method == "tools/call" {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithDeadline(ctx, time.Now())
+					defer cancel()
+				}
+				return next(ctx, method, req)
+			}
+		})
+	}
+	session := connectTestClient(t, mockCtx,
+		expireDeadline,
+		tools.RegisterQueryTraces,
+		tools.RegisterListSpanNames,
+		tools.RegisterSearchAll,
+		tools.RegisterLatencyHistogram,
+	)
+
+	t.Run("query_traces", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{})
+		assert.True(t, out.Truncated)
+		assert.Equal(t, 0, out.SpanCount)
+	})
+
+	t.Run("query_traces_root_span_name", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"root_span_name": "GET",
+		})
+		assert.True(t, out.Truncated)
+	})
+
+	t.Run("list_span_names", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanNamesOutput](t, session, "list_span_names", map[string]any{})
+		assert.True(t, out.Truncated)
+		assert.Empty(t, out.SpanNames)
+	})
+
+	t.Run("search_all", func(t *testing.T) {
+		out := callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{
+			"query": "cart",
+		})
+		assert.True(t, out.Truncated)
+	})
+
+	t.Run("latency_histogram", func(t *testing.T) {
+		out := callToolOutput[tools.LatencyHistogramOutput](t, session, "latency_histogram", map[string]any{
+			"service_name": "checkout",
+		})
+		assert.True(t, out.Truncated)
+		assert.Equal(t, 0, out.SpanCount)
+	})
+}
+
+func TestQueryTimeoutReturnsPartialResults(t *testing.T) {
+	// Each matching record takes longer than the query timeout to check, so the
+	// deadline passes after the first batch was scanned
+	mockCtx := newMockExtensionContext()
+	mockCtx.redactDelay = 100 * time.Millisecond
+	for i := range 3 {
+		ld := plog.NewLogs()
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "checkout")
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.Body().SetStr("request done")
+		lr.Attributes().PutStr("http.route", fmt.Sprintf("/cart/%d", i))
+		mockCtx.recentLogs = append(mockCtx.recentLogs, ld)
+	}
+
+	withTimeout := func(server *mcp.Server, _ tools.ExtensionContext) {
+		server.AddReceivingMiddleware(toolCallTimeoutMiddleware(20 * time.Millisecond))
+	}
+	session := connectTestClient(t, mockCtx, withTimeout, tools.RegisterSearchAll)
+
+	out := callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{"query": "cart", "signals": []string{"logs"}})
+	assert.True(t, out.Truncated)
+	require.Len(t, out.Logs, 1, "matches found before the deadline are returned")
+	assert.Equal(t, "/cart/0", out.Logs[0].Value)
+}
+
+func TestGetPipelineHealth(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.SetConf(confmap.NewFromStringMap(map[string]any{
+		"service": map[string]any{
+			"pipelines": map[string]any{
+				"traces": map[string]any{
+					"receivers": []any{"otlp"},
+					"exporters": []any{"debug", "mcp"},
+				},
+				"logs": map[string]any{
+					"receivers": []any{"otlp"},
+					"exporters": []any{"mcp/prod"},
+				},
+				"metrics/internal": map[string]any{
+					"receivers": []any{"prometheus"},
+					"exporters": []any{"debug"},
+				},
+				"profiles": map[string]any{
+					"receivers": []any{"otlp"},
+					"exporters": []any{"debug"},
+				},
+			},
+		},
+	}))
+	mockCtx.bufferStats.MetricsCapacity = 0
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(10, 0)))
+	spans.AppendEmpty().SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(20, 0)))
+	mockCtx.recentTraces = []ptrace.Traces{td}
+	receivedAt := time.Now().Add(-10 * time.Second)
+	mockCtx.batchInfos = map[string][]tools.BatchInfo{
+		"traces": {{Seq: 1, ReceivedAt: receivedAt, Items: 2}},
+	}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetPipelineHealth)
+
+	t.Run("all", func(t *testing.T) {
+		out := callToolOutput[tools.GetPipelineHealthOutput](t, session, "get_pipeline_health", map[string]any{})
+		require.Equal(t, 4, out.Count)
+
+		byID := make(map[string]tools.PipelineHealth)
+		for _, p := range out.Pipelines {
+			byID[p.PipelineID] = p
+		}
+
+		assert.Equal(t, tools.PipelineHealth{
+			PipelineID:      "traces",
+			Signal:          "traces",
+			ExportsToMCP:    true,
+			BufferedBatches: 1,
+			BufferedItems:   2,
+			LatestTimestamp: "1970-01-01T00:00:20Z",
+			LastReceivedAt:  receivedAt.UTC().Format(time.RFC3339Nano),
+			Status:          "flowing",
+		}, byID["traces"])
+		assert.Equal(t, "possibly_idle", byID["logs"].Status)
+		assert.True(t, byID["logs"].ExportsToMCP)
+		// The metrics buffer is disabled, so there is no evidence either way
+		assert.Equal(t, "unknown", byID["metrics/internal"].Status)
+		assert.False(t, byID["metrics/internal"].ExportsToMCP)
+		assert.Equal(t, "unknown", byID["profiles"].Status)
+	})
+
+	t.Run("pipeline_filter", func(t *testing.T) {
+		out := callToolOutput[tools.GetPipelineHealthOutput](t, session, "get_pipeline_health", map[string]any{
+			"pipeline_id": "logs",
+		})
+		require.Len(t, out.Pipelines, 1)
+		assert.Equal(t, "logs", out.Pipelines[0].PipelineID)
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		// The span timestamps are ancient, but staleness is judged by when the
+		// newest batch was received
+		out := callToolOutput[tools.GetPipelineHealthOutput](t, session, "get_pipeline_health", map[string]any{
+			"pipeline_id": "traces",
+			"stale_after": "1s",
+		})
+		require.Len(t, out.Pipelines, 1)
+		assert.Equal(t, "stalled", out.Pipelines[0].Status)
+	})
+
+	t.Run("invalid_stale_after", func(t *testing.T) {
+		for _, staleAfter := range []string{"soon", "-1m", "0s"} {
+			result, err := callTool(session, "get_pipeline_health", map[string]any{"stale_after": staleAfter})
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for stale_after %q", staleAfter)
+			}
+		}
+	})
+}
+
+func TestListBatches(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	receivedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockCtx.batchInfos = map[string][]tools.BatchInfo{
+		"traces": {
+			{Seq: 7, ReceivedAt: receivedAt, Items: 3, SizeBytes: 120},
+			{Seq: 8, ReceivedAt: receivedAt.Add(time.Second), Items: 1, SizeBytes: 40},
+		},
+	}
+	mockCtx.bufferStats.TracesCount = 2
+	mockCtx.bufferStats.LogsCapacity = 0
+
+	session := connectTestClient(t, mockCtx, tools.RegisterListBatches)
+
+	t.Run("all", func(t *testing.T) {
+		out := callToolOutput[tools.ListBatchesOutput](t, session, "list_batches", map[string]any{
+			"signal": "traces",
+		})
+		assert.Equal(t, "traces", out.Signal)
+		assert.Equal(t, 2, out.BatchCount)
+		assert.Equal(t, 100, out.Capacity)
+		require.Len(t, out.Batches, 2)
+		assert.Equal(t, tools.BufferBatch{
+			Index:      0,
+			Seq:        7,
+			ReceivedAt: "2025-01-02T03:04:05Z",
+			Items:      3,
+			SizeBytes:  120,
+		}, out.Batches[0])
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		out := callToolOutput[tools.ListBatchesOutput](t, session, "list_batches", map[string]any{
+			"signal": "traces",
+			"offset": 1,
+		})
+		require.Len(t, out.Batches, 1)
+		assert.Equal(t, 1, out.Batches[0].Index)
+		assert.Equal(t, uint64(8), out.Batches[0].Seq)
+	})
+
+	for name, signal := range map[string]string{"invalid_signal": "profiles", "disabled_signal": "logs"} {
+		t.Run(name, func(t *testing.T) {
+			result, err := callTool(session, "list_batches", map[string]any{"signal": signal})
+			if err == nil {
+				assert.True(t, result.IsError, "should return error for %s", signal)
+			}
+		})
+	}
+}
+
+func TestQueryMetricsCSV(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	ts := pcommon.NewTimestampFromTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	requests := metrics.AppendEmpty()
+	requests.SetName("http.requests")
+	requests.SetUnit("1")
+	sum := requests.SetEmptySum()
+	for _, route := range []string{"/cart", "/order"} {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetTimestamp(ts)
+		dp.SetIntValue(42)
+		dp.Attributes().PutStr("http.route", route)
+	}
+
+	load := metrics.AppendEmpty()
+	load.SetName("system.load")
+	dp := load.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(0.125)
+
+	latency := metrics.AppendEmpty()
+	latency.SetName("http.duration")
+	latency.SetUnit("ms")
+	hdp := latency.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(4)
+	hdp.SetSum(10.5)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics)
+
+	t.Run("csv", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"format": "csv",
+		})
+		assert.Equal(t, 3, out.MetricCount)
+		assert.Empty(t, out.Markdown)
+
+		records, err := csv.NewReader(strings.NewReader(out.CSV)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 5)
+		assert.Equal(t, []string{"metric_name", "type", "unit", "service", "timestamp", "value", "count", "sum", "attributes"}, records[0])
+		assert.Equal(t, []string{"http.requests", "Sum", "1", "checkout", "2025-01-02T03:04:05Z", "42", "", "", "http.route=/cart"}, records[1])
+		assert.Equal(t, "http.route=/order", records[2][8])
+		assert.Equal(t, []string{"system.load", "Gauge", "", "checkout", "2025-01-02T03:04:05Z", "0.125", "", "", ""}, records[3])
+		assert.Equal(t, []string{"http.duration", "Histogram", "ms", "checkout", "2025-01-02T03:04:05Z", "", "4", "10.5", ""}, records[4])
+	})
+
+	t.Run("attribute_columns", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"format":         "csv",
+			"metric_name":    "http",
+			"attribute_keys": []string{"http.route", "http.method"},
+		})
+
+		records, err := csv.NewReader(strings.NewReader(out.CSV)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, []string{"metric_name", "type", "unit", "service", "timestamp", "value", "count", "sum", "attributes", "http.route", "http.method"}, records[0])
+		// Promoted attributes leave the attributes column
+		assert.Equal(t, []string{"http.requests", "Sum", "1", "checkout", "2025-01-02T03:04:05Z", "42", "", "", "", "/cart", ""}, records[1])
+		assert.Equal(t, "/order", records[2][9])
+		assert.Equal(t, []string{"", ""}, records[3][9:])
+	})
+
+	t.Run("table_has_no_csv", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{})
+		assert.Empty(t, out.CSV)
+		assert.Contains(t, out.Markdown, "| http.requests |")
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		result, err := callTool(session, "query_metrics", map[string]any{"format": "xml"})
+		if err == nil {
+			assert.True(t, result.IsError, "should return error for invalid format")
+		}
+	})
+}
+
+func TestQueryToolsTextContent(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /cart")
+	span.SetTraceID(traceID)
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("cart loaded")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("http.requests")
+	metric.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(3)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterQueryTraces,
+		tools.RegisterQueryLogs,
+		tools.RegisterQueryMetrics,
+		tools.RegisterGetTraceByID,
+	)
+
+	// callText returns the text content block and the structured output of a tool call
+	callText := func(t *testing.T, name string, args map[string]any) (string, map[string]any) {
+		t.Helper()
+		result, err := callTool(session, name, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, "tool %s returned error: %v", name, result.Content)
+		require.Len(t, result.Content, 1)
+		text, ok := result.Content[0].(*mcp.TextContent)
+		require.True(t, ok, "content of %s should be text", name)
+
+		raw, err := json.Marshal(result.StructuredContent)
+		require.NoError(t, err)
+		var structured map[string]any
+		require.NoError(t, json.Unmarshal(raw, &structured))
+		return text.Text, structured
+	}
+
+	for _, name := range []string{"query_traces", "query_logs", "query_metrics"} {
+		t.Run(name, func(t *testing.T) {
+			text, structured := callText(t, name, map[string]any{})
+			assert.Equal(t, structured["markdown"], text)
+			assert.NotEmpty(t, text)
+		})
+	}
+
+	t.Run("query_metrics_csv", func(t *testing.T) {
+		text, structured := callText(t, "query_metrics", map[string]any{"format": "csv"})
+		assert.Equal(t, structured["csv"], text)
+		assert.True(t, strings.HasPrefix(text, "metric_name,"))
+	})
+
+	t.Run("get_trace_by_id", func(t *testing.T) {
+		text, structured := callText(t, "get_trace_by_id", map[string]any{"trace_id": traceID.String()})
+		assert.Equal(t, structured["markdown"], text)
+		assert.Contains(t, text, "GET /cart")
+	})
+}
+
+func TestQueryTracesMinSpans(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	addSpan := func(spans ptrace.SpanSlice, name string, traceID byte, spanID, parentID byte) {
+		span := spans.AppendEmpty()
+		span.SetName(name)
+		span.SetTraceID(pcommon.TraceID([16]byte{traceID}))
+		span.SetSpanID(pcommon.SpanID([8]byte{spanID}))
+		if parentID != 0 {
+			span.SetParentSpanID(pcommon.SpanID([8]byte{parentID}))
+		}
+	}
+
+	// A single-span trace and a 5-span trace whose spans span two batches
+	first := ptrace.NewTraces()
+	firstSpans := first.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	addSpan(firstSpans, "GET /health", 1, 1, 0)
+	addSpan(firstSpans, "GET /checkout", 2, 1, 0)
+	addSpan(firstSpans, "SELECT orders", 2, 2, 1)
+	second := ptrace.NewTraces()
+	secondSpans := second.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	addSpan(secondSpans, "SELECT carts", 2, 3, 1)
+	addSpan(secondSpans, "POST /payment", 2, 4, 1)
+	addSpan(secondSpans, "INSERT payments", 2, 5, 4)
+	mockCtx.recentTraces = []ptrace.Traces{first, second}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	t.Run("skips_single_span_trace", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"min_spans": 2,
+		})
+		assert.Equal(t, 5, out.SpanCount)
+		assert.NotContains(t, out.Markdown, "GET /health")
+		assert.Contains(t, out.Markdown, "INSERT payments")
+	})
+
+	t.Run("above_largest_trace", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"min_spans": 6,
+		})
+		assert.Equal(t, 0, out.SpanCount)
+	})
+
+	t.Run("with_root_span_name", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"min_spans":      2,
+			"root_span_name": "GET",
+			"root_only":      true,
+		})
+		assert.Equal(t, 1, out.SpanCount)
+		assert.Contains(t, out.Markdown, "GET /checkout")
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		result, err := callTool(session, "query_traces", map[string]any{"min_spans": -1})
+		if err == nil {
+			assert.True(t, result.IsError, "negative min_spans should return an error")
+		}
+	})
+}
+
+func TestGetDroppedTelemetry(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	droppedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockCtx.signalDrops = map[string]tools.SignalDrops{
+		"logs": {
+			Batches: 4,
+			Items:   12,
+			Recent: []tools.DroppedBatch{
+				{DroppedAt: droppedAt, Items: 3, Services: []string{"cart", "checkout"}},
+			},
+		},
+	}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetDroppedTelemetry)
+
+	t.Run("all_signals", func(t *testing.T) {
+		out := callToolOutput[tools.GetDroppedTelemetryOutput](t, session, "get_dropped_telemetry", map[string]any{})
+		require.Len(t, out.Signals, 3)
+		assert.Equal(t, "traces", out.Signals[0].Signal)
+		assert.Zero(t, out.Signals[0].DroppedBatches)
+		assert.Equal(t, 100, out.Signals[0].Capacity)
+		assert.Equal(t, "logs", out.Signals[2].Signal)
+		assert.Equal(t, 4, out.Signals[2].DroppedBatches)
+	})
+
+	t.Run("single_signal", func(t *testing.T) {
+		out := callToolOutput[tools.GetDroppedTelemetryOutput](t, session, "get_dropped_telemetry", map[string]any{
+			"signal": "Logs",
+		})
+		require.Len(t, out.Signals, 1)
+		logs := out.Signals[0]
+		assert.Equal(t, 12, logs.DroppedItems)
+		require.Len(t, logs.Samples, 1)
+		assert.Equal(t, "2025-01-02T03:04:05Z", logs.Samples[0].DroppedAt)
+		assert.Equal(t, []string{"cart", "checkout"}, logs.Samples[0].Services)
+	})
+
+	t.Run("invalid_signal", func(t *testing.T) {
+		result, err := callTool(session, "get_dropped_telemetry", map[string]any{"signal": "profiles"})
+		if err == nil {
+			assert.True(t, result.IsError, "unknown signal should return an error")
+		}
+	})
+}
+
+func TestGetAttributeValues(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	for _, pod := range []string{"cart-1", "cart-2"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("k8s.pod.name", pod)
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		for _, route := range []string{"/cart", "/cart", "/checkout"} {
+			spans.AppendEmpty().Attributes().PutStr("http.route", route)
+		}
+		// A span without the key is not counted
+		spans.AppendEmpty().SetName("internal")
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	md := pmetric.NewMetrics()
+	dps := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptySum().DataPoints()
+	dps.AppendEmpty().Attributes().PutInt("http.status_code", 200)
+	dps.AppendEmpty().Attributes().PutInt("http.status_code", 500)
+	dps.AppendEmpty().Attributes().PutInt("http.status_code", 200)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetAttributeValues)
+
+	t.Run("span_attributes", func(t *testing.T) {
+		out := callToolOutput[tools.GetAttributeValuesOutput](t, session, "get_attribute_values", map[string]any{
+			"signal": "traces",
+			"key":    "http.route",
+		})
+		assert.Equal(t, 6, out.Matched)
+		assert.Equal(t, 2, out.TotalDistinct)
+		assert.Equal(t, []tools.AttributeValueFacet{{Value: "/cart", Count: 4}, {Value: "/checkout", Count: 2}}, out.Values)
+	})
+
+	t.Run("resource_attributes_top_n", func(t *testing.T) {
+		out := callToolOutput[tools.GetAttributeValuesOutput](t, session, "get_attribute_values", map[string]any{
+			"signal":   "traces",
+			"key":      "k8s.pod.name",
+			"resource": true,
+			"limit":    1,
+		})
+		assert.Equal(t, 8, out.Matched)
+		assert.Equal(t, 2, out.TotalDistinct)
+		assert.Equal(t, []tools.AttributeValueFacet{{Value: "cart-1", Count: 4}}, out.Values)
+	})
+
+	t.Run("data_point_attributes", func(t *testing.T) {
+		out := callToolOutput[tools.GetAttributeValuesOutput](t, session, "get_attribute_values", map[string]any{
+			"signal": "metrics",
+			"key":    "http.status_code",
+		})
+		assert.Equal(t, []tools.AttributeValueFacet{{Value: "200", Count: 2}, {Value: "500", Count: 1}}, out.Values)
+	})
+
+	t.Run("span_key_not_on_resource", func(t *testing.T) {
+		out := callToolOutput[tools.GetAttributeValuesOutput](t, session, "get_attribute_values", map[string]any{
+			"signal":   "traces",
+			"key":      "http.route",
+			"resource": true,
+		})
+		assert.Zero(t, out.Matched)
+		assert.Empty(t, out.Values)
+	})
+
+	for name, args := range map[string]map[string]any{
+		"invalid_signal": {"signal": "profiles", "key": "http.route"},
+		"missing_key":    {"signal": "traces"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := callTool(session, "get_attribute_values", args)
+			if err == nil {
+				assert.True(t, result.IsError, "%s should return an error", name)
+			}
+		})
+	}
+}
+
+func TestMetricsDataPointSelection(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	// The latest data point is in the middle of the slice
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("queue.depth")
+	dps := metric.SetEmptyGauge().DataPoints()
+	for _, point := range []struct {
+		ts    pcommon.Timestamp
+		value float64
+	}{{10, 1}, {30, 3}, {20, 2}} {
+		dp := dps.AppendEmpty()
+		dp.SetTimestamp(point.ts)
+		dp.SetDoubleValue(point.value)
+	}
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics, tools.RegisterSearchMetrics)
+
+	tests := []struct {
+		selection string
+		want      []string
+		notWant   []string
+	}{
+		{selection: "", want: []string{"| 3.00 |"}, notWant: []string{"| 1.00 |", "| 2.00 |"}},
+		{selection: "last", want: []string{"| 3.00 |"}, notWant: []string{"| 1.00 |", "| 2.00 |"}},
+		{selection: "first", want: []string{"| 1.00 |"}, notWant: []string{"| 2.00 |", "| 3.00 |"}},
+		{selection: "all", want: []string{"| 1.00 |", "| 2.00 |", "| 3.00 |"}},
+	}
+
+	for _, tool := range []string{"query_metrics", "search_metrics"} {
+		for _, tt := range tests {
+			t.Run(tool+"_"+tt.selection, func(t *testing.T) {
+				args := map[string]any{}
+				if tt.selection != "" {
+					args["data_point_selection"] = tt.selection
+				}
+				var markdown string
+				if tool == "query_metrics" {
+					out := callToolOutput[tools.QueryMetricsOutput](t, session, tool, args)
+					assert.Equal(t, 1, out.MetricCount)
+					markdown = out.Markdown
+				} else {
+					out := callToolOutput[tools.SearchMetricsOutput](t, session, tool, args)
+					assert.Equal(t, 1, out.MetricCount)
+					markdown = out.Markdown
+				}
+				for _, want := range tt.want {
+					assert.Contains(t, markdown, want)
+				}
+				for _, notWant := range tt.notWant {
+					assert.NotContains(t, markdown, notWant)
+				}
+			})
+		}
+
+		t.Run(tool+"_invalid", func(t *testing.T) {
+			result, err := callTool(session, tool, map[string]any{"data_point_selection": "max"})
+			if err == nil {
+				assert.True(t, result.IsError, "invalid data_point_selection should return an error")
+			}
+		})
+	}
+
+	t.Run("int_gauge", func(t *testing.T) {
+		intMetrics := pmetric.NewMetrics()
+		gauge := intMetrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		gauge.SetName("queue.size")
+		gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(42)
+		intCtx := newMockExtensionContext()
+		intCtx.recentMetrics = []pmetric.Metrics{intMetrics}
+		intSession := connectTestClient(t, intCtx, tools.RegisterQueryMetrics)
+
+		out := callToolOutput[tools.QueryMetricsOutput](t, intSession, "query_metrics", map[string]any{})
+		assert.Contains(t, out.Markdown, "| 42 |")
+		assert.NotContains(t, out.Markdown, "0.00")
+	})
+}
+
+func TestQueryResourceAttrColumns(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "cart")
+	rs.Resource().Attributes().PutStr("k8s.pod.name", "cart-7d9f")
+	rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("GET /cart")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "cart")
+	rl.Resource().Attributes().PutStr("host.name", "node-1")
+	rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("cart loaded")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces, tools.RegisterQueryLogs)
+
+	keys := []any{"k8s.pod.name", "host.name"}
+
+	t.Run("traces", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"resource_attr_keys": keys,
+		})
+		lines := strings.Split(strings.TrimSpace(out.Markdown), "\n")
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasSuffix(lines[0], "| Attributes | k8s.pod.name | host.name |"), lines[0])
+		assert.True(t, strings.HasSuffix(lines[1], "|---|---|"), lines[1])
+		assert.True(t, strings.HasSuffix(lines[2], "| cart-7d9f | - |"), lines[2])
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"resource_attr_keys": keys,
+		})
+		lines := strings.Split(strings.TrimSpace(out.Markdown), "\n")
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasSuffix(lines[0], "| Attributes | k8s.pod.name | host.name |"), lines[0])
+		assert.True(t, strings.HasSuffix(lines[2], "| - | node-1 |"), lines[2])
+	})
+
+	t.Run("default_table_unchanged", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{})
+		assert.NotContains(t, out.Markdown, "k8s.pod.name")
+		assert.NotContains(t, out.Markdown, "cart-7d9f")
+	})
+}
+
+func TestQueryTracesQueryString(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	addSpan := func(service, name string, duration time.Duration, status ptrace.StatusCode, route string) {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		rs.Resource().Attributes().PutStr("k8s.pod.name", service+"-pod")
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetName(name)
+		span.SetStartTimestamp(pcommon.Timestamp(1_000_000_000))
+		span.SetEndTimestamp(pcommon.Timestamp(1_000_000_000 + duration.Nanoseconds()))
+		span.Status().SetCode(status)
+		if route != "" {
+			span.Attributes().PutStr("http.route", route)
+		}
+	}
+	addSpan("checkout", "GET /checkout", 800*time.Millisecond, ptrace.StatusCodeError, "/checkout")
+	addSpan("checkout", "GET /cart", 100*time.Millisecond, ptrace.StatusCodeOk, "/cart")
+	addSpan("payment", "POST /pay", 900*time.Millisecond, ptrace.StatusCodeError, "")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	tests := []struct {
+		name  string
+		args  map[string]any
+		spans []string
+	}{
+		{name: "service_and_status", args: map[string]any{"query": "service:checkout status:error"}, spans: []string{"GET /checkout"}},
+		{name: "duration", args: map[string]any{"query": "duration>=500ms"}, spans: []string{"GET /checkout", "POST /pay"}},
+		{name: "duration_inclusive", args: map[string]any{"query": "duration>=800ms"}, spans: []string{"GET /checkout", "POST /pay"}},
+		{name: "duration_range", args: map[string]any{"query": "duration>=50ms duration<=500ms"}, spans: []string{"GET /cart"}},
+		{name: "span_attribute", args: map[string]any{"query": "attr.http.route:/cart"}, spans: []string{"GET /cart"}},
+		{name: "keys_case_insensitive", args: map[string]any{"query": "SERVICE:checkout ATTR.http.route:/cart"}, spans: []string{"GET /cart"}},
+		{name: "resource_attribute", args: map[string]any{"query": "attr.k8s.pod.name:payment-pod"}, spans: []string{"POST /pay"}},
+		{name: "bare_words", args: map[string]any{"query": "GET /c"}, spans: []string{"GET /checkout", "GET /cart"}},
+		{name: "quoted_value", args: map[string]any{"query": `span:"GET /cart"`}, spans: []string{"GET /cart"}},
+		{name: "combined_with_fields", args: map[string]any{"query": "status:error", "service_name": "payment"}, spans: []string{"POST /pay"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", tt.args)
+			assert.Equal(t, len(tt.spans), out.SpanCount, out.Markdown)
+			for _, span := range tt.spans {
+				assert.Contains(t, out.Markdown, "| "+span+" |")
+			}
+		})
+	}
+
+	for name, query := range map[string]string{
+		"unknown_key":      "host:node-1",
+		"invalid_status":   "status:broken",
+		"invalid_duration": "duration>=fast",
+		"strict_greater":   "duration>500ms",
+		"strict_less":      "duration<500ms",
+		"conflict":         "service:checkout service:payment",
+		"unterminated":     `span:"GET /cart`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := callTool(session, "query_traces", map[string]any{"query": query})
+			if err == nil {
+				assert.True(t, result.IsError, "query %q should return an error", query)
+			}
+		})
+	}
+}
+
+func TestExportImportBuffer(t *testing.T) {
+
+	source := newMockExtensionContext()
+	for i := range 2 {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", "checkout")
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetName(fmt.Sprintf("span-%d", i))
+		span.SetTraceID([16]byte{byte(i + 1)})
+		source.recentTraces = append(source.recentTraces, td)
+	}
+	md := pmetric.NewMetrics()
+	gauge := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	gauge.SetName("queue_size")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(7)
+	source.recentMetrics = []pmetric.Metrics{md}
+
+	connect := func(t *testing.T, mockCtx *mockExtensionContext) *mcp.ClientSession {
+		return connectTestClient(t, mockCtx, tools.RegisterExportBuffer, tools.RegisterImportBuffer)
+	}
+	session := connect(t, source)
+
+	for _, format := range []string{"json", "proto"} {
+		t.Run(format+"_base64", func(t *testing.T) {
+			exported := callToolOutput[tools.ExportBufferOutput](t, session, "export_buffer", map[string]any{"format": format})
+			require.Len(t, exported.Signals, 3)
+			assert.Equal(t, 2, exported.Signals[0].Batches)
+			assert.Equal(t, 2, exported.Signals[0].Items)
+			assert.Equal(t, 1, exported.Signals[1].Items)
+			assert.Empty(t, exported.Signals[2].Data, "empty logs buffer has no data")
+
+			target := newMockExtensionContext()
+			imported := callToolOutput[tools.ImportBufferOutput](t, connect(t, target), "import_buffer", map[string]any{
+				"format":  format,
+				"traces":  exported.Signals[0].Data,
+				"metrics": exported.Signals[1].Data,
+			})
+			require.Len(t, imported.Signals, 2)
+			assert.Equal(t, 2, imported.Signals[0].Batches)
+
+			require.Len(t, target.recentTraces, 2)
+			assert.Equal(t, "span-1", target.recentTraces[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+			require.Len(t, target.recentMetrics, 1)
+			assert.Equal(t, int64(7), target.recentMetrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).IntValue())
+		})
+	}
+
+	t.Run("path", func(t *testing.T) {
+		source.dumpDir = t.TempDir()
+		defer func() { source.dumpDir = "" }()
+		exported := callToolOutput[tools.ExportBufferOutput](t, session, "export_buffer", map[string]any{"path": "repro/1"})
+		assert.Equal(t, filepath.Join(source.dumpDir, "repro", "1", "traces.jsonl"), exported.Signals[0].File)
+		assert.Empty(t, exported.Signals[0].Data)
+		assert.Empty(t, exported.Signals[2].File)
+
+		target := newMockExtensionContext()
+		target.dumpDir = source.dumpDir
+		imported := callToolOutput[tools.ImportBufferOutput](t, connect(t, target), "import_buffer", map[string]any{"path": "repro/1"})
+		require.Len(t, imported.Signals, 2, "missing logs file is skipped")
+		assert.Len(t, target.recentTraces, 2)
+		assert.Len(t, target.recentMetrics, 1)
+
+		result, err := callTool(session, "export_buffer", map[string]any{"path": "repro/1"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error when the dump files exist")
+		}
+		data, err := os.ReadFile(exported.Signals[0].File)
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(data), "\n"), "existing dump is left untouched")
+	})
+
+	t.Run("path_outside_dump_dir", func(t *testing.T) {
+		source.dumpDir = t.TempDir()
+		defer func() { source.dumpDir = "" }()
+		outside := t.TempDir()
+		for name, path := range map[string]string{
+			"absolute":  outside,
+			"parent":    "../escape",
+			"dot_dot":   "repro/../../escape",
+			"traversal": "..",
+		} {
+			for _, tool := range []string{"export_buffer", "import_buffer"} {
+				result, err := callTool(session, tool, map[string]any{"path": path})
+				if err == nil {
+					assert.True(t, result.IsError, "expected %s error for %s path", tool, name)
+				}
+			}
+		}
+		entries, err := os.ReadDir(outside)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("path_without_dump_dir", func(t *testing.T) {
+		for _, tool := range []string{"export_buffer", "import_buffer"} {
+			result, err := callTool(session, tool, map[string]any{"path": "repro"})
+			if err == nil {
+				assert.True(t, result.IsError, "expected %s error without dump_dir", tool)
+			}
+		}
+	})
+
+	t.Run("invalid_input", func(t *testing.T) {
+		for name, args := range map[string]map[string]any{
+			"no_source":     {},
+			"absolute_path": {"path": "/tmp"},
+			"bad_format":    {"format": "csv", "traces": "e30="},
+			"bad_base64":    {"traces": "not base64!"},
+			"bad_otlp":      {"traces": base64.StdEncoding.EncodeToString([]byte("not json\n"))},
+		} {
+			result, err := callTool(session, "import_buffer", args)
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for %s", name)
+			}
+		}
+		assert.Len(t, source.recentTraces, 2, "failed imports add nothing")
+	})
+}
+
+func TestQueryTracesSlowerThanPercentile(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	addSpan := func(name string, id byte, duration time.Duration) {
+		span := spans.AppendEmpty()
+		span.SetName(name)
+		span.SetTraceID(pcommon.TraceID([16]byte{id}))
+		span.SetSpanID(pcommon.SpanID([8]byte{id}))
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(duration)))
+	}
+	// A fast operation with one outlier and a uniformly slow one
+	for i := range 10 {
+		addSpan("GET /cart", byte(i+1), time.Duration(10+i)*time.Millisecond)
+		addSpan("SELECT orders", byte(i+20), 500*time.Millisecond)
+	}
+	addSpan("GET /cart", 40, 200*time.Millisecond)
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	t.Run("outlier_only", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"slower_than_percentile": 90,
+		})
+		assert.Equal(t, 1, out.SpanCount)
+		assert.Contains(t, out.Markdown, "GET /cart")
+		assert.NotContains(t, out.Markdown, "SELECT orders")
+	})
+
+	t.Run("absolute_threshold_for_comparison", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"min_duration": "100ms",
+		})
+		assert.Equal(t, 11, out.SpanCount)
+	})
+
+	t.Run("lower_percentile", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"slower_than_percentile": 50,
+			"span_name":              "GET",
+		})
+		// p50 of 11 spans is the 6th fastest (15ms)
+		assert.Equal(t, 5, out.SpanCount)
+	})
+
+	t.Run("baseline_per_service", func(t *testing.T) {
+		// Another service's slow spans of the same name must not raise the
+		// baseline of the first service above its outlier
+		mixedTraces := ptrace.NewTraces()
+		td.CopyTo(mixedTraces)
+		other := mixedTraces.ResourceSpans().AppendEmpty()
+		other.Resource().Attributes().PutStr("service.name", "admin")
+		otherSpans := other.ScopeSpans().AppendEmpty().Spans()
+		for i := range 10 {
+			span := otherSpans.AppendEmpty()
+			span.SetName("GET /cart")
+			span.SetTraceID(pcommon.TraceID([16]byte{byte(i + 50)}))
+			span.SetSpanID(pcommon.SpanID([8]byte{byte(i + 50)}))
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+			span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(300 * time.Millisecond)))
+		}
+		mixedCtx := newMockExtensionContext()
+		mixedCtx.recentTraces = []ptrace.Traces{mixedTraces}
+		mixed := connectTestClient(t, mixedCtx, tools.RegisterQueryTraces)
+
+		out := callToolOutput[tools.QueryTracesOutput](t, mixed, "query_traces", map[string]any{
+			"slower_than_percentile": 90,
+			"span_name":              "GET /cart",
+		})
+		assert.Equal(t, 1, out.SpanCount)
+		assert.Contains(t, out.Markdown, "200")
+		assert.NotContains(t, out.Markdown, "admin")
+	})
+
+	t.Run("invalid_percentile", func(t *testing.T) {
+		for _, p := range []float64{-1, 100} {
+			result, err := callTool(session, "query_traces", map[string]any{"slower_than_percentile": p})
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for percentile %g", p)
+			}
+		}
+	})
+}
+
+func TestMetricRate(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	newCounter := func(name string) pmetric.Metric {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "checkout")
+		metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName(name)
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.SetIsMonotonic(true)
+		mockCtx.recentMetrics = append(mockCtx.recentMetrics, md)
+		return metric
+	}
+	addPoint := func(metric pmetric.Metric, route string, offset time.Duration, value int64) {
+		dp := metric.Sum().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("http.route", route)
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+		dp.SetIntValue(value)
+	}
+
+	// One point per batch, as exported periodically; /cart resets after 20s
+	for i, value := range []int64{0, 10, 30, 5, 25} {
+		metric := newCounter("http.server.requests")
+		addPoint(metric, "/cart", time.Duration(i)*10*time.Second, value)
+		addPoint(metric, "/health", time.Duration(i)*10*time.Second, int64(i)*100)
+	}
+	gauge := mockCtx.recentMetrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().AppendEmpty()
+	gauge.SetName("queue.size")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(3)
+	deltaSum := newCounter("bytes.sent")
+	deltaSum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+	// Two instances of the service export the same counter at different values
+	for i := range 3 {
+		for instance, base := range map[string]int64{"a": 0, "b": 1000} {
+			metric := newCounter("orders.placed")
+			mockCtx.recentMetrics[len(mockCtx.recentMetrics)-1].ResourceMetrics().At(0).Resource().Attributes().PutStr("service.instance.id", instance)
+			addPoint(metric, "/order", time.Duration(i)*10*time.Second, base+int64(i)*10)
+		}
+	}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterMetricRate)
+
+	t.Run("series_per_attributes", func(t *testing.T) {
+		out := callToolOutput[tools.MetricRateOutput](t, session, "metric_rate", map[string]any{
+			"metric_name": "http.server.requests",
+		})
+		assert.True(t, out.Monotonic)
+		require.Len(t, out.Series, 2)
+		assert.Equal(t, map[string]string{"http.route": "/cart"}, out.Series[0].Attributes)
+		health := out.Series[1]
+		assert.Equal(t, 5, health.DataPoints)
+		assert.Len(t, health.Intervals, 4)
+		assert.InDelta(t, 10.0, health.AverageRatePerSecond, 1e-9)
+	})
+
+	t.Run("counter_reset", func(t *testing.T) {
+		out := callToolOutput[tools.MetricRateOutput](t, session, "metric_rate", map[string]any{
+			"metric_name": "http.server.requests",
+			"attributes":  map[string]string{"http.route": "/cart"},
+		})
+		require.Len(t, out.Series, 1)
+		cart := out.Series[0]
+		assert.Equal(t, 1, cart.Resets)
+		require.Len(t, cart.Intervals, 3)
+		assert.InDelta(t, 1.0, cart.Intervals[0].RatePerSecond, 1e-9)
+		assert.InDelta(t, 2.0, cart.Intervals[1].RatePerSecond, 1e-9)
+		assert.Equal(t, "2025-01-02T03:04:35Z", cart.Intervals[2].Start, "interval across the reset is skipped")
+		// (10 + 20 + 20) over 30s of non-reset intervals
+		assert.InDelta(t, 50.0/30.0, cart.AverageRatePerSecond, 1e-9)
+	})
+
+	t.Run("series_per_resource", func(t *testing.T) {
+		out := callToolOutput[tools.MetricRateOutput](t, session, "metric_rate", map[string]any{
+			"metric_name": "orders.placed",
+		})
+		require.Len(t, out.Series, 2)
+		for i, instance := range []string{"a", "b"} {
+			s := out.Series[i]
+			assert.Equal(t, "checkout", s.ServiceName)
+			assert.Equal(t, instance, s.ResourceAttributes["service.instance.id"])
+			assert.Equal(t, 3, s.DataPoints)
+			assert.Zero(t, s.Resets)
+			assert.InDelta(t, 1.0, s.AverageRatePerSecond, 1e-9)
+		}
+	})
+
+	t.Run("unknown_metric", func(t *testing.T) {
+		out := callToolOutput[tools.MetricRateOutput](t, session, "metric_rate", map[string]any{
+			"metric_name": "missing",
+		})
+		assert.Empty(t, out.Series)
+	})
+
+	t.Run("not_a_cumulative_sum", func(t *testing.T) {
+		for _, name := range []string{"queue.size", "bytes.sent", ""} {
+			result, err := callTool(session, "metric_rate", map[string]any{"metric_name": name})
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for metric %q", name)
+			}
+		}
+	})
+}
+
+func TestTimestampTimezone(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	mockCtx.location = tokyo
+
+	ts := pcommon.NewTimestampFromTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	ld := plog.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTimestamp(ts)
+	lr.Body().SetStr("checkout failed")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("queue.size")
+	dp := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(3)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs, tools.RegisterQueryMetrics)
+
+	t.Run("configured_timezone", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"format":       "plain",
+			"plain_prefix": true,
+		})
+		assert.Contains(t, out.Markdown, "2025-01-02T12:04:05+09:00")
+	})
+
+	t.Run("input_override", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"timezone": "UTC",
+		})
+		assert.Contains(t, out.Markdown, "03:04:05.000")
+
+		metrics := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"format":   "csv",
+			"timezone": "UTC",
+		})
+		assert.Contains(t, metrics.CSV, "2025-01-02T03:04:05Z")
+	})
+
+	t.Run("invalid_override", func(t *testing.T) {
+		result, err := callTool(session, "query_logs", map[string]any{"timezone": "Mars/Olympus_Mons"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for unknown timezone")
+		}
+	})
+}
+
+func TestDescribeResources(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	setResource := func(res pcommon.Resource, service, host string) {
+		res.Attributes().PutStr("service.name", service)
+		res.Attributes().PutStr("host.name", host)
+	}
+	// checkout on host-a is buffered in two trace batches and in logs
+	for range 2 {
+		td := ptrace.NewTraces()
+		setResource(td.ResourceSpans().AppendEmpty().Resource(), "checkout", "host-a")
+		mockCtx.recentTraces = append(mockCtx.recentTraces, td)
+	}
+	td := ptrace.NewTraces()
+	setResource(td.ResourceSpans().AppendEmpty().Resource(), "cart", "host-b")
+	mockCtx.recentTraces = append(mockCtx.recentTraces, td)
+
+	ld := plog.NewLogs()
+	setResource(ld.ResourceLogs().AppendEmpty().Resource(), "checkout", "host-a")
+	rl := ld.ResourceLogs().AppendEmpty()
+	setResource(rl.Resource(), "checkout", "host-c")
+	rl.Resource().Attributes().PutStr("deployment.environment", "prod")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterDescribeResources)
+
+	t.Run("all_signals", func(t *testing.T) {
+		out := callToolOutput[tools.DescribeResourcesOutput](t, session, "describe_resources", map[string]any{})
+		assert.Equal(t, 3, out.ResourceCount)
+		require.Len(t, out.Attributes, 3)
+
+		env := out.Attributes[0]
+		assert.Equal(t, "deployment.environment", env.Key)
+		assert.Equal(t, 1, env.Resources)
+		assert.Equal(t, []string{"logs"}, env.Signals)
+
+		host := out.Attributes[1]
+		assert.Equal(t, "host.name", host.Key)
+		assert.Equal(t, 3, host.DistinctValues)
+
+		service := out.Attributes[2]
+		assert.Equal(t, "service.name", service.Key)
+		assert.Equal(t, 3, service.Resources)
+		assert.Equal(t, 2, service.DistinctValues)
+		assert.Equal(t, []string{"checkout", "cart"}, service.Samples, "most shared value first")
+		assert.Equal(t, []string{"traces", "logs"}, service.Signals)
+	})
+
+	t.Run("single_signal_with_samples", func(t *testing.T) {
+		out := callToolOutput[tools.DescribeResourcesOutput](t, session, "describe_resources", map[string]any{
+			"signal":  "traces",
+			"samples": 1,
+		})
+		assert.Equal(t, 2, out.ResourceCount)
+		require.Len(t, out.Attributes, 2)
+		assert.Equal(t, []string{"cart"}, out.Attributes[1].Samples)
+	})
+
+	t.Run("invalid_signal", func(t *testing.T) {
+		result, err := callTool(session, "describe_resources", map[string]any{"signal": "profiles"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for invalid signal")
+		}
+	})
+}
+
+func TestAttributePrefixFilters(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	rs.Resource().Attributes().PutStr("telemetry.sdk.name", "opentelemetry")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /cart")
+	span.SetTraceID(pcommon.TraceID([16]byte{1}))
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	span.Attributes().PutStr("http.method", "GET")
+	span.Attributes().PutStr("http.route", "/cart")
+	span.Attributes().PutStr("db.system", "redis")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Body().SetStr("cart loaded")
+	lr.Attributes().PutStr("http.route", "/cart")
+	lr.Attributes().PutStr("thread.id", "7")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces, tools.RegisterQueryLogs)
+
+	t.Run("include", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"max_attr_length":            0,
+			"attribute_include_prefixes": []string{"http."},
+		})
+		assert.Contains(t, out.Markdown, "http.method=GET")
+		assert.Contains(t, out.Markdown, "http.route=/cart")
+		assert.NotContains(t, out.Markdown, "db.system")
+	})
+
+	t.Run("exclude_wins", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"detailed":                   true,
+			"attribute_include_prefixes": []string{"http.", "telemetry."},
+			"attribute_exclude_prefixes": []string{"http.method", "telemetry.sdk."},
+		})
+		assert.Contains(t, out.Markdown, "| http.route | /cart |")
+		assert.NotContains(t, out.Markdown, "http.method")
+		assert.NotContains(t, out.Markdown, "db.system")
+		// No resource attribute is left, so the table is omitted
+		assert.NotContains(t, out.Markdown, "Resource Attributes")
+	})
+
+	t.Run("records_still_match", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"attribute_exclude_prefixes": []string{"thread."},
+		})
+		assert.Contains(t, out.Markdown, "cart loaded")
+		assert.Contains(t, out.Markdown, "http.route=/cart")
+		assert.NotContains(t, out.Markdown, "thread.id")
+	})
+
+	t.Run("included_keys_past_attribute_cap", func(t *testing.T) {
+		// The included keys come after more than 5 other attributes, on the
+		// span and on its event
+		crowded := ptrace.NewTraces()
+		crowdedRS := crowded.ResourceSpans().AppendEmpty()
+		crowdedRS.Resource().Attributes().PutStr("service.name", "checkout")
+		crowdedSpan := crowdedRS.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		crowdedSpan.SetName("GET /cart")
+		crowdedSpan.SetTraceID(pcommon.TraceID([16]byte{2}))
+		crowdedSpan.SetSpanID(pcommon.SpanID([8]byte{2}))
+		event := crowdedSpan.Events().AppendEmpty()
+		event.SetName("retry")
+		for i := range 6 {
+			crowdedSpan.Attributes().PutStr(fmt.Sprintf("app.key%d", i), "x")
+			event.Attributes().PutStr(fmt.Sprintf("app.key%d", i), "x")
+		}
+		crowdedSpan.Attributes().PutStr("http.route", "/cart")
+		event.Attributes().PutStr("http.attempt", "2")
+
+		crowdedCtx := newMockExtensionContext()
+		crowdedCtx.recentTraces = []ptrace.Traces{crowded}
+		crowdedSession := connectTestClient(t, crowdedCtx, tools.RegisterQueryTraces)
+
+		out := callToolOutput[tools.QueryTracesOutput](t, crowdedSession, "query_traces", map[string]any{
+			"max_attr_length":            0,
+			"include_events":             true,
+			"attribute_include_prefixes": []string{"http."},
+		})
+		assert.Contains(t, out.Markdown, "http.route=/cart")
+		assert.Contains(t, out.Markdown, "http.attempt=2")
+		assert.NotContains(t, out.Markdown, "app.key")
+
+		// attribute_keys projections are filtered by the prefixes as well
+		out = callToolOutput[tools.QueryTracesOutput](t, crowdedSession, "query_traces", map[string]any{
+			"attribute_keys":             []string{"app.key0", "http.route"},
+			"attribute_include_prefixes": []string{"http."},
+		})
+		assert.Contains(t, out.Markdown, "http.route=/cart")
+		assert.NotContains(t, out.Markdown, "app.key0")
+	})
+}
+
+func TestRootOnly(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	addSpan := func(td ptrace.Traces, name string, traceID byte, spanID, parentID byte) {
+		span := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().AppendEmpty()
+		span.SetName(name)
+		span.SetTraceID(pcommon.TraceID([16]byte{traceID}))
+		span.SetSpanID(pcommon.SpanID([8]byte{spanID}))
+		if parentID != 0 {
+			span.SetParentSpanID(pcommon.SpanID([8]byte{parentID}))
+		}
+	}
+	newBatch := func() ptrace.Traces {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", "checkout")
+		rs.ScopeSpans().AppendEmpty()
+		return td
+	}
+
+	// The root of trace 1 is buffered in an older batch than its children
+	older := newBatch()
+	addSpan(older, "GET /cart", 1, 1, 0)
+	newer := newBatch()
+	addSpan(newer, "load cart", 1, 2, 1)
+	addSpan(newer, "redis GET", 1, 3, 2)
+	// Trace 2 lost its root, so its top buffered span is treated as the root
+	addSpan(newer, "POST /checkout handler", 2, 5, 4)
+	addSpan(newer, "charge card", 2, 6, 5)
+	// Newest first, like the buffer
+	mockCtx.recentTraces = []ptrace.Traces{newer, older}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetRecentTraces, tools.RegisterQueryTraces)
+
+	t.Run("query_traces", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{"root_only": true})
+		assert.Equal(t, 2, out.SpanCount)
+		assert.Contains(t, out.Markdown, "GET /cart")
+		assert.Contains(t, out.Markdown, "POST /checkout handler")
+		assert.NotContains(t, out.Markdown, "redis GET")
+
+		out = callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"root_only":      true,
+			"root_span_name": "checkout",
+		})
+		assert.Equal(t, 1, out.SpanCount)
+
+		// The root_spans_only alias does not need root_span_name either
+		out = callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{"root_spans_only": true})
+		assert.Equal(t, 2, out.SpanCount)
+	})
+
+	t.Run("get_recent_traces", func(t *testing.T) {
+		out := callToolOutput[tools.TracesOutput](t, session, "get_recent_traces", map[string]any{"root_only": true})
+		assert.Contains(t, out.CSV, "GET /cart")
+		assert.Contains(t, out.CSV, "POST /checkout handler")
+		assert.NotContains(t, out.CSV, "load cart")
+		assert.Equal(t, []string{"Total root spans: 2 across 2 batches"}, out.Traces)
+
+		// The page only holds the newer batch, whose "load cart" parent is
+		// still found in the buffer
+		out = callToolOutput[tools.TracesOutput](t, session, "get_recent_traces", map[string]any{
+			"root_only": true,
+			"limit":     1,
+		})
+		assert.NotContains(t, out.CSV, "load cart")
+		assert.Contains(t, out.CSV, "POST /checkout handler")
+	})
+}
+
+func TestBreakdownSpans(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	addSpan := func(name string, status int64, duration time.Duration) {
+		span := spans.AppendEmpty()
+		span.SetName(name)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(duration)))
+		if status != 0 {
+			span.Attributes().PutInt("http.status_code", status)
+		}
+	}
+	addSpan("GET /checkout", 200, 10*time.Millisecond)
+	addSpan("GET /checkout", 200, 30*time.Millisecond)
+	addSpan("GET /checkout", 500, 100*time.Millisecond)
+	addSpan("GET /checkout", 0, time.Millisecond)
+	addSpan("GET /cart", 404, time.Millisecond)
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterBreakdownSpans)
+
+	t.Run("counts", func(t *testing.T) {
+		out := callToolOutput[tools.BreakdownSpansOutput](t, session, "breakdown_spans", map[string]any{
+			"span_name":     "GET /checkout",
+			"attribute_key": "http.status_code",
+		})
+		assert.Equal(t, 4, out.SpanCount)
+		assert.Equal(t, 1, out.Missing)
+		assert.Equal(t, 2, out.TotalDistinct)
+		assert.Equal(t, []tools.SpanBreakdownValue{
+			{Value: "200", Count: 2},
+			{Value: "500", Count: 1},
+		}, out.Values)
+	})
+
+	t.Run("durations", func(t *testing.T) {
+		out := callToolOutput[tools.BreakdownSpansOutput](t, session, "breakdown_spans", map[string]any{
+			"span_name":     "GET /checkout",
+			"attribute_key": "http.status_code",
+			"durations":     true,
+			"limit":         1,
+		})
+		require.Len(t, out.Values, 1)
+		assert.Equal(t, 2, out.TotalDistinct)
+		assert.InDelta(t, 20.0, out.Values[0].AvgDurationMs, 0.001)
+		assert.InDelta(t, 30.0, out.Values[0].P95DurationMs, 0.001)
+	})
+
+	t.Run("missing_attribute_key", func(t *testing.T) {
+		result, err := callTool(session, "breakdown_spans", map[string]any{"span_name": "GET /checkout"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error without attribute_key")
+		}
+	})
+}
+
+func TestListCapabilities(t *testing.T) {
+	ctx := context.Background()
+	mockCtx := newMockExtensionContext()
+	mockCtx.toolGroups = map[string]string{
+		"query_traces":      "telemetry",
+		"evict_trace":       "telemetry",
+		"list_capabilities": "discovery",
+	}
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterQueryTraces,
+		tools.RegisterEvictTrace,
+		tools.RegisterListCapabilities,
+	)
+
+	listed, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	mockCtx.registeredTools = listed.Tools
+
+	t.Run("all", func(t *testing.T) {
+		out := callToolOutput[tools.ListCapabilitiesOutput](t, session, "list_capabilities", map[string]any{})
+		require.Equal(t, 3, out.Count)
+		names := make([]string, 0, len(out.Tools))
+		for _, tool := range out.Tools {
+			names = append(names, tool.Name)
+		}
+		assert.Equal(t, []string{"evict_trace", "list_capabilities", "query_traces"}, names)
+
+		evict := out.Tools[0]
+		assert.Equal(t, "telemetry", evict.Group)
+		assert.False(t, evict.ReadOnly)
+		assert.NotEmpty(t, evict.Description)
+
+		query := out.Tools[2]
+		assert.True(t, query.ReadOnly)
+		schema, ok := query.InputSchema.(map[string]any)
+		require.True(t, ok)
+		properties, ok := schema["properties"].(map[string]any)
+		require.True(t, ok)
+		assert.Contains(t, properties, "service_name")
+		assert.NotNil(t, query.OutputSchema)
+	})
+
+	t.Run("group", func(t *testing.T) {
+		out := callToolOutput[tools.ListCapabilitiesOutput](t, session, "list_capabilities", map[string]any{"group": "discovery"})
+		require.Len(t, out.Tools, 1)
+		assert.Equal(t, "list_capabilities", out.Tools[0].Name)
+	})
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.maxResponseBytes = 1000
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := range 50 {
+		records.AppendEmpty().Body().SetStr(fmt.Sprintf("request %d handled", i))
+	}
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	for i := range 50 {
+		metric := metrics.AppendEmpty()
+		metric.SetName(fmt.Sprintf("queue.size.%d", i))
+		metric.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(int64(i))
+	}
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs, tools.RegisterQueryMetrics)
+
+	// callLimited returns the text content block of a tool call and its
+	// structured output, checking the whole result stays near the text's size
+	callLimited := func(t *testing.T, name string, args map[string]any) (string, map[string]any) {
+		t.Helper()
+		result, err := callTool(session, name, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, "tool %s returned error: %v", name, result.Content)
+		require.Len(t, result.Content, 1)
+		text, ok := result.Content[0].(*mcp.TextContent)
+		require.True(t, ok)
+
+		raw, err := json.Marshal(result.StructuredContent)
+		require.NoError(t, err)
+		assert.Less(t, len(raw), 200, "structured output must not repeat the rendered output")
+		var structured map[string]any
+		require.NoError(t, json.Unmarshal(raw, &structured))
+		assert.NotContains(t, structured, "markdown")
+		assert.NotContains(t, structured, "csv")
+		return text.Text, structured
+	}
+
+	t.Run("configured_limit", func(t *testing.T) {
+		text, structured := callLimited(t, "query_logs", map[string]any{})
+		assert.EqualValues(t, 50, structured["log_count"])
+		assert.Equal(t, true, structured["output_truncated"])
+		assert.LessOrEqual(t, len(text), 1000)
+		assert.True(t, strings.HasSuffix(text, "|\n\n[output truncated, refine your query]\n"), "output should end with a whole row and the marker")
+	})
+
+	t.Run("call_lowers_limit", func(t *testing.T) {
+		text, _ := callLimited(t, "query_logs", map[string]any{"max_response_bytes": 300})
+		assert.LessOrEqual(t, len(text), 300)
+
+		// Larger values are capped by the configured limit
+		text, _ = callLimited(t, "query_logs", map[string]any{"max_response_bytes": 1 << 20})
+		assert.LessOrEqual(t, len(text), 1000)
+	})
+
+	t.Run("limit_below_marker", func(t *testing.T) {
+		text, _ := callLimited(t, "query_logs", map[string]any{"max_response_bytes": 10})
+		assert.Equal(t, "\n[output t", text)
+	})
+
+	t.Run("fits", func(t *testing.T) {
+		text, structured := callLimited(t, "query_logs", map[string]any{"limit": 2})
+		assert.NotContains(t, structured, "output_truncated")
+		assert.NotContains(t, text, "output truncated")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		text, structured := callLimited(t, "query_metrics", map[string]any{
+			"format":             "csv",
+			"max_response_bytes": 500,
+		})
+		assert.Equal(t, true, structured["output_truncated"])
+		assert.LessOrEqual(t, len(text), 500)
+		rows, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+		require.NoError(t, err, "CSV is cut after a whole row")
+		assert.Less(t, len(rows), 51)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		mockCtx.maxResponseBytes = 0
+		defer func() { mockCtx.maxResponseBytes = 1000 }()
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{})
+		assert.False(t, out.OutputTruncated)
+		assert.Contains(t, out.Markdown, "request 49 handled", "without a limit the structured output keeps the markdown")
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		result, err := callTool(session, "query_logs", map[string]any{"max_response_bytes": -1})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for negative max_response_bytes")
+		}
+	})
+}
+
+func TestQueryTracesStatusMessage(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	addSpan := func(name string, code ptrace.StatusCode, message string) {
+		span := spans.AppendEmpty()
+		span.SetName(name)
+		span.Status().SetCode(code)
+		span.Status().SetMessage(message)
+	}
+	addSpan("charge card", ptrace.StatusCodeError, "dial tcp 10.0.0.7:443: connection refused by payment gateway")
+	addSpan("load cart", ptrace.StatusCodeError, "redis: nil | cache miss")
+	addSpan("render page", ptrace.StatusCodeOk, "connection refused but retried")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	t.Run("filter", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"status_message": "Connection Refused",
+		})
+		assert.Equal(t, 2, out.SpanCount)
+		assert.Contains(t, out.Markdown, "charge card")
+		assert.Contains(t, out.Markdown, "render page")
+
+		out = callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"status_message": "connection refused",
+			"status":         "Error",
+		})
+		assert.Equal(t, 1, out.SpanCount)
+	})
+
+	t.Run("summary_rows", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{})
+		// Error messages are truncated and pipes escaped, other statuses show the code only
+		assert.Contains(t, out.Markdown, "| Error: dial tcp 10.0.0.7:443: connection refuse... |")
+		assert.Contains(t, out.Markdown, `| Error: redis: nil \| cache miss |`)
+		assert.Contains(t, out.Markdown, "| Ok |")
+		assert.NotContains(t, out.Markdown, "retried")
+	})
+}
+
+func TestTelemetrySummarySpanLatency(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	start := time.Unix(1700000000, 0)
+	for _, d := range []time.Duration{time.Millisecond, 5 * time.Millisecond, 50 * time.Millisecond, 2 * time.Second, 10 * time.Second} {
+		span := spans.AppendEmpty()
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(d)))
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTelemetrySummary)
+
+	t.Run("histogram", func(t *testing.T) {
+		out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{
+			"include_span_latency": true,
+		})
+		require.Len(t, out.SpanLatency, 5)
+		counts := make(map[string]int)
+		for _, bucket := range out.SpanLatency {
+			counts[bucket.Label] = bucket.Count
+		}
+		assert.Equal(t, map[string]int{
+			"0s - 10ms":    2,
+			"10ms - 100ms": 1,
+			"100ms - 1s":   0,
+			"1s - 10s":     1,
+			">= 10s":       1,
+		}, counts)
+		assert.Nil(t, out.SpansByStatus)
+		assert.Nil(t, out.LogsBySeverity)
+	})
+
+	t.Run("not_requested", func(t *testing.T) {
+		out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{
+			"include_distributions": true,
+		})
+		assert.Nil(t, out.SpanLatency)
+		assert.Equal(t, 5, out.SpansByStatus["Unset"])
+	})
+}
+
+func TestQueryScopeName(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "frontend")
+	httpSpans := rs.ScopeSpans().AppendEmpty()
+	httpSpans.Scope().SetName("go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp")
+	httpSpans.Spans().AppendEmpty().SetName("GET /cart")
+	sqlSpans := rs.ScopeSpans().AppendEmpty()
+	sqlSpans.Scope().SetName("github.com/XSAM/otelsql")
+	sqlSpans.Spans().AppendEmpty().SetName("SELECT carts")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "frontend")
+	httpLogs := rl.ScopeLogs().AppendEmpty()
+	httpLogs.Scope().SetName("net/http")
+	httpLogs.LogRecords().AppendEmpty().Body().SetStr("request served")
+	appLogs := rl.ScopeLogs().AppendEmpty()
+	appLogs.Scope().SetName("frontend/cart")
+	appLogs.LogRecords().AppendEmpty().Body().SetStr("cart loaded")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "frontend")
+	httpMetrics := rm.ScopeMetrics().AppendEmpty()
+	httpMetrics.Scope().SetName("go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp")
+	m := httpMetrics.Metrics().AppendEmpty()
+	m.SetName("http.server.request.duration")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(1)
+	runtimeMetrics := rm.ScopeMetrics().AppendEmpty()
+	runtimeMetrics.Scope().SetName("go.opentelemetry.io/contrib/instrumentation/runtime")
+	m = runtimeMetrics.Metrics().AppendEmpty()
+	m.SetName("go.goroutines")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(12)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterQueryTraces,
+		tools.RegisterQueryLogs,
+		tools.RegisterQueryMetrics,
+	)
+
+	t.Run("query_traces", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"scope_name": "Net/HTTP",
+		})
+		assert.Equal(t, 1, out.SpanCount)
+		assert.Contains(t, out.Markdown, "GET /cart")
+		assert.NotContains(t, out.Markdown, "SELECT carts")
+	})
+
+	t.Run("query_logs", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"scope_name": "net/http",
+		})
+		assert.Equal(t, 1, out.LogCount)
+		assert.Contains(t, out.Markdown, "request served")
+		assert.NotContains(t, out.Markdown, "cart loaded")
+	})
+
+	t.Run("query_metrics", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"scope_name": "runtime",
+		})
+		assert.Equal(t, 1, out.MetricCount)
+		assert.Contains(t, out.Markdown, "go.goroutines")
+		assert.NotContains(t, out.Markdown, "http.server.request.duration")
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"scope_name": "grpc",
+		})
+		assert.Equal(t, 0, out.SpanCount)
+	})
+}
+
+func TestGetConfigValue(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetConfigValue)
+
+	t.Run("scalar", func(t *testing.T) {
+		out := callToolOutput[tools.GetConfigValueOutput](t, session, "get_config_value", map[string]any{
+			"key": "receivers::otlp::protocols::grpc::endpoint",
+		})
+		assert.Equal(t, "receivers::otlp::protocols::grpc::endpoint", out.Key)
+		assert.Equal(t, "0.0.0.0:4317", out.Value)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		out := callToolOutput[tools.GetConfigValueOutput](t, session, "get_config_value", map[string]any{
+			"key": "service::pipelines::traces::receivers",
+		})
+		assert.Equal(t, []any{"otlp"}, out.Value)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		out := callToolOutput[tools.GetConfigValueOutput](t, session, "get_config_value", map[string]any{
+			"key": "service::telemetry::logs",
+		})
+		assert.Equal(t, map[string]any{"level": "debug"}, out.Value)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		result, err := callTool(session, "get_config_value", map[string]any{"key": "receivers::otlp::protocols::http::endpoint"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for missing key")
+		}
+	})
+
+	t.Run("config_not_available", func(t *testing.T) {
+		mockCtx.conf = nil
+		defer func() { mockCtx.conf = newMockExtensionContext().conf }()
+
+		result, err := callTool(session, "get_config_value", map[string]any{"key": "receivers"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error without a config")
+		}
+	})
+}
+
+func TestFindRelatedTelemetryLinkedSpans(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	producerTrace := pcommon.TraceID([16]byte{1})
+	consumerTrace := pcommon.TraceID([16]byte{2})
+	jobTrace := pcommon.TraceID([16]byte{3})
+	otherTrace := pcommon.TraceID([16]byte{4})
+
+	td := ptrace.NewTraces()
+	addSpan := func(service string, traceID pcommon.TraceID, spanID byte, name string) ptrace.Span {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(pcommon.SpanID([8]byte{spanID}))
+		span.SetName(name)
+		return span
+	}
+	addSpan("producer", producerTrace, 1, "publish orders")
+	// A link within the trace is not a linked span
+	addSpan("producer", producerTrace, 2, "send").Links().AppendEmpty().SetTraceID(producerTrace)
+	link := addSpan("consumer", consumerTrace, 3, "process orders").Links().AppendEmpty()
+	link.SetTraceID(producerTrace)
+	link.SetSpanID(pcommon.SpanID([8]byte{1}))
+	addSpan("producer", producerTrace, 4, "schedule job").Links().AppendEmpty().SetTraceID(jobTrace)
+	addSpan("unrelated", otherTrace, 5, "noop").Links().AppendEmpty().SetTraceID(jobTrace)
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterFindRelatedTelemetry)
+
+	out := callToolOutput[tools.FindRelatedTelemetryOutput](t, session, "find_related_telemetry", map[string]any{
+		"trace_id": producerTrace.String(),
+	})
+	assert.Equal(t, 3, out.SpanCount)
+	require.Len(t, out.LinkedSpans, 2)
+	assert.Equal(t, tools.LinkedSpan{
+		Relationship:  "links_to_trace",
+		TraceID:       consumerTrace.String(),
+		SpanID:        pcommon.SpanID([8]byte{3}).String(),
+		Name:          "process orders",
+		Service:       "consumer",
+		LinkedTraceID: producerTrace.String(),
+		LinkedSpanID:  pcommon.SpanID([8]byte{1}).String(),
+	}, out.LinkedSpans[0])
+	assert.Equal(t, "linked_from_trace", out.LinkedSpans[1].Relationship)
+	assert.Equal(t, "schedule job", out.LinkedSpans[1].Name)
+	assert.Equal(t, jobTrace.String(), out.LinkedSpans[1].LinkedTraceID)
+
+	t.Run("span_id_only", func(t *testing.T) {
+		out := callToolOutput[tools.FindRelatedTelemetryOutput](t, session, "find_related_telemetry", map[string]any{
+			"span_id": pcommon.SpanID([8]byte{1}).String(),
+		})
+		assert.Empty(t, out.LinkedSpans)
+	})
+}
+
+func TestQueryMetricsUnitLabels(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	for name, unit := range map[string]string{
+		"memory.usage":     "By",
+		"request.duration": "ms",
+		"queue.depth":      "1",
+		"requests":         "{request}",
+		"network.io.rate":  "By/s",
+		"widgets":          "widget",
+	} {
+		m := metrics.AppendEmpty()
+		m.SetName(name)
+		m.SetUnit(unit)
+		m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	}
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryMetrics)
+
+	t.Run("summary", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{})
+		assert.Contains(t, out.Markdown, "| bytes (By) |")
+		assert.Contains(t, out.Markdown, "| milliseconds (ms) |")
+		assert.Contains(t, out.Markdown, "| count (1) |")
+		assert.Contains(t, out.Markdown, "| request count ({request}) |")
+		assert.Contains(t, out.Markdown, "| bytes per second (By/s) |")
+		assert.Contains(t, out.Markdown, "| widget |")
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_name": "memory.usage",
+			"detailed":    true,
+		})
+		assert.Contains(t, out.Markdown, "**Unit:** bytes (By)")
+	})
+
+	t.Run("csv_keeps_raw_unit", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"metric_name": "memory.usage",
+			"format":      "csv",
+		})
+		assert.Contains(t, out.CSV, "memory.usage,Gauge,By,api,")
+	})
+}
+
+func TestCSVDelimiterAndHeader(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("GET /cart")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetSeverityText("INFO")
+	lr.Body().SetStr("cart loaded")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("queue.size")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(3)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterGetRecentTraces,
+		tools.RegisterGetRecentLogs,
+		tools.RegisterQueryMetrics,
+	)
+
+	t.Run("traces_tsv", func(t *testing.T) {
+		out := callToolOutput[tools.TracesOutput](t, session, "get_recent_traces", map[string]any{
+			"csv_delimiter": "\t",
+		})
+		lines := strings.Split(strings.TrimSpace(out.CSV), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "trace_id\tspan_id\t"))
+		assert.Contains(t, lines[1], "\tGET /cart\tcheckout\t")
+	})
+
+	t.Run("logs_without_header", func(t *testing.T) {
+		out := callToolOutput[tools.LogsOutput](t, session, "get_recent_logs", map[string]any{
+			"csv_delimiter": ";",
+			"csv_header":    false,
+		})
+		lines := strings.Split(strings.TrimSpace(out.CSV), "\n")
+		require.Len(t, lines, 1)
+		assert.Contains(t, lines[0], ";INFO;cart loaded;")
+	})
+
+	t.Run("metrics_csv", func(t *testing.T) {
+		out := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"format":        "csv",
+			"csv_delimiter": "|",
+			"csv_header":    false,
+		})
+		lines := strings.Split(strings.TrimSpace(out.CSV), "\n")
+		require.Len(t, lines, 1)
+		assert.True(t, strings.HasPrefix(lines[0], "queue.size|Gauge||checkout|"))
+		assert.Empty(t, out.Markdown)
+	})
+
+	t.Run("invalid_delimiter", func(t *testing.T) {
+		for _, delimiter := range []string{",,", "\"", "\n"} {
+			result, err := callTool(session, "get_recent_traces", map[string]any{"csv_delimiter": delimiter})
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for delimiter %q", delimiter)
+			}
+		}
+	})
+}
+
+// singleFactory is a ComponentFactory providing one factory
+type singleFactory struct {
+	kind    component.Kind
+	factory component.Factory
+}
+
+func (f singleFactory) GetFactory(kind component.Kind, componentType component.Type) component.Factory {
+	if kind != f.kind || componentType != f.factory.Type() {
+		return nil
+	}
+	return f.factory
+}
+
+func TestGenerateComponentConfig(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.componentFactory = singleFactory{kind: component.KindExtension, factory: NewFactory()}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGenerateComponentConfig)
+
+	t.Run("defaults", func(t *testing.T) {
+		out := callToolOutput[tools.GenerateComponentConfigOutput](t, session, "generate_component_config", map[string]any{
+			"kind":           "extension",
+			"component_type": "mcp",
+			"name":           "local",
+		})
+		assert.Equal(t, "mcp/local", out.ComponentID)
+		assert.Equal(t, "*mcpextension.Config", out.ConfigType)
+		assert.True(t, strings.HasPrefix(out.YAML, "# Default config of the mcp extension (*mcpextension.Config)\n"))
+		assert.Contains(t, out.YAML, "extensions:\n  mcp/local:\n")
+		assert.Contains(t, out.YAML, "    endpoint: localhost:9999\n")
+		assert.Contains(t, out.YAML, "    read_timeout: 0s # duration\n")
+		assert.Contains(t, out.YAML, "    cors: null # no default\n")
+		assert.Contains(t, out.YAML, "    enabled_tools: []\n")
+
+		// The snippet unmarshals into a valid config holding the defaults
+		var parsed map[string]map[string]map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(out.YAML), &parsed))
+		cfg := &Config{}
+		require.NoError(t, confmap.NewFromStringMap(parsed["extensions"]["mcp/local"]).Unmarshal(cfg))
+		require.NoError(t, cfg.Validate())
+		defaults := createDefaultConfig().(*Config)
+		assert.Equal(t, defaults.Endpoint, cfg.Endpoint)
+		assert.Equal(t, defaults.TracesBufferSize, cfg.TracesBufferSize)
+		assert.Equal(t, defaults.EvictionPolicy, cfg.EvictionPolicy)
+		assert.Nil(t, cfg.CORS)
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		result, err := callTool(session, "generate_component_config", map[string]any{"kind": "extension", "component_type": "nope"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for a type without factory")
+		}
+	})
+
+	t.Run("invalid_name", func(t *testing.T) {
+		result, err := callTool(session, "generate_component_config", map[string]any{"kind": "extension", "component_type": "mcp", "name": "bad name/x"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for an invalid name")
+		}
+	})
+}
+
+func TestFindTracesByAttribute(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := ptrace.NewTraces()
+	frontend := td.ResourceSpans().AppendEmpty()
+	frontend.Resource().Attributes().PutStr("service.name", "frontend")
+	spans := frontend.ScopeSpans().AppendEmpty().Spans()
+	addSpan := func(spans ptrace.SpanSlice, traceID byte, name string, offset time.Duration, orderID string) {
+		span := spans.AppendEmpty()
+		span.SetTraceID(pcommon.TraceID([16]byte{traceID}))
+		span.SetSpanID(pcommon.SpanID([8]byte{traceID, byte(spans.Len())}))
+		span.SetName(name)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+		if orderID != "" {
+			span.Attributes().PutStr("order.id", orderID)
+		}
+	}
+	addSpan(spans, 1, "POST /orders", 0, "42")
+	addSpan(spans, 1, "validate", time.Second, "42")
+	addSpan(spans, 2, "POST /orders", time.Minute, "43")
+	addSpan(spans, 3, "POST /orders", 2*time.Minute, "42")
+
+	// Resource level: every span of the resource matches
+	worker := td.ResourceSpans().AppendEmpty()
+	worker.Resource().Attributes().PutStr("service.name", "worker")
+	worker.Resource().Attributes().PutStr("order.id", "42")
+	addSpan(worker.ScopeSpans().AppendEmpty().Spans(), 4, "process", 3*time.Minute, "")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterFindTracesByAttribute)
+
+	traceID := func(id byte) string { return pcommon.TraceID([16]byte{id}).String() }
+
+	t.Run("span_and_resource", func(t *testing.T) {
+		out := callToolOutput[tools.FindTracesByAttributeOutput](t, session, "find_traces_by_attribute", map[string]any{
+			"attribute_key": "order.id",
+			"value":         "42",
+		})
+		assert.Equal(t, 3, out.TotalTraces)
+		require.Len(t, out.Traces, 3)
+		assert.Equal(t, tools.TraceAttributeMatch{
+			TraceID:       traceID(1),
+			SpanID:        pcommon.SpanID([8]byte{1, 1}).String(),
+			SpanName:      "POST /orders",
+			Service:       "frontend",
+			Level:         "span",
+			Timestamp:     "2025-01-02T03:04:05Z",
+			MatchingSpans: 2,
+		}, out.Traces[0])
+		assert.Equal(t, traceID(3), out.Traces[1].TraceID)
+		assert.Equal(t, traceID(4), out.Traces[2].TraceID)
+		assert.Equal(t, "worker", out.Traces[2].Service)
+		assert.Equal(t, "resource", out.Traces[2].Level)
+	})
+
+	t.Run("time_range_and_limit", func(t *testing.T) {
+		out := callToolOutput[tools.FindTracesByAttributeOutput](t, session, "find_traces_by_attribute", map[string]any{
+			"attribute_key": "order.id",
+			"value":         "42",
+			"start_time":    "2025-01-02T03:05:00Z",
+			"limit":         1,
+		})
+		assert.Equal(t, 2, out.TotalTraces)
+		require.Len(t, out.Traces, 1)
+		assert.Equal(t, traceID(3), out.Traces[0].TraceID)
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		out := callToolOutput[tools.FindTracesByAttributeOutput](t, session, "find_traces_by_attribute", map[string]any{
+			"attribute_key": "order.id",
+			"value":         "99",
+		})
+		assert.Equal(t, 0, out.TotalTraces)
+		assert.Empty(t, out.Traces)
+	})
+
+	t.Run("missing_value", func(t *testing.T) {
+		result, err := callTool(session, "find_traces_by_attribute", map[string]any{"attribute_key": "order.id"})
+		if err == nil {
+			assert.True(t, result.IsError, "Expected error when value is missing")
+		}
+	})
+}
+
+func TestGetConfigResolved(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetConfig)
+
+	t.Run("plain_config_by_default", func(t *testing.T) {
+		out := callToolOutput[map[string]any](t, session, "get_config", map[string]any{})
+		assert.Equal(t, mockCtx.conf.ToStringMap(), out)
+		assert.NotContains(t, out, "resolved")
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		out := callToolOutput[tools.GetConfigOutput](t, session, "get_config", map[string]any{"check_references": true})
+		assert.True(t, out.Resolved)
+		assert.Empty(t, out.UnresolvedReferences)
+		assert.Equal(t, mockCtx.conf.ToStringMap(), out.Config)
+	})
+
+	t.Run("unresolved_references", func(t *testing.T) {
+		mockCtx.conf = confmap.NewFromStringMap(map[string]any{
+			"exporters": map[string]any{
+				"otlp": map[string]any{
+					"endpoint": "${env:OTLP_ENDPOINT}",
+					"headers":  map[string]any{"authorization": "Bearer ${env:TOKEN}"},
+				},
+			},
+			"processors": map[string]any{
+				"transform": map[string]any{
+					// Two escaped literals, then an escaped $ followed by a reference
+					"statements": []any{`set(attributes["x"], "$${env:LITERAL}")`, "$$$${env:ALSO_LITERAL}", "$$${env:HOST}"},
+				},
+			},
+			"service": map[string]any{
+				"pipelines": map[string]any{
+					"traces": map[string]any{"exporters": []any{"otlp", "${file:/etc/exporter}"}},
+				},
+			},
+		})
+		defer func() { mockCtx.conf = newMockExtensionContext().conf }()
+
+		out := callToolOutput[tools.GetConfigOutput](t, session, "get_config", map[string]any{"check_references": true})
+		assert.False(t, out.Resolved)
+		assert.Equal(t, []tools.UnresolvedReference{
+			{Key: "exporters::otlp::endpoint", Reference: "${env:OTLP_ENDPOINT}"},
+			{Key: "exporters::otlp::headers::authorization", Reference: "${env:TOKEN}"},
+			{Key: "processors::transform::statements::2", Reference: "${env:HOST}"},
+			{Key: "service::pipelines::traces::exporters::1", Reference: "${file:/etc/exporter}"},
+		}, out.UnresolvedReferences)
+
+		out = callToolOutput[tools.GetConfigOutput](t, session, "get_config", map[string]any{"section": "service", "check_references": true})
+		assert.False(t, out.Resolved)
+		require.Len(t, out.UnresolvedReferences, 1)
+		assert.Equal(t, "service::pipelines::traces::exporters::1", out.UnresolvedReferences[0].Key)
+	})
+}
+
+func TestGetTraceByIDMaxSpans(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.maxTraceSpans = 3
+
+	traceID := pcommon.TraceID([16]byte{1})
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "frontend")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 1; i <= 5; i++ {
+		span := spans.AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(pcommon.SpanID([8]byte{byte(i)}))
+		if i > 1 {
+			span.SetParentSpanID(pcommon.SpanID([8]byte{1}))
+		}
+		span.SetName(fmt.Sprintf("op-%d", i))
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(time.Millisecond)))
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTraceByID)
+
+	t.Run("over_limit", func(t *testing.T) {
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id": traceID.String(),
+		})
+		assert.True(t, out.Found)
+		assert.True(t, out.Truncated)
+		assert.Equal(t, 3, out.SpanCount)
+		assert.Equal(t, 5, out.TotalSpans)
+		assert.Contains(t, out.Markdown, "op-3")
+		assert.NotContains(t, out.Markdown, "op-4")
+		assert.Contains(t, out.Markdown, "showing 3 of 5 spans")
+	})
+
+	t.Run("within_limit", func(t *testing.T) {
+		mockCtx.maxTraceSpans = 5
+		defer func() { mockCtx.maxTraceSpans = 3 }()
+
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id": traceID.String(),
+		})
+		assert.False(t, out.Truncated)
+		assert.Equal(t, 5, out.SpanCount)
+		assert.Zero(t, out.TotalSpans)
+	})
+}
+
+func TestGetTraceByIDCacheHit(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	traceID := pcommon.TraceID([16]byte{1})
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(traceID)
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	span.SetName("GET /cart")
+	mockCtx.cachedTraces = map[string]ptrace.Traces{traceID.String(): td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTraceByID)
+
+	out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+		"trace_id": traceID.String(),
+	})
+	assert.True(t, out.FromCache)
+	assert.Equal(t, 1, out.SpanCount)
+	// A cache hit does not copy the buffer
+	assert.Zero(t, mockCtx.recentTracesCalls.Load())
+
+	out = callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+		"trace_id": pcommon.TraceID([16]byte{2}).String(),
+	})
+	assert.False(t, out.Found)
+	assert.Equal(t, int64(1), mockCtx.recentTracesCalls.Load())
+}
+
+func TestConfigDrift(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	session := connectTestClient(t, mockCtx, tools.RegisterConfigDrift)
+
+	t.Run("no_drift", func(t *testing.T) {
+		out := callToolOutput[tools.ConfigDriftOutput](t, session, "config_drift", map[string]any{
+			"baseline": mockCtx.conf.ToStringMap(),
+		})
+		assert.False(t, out.Drifted)
+		assert.Equal(t, 0, out.ChangeCount)
+		assert.Empty(t, out.Changes)
+	})
+
+	baseline := mockCtx.conf.ToStringMap()
+	baseline["receivers"] = map[string]any{
+		"otlp": map[string]any{
+			"protocols": map[string]any{
+				"grpc": map[string]any{"endpoint": "localhost:4317"},
+			},
+		},
+	}
+	baseline["exporters"] = map[string]any{
+		"debug":    map[string]any{},
+		"otlphttp": map[string]any{"endpoint": "https://backend:4318"},
+	}
+	service := baseline["service"].(map[string]any)
+	service["telemetry"] = map[string]any{
+		"logs":    map[string]any{"level": "info"},
+		"metrics": map[string]any{"level": "detailed"},
+	}
+	traces := service["pipelines"].(map[string]any)["traces"].(map[string]any)
+	traces["exporters"] = []any{"debug", "otlphttp"}
+
+	t.Run("drift", func(t *testing.T) {
+		out := callToolOutput[tools.ConfigDriftOutput](t, session, "config_drift", map[string]any{
+			"baseline": baseline,
+		})
+		assert.True(t, out.Drifted)
+		assert.Equal(t, 4, out.ChangeCount)
+		assert.Equal(t, map[string]int{"high": 2, "medium": 1, "low": 1}, out.Severities)
+		assert.Equal(t, []tools.ConfigChange{
+			{Key: "exporters::otlphttp", Section: "exporters", Change: "removed", Severity: "high", Baseline: map[string]any{"endpoint": "https://backend:4318"}},
+			{Key: "receivers::otlp::protocols::grpc::endpoint", Section: "receivers", Change: "changed", Severity: "medium", Baseline: "localhost:4317", Running: "0.0.0.0:4317"},
+			{Key: "service::pipelines::traces::exporters", Section: "service", Change: "changed", Severity: "high", Baseline: []any{"debug", "otlphttp"}, Running: []any{"debug"}},
+			{Key: "service::telemetry::logs::level", Section: "service", Change: "changed", Severity: "low", Baseline: "info", Running: "debug"},
+		}, out.Changes)
+	})
+
+	t.Run("section", func(t *testing.T) {
+		out := callToolOutput[tools.ConfigDriftOutput](t, session, "config_drift", map[string]any{
+			"baseline": baseline,
+			"section":  "receivers",
+		})
+		require.Len(t, out.Changes, 1)
+		assert.Equal(t, "receivers::otlp::protocols::grpc::endpoint", out.Changes[0].Key)
+	})
+
+	t.Run("missing_baseline", func(t *testing.T) {
+		result, err := callTool(session, "config_drift", map[string]any{})
+		if err == nil {
+			assert.True(t, result.IsError, "Expected error when baseline is missing")
+		}
+	})
+}
+
+func TestQueryTimeBounds(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for _, offset := range []time.Duration{time.Minute, 0, time.Hour} {
+		span := spans.AppendEmpty()
+		span.SetName("op")
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(offset + time.Millisecond)))
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	first := records.AppendEmpty()
+	first.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(30 * time.Second)))
+	first.Body().SetStr("first")
+	// Without a timestamp the observed time is used
+	observed := records.AppendEmpty()
+	observed.SetObservedTimestamp(pcommon.NewTimestampFromTime(start.Add(10 * time.Second)))
+	observed.Body().SetStr("observed")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces, tools.RegisterQueryLogs)
+
+	t.Run("traces", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{})
+		assert.Equal(t, "2025-01-02T03:04:05Z", out.Earliest)
+		assert.Equal(t, "2025-01-02T04:04:05Z", out.Latest)
+
+		out = callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"timezone": "Europe/Berlin",
+			"limit":    1,
+		})
+		// Structured timestamps stay in UTC
+		assert.Equal(t, "2025-01-02T03:05:05Z", out.Earliest)
+		assert.Equal(t, out.Earliest, out.Latest)
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{})
+		assert.Equal(t, "2025-01-02T03:04:15Z", out.Earliest)
+		assert.Equal(t, "2025-01-02T03:04:35Z", out.Latest)
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{"body": "missing"})
+		assert.Empty(t, out.Earliest)
+		assert.Empty(t, out.Latest)
+	})
+}
+
+func TestGetTraceByIDCollapseRepeats(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	traceID := pcommon.TraceID([16]byte{1})
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	addSpan := func(id, parent byte, name string, offset, duration time.Duration) ptrace.Span {
+		span := spans.AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(pcommon.SpanID([8]byte{id}))
+		if parent != 0 {
+			span.SetParentSpanID(pcommon.SpanID([8]byte{parent}))
+		}
+		span.SetName(name)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(offset + duration)))
+		return span
+	}
+	addSpan(1, 0, "GET /users", 0, 50*time.Millisecond)
+	addSpan(2, 1, "auth", time.Millisecond, time.Millisecond)
+	for i := byte(0); i < 3; i++ {
+		addSpan(3+i, 1, "SELECT user", time.Duration(10+i*5)*time.Millisecond, 2*time.Millisecond)
+	}
+	spans.At(spans.Len() - 1).Status().SetCode(ptrace.StatusCodeError)
+	addSpan(6, 4, "connect", 16*time.Millisecond, 500*time.Microsecond)
+	addSpan(7, 1, "render", 40*time.Millisecond, time.Millisecond)
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTraceByID)
+
+	t.Run("collapsed", func(t *testing.T) {
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id":         traceID.String(),
+			"collapse_repeats": true,
+		})
+		assert.Equal(t, 7, out.SpanCount)
+		assert.Equal(t, 1, strings.Count(out.Markdown, "SELECT user"))
+		assert.Contains(t, out.Markdown, "SELECT user ×3 | "+pcommon.SpanID([8]byte{3}).String()[:8]+" | 6.0ms total | 0.010s | Error |")
+		// Children of every span of the run stay visible
+		assert.Contains(t, out.Markdown, "connect")
+		assert.Contains(t, out.Markdown, "render")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id": traceID.String(),
+		})
+		assert.Equal(t, 3, strings.Count(out.Markdown, "SELECT user"))
+		assert.NotContains(t, out.Markdown, "×")
+	})
+}
+
+func TestGetMetricSeriesDetail(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	batch := func(offset time.Duration, value int64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "checkout")
+		rm.Resource().Attributes().PutStr("host.name", "node-1")
+		metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+		requests := metrics.AppendEmpty()
+		requests.SetName("http.server.requests")
+		requests.SetUnit("{request}")
+		sum := requests.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.SetIsMonotonic(true)
+		for i, route := range []string{"/cart", "/order"} {
+			dp := sum.DataPoints().AppendEmpty()
+			dp.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+			dp.SetIntValue(value * int64(i+1))
+			dp.Attributes().PutStr("http.route", route)
+			dp.Attributes().PutStr("http.request.method", "GET")
+		}
+
+		latency := metrics.AppendEmpty()
+		latency.SetName("http.server.duration")
+		hdp := latency.SetEmptyHistogram().DataPoints().AppendEmpty()
+		hdp.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+		hdp.SetCount(uint64(value))
+		hdp.SetSum(float64(value) * 1.5)
+		return md
+	}
+	// Buffered newest first to check points are ordered by timestamp
+	mockCtx.recentMetrics = []pmetric.Metrics{batch(2*time.Minute, 30), batch(0, 10), batch(time.Minute, 20)}
+
+	// Two hosts of the service report the same gauge without data point attributes
+	for i, host := range []string{"node-1", "node-2"} {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "checkout")
+		rm.Resource().Attributes().PutStr("host.name", host)
+		gauge := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		gauge.SetName("queue.size")
+		dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(start))
+		dp.SetIntValue(int64(i + 1))
+		mockCtx.recentMetrics = append(mockCtx.recentMetrics, md)
+	}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetMetricSeriesDetail)
+
+	value := func(v float64) *float64 { return &v }
+
+	t.Run("series", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricSeriesDetailOutput](t, session, "get_metric_series_detail", map[string]any{
+			"metric_name": "http.server.requests",
+			"points":      2,
+		})
+		assert.True(t, out.Found)
+		assert.Equal(t, "Sum (Cumulative, monotonic)", out.Type)
+		assert.Equal(t, "{request}", out.Unit)
+		assert.Equal(t, 2, out.SeriesCount)
+		require.Len(t, out.Series, 2)
+
+		cart := out.Series[0]
+		assert.Equal(t, "checkout", cart.ServiceName)
+		assert.Equal(t, map[string]string{"http.route": "/cart", "http.request.method": "GET"}, cart.Attributes)
+		assert.Equal(t, map[string]string{"service.name": "checkout", "host.name": "node-1"}, cart.ResourceAttributes)
+		assert.Equal(t, 3, cart.DataPoints)
+		assert.Equal(t, []tools.SeriesPoint{
+			{Timestamp: "2025-01-02T03:05:05Z", Value: value(20)},
+			{Timestamp: "2025-01-02T03:06:05Z", Value: value(30)},
+		}, cart.Latest)
+		assert.Equal(t, "/order", out.Series[1].Attributes["http.route"])
+		assert.Equal(t, value(60), out.Series[1].Latest[1].Value)
+	})
+
+	t.Run("attributes_and_limit", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricSeriesDetailOutput](t, session, "get_metric_series_detail", map[string]any{
+			"metric_name": "http.server.requests",
+			"attributes":  map[string]string{"http.request.method": "GET"},
+			"limit":       1,
+		})
+		assert.Equal(t, 2, out.SeriesCount)
+		require.Len(t, out.Series, 1)
+		assert.Len(t, out.Series[0].Latest, 3)
+	})
+
+	t.Run("series_per_resource", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricSeriesDetailOutput](t, session, "get_metric_series_detail", map[string]any{
+			"metric_name": "queue.size",
+		})
+		require.Len(t, out.Series, 2)
+		for i, host := range []string{"node-1", "node-2"} {
+			assert.Equal(t, host, out.Series[i].ResourceAttributes["host.name"])
+			assert.Equal(t, 1, out.Series[i].DataPoints)
+			assert.Equal(t, value(float64(i+1)), out.Series[i].Latest[0].Value)
+		}
+	})
+
+	t.Run("histogram", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricSeriesDetailOutput](t, session, "get_metric_series_detail", map[string]any{
+			"metric_name": "http.server.duration",
+			"points":      1,
+		})
+		require.Len(t, out.Series, 1)
+		require.Len(t, out.Series[0].Latest, 1)
+		latest := out.Series[0].Latest[0]
+		assert.Nil(t, latest.Value)
+		require.NotNil(t, latest.Count)
+		assert.Equal(t, uint64(30), *latest.Count)
+		assert.Equal(t, value(45), latest.Sum)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricSeriesDetailOutput](t, session, "get_metric_series_detail", map[string]any{
+			"metric_name": "missing",
+		})
+		assert.False(t, out.Found)
+		assert.Empty(t, out.Series)
+	})
+}
+
+func TestQueryLogsSort(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, entry := range []struct {
+		body     string
+		severity plog.SeverityNumber
+		offset   time.Duration
+	}{
+		{"error-early", plog.SeverityNumberError, time.Second},
+		{"warn", plog.SeverityNumberWarn, 2 * time.Second},
+		{"error-late", plog.SeverityNumberError, 3 * time.Second},
+		{"info-late", plog.SeverityNumberInfo, 4 * time.Second},
+		// Buffer order differs from time order
+		{"info-early", plog.SeverityNumberInfo, 0},
+	} {
+		lr := records.AppendEmpty()
+		lr.Body().SetStr(entry.body)
+		lr.SetSeverityNumber(entry.severity)
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(entry.offset)))
+	}
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryLogs)
+
+	bodies := func(args map[string]any) []string {
+		args["format"] = "plain"
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", args)
+		return strings.Fields(out.Markdown)
+	}
+
+	t.Run("buffer_order", func(t *testing.T) {
+		assert.Equal(t, []string{"error-early", "warn", "error-late", "info-late", "info-early"}, bodies(map[string]any{}))
+	})
+
+	t.Run("severity", func(t *testing.T) {
+		assert.Equal(t, []string{"error-late", "error-early", "warn", "info-late", "info-early"}, bodies(map[string]any{"sort_by": "severity"}))
+		assert.Equal(t, []string{"info-early", "info-late", "warn", "error-early", "error-late"}, bodies(map[string]any{"sort_by": "severity", "sort_order": "asc"}))
+	})
+
+	t.Run("time", func(t *testing.T) {
+		assert.Equal(t, []string{"info-late", "error-late", "warn", "error-early", "info-early"}, bodies(map[string]any{"sort_by": "time"}))
+		assert.Equal(t, []string{"info-early", "error-early", "warn", "error-late", "info-late"}, bodies(map[string]any{"sort_by": "time", "sort_order": "asc"}))
+	})
+
+	t.Run("offset_and_limit_after_sort", func(t *testing.T) {
+		assert.Equal(t, []string{"error-early", "warn"}, bodies(map[string]any{"sort_by": "severity", "offset": 1, "limit": 2}))
+		out := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{"sort_by": "severity", "offset": 10})
+		assert.Zero(t, out.LogCount)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, args := range []map[string]any{{"sort_by": "body"}, {"sort_by": "time", "sort_order": "up"}} {
+			result, err := callTool(session, "query_logs", args)
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for %v", args)
+			}
+		}
+	})
+}
+
+func TestGetTraceByIDMaxDepth(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	// A chain of 6 spans, each the parent of the next
+	traceID := pcommon.TraceID([16]byte{1})
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for id := byte(1); id <= 6; id++ {
+		span := spans.AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(pcommon.SpanID([8]byte{id}))
+		if id > 1 {
+			span.SetParentSpanID(pcommon.SpanID([8]byte{id - 1}))
+		}
+		span.SetName(fmt.Sprintf("level-%d", id))
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(time.Duration(id) * time.Millisecond)))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(10 * time.Millisecond)))
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTraceByID)
+
+	t.Run("capped", func(t *testing.T) {
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id":  traceID.String(),
+			"max_depth": 2,
+		})
+		assert.Equal(t, 6, out.SpanCount)
+		assert.Contains(t, out.Markdown, "level-2")
+		assert.NotContains(t, out.Markdown, "level-3")
+		assert.Contains(t, out.Markdown, "└─ … 4 deeper spans |")
+	})
+
+	t.Run("singular", func(t *testing.T) {
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id":  traceID.String(),
+			"max_depth": 5,
+		})
+		assert.Contains(t, out.Markdown, "level-5")
+		assert.Contains(t, out.Markdown, "… 1 deeper span |")
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		for _, maxDepth := range []int{0, 6} {
+			out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+				"trace_id":  traceID.String(),
+				"max_depth": maxDepth,
+			})
+			assert.Contains(t, out.Markdown, "level-6")
+			assert.NotContains(t, out.Markdown, "deeper")
+		}
+	})
+}
+
+// testReceiverConfig is the config of a receiver listening on two protocols
+type testReceiverConfig struct {
+	Protocols struct {
+		GRPC struct {
+			Endpoint string `mapstructure:"endpoint"`
+		} `mapstructure:"grpc"`
+		HTTP struct {
+			Endpoint string `mapstructure:"endpoint"`
+		} `mapstructure:"http"`
+	} `mapstructure:"protocols"`
+}
+
+func TestGetComponentEndpoints(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.conf = confmap.NewFromStringMap(map[string]any{
+		"receivers": map[string]any{
+			"otlp": map[string]any{
+				"protocols": map[string]any{
+					"http": map[string]any{"endpoint": "0.0.0.0:4318"},
+				},
+			},
+		},
+		"extensions": map[string]any{
+			"mcp": nil,
+		},
+	})
+	receiverFactory := receiver.NewFactory(component.MustNewType("otlp"), func() component.Config {
+		cfg := &testReceiverConfig{}
+		cfg.Protocols.GRPC.Endpoint = "localhost:4317"
+		cfg.Protocols.HTTP.Endpoint = "localhost:4318"
+		return cfg
+	})
+	mockCtx.componentFactory = singleFactory{kind: component.KindReceiver, factory: receiverFactory}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetComponentEndpoints)
+
+	t.Run("protocols", func(t *testing.T) {
+		out := callToolOutput[tools.GetComponentEndpointsOutput](t, session, "get_component_endpoints", map[string]any{
+			"component_id": "otlp",
+		})
+		assert.Equal(t, "receiver", out.Kind)
+		assert.Equal(t, []tools.ComponentEndpoint{
+			{Key: "protocols::grpc::endpoint", Protocol: "grpc", Endpoint: "localhost:4317", Default: true},
+			{Key: "protocols::http::endpoint", Protocol: "http", Endpoint: "0.0.0.0:4318"},
+		}, out.Endpoints)
+	})
+
+	t.Run("extension", func(t *testing.T) {
+		mockCtx.componentFactory = singleFactory{kind: component.KindExtension, factory: NewFactory()}
+		defer func() {
+			mockCtx.componentFactory = singleFactory{kind: component.KindReceiver, factory: receiverFactory}
+		}()
+
+		out := callToolOutput[tools.GetComponentEndpointsOutput](t, session, "get_component_endpoints", map[string]any{
+			"component_id": "mcp",
+			"kind":         "extension",
+		})
+		require.Len(t, out.Endpoints, 1)
+		assert.Equal(t, tools.ComponentEndpoint{Key: "endpoint", Endpoint: "localhost:9999", Default: true}, out.Endpoints[0])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, args := range []map[string]any{
+			{"component_id": "jaeger"},
+			{"component_id": "otlp", "kind": "exporter"},
+			{"component_id": "mcp", "kind": "extension"},
+		} {
+			result, err := callTool(session, "get_component_endpoints", args)
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for %v", args)
+			}
+		}
+	})
+}
+
+func TestGetTraceByIDJSON(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	traceID := pcommon.TraceID([16]byte{1})
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	addSpan := func(id, parent byte, name string, offset time.Duration) ptrace.Span {
+		span := spans.AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(pcommon.SpanID([8]byte{id}))
+		if parent != 0 {
+			span.SetParentSpanID(pcommon.SpanID([8]byte{parent}))
+		}
+		span.SetName(name)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(offset + time.Millisecond)))
+		return span
+	}
+	// Children are buffered before their parent
+	addSpan(3, 1, "SELECT", 2*time.Millisecond).Status().SetCode(ptrace.StatusCodeError)
+	addSpan(2, 1, "auth", time.Millisecond).Attributes().PutStr("user", "alice")
+	root := addSpan(1, 0, "GET /users", 0)
+	root.SetKind(ptrace.SpanKindServer)
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTraceByID)
+
+	t.Run("json", func(t *testing.T) {
+		result, err := callTool(session, "get_trace_by_id", map[string]any{"trace_id": traceID.String(), "format": "json"})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		var out tools.GetTraceByIDOutput
+		data, err := json.Marshal(result.StructuredContent)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Empty(t, out.Markdown)
+
+		rootID := pcommon.SpanID([8]byte{1}).String()
+		require.Len(t, out.Spans, 3)
+		assert.Equal(t, tools.TraceSpan{
+			SpanID:    rootID,
+			Name:      "GET /users",
+			StartTime: start.UnixNano(),
+			EndTime:   start.Add(time.Millisecond).UnixNano(),
+			Status:    "Unset",
+			Kind:      "Server",
+		}, out.Spans[0])
+		assert.Equal(t, "auth", out.Spans[1].Name)
+		assert.Equal(t, rootID, out.Spans[1].ParentSpanID)
+		assert.Equal(t, map[string]string{"user": "alice"}, out.Spans[1].Attributes)
+		assert.Equal(t, "SELECT", out.Spans[2].Name)
+		assert.Equal(t, "Error", out.Spans[2].Status)
+
+		// The text content holds the span array
+		var spans []tools.TraceSpan
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &spans))
+		assert.Equal(t, out.Spans, spans)
+	})
+
+	t.Run("waterfall", func(t *testing.T) {
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id": traceID.String(),
+		})
+		assert.Contains(t, out.Markdown, "GET /users")
+		assert.Empty(t, out.Spans)
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		result, err := callTool(session, "get_trace_by_id", map[string]any{"trace_id": traceID.String(), "format": "csv"})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for format csv")
+		}
+	})
+}
+
+func TestErrorRateTimeseries(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC)
+	td := ptrace.NewTraces()
+	addSpans := func(service string, offset time.Duration, ok, failed int) {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		for i := 0; i < ok+failed; i++ {
+			span := spans.AppendEmpty()
+			span.SetName("op")
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+			span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(offset + time.Millisecond)))
+			if i >= ok {
+				span.Status().SetCode(ptrace.StatusCodeError)
+			}
+		}
+	}
+	addSpans("checkout", 10*time.Second, 4, 0)
+	addSpans("checkout", 70*time.Second, 1, 3)
+	// Nothing in the third minute
+	addSpans("checkout", 190*time.Second, 2, 0)
+	addSpans("cart", 10*time.Second, 0, 5)
+	// Spans without a start timestamp or past the int64 range would otherwise
+	// stretch the series over decades
+	broken := td.ResourceSpans().AppendEmpty()
+	broken.Resource().Attributes().PutStr("service.name", "checkout")
+	brokenSpans := broken.ScopeSpans().AppendEmpty().Spans()
+	brokenSpans.AppendEmpty().SetName("no start")
+	brokenSpans.AppendEmpty().SetStartTimestamp(pcommon.Timestamp(math.MaxUint64))
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterErrorRateTimeseries)
+
+	t.Run("minutes", func(t *testing.T) {
+		out := callToolOutput[tools.ErrorRateTimeseriesOutput](t, session, "error_rate_timeseries", map[string]any{
+			"service_name": "checkout",
+		})
+		assert.Equal(t, "1m0s", out.Bucket)
+		assert.Equal(t, 10, out.TotalSpans)
+		assert.Equal(t, 2, out.SkippedSpans)
+		assert.Equal(t, 3, out.ErrorSpans)
+		assert.InDelta(t, 0.3, out.ErrorRate, 1e-9)
+		assert.Equal(t, []tools.ErrorRateBucket{
+			{Start: "2025-01-02T03:04:00Z", End: "2025-01-02T03:05:00Z", Spans: 4},
+			{Start: "2025-01-02T03:05:00Z", End: "2025-01-02T03:06:00Z", Spans: 4, Errors: 3, ErrorRate: 0.75},
+			{Start: "2025-01-02T03:06:00Z", End: "2025-01-02T03:07:00Z"},
+			{Start: "2025-01-02T03:07:00Z", End: "2025-01-02T03:08:00Z", Spans: 2},
+		}, out.Buckets)
+	})
+
+	t.Run("larger_bucket_and_range", func(t *testing.T) {
+		out := callToolOutput[tools.ErrorRateTimeseriesOutput](t, session, "error_rate_timeseries", map[string]any{
+			"service_name": "checkout",
+			"bucket":       "10m",
+			"end_time":     "2025-01-02T03:06:00Z",
+		})
+		assert.Equal(t, "10m0s", out.Bucket)
+		assert.Equal(t, []tools.ErrorRateBucket{
+			{Start: "2025-01-02T03:00:00Z", End: "2025-01-02T03:10:00Z", Spans: 8, Errors: 3, ErrorRate: 0.375},
+		}, out.Buckets)
+	})
+
+	t.Run("no_spans", func(t *testing.T) {
+		out := callToolOutput[tools.ErrorRateTimeseriesOutput](t, session, "error_rate_timeseries", map[string]any{
+			"service_name": "payment",
+		})
+		assert.Zero(t, out.TotalSpans)
+		assert.Empty(t, out.Buckets)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, args := range []map[string]any{
+			{},
+			{"service_name": "checkout", "bucket": "soon"},
+			{"service_name": "checkout", "bucket": "-1m"},
+			{"service_name": "checkout", "bucket": "1ms"},
+		} {
+			result, err := callTool(session, "error_rate_timeseries", args)
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for %v", args)
+			}
+		}
+	})
+}
+
+func TestQueryTracesTraceCount(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	// Three spans of one trace and one span of another
+	for i, trace := range []byte{1, 1, 2, 1} {
+		span := spans.AppendEmpty()
+		span.SetTraceID(pcommon.TraceID([16]byte{trace}))
+		span.SetSpanID(pcommon.SpanID([8]byte{byte(i + 1)}))
+		span.SetName("op")
+	}
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{})
+	assert.Equal(t, 4, out.SpanCount)
+	assert.Equal(t, 2, out.TraceCount)
+
+	// Only the returned spans are counted
+	out = callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{"limit": 2})
+	assert.Equal(t, 2, out.SpanCount)
+	assert.Equal(t, 1, out.TraceCount)
+
+	out = callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{"service_name": "cart"})
+	assert.Zero(t, out.TraceCount)
+}
+
+func TestQueryTracesIncludeEvents(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("charge")
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(50 * time.Millisecond)))
+	retry := span.Events().AppendEmpty()
+	retry.SetName("retry")
+	retry.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(10 * time.Millisecond)))
+	exception := span.Events().AppendEmpty()
+	exception.SetName("exception")
+	exception.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(40 * time.Millisecond)))
+	exception.Attributes().PutStr("exception.type", "Timeout")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterQueryTraces)
+
+	t.Run("included", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"include_events":     true,
+			"resource_attr_keys": []string{"host.name"},
+		})
+		assert.Equal(t, 1, out.SpanCount)
+		lines := strings.Split(strings.TrimSpace(out.Markdown), "\n")
+		require.Len(t, lines, 5)
+		assert.True(t, strings.HasPrefix(lines[2], "| charge |"))
+		assert.Equal(t, "| ↳ retry | | +10.0ms | | | - | |", lines[3])
+		assert.Equal(t, "| ↳ exception | | +40.0ms | | | exception.type=Timeout | |", lines[4])
+	})
+
+	t.Run("default", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{})
+		assert.NotContains(t, out.Markdown, "↳")
+	})
+}
+
+func TestGetBufferAge(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	now := time.Now()
+	mockCtx.batchInfos = map[string][]tools.BatchInfo{
+		"traces": {
+			{Seq: 1, ReceivedAt: now.Add(-2 * time.Hour), Items: 10},
+			{Seq: 2, ReceivedAt: now.Add(-10 * time.Minute), Items: 4},
+			{Seq: 3, ReceivedAt: now.Add(-9 * time.Minute), Items: 1},
+			{Seq: 4, ReceivedAt: now.Add(-10 * time.Second), Items: 2},
+		},
+	}
+	mockCtx.bufferStats.LogsCapacity = 0
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetBufferAge)
+
+	t.Run("all_signals", func(t *testing.T) {
+		out := callToolOutput[tools.GetBufferAgeOutput](t, session, "get_buffer_age", map[string]any{})
+		// Logs buffering is disabled
+		require.Len(t, out.Signals, 2)
+		traces := out.Signals[0]
+		assert.Equal(t, "traces", traces.Signal)
+		assert.Equal(t, 4, traces.Batches)
+		assert.Equal(t, 17, traces.Items)
+		assert.Equal(t, 100, traces.Capacity)
+		assert.Equal(t, now.Add(-2*time.Hour).UTC().Format(time.RFC3339Nano), traces.Oldest)
+		assert.Equal(t, now.Add(-10*time.Second).UTC().Format(time.RFC3339Nano), traces.Newest)
+		assert.Equal(t, "1h59m50s", traces.Span)
+		assert.Equal(t, []tools.BufferAgeBucket{
+			{Bucket: "<1m", Batches: 1, Items: 2},
+			{Bucket: "1m-5m"},
+			{Bucket: "5m-15m", Batches: 2, Items: 5},
+			{Bucket: "15m-1h"},
+			{Bucket: "1h-6h", Batches: 1, Items: 10},
+			{Bucket: ">=6h"},
+		}, traces.Histogram)
+
+		metrics := out.Signals[1]
+		assert.Equal(t, "metrics", metrics.Signal)
+		assert.Zero(t, metrics.Batches)
+		assert.Empty(t, metrics.Oldest)
+		assert.Len(t, metrics.Histogram, 6)
+	})
+
+	t.Run("single_signal", func(t *testing.T) {
+		out := callToolOutput[tools.GetBufferAgeOutput](t, session, "get_buffer_age", map[string]any{"signal": "Traces"})
+		require.Len(t, out.Signals, 1)
+		assert.Equal(t, "traces", out.Signals[0].Signal)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, signal := range []string{"profiles", "logs"} {
+			result, err := callTool(session, "get_buffer_age", map[string]any{"signal": signal})
+			if err == nil {
+				assert.True(t, result.IsError, "expected error for signal %s", signal)
+			}
+		}
+	})
+}
+
+func TestConfigStructureWarnings(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.conf = confmap.NewFromStringMap(map[string]any{
+		"receivers":  []any{"otlp"},
+		"processors": "batch",
+		"exporters": map[string]any{
+			"debug": nil,
+		},
+		"service": map[string]any{
+			"pipelines": map[string]any{
+				"traces": map[string]any{
+					"receivers": "otlp",
+					"exporters": []any{"debug"},
+				},
+				"logs": "otlp",
+			},
+		},
+	})
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterListConfiguredComponents,
+		tools.RegisterGetComponentStatus,
+		tools.RegisterGetPipelineMetrics,
+	)
+
+	sectionWarnings := []string{
+		"receivers is a list, expected a map of components",
+		"processors is a string, expected a map of components",
+	}
+
+	t.Run("list_configured_components", func(t *testing.T) {
+		out := callToolOutput[tools.ListConfiguredComponentsOutput](t, session, "list_configured_components", map[string]any{})
+		assert.Equal(t, map[string][]string{"exporter": {"debug"}}, out.Components)
+		assert.Equal(t, sectionWarnings, out.Warnings)
+	})
+
+	t.Run("get_component_status", func(t *testing.T) {
+		out := callToolOutput[tools.GetComponentStatusOutput](t, session, "get_component_status", map[string]any{})
+		assert.Equal(t, 1, out.Count)
+		assert.Equal(t, sectionWarnings, out.Warnings)
+
+		out = callToolOutput[tools.GetComponentStatusOutput](t, session, "get_component_status", map[string]any{"kind": "exporter"})
+		assert.Empty(t, out.Warnings)
+	})
+
+	t.Run("get_pipeline_metrics", func(t *testing.T) {
+		out := callToolOutput[tools.GetPipelineMetricsOutput](t, session, "get_pipeline_metrics", map[string]any{})
+		assert.Equal(t, 2, out.Count)
+		assert.Equal(t, []string{
+			"service::pipelines::logs is a string, expected a map with receivers, processors and exporters",
+			"service::pipelines::traces::receivers is a string, expected a list of component IDs",
+		}, out.Warnings)
+
+		out = callToolOutput[tools.GetPipelineMetricsOutput](t, session, "get_pipeline_metrics", map[string]any{"pipeline_id": "traces"})
+		require.Len(t, out.Pipelines, 1)
+		assert.Equal(t, 1, out.Pipelines[0].ExporterCount)
+		assert.Equal(t, []string{"service::pipelines::traces::receivers is a string, expected a list of component IDs"}, out.Warnings)
+	})
+
+	t.Run("pipelines_not_a_map", func(t *testing.T) {
+		mockCtx.conf = confmap.NewFromStringMap(map[string]any{
+			"service": map[string]any{"pipelines": []any{"traces"}},
+		})
+		out := callToolOutput[tools.GetPipelineMetricsOutput](t, session, "get_pipeline_metrics", map[string]any{})
+		assert.Zero(t, out.Count)
+		assert.Equal(t, []string{"service::pipelines is a list, expected a map of pipelines"}, out.Warnings)
+	})
+}
+
+func TestGetExemplarTraces(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	buffered := pcommon.TraceID([16]byte{1})
+	cached := pcommon.TraceID([16]byte{2})
+	evicted := pcommon.TraceID([16]byte{3})
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("http.server.duration")
+	dps := metric.SetEmptyHistogram().DataPoints()
+	addExemplar := func(dp pmetric.HistogramDataPoint, traceID pcommon.TraceID, value float64) {
+		exemplar := dp.Exemplars().AppendEmpty()
+		exemplar.SetTraceID(traceID)
+		exemplar.SetSpanID(pcommon.SpanID([8]byte{traceID[0]}))
+		exemplar.SetDoubleValue(value)
+		exemplar.SetTimestamp(pcommon.NewTimestampFromTime(start))
+	}
+	dp := dps.AppendEmpty()
+	addExemplar(dp, buffered, 120)
+	addExemplar(dp, cached, 80)
+	dp = dps.AppendEmpty()
+	addExemplar(dp, buffered, 130)
+	addExemplar(dp, evicted, 5)
+	// Exemplars without trace context are skipped
+	dp.Exemplars().AppendEmpty().SetDoubleValue(1)
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	newTrace := func(traceID pcommon.TraceID, service string) ptrace.Traces {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		root := spans.AppendEmpty()
+		root.SetTraceID(traceID)
+		root.SetSpanID(pcommon.SpanID([8]byte{traceID[0]}))
+		root.SetName("GET /cart")
+		root.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		root.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(120 * time.Millisecond)))
+		child := spans.AppendEmpty()
+		child.SetTraceID(traceID)
+		child.SetSpanID(pcommon.SpanID([8]byte{traceID[0], 1}))
+		child.SetParentSpanID(root.SpanID())
+		child.SetName("SELECT")
+		child.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(10 * time.Millisecond)))
+		child.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(20 * time.Millisecond)))
+		child.Status().SetCode(ptrace.StatusCodeError)
+		return td
+	}
+	mockCtx.recentTraces = []ptrace.Traces{newTrace(buffered, "checkout")}
+	mockCtx.cachedTraces = map[string]ptrace.Traces{cached.String(): newTrace(cached, "cart")}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetExemplarTraces)
+
+	t.Run("resolved", func(t *testing.T) {
+		out := callToolOutput[tools.GetExemplarTracesOutput](t, session, "get_exemplar_traces", map[string]any{
+			"metric_name": "http.server.duration",
+		})
+		assert.True(t, out.Found)
+		assert.Equal(t, 4, out.ExemplarCount)
+		assert.Equal(t, 3, out.TraceCount)
+		assert.Equal(t, 2, out.AvailableCount)
+		require.Len(t, out.Traces, 3)
+
+		assert.Equal(t, tools.ExemplarTrace{
+			TraceID:    buffered.String(),
+			SpanID:     pcommon.SpanID([8]byte{1}).String(),
+			Exemplars:  2,
+			Value:      120,
+			Timestamp:  "2025-01-02T03:04:05Z",
+			Available:  true,
+			SpanCount:  2,
+			RootSpan:   "GET /cart",
+			Service:    "checkout",
+			Duration:   "120.0ms",
+			ErrorSpans: 1,
+		}, out.Traces[0])
+		// Found in the trace cache
+		assert.True(t, out.Traces[1].Available)
+		assert.Equal(t, "cart", out.Traces[1].Service)
+		assert.Equal(t, tools.ExemplarTrace{
+			TraceID:   evicted.String(),
+			SpanID:    pcommon.SpanID([8]byte{3}).String(),
+			Exemplars: 1,
+			Value:     5,
+			Timestamp: "2025-01-02T03:04:05Z",
+		}, out.Traces[2])
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		out := callToolOutput[tools.GetExemplarTracesOutput](t, session, "get_exemplar_traces", map[string]any{
+			"metric_name": "http.server.duration",
+			"limit":       1,
+		})
+		assert.Equal(t, 3, out.TraceCount)
+		require.Len(t, out.Traces, 1)
+		assert.Equal(t, 1, out.AvailableCount)
+	})
+
+	t.Run("other_service", func(t *testing.T) {
+		out := callToolOutput[tools.GetExemplarTracesOutput](t, session, "get_exemplar_traces", map[string]any{
+			"metric_name":  "http.server.duration",
+			"service_name": "cart",
+		})
+		assert.False(t, out.Found)
+		assert.Empty(t, out.Traces)
+	})
+}
+
+func TestRedactAttributes(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.redactKeys = []string{"authorization", "password"}
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID([16]byte{1}))
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	span.SetName("charge")
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(50 * time.Millisecond)))
+	span.Attributes().PutStr("http.method", "POST")
+	span.Attributes().PutStr("http.request.header.Authorization", "Bearer s3cret")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("db.password", "s3cret")
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(start))
+	lr.Body().SetStr("login")
+	lr.Attributes().PutStr("user.password", "s3cret")
+	lr.Attributes().PutStr("user.name", "alice")
+	mockCtx.recentLogs = []plog.Logs{ld}
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("requests")
+	dp := metric.SetEmptySum().DataPoints().AppendEmpty()
+	dp.SetIntValue(1)
+	dp.Attributes().PutStr("authorization", "s3cret")
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterQueryTraces,
+		tools.RegisterGetTraceByID,
+		tools.RegisterQueryLogs,
+		tools.RegisterQueryMetrics,
+	)
+
+	for _, detailed := range []bool{false, true} {
+		traces := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{"detailed": detailed, "max_attr_length": 0})
+		assert.Contains(t, traces.Markdown, "http.method")
+		assert.Contains(t, traces.Markdown, "[REDACTED]")
+		assert.NotContains(t, traces.Markdown, "s3cret")
+
+		logs := callToolOutput[tools.QueryLogsOutput](t, session, "query_logs", map[string]any{
+			"detailed":           detailed,
+			"max_attr_length":    0,
+			"resource_attr_keys": []string{"db.password"},
+		})
+		assert.Contains(t, logs.Markdown, "alice")
+		assert.Contains(t, logs.Markdown, "[REDACTED]")
+		assert.NotContains(t, logs.Markdown, "s3cret")
+
+		metrics := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{"detailed": detailed})
+		assert.Contains(t, metrics.Markdown, "authorization=[REDACTED]")
+		assert.NotContains(t, metrics.Markdown, "s3cret")
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		metrics := callToolOutput[tools.QueryMetricsOutput](t, session, "query_metrics", map[string]any{
+			"format":         "csv",
+			"attribute_keys": []string{"authorization"},
+		})
+		assert.Contains(t, metrics.CSV, "[REDACTED]")
+		assert.NotContains(t, metrics.CSV, "s3cret")
+	})
+
+	t.Run("trace by id", func(t *testing.T) {
+		out := callToolOutput[tools.GetTraceByIDOutput](t, session, "get_trace_by_id", map[string]any{
+			"trace_id": span.TraceID().String(),
+			"format":   "json",
+		})
+		require.Len(t, out.Spans, 1)
+		assert.Equal(t, "[REDACTED]", out.Spans[0].Attributes["http.request.header.Authorization"])
+		assert.Equal(t, "POST", out.Spans[0].Attributes["http.method"])
+	})
+}
+
+func TestRedactAttributesAcrossTools(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.redactKeys = []string{"authorization", "cookie", "set-cookie", "password"}
+	mockCtx.dumpDir = t.TempDir()
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	rs.Resource().Attributes().PutStr("db.password", "s3cret")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID([16]byte{1}))
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	span.SetName("charge")
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(50 * time.Millisecond)))
+	span.Attributes().PutStr("http.route", "/cart")
+	span.Attributes().PutStr("http.request.header.authorization", "Bearer s3cret")
+	event := span.Events().AppendEmpty()
+	event.SetName("exception")
+	event.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(10 * time.Millisecond)))
+	event.Attributes().PutStr("exception.type", "AuthError")
+	event.Attributes().PutStr("http.request.header.cookie", "session=s3cret")
+	mockCtx.recentTraces = []ptrace.Traces{td}
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	rm.Resource().Attributes().PutStr("db.password", "s3cret")
+	metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("requests")
+	sum := metric.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for i := range 2 {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(time.Duration(i) * time.Minute)))
+		dp.SetIntValue(int64(10 * (i + 1)))
+		dp.Attributes().PutStr("authorization", "s3cret")
+	}
+	mockCtx.recentMetrics = []pmetric.Metrics{md}
+
+	session := connectTestClient(t, mockCtx,
+		tools.RegisterGetAttributeValues,
+		tools.RegisterDescribeResources,
+		tools.RegisterGetMetricsByResource,
+		tools.RegisterGetRecentMetrics,
+		tools.RegisterQueryTraces,
+		tools.RegisterSearchAll,
+		tools.RegisterBreakdownSpans,
+		tools.RegisterMetricRate,
+		tools.RegisterGetMetricSeriesDetail,
+		tools.RegisterListSpanEvents,
+		tools.RegisterExportTraceOTLP,
+		tools.RegisterExportBuffer,
+	)
+
+	// assertRedacted checks that the secret is absent from the whole output and
+	// its placeholder present
+	assertRedacted := func(t *testing.T, out any) {
+		t.Helper()
+		raw, err := json.Marshal(out)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "s3cret")
+		assert.Contains(t, string(raw), "[REDACTED]")
+	}
+
+	t.Run("get_attribute_values", func(t *testing.T) {
+		out := callToolOutput[tools.GetAttributeValuesOutput](t, session, "get_attribute_values", map[string]any{
+			"signal": "traces",
+			"key":    "http.request.header.authorization",
+		})
+		require.Len(t, out.Values, 1)
+		assert.Equal(t, "[REDACTED]", out.Values[0].Value)
+		assertRedacted(t, out)
+	})
+
+	t.Run("describe_resources", func(t *testing.T) {
+		assertRedacted(t, callToolOutput[tools.DescribeResourcesOutput](t, session, "describe_resources", map[string]any{}))
+	})
+
+	t.Run("get_metrics_by_resource", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricsByResourceOutput](t, session, "get_metrics_by_resource", map[string]any{"group_by": "db.password"})
+		require.Len(t, out.Groups, 1)
+		assert.Equal(t, "[REDACTED]", out.Groups[0].Value)
+	})
+
+	t.Run("get_recent_metrics", func(t *testing.T) {
+		assertRedacted(t, callToolOutput[tools.MetricsOutput](t, session, "get_recent_metrics", map[string]any{"metric_name": "requests"}))
+	})
+
+	t.Run("query_traces_attribute_keys", func(t *testing.T) {
+		out := callToolOutput[tools.QueryTracesOutput](t, session, "query_traces", map[string]any{
+			"attribute_keys":  []string{"http.route", "http.request.header.authorization"},
+			"max_attr_length": 0,
+		})
+		assert.Contains(t, out.Markdown, "http.route=/cart")
+		assertRedacted(t, out)
+	})
+
+	t.Run("search_all", func(t *testing.T) {
+		out := callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{"query": "s3cret"})
+		assert.Zero(t, out.MatchCount, "redacted values must not be searchable")
+
+		out = callToolOutput[tools.SearchAllOutput](t, session, "search_all", map[string]any{"query": "authorization"})
+		require.NotZero(t, out.MatchCount)
+		assertRedacted(t, out)
+	})
+
+	t.Run("breakdown_spans", func(t *testing.T) {
+		out := callToolOutput[tools.BreakdownSpansOutput](t, session, "breakdown_spans", map[string]any{
+			"span_name":     "charge",
+			"attribute_key": "http.request.header.authorization",
+		})
+		require.Len(t, out.Values, 1)
+		assert.Equal(t, "[REDACTED]", out.Values[0].Value)
+	})
+
+	t.Run("metric_rate", func(t *testing.T) {
+		out := callToolOutput[tools.MetricRateOutput](t, session, "metric_rate", map[string]any{"metric_name": "requests"})
+		require.Len(t, out.Series, 1)
+		assert.Equal(t, "[REDACTED]", out.Series[0].Attributes["authorization"])
+		assertRedacted(t, out)
+	})
+
+	t.Run("get_metric_series_detail", func(t *testing.T) {
+		out := callToolOutput[tools.GetMetricSeriesDetailOutput](t, session, "get_metric_series_detail", map[string]any{"metric_name": "requests"})
+		require.Len(t, out.Series, 1)
+		assert.Equal(t, "[REDACTED]", out.Series[0].Attributes["authorization"])
+		assert.Equal(t, "[REDACTED]", out.Series[0].ResourceAttributes["db.password"])
+		assertRedacted(t, out)
+	})
+
+	t.Run("list_span_events", func(t *testing.T) {
+		out := callToolOutput[tools.ListSpanEventsOutput](t, session, "list_span_events", map[string]any{})
+		require.Len(t, out.Events, 1)
+		assert.Equal(t, "AuthError", out.Events[0].ExceptionType)
+		assertRedacted(t, out)
+	})
+
+	t.Run("export_trace_otlp", func(t *testing.T) {
+		out := callToolOutput[tools.ExportTraceOTLPOutput](t, session, "export_trace_otlp", map[string]any{
+			"trace_id": "01000000000000000000000000000000",
+		})
+		require.Equal(t, 1, out.SpanCount)
+		assertRedacted(t, out)
+	})
+
+	t.Run("export_buffer", func(t *testing.T) {
+		out := callToolOutput[tools.ExportBufferOutput](t, session, "export_buffer", map[string]any{})
+		for _, signal := range out.Signals[:2] {
+			data, err := base64.StdEncoding.DecodeString(signal.Data)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "s3cret", signal.Signal)
+			assert.Contains(t, string(data), "[REDACTED]", signal.Signal)
+		}
+
+		callToolOutput[tools.ExportBufferOutput](t, session, "export_buffer", map[string]any{"path": "dump"})
+		data, err := os.ReadFile(filepath.Join(mockCtx.dumpDir, "dump", "traces.jsonl"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "s3cret")
+
+		v, ok := mockCtx.recentTraces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().Get("http.request.header.authorization")
+		require.True(t, ok)
+		assert.Equal(t, "Bearer s3cret", v.Str(), "buffered telemetry is not modified")
+	})
+}
+
+func TestValidateConfigStrict(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.componentFactory = singleFactory{kind: component.KindExtension, factory: NewFactory()}
+
+	session := connectTestClient(t, mockCtx, tools.RegisterValidateConfigStrict)
+
+	t.Run("per component", func(t *testing.T) {
+		out := callToolOutput[tools.ValidateConfigStrictOutput](t, session, "validate_config_strict", map[string]any{
+			"config": map[string]any{
+				"receivers": map[string]any{"otlp": nil},
+				"extensions": map[string]any{
+					"mcp":         map[string]any{"endpoint": "localhost:9998", "traces_buffer_size": 10},
+					"mcp/default": nil,
+					"mcp/path":    map[string]any{"path": "mcp"},
+					"mcp/typo":    map[string]any{"endpont": "localhost:9998"},
+				},
+				"service": map[string]any{"extensions": []any{"mcp"}},
+			},
+		})
+		assert.False(t, out.Valid)
+		assert.Equal(t, 3, out.InvalidCount)
+		assert.Equal(t, "3 of 5 components are invalid", out.Message)
+		require.Len(t, out.Components, 5)
+
+		byID := make(map[string]tools.ComponentValidation)
+		for _, c := range out.Components {
+			byID[c.Kind+"/"+c.ComponentID] = c
+		}
+		assert.Equal(t, "receiver", out.Components[0].Kind)
+		assert.Contains(t, byID["receiver/otlp"].Error, "unknown receiver type \"otlp\"")
+		assert.True(t, byID["extension/mcp"].Valid)
+		assert.True(t, byID["extension/mcp/default"].Valid)
+		assert.Contains(t, byID["extension/mcp/path"].Error, "path must start with")
+		assert.Contains(t, byID["extension/mcp/typo"].Error, "endpont")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		out := callToolOutput[tools.ValidateConfigStrictOutput](t, session, "validate_config_strict", map[string]any{
+			"config": map[string]any{"extensions": map[string]any{"mcp": map[string]any{"read_only": true}}},
+		})
+		assert.True(t, out.Valid)
+		assert.Equal(t, "All 1 components are valid", out.Message)
+	})
+
+	t.Run("invalid section", func(t *testing.T) {
+		result, err := callTool(session, "validate_config_strict", map[string]any{"config": map[string]any{"receivers": []any{"otlp"}}})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error for a receivers list")
+		}
+	})
+
+	t.Run("no factory capability", func(t *testing.T) {
+		mockCtx.componentFactory = nil
+		defer func() {
+			mockCtx.componentFactory = singleFactory{kind: component.KindExtension, factory: NewFactory()}
+		}()
+		result, err := callTool(session, "validate_config_strict", map[string]any{"config": map[string]any{}})
+		if err == nil {
+			assert.True(t, result.IsError, "expected error without ComponentFactory")
+		}
+	})
+}
+
+func TestTelemetrySummaryBytesUsed(t *testing.T) {
+	mockCtx := newMockExtensionContext()
+	mockCtx.batchInfos = map[string][]tools.BatchInfo{
+		"traces":  {{Seq: 1, Items: 2, SizeBytes: 300}, {Seq: 2, Items: 1, SizeBytes: 120}},
+		"metrics": {{Seq: 1, Items: 5, SizeBytes: 64}},
+		"logs":    {{Seq: 1, Items: 1, SizeBytes: 50}},
+	}
+	mockCtx.bufferStats.LogsCapacity = 0
+
+	session := connectTestClient(t, mockCtx, tools.RegisterGetTelemetrySummary)
+
+	out := callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{})
+	assert.Equal(t, 0, out.Traces.BytesUsed, "batches are only sized with include_bytes")
+	assert.Equal(t, 0, out.Metrics.BytesUsed)
+
+	out = callToolOutput[tools.TelemetrySummaryOutput](t, session, "get_telemetry_summary", map[string]any{"include_bytes": true})
+	assert.Equal(t, 420, out.Traces.BytesUsed)
+	assert.Equal(t, 64, out.Metrics.BytesUsed)
+	assert.True(t, out.Logs.Disabled)
+	assert.Equal(t, 0, out.Logs.BytesUsed)
 }
@@ -0,0 +1,163 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpextension
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/pavolloffay/otel-mcp/internal/tools"
+)
+
+// replayStats counts the batches loaded from a replay directory per signal
+type replayStats struct {
+	traces  int
+	metrics int
+	logs    int
+}
+
+// replayDir adds the OTLP files in dir to the buffer, in file name order.
+// JSON files (.json, .jsonl) hold one OTLP/JSON batch, or one per line, of any
+// signal, detected per batch. Proto files (.pb, .binpb) hold length-prefixed
+// batches as written by export_buffer, of the signal their name starts with.
+// Other files are skipped.
+func (e *mcpExtension) replayDir(dir string) (replayStats, error) {
+	var stats replayStats
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read replay directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+
+		var err error
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".json", ".jsonl":
+			err = e.replayJSONFile(path, &stats)
+		case ".pb", ".binpb":
+			err = e.replayProtoFile(path, &stats)
+		default:
+			e.logger.Debug("Skipping replay file with unknown extension", zap.String("file", path))
+			continue
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to replay %s: %w", path, err)
+		}
+	}
+	return stats, nil
+}
+
+// replayJSONFile adds the OTLP/JSON batches of a file. A file that is not a
+// single JSON document is read as JSON Lines.
+func (e *mcpExtension) replayJSONFile(path string, stats *replayStats) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	batches := [][]byte{data}
+	if !json.Valid(data) {
+		if batches, err = tools.DecodeDump(data, "json"); err != nil {
+			return err
+		}
+	}
+
+	for i, batch := range batches {
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(batch, &keys); err != nil {
+			return fmt.Errorf("batch %d: %w", i+1, err)
+		}
+		switch {
+		case keys["resourceSpans"] != nil || keys["resource_spans"] != nil:
+			td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(batch)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w", i+1, err)
+			}
+			e.AddTraces(td)
+			stats.traces++
+		case keys["resourceMetrics"] != nil || keys["resource_metrics"] != nil:
+			md, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(batch)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w", i+1, err)
+			}
+			e.AddMetrics(md)
+			stats.metrics++
+		case keys["resourceLogs"] != nil || keys["resource_logs"] != nil:
+			ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(batch)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w", i+1, err)
+			}
+			e.AddLogs(ld)
+			stats.logs++
+		default:
+			return fmt.Errorf("batch %d: no resourceSpans, resourceMetrics or resourceLogs", i+1)
+		}
+	}
+	return nil
+}
+
+// replayProtoFile adds the length-prefixed OTLP protobuf batches of a file
+// named after its signal, e.g. traces.pb
+func (e *mcpExtension) replayProtoFile(path string, stats *replayStats) error {
+	name := strings.ToLower(filepath.Base(path))
+	var signal string
+	for _, s := range []string{"traces", "metrics", "logs"} {
+		if strings.HasPrefix(name, s) {
+			signal = s
+			break
+		}
+	}
+	if signal == "" {
+		return errors.New("proto file name must start with traces, metrics or logs")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	batches, err := tools.DecodeDump(data, "proto")
+	if err != nil {
+		return err
+	}
+
+	for i, batch := range batches {
+		switch signal {
+		case "traces":
+			td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(batch)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w", i+1, err)
+			}
+			e.AddTraces(td)
+			stats.traces++
+		case "metrics":
+			md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(batch)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w", i+1, err)
+			}
+			e.AddMetrics(md)
+			stats.metrics++
+		case "logs":
+			ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(batch)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w", i+1, err)
+			}
+			e.AddLogs(ld)
+			stats.logs++
+		}
+	}
+	return nil
+}
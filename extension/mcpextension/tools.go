@@ -4,43 +4,214 @@
 package mcpextension
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
 	"github.com/pavolloffay/otel-mcp/internal/tools"
 )
 
-// registerTools registers all MCP tools with the server
-func (e *mcpExtension) registerTools() error {
+// Tool groups accepted as shorthand in enabled_tools and disabled_tools
+const (
+	toolGroupConfig    = "config"
+	toolGroupDiscovery = "discovery"
+	toolGroupTelemetry = "telemetry"
+)
+
+// toolRegistration ties a tool name to its group and registration function
+type toolRegistration struct {
+	name     string
+	group    string
+	register func(*mcp.Server, tools.ExtensionContext)
+}
+
+// toolRegistrations lists every tool the extension can expose, in registration order
+var toolRegistrations = []toolRegistration{
 	// Config inspection tools
-	tools.RegisterGetConfig(e.server, e)
-	tools.RegisterGetComponentConfig(e.server, e)
-	tools.RegisterListConfiguredComponents(e.server, e)
-	tools.RegisterGetPipelineConfig(e.server, e)
+	{"get_config", toolGroupConfig, tools.RegisterGetConfig},
+	{"get_component_config", toolGroupConfig, tools.RegisterGetComponentConfig},
+	{"list_configured_components", toolGroupConfig, tools.RegisterListConfiguredComponents},
+	{"get_pipeline_config", toolGroupConfig, tools.RegisterGetPipelineConfig},
+	{"get_config_value", toolGroupConfig, tools.RegisterGetConfigValue},
+	{"config_drift", toolGroupConfig, tools.RegisterConfigDrift},
+	{"get_component_endpoints", toolGroupConfig, tools.RegisterGetComponentEndpoints},
 
 	// Component discovery tools
-	tools.RegisterListAvailableComponents(e.server, e)
-	tools.RegisterGetComponentSchema(e.server, e)
-	tools.RegisterGetFactoryInfo(e.server, e)
+	{"list_available_components", toolGroupDiscovery, tools.RegisterListAvailableComponents},
+	{"get_component_schema", toolGroupDiscovery, tools.RegisterGetComponentSchema},
+	{"generate_component_config", toolGroupDiscovery, tools.RegisterGenerateComponentConfig},
+	{"get_factory_info", toolGroupDiscovery, tools.RegisterGetFactoryInfo},
+	{"inspect_component", toolGroupDiscovery, tools.RegisterInspectComponent},
 
 	// Config validation tools
-	tools.RegisterValidateConfigSection(e.server, e)
-	tools.RegisterAddComponent(e.server, e)
-	tools.RegisterRemoveComponent(e.server, e)
-	tools.RegisterValidateConfig(e.server, e)
-	tools.RegisterUpdatePipeline(e.server, e)
+	{"validate_config_section", toolGroupConfig, tools.RegisterValidateConfigSection},
+	{"add_component", toolGroupConfig, tools.RegisterAddComponent},
+	{"remove_component", toolGroupConfig, tools.RegisterRemoveComponent},
+	{"validate_config", toolGroupConfig, tools.RegisterValidateConfig},
+	{"validate_config_strict", toolGroupConfig, tools.RegisterValidateConfigStrict},
+	{"update_pipeline", toolGroupConfig, tools.RegisterUpdatePipeline},
+	{"validate_ottl", toolGroupConfig, tools.RegisterValidateOTTL},
 
 	// Telemetry query tools (consolidated from search + recent)
-	tools.RegisterQueryTraces(e.server, e)
-	tools.RegisterQueryLogs(e.server, e)
-	tools.RegisterQueryMetrics(e.server, e)
-	tools.RegisterGetTelemetrySummary(e.server, e)
+	{"query_traces", toolGroupTelemetry, tools.RegisterQueryTraces},
+	{"query_logs", toolGroupTelemetry, tools.RegisterQueryLogs},
+	{"query_metrics", toolGroupTelemetry, tools.RegisterQueryMetrics},
+	{"get_telemetry_summary", toolGroupTelemetry, tools.RegisterGetTelemetrySummary},
+	{"search_all", toolGroupTelemetry, tools.RegisterSearchAll},
 
 	// Specialized telemetry tools
-	tools.RegisterGetTraceByID(e.server, e)
-	tools.RegisterFindRelatedTelemetry(e.server, e)
+	{"get_trace_by_id", toolGroupTelemetry, tools.RegisterGetTraceByID},
+	{"get_span_by_id", toolGroupTelemetry, tools.RegisterGetSpanByID},
+	{"get_log_by_id", toolGroupTelemetry, tools.RegisterGetLogByID},
+	{"find_related_telemetry", toolGroupTelemetry, tools.RegisterFindRelatedTelemetry},
+	{"check_correlation", toolGroupTelemetry, tools.RegisterCheckCorrelation},
+	{"get_logs_for_trace", toolGroupTelemetry, tools.RegisterGetLogsForTrace},
+	{"get_metrics_by_resource", toolGroupTelemetry, tools.RegisterGetMetricsByResource},
+	{"list_span_names", toolGroupTelemetry, tools.RegisterListSpanNames},
+	{"list_instrumentation_scopes", toolGroupTelemetry, tools.RegisterListInstrumentationScopes},
+	{"get_attribute_values", toolGroupTelemetry, tools.RegisterGetAttributeValues},
+	{"describe_resources", toolGroupTelemetry, tools.RegisterDescribeResources},
+	{"list_span_events", toolGroupTelemetry, tools.RegisterListSpanEvents},
+	{"latency_histogram", toolGroupTelemetry, tools.RegisterLatencyHistogram},
+	{"error_rate_timeseries", toolGroupTelemetry, tools.RegisterErrorRateTimeseries},
+	{"breakdown_spans", toolGroupTelemetry, tools.RegisterBreakdownSpans},
+	{"find_traces_by_attribute", toolGroupTelemetry, tools.RegisterFindTracesByAttribute},
+	{"metric_rate", toolGroupTelemetry, tools.RegisterMetricRate},
+	{"get_metric_series_detail", toolGroupTelemetry, tools.RegisterGetMetricSeriesDetail},
+	{"get_exemplar_traces", toolGroupTelemetry, tools.RegisterGetExemplarTraces},
+	{"export_trace_otlp", toolGroupTelemetry, tools.RegisterExportTraceOTLP},
+	{"export_buffer", toolGroupTelemetry, tools.RegisterExportBuffer},
+	{"import_buffer", toolGroupTelemetry, tools.RegisterImportBuffer},
+	{"list_batches", toolGroupTelemetry, tools.RegisterListBatches},
+	{"get_buffer_age", toolGroupTelemetry, tools.RegisterGetBufferAge},
+	{"get_dropped_telemetry", toolGroupTelemetry, tools.RegisterGetDroppedTelemetry},
+	{"evict_trace", toolGroupTelemetry, tools.RegisterEvictTrace},
 
 	// Runtime/status tools
-	tools.RegisterGetComponentStatus(e.server, e)
-	tools.RegisterGetPipelineMetrics(e.server, e)
-	tools.RegisterGetExtensions(e.server, e)
+	{"get_component_status", toolGroupDiscovery, tools.RegisterGetComponentStatus},
+	{"get_pipeline_metrics", toolGroupDiscovery, tools.RegisterGetPipelineMetrics},
+	{"get_pipeline_health", toolGroupDiscovery, tools.RegisterGetPipelineHealth},
+	{"get_extensions", toolGroupDiscovery, tools.RegisterGetExtensions},
+	{"get_collector_info", toolGroupDiscovery, tools.RegisterGetCollectorInfo},
+	{"list_capabilities", toolGroupDiscovery, tools.RegisterListCapabilities},
+}
+
+// mutatingTools are the tools skipped in read_only mode: tools that change state
+// (the buffer or a proposed config) and the config validation tools
+var mutatingTools = map[string]bool{
+	"validate_config_section": true,
+	"add_component":           true,
+	"remove_component":        true,
+	"validate_config":         true,
+	"validate_config_strict":  true,
+	"update_pipeline":         true,
+	"validate_ottl":           true,
+	"evict_trace":             true,
+	"import_buffer":           true,
+	"export_buffer":           true,
+}
+
+// registerTools registers the MCP tools allowed by the config, the config resources and the prompts with the server
+func (e *mcpExtension) registerTools() error {
+	if e.config.LogToolCalls {
+		e.server.AddReceivingMiddleware(toolCallLoggingMiddleware(e.logger))
+	}
+
+	registered := make(map[string]bool)
+	for _, tool := range toolRegistrations {
+		if e.config.toolEnabled(tool) {
+			tool.register(e.server, e)
+			registered[tool.name] = true
+		}
+	}
+
+	// The config resources expose the same data as get_config, so they follow its enablement
+	if registered["get_config"] {
+		tools.RegisterConfigResources(e.server, e)
+	}
+
+	// Prompts
+	tools.RegisterTroubleshootPipelinePrompt(e.server)
 
+	registeredTools, err := listRegisteredTools(e.server)
+	if err != nil {
+		return fmt.Errorf("failed to list registered MCP tools: %w", err)
+	}
+	e.registeredTools = registeredTools
 	return nil
 }
+
+// listRegisteredTools returns the tools registered on server as a client sees
+// them, with the schemas the SDK inferred. The server does not expose its tools
+// otherwise, so they are listed over an in-memory session.
+func listRegisteredTools(server *mcp.Server) ([]*mcp.Tool, error) {
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer serverSession.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "list_registered_tools", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var registered []*mcp.Tool
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		registered = append(registered, tool)
+	}
+	return registered, nil
+}
+
+// GetRegisteredTools returns the tools registered on the MCP server
+func (e *mcpExtension) GetRegisteredTools() []*mcp.Tool {
+	return e.registeredTools
+}
+
+// toolEnabled reports whether a tool is allowed by read_only and passes the
+// enabled_tools and disabled_tools lists. Entries match either the tool name or its group.
+func (cfg *Config) toolEnabled(tool toolRegistration) bool {
+	if cfg.ReadOnly && mutatingTools[tool.name] {
+		return false
+	}
+	if len(cfg.EnabledTools) > 0 && !matchesTool(cfg.EnabledTools, tool) {
+		return false
+	}
+	return !matchesTool(cfg.DisabledTools, tool)
+}
+
+func matchesTool(entries []string, tool toolRegistration) bool {
+	for _, entry := range entries {
+		if entry == tool.name || entry == tool.group {
+			return true
+		}
+	}
+	return false
+}
+
+// GetToolGroups returns the group of every tool in toolRegistrations by name
+func (*mcpExtension) GetToolGroups() map[string]string {
+	groups := make(map[string]string, len(toolRegistrations))
+	for _, tool := range toolRegistrations {
+		groups[tool.name] = tool.group
+	}
+	return groups
+}
+
+// isKnownTool reports whether entry is a tool name or a tool group
+func isKnownTool(entry string) bool {
+	for _, tool := range toolRegistrations {
+		if entry == tool.name || entry == tool.group {
+			return true
+		}
+	}
+	return false
+}
@@ -4,9 +4,13 @@
 package buffer
 
 import (
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/earthboundkid/deque/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -27,9 +31,25 @@ type TelemetryBuffer interface {
 	GetRecentMetrics(limit, offset int) []pmetric.Metrics
 	// GetRecentLogs retrieves recent logs with pagination
 	GetRecentLogs(limit, offset int) []plog.Logs
+	// GetRecentTraceBatches retrieves recent traces with their batch metadata
+	GetRecentTraceBatches(limit, offset int) []Batch[ptrace.Traces]
+	// GetRecentMetricBatches retrieves recent metrics with their batch metadata
+	GetRecentMetricBatches(limit, offset int) []Batch[pmetric.Metrics]
+	// GetRecentLogBatches retrieves recent logs with their batch metadata
+	GetRecentLogBatches(limit, offset int) []Batch[plog.Logs]
+
+	// EvictTrace removes all spans of a trace from the buffered traces and
+	// returns the number of spans removed
+	EvictTrace(traceID string) int
+
+	// Resize changes the capacity of each signal's buffer, keeping the most
+	// recent batches that fit
+	Resize(tracesCapacity, metricsCapacity, logsCapacity int)
 
 	// GetStats returns buffer statistics
 	GetStats() BufferStats
+	// GetDropStats returns how much telemetry each signal's buffer rejected
+	GetDropStats() DropStats
 }
 
 // BufferStats contains information about the buffer state
@@ -44,17 +64,85 @@ type BufferStats struct {
 	LogsCapacity int
 }
 
+// DropStats counts the batches each signal's buffer rejected
+type DropStats struct {
+	Traces  SignalDrops
+	Metrics SignalDrops
+	Logs    SignalDrops
+}
+
+// SignalDrops counts the batches a signal's buffer rejected, either because
+// buffering is disabled for the signal or because the buffer was full under
+// the reject_new policy. Batches evicted to make room are not counted.
+type SignalDrops struct {
+	Batches int
+	Items   int
+	// Recent summarizes the most recently rejected batches, oldest first
+	Recent []DroppedBatch
+}
+
+// DroppedBatch summarizes a rejected batch
+type DroppedBatch struct {
+	DroppedAt time.Time
+	// Items is the number of spans, data points or log records in the batch
+	Items int
+	// Services are the distinct service.name values of the batch's resources
+	Services []string
+}
+
+// DefaultDropSamples is the number of rejected batch summaries kept per signal
+// by New
+const DefaultDropSamples = 10
+
+// Batch is a buffered telemetry batch. Seq is assigned when the batch is added
+// and increases monotonically, so it identifies the batch for as long as it
+// stays in the buffer.
+type Batch[T any] struct {
+	Seq  uint64
+	Data T
+	// ReceivedAt is when the batch was added to the buffer
+	ReceivedAt time.Time
+	// Items is the number of spans, data points or log records in the batch
+	Items int
+}
+
+// EvictionPolicy determines what happens when an item is added to a full buffer
+type EvictionPolicy string
+
+const (
+	// DropOldest removes the oldest item to make room for the new one
+	DropOldest EvictionPolicy = "drop_oldest"
+	// DropNewest removes the most recently added item to make room for the new one
+	DropNewest EvictionPolicy = "drop_newest"
+	// RejectNew keeps the buffer as is and discards the new item
+	RejectNew EvictionPolicy = "reject_new"
+)
+
 // fixedDeque wraps a deque with a fixed capacity limit
 type fixedDeque[T any] struct {
-	deque    *deque.Deque[T]
+	deque    *deque.Deque[Batch[T]]
 	capacity int
+	policy   EvictionPolicy
+	count    func(T) int
+	services func(T) []string
+	nextSeq  uint64
 	mu       sync.RWMutex
+
+	// Rejected batches and summaries of the last dropSamples of them
+	dropped     SignalDrops
+	dropSamples int
 }
 
-func newFixedDeque[T any](capacity int) *fixedDeque[T] {
+// newFixedDeque creates a deque that uses count to record the number of items
+// in each batch and services to summarize rejected batches
+func newFixedDeque[T any](capacity int, policy EvictionPolicy, dropSamples int, count func(T) int, services func(T) []string) *fixedDeque[T] {
 	return &fixedDeque[T]{
-		deque:    deque.Make[T](capacity),
-		capacity: capacity,
+		deque:       deque.Make[Batch[T]](capacity),
+		capacity:    capacity,
+		policy:      policy,
+		count:       count,
+		services:    services,
+		dropSamples: dropSamples,
 	}
 }
 
@@ -62,23 +150,76 @@ func (fd *fixedDeque[T]) Add(item T) {
 	fd.mu.Lock()
 	defer fd.mu.Unlock()
 
-	// If at capacity, remove oldest item (from front)
+	// A zero capacity disables buffering for the signal
+	if fd.capacity == 0 {
+		fd.reject(item)
+		return
+	}
+
 	if fd.deque.Len() >= fd.capacity {
-		fd.deque.RemoveFront()
+		switch fd.policy {
+		case RejectNew:
+			fd.reject(item)
+			return
+		case DropNewest:
+			fd.deque.RemoveBack()
+		default:
+			// Remove oldest item (from front)
+			fd.deque.RemoveFront()
+		}
 	}
 
 	// Add new item to back
-	fd.deque.PushBack(item)
+	fd.deque.PushBack(Batch[T]{Seq: fd.nextSeq, Data: item, ReceivedAt: time.Now(), Items: fd.count(item)})
+	fd.nextSeq++
+}
+
+// reject records a batch that was not buffered. The caller must hold the write lock.
+func (fd *fixedDeque[T]) reject(item T) {
+	items := fd.count(item)
+	fd.dropped.Batches++
+	fd.dropped.Items += items
+	if fd.dropSamples <= 0 {
+		return
+	}
+
+	if len(fd.dropped.Recent) >= fd.dropSamples {
+		fd.dropped.Recent = append(fd.dropped.Recent[:0], fd.dropped.Recent[len(fd.dropped.Recent)-fd.dropSamples+1:]...)
+	}
+	fd.dropped.Recent = append(fd.dropped.Recent, DroppedBatch{
+		DroppedAt: time.Now(),
+		Items:     items,
+		Services:  fd.services(item),
+	})
+}
+
+// Dropped returns a copy of the rejected batch counters and summaries
+func (fd *fixedDeque[T]) Dropped() SignalDrops {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	dropped := fd.dropped
+	dropped.Recent = append([]DroppedBatch(nil), fd.dropped.Recent...)
+	return dropped
 }
 
 func (fd *fixedDeque[T]) Get(limit, offset int) []T {
+	batches := fd.GetBatches(limit, offset)
+	result := make([]T, len(batches))
+	for i, batch := range batches {
+		result[i] = batch.Data
+	}
+	return result
+}
+
+func (fd *fixedDeque[T]) GetBatches(limit, offset int) []Batch[T] {
 	fd.mu.RLock()
 	defer fd.mu.RUnlock()
 
 	length := fd.deque.Len()
 
 	if offset >= length {
-		return []T{}
+		return []Batch[T]{}
 	}
 
 	actualLimit := limit
@@ -86,7 +227,7 @@ func (fd *fixedDeque[T]) Get(limit, offset int) []T {
 		actualLimit = length - offset
 	}
 
-	result := make([]T, actualLimit)
+	result := make([]Batch[T], actualLimit)
 	for i := 0; i < actualLimit; i++ {
 		item, _ := fd.deque.At(offset + i)
 		result[i] = item
@@ -95,6 +236,46 @@ func (fd *fixedDeque[T]) Get(limit, offset int) []T {
 	return result
 }
 
+// Rewrite replaces every item with the result of fn, dropping the items for
+// which fn returns false. Order, sequence numbers and received times are
+// preserved.
+func (fd *fixedDeque[T]) Rewrite(fn func(T) (T, bool)) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	length := fd.deque.Len()
+	for i := 0; i < length; i++ {
+		item, _ := fd.deque.RemoveFront()
+		if updated, keep := fn(item.Data); keep {
+			item.Data = updated
+			item.Items = fd.count(updated)
+			fd.deque.PushBack(item)
+		}
+	}
+}
+
+// Resize replaces the deque with one of the new capacity holding the most
+// recent items that fit. Sequence numbers are preserved. The write lock is held
+// for the copy, so concurrent readers see either the old or the resized
+// contents and adds wait until the resize is done.
+func (fd *fixedDeque[T]) Resize(capacity int) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if capacity == fd.capacity {
+		return
+	}
+
+	resized := deque.Make[Batch[T]](capacity)
+	length := fd.deque.Len()
+	for i := max(0, length-capacity); i < length; i++ {
+		item, _ := fd.deque.At(i)
+		resized.PushBack(item)
+	}
+	fd.deque = resized
+	fd.capacity = capacity
+}
+
 func (fd *fixedDeque[T]) Count() int {
 	fd.mu.RLock()
 	defer fd.mu.RUnlock()
@@ -102,6 +283,8 @@ func (fd *fixedDeque[T]) Count() int {
 }
 
 func (fd *fixedDeque[T]) Capacity() int {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
 	return fd.capacity
 }
 
@@ -113,11 +296,19 @@ type buffer struct {
 }
 
 // New creates a new TelemetryBuffer with the specified capacity for each signal type
+// that drops the oldest batch when full
 func New(tracesCapacity, metricsCapacity, logsCapacity int) TelemetryBuffer {
+	return NewWithPolicy(tracesCapacity, metricsCapacity, logsCapacity, DropOldest, DefaultDropSamples)
+}
+
+// NewWithPolicy creates a new TelemetryBuffer that applies policy when a signal's
+// buffer is full and keeps summaries of the last dropSamples rejected batches
+// per signal. A zero dropSamples only counts rejected batches.
+func NewWithPolicy(tracesCapacity, metricsCapacity, logsCapacity int, policy EvictionPolicy, dropSamples int) TelemetryBuffer {
 	return &buffer{
-		traces:  newFixedDeque[ptrace.Traces](tracesCapacity),
-		metrics: newFixedDeque[pmetric.Metrics](metricsCapacity),
-		logs:    newFixedDeque[plog.Logs](logsCapacity),
+		traces:  newFixedDeque(tracesCapacity, policy, dropSamples, ptrace.Traces.SpanCount, traceServices),
+		metrics: newFixedDeque(metricsCapacity, policy, dropSamples, pmetric.Metrics.DataPointCount, metricServices),
+		logs:    newFixedDeque(logsCapacity, policy, dropSamples, plog.Logs.LogRecordCount, logServices),
 	}
 }
 
@@ -145,6 +336,50 @@ func (b *buffer) GetRecentLogs(limit, offset int) []plog.Logs {
 	return b.logs.Get(limit, offset)
 }
 
+func (b *buffer) GetRecentTraceBatches(limit, offset int) []Batch[ptrace.Traces] {
+	return b.traces.GetBatches(limit, offset)
+}
+
+func (b *buffer) GetRecentMetricBatches(limit, offset int) []Batch[pmetric.Metrics] {
+	return b.metrics.GetBatches(limit, offset)
+}
+
+func (b *buffer) GetRecentLogBatches(limit, offset int) []Batch[plog.Logs] {
+	return b.logs.GetBatches(limit, offset)
+}
+
+func (b *buffer) EvictTrace(traceID string) int {
+	traceID = strings.ToLower(traceID)
+	removed := 0
+
+	b.traces.Rewrite(func(td ptrace.Traces) (ptrace.Traces, bool) {
+		matched := countTraceSpans(td, traceID)
+		if matched == 0 {
+			return td, true
+		}
+		removed += matched
+		if matched == td.SpanCount() {
+			return td, false
+		}
+
+		// Rewrite a copy so readers still holding the original batch are unaffected
+		rewritten := ptrace.NewTraces()
+		td.CopyTo(rewritten)
+		removeTraceSpans(rewritten, traceID)
+		return rewritten, true
+	})
+
+	return removed
+}
+
+// Resize resizes each signal's buffer independently. A reader may briefly see
+// the traces buffer resized while the metrics buffer is not yet.
+func (b *buffer) Resize(tracesCapacity, metricsCapacity, logsCapacity int) {
+	b.traces.Resize(tracesCapacity)
+	b.metrics.Resize(metricsCapacity)
+	b.logs.Resize(logsCapacity)
+}
+
 func (b *buffer) GetStats() BufferStats {
 	return BufferStats{
 		TracesCount:    b.traces.Count(),
@@ -157,3 +392,75 @@ func (b *buffer) GetStats() BufferStats {
 		LogsCapacity: b.logs.Capacity(),
 	}
 }
+
+func (b *buffer) GetDropStats() DropStats {
+	return DropStats{
+		Traces:  b.traces.Dropped(),
+		Metrics: b.metrics.Dropped(),
+		Logs:    b.logs.Dropped(),
+	}
+}
+
+func traceServices(td ptrace.Traces) []string {
+	rss := td.ResourceSpans()
+	return serviceNames(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() })
+}
+
+func metricServices(md pmetric.Metrics) []string {
+	rms := md.ResourceMetrics()
+	return serviceNames(rms.Len(), func(i int) pcommon.Resource { return rms.At(i).Resource() })
+}
+
+func logServices(ld plog.Logs) []string {
+	rls := ld.ResourceLogs()
+	return serviceNames(rls.Len(), func(i int) pcommon.Resource { return rls.At(i).Resource() })
+}
+
+// serviceNames returns the sorted distinct service.name values of n resources
+func serviceNames(n int, resource func(int) pcommon.Resource) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for i := 0; i < n; i++ {
+		name := "unknown"
+		if sn, ok := resource(i).Attributes().Get("service.name"); ok {
+			name = sn.AsString()
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// countTraceSpans returns the number of spans in td belonging to traceID
+func countTraceSpans(td ptrace.Traces, traceID string) int {
+	count := 0
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if spans.At(k).TraceID().String() == traceID {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// removeTraceSpans removes the spans of traceID from td along with any scope
+// and resource that is left empty
+func removeTraceSpans(td ptrace.Traces, traceID string) {
+	td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			ss.Spans().RemoveIf(func(span ptrace.Span) bool {
+				return span.TraceID().String() == traceID
+			})
+			return ss.Spans().Len() == 0
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+}
@@ -4,11 +4,14 @@
 package buffer
 
 import (
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -183,6 +186,225 @@ func TestRingBufferWraparound(t *testing.T) {
 	}
 }
 
+func TestEvictionPolicyWraparound(t *testing.T) {
+	tests := []struct {
+		policy EvictionPolicy
+		orders []int64
+	}{
+		{policy: DropOldest, orders: []int64{2, 3, 4}},
+		{policy: DropNewest, orders: []int64{0, 1, 4}},
+		{policy: RejectNew, orders: []int64{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			capacity := 3
+			b := NewWithPolicy(capacity, capacity, capacity, tt.policy, DefaultDropSamples)
+
+			// Add items beyond capacity
+			for i := 0; i < 5; i++ {
+				td := ptrace.NewTraces()
+				rs := td.ResourceSpans().AppendEmpty()
+				rs.Resource().Attributes().PutInt("order", int64(i))
+				b.AddTraces(td)
+			}
+
+			traces := b.GetRecentTraces(10, 0)
+			require.Len(t, traces, capacity)
+
+			for i, td := range traces {
+				rs := td.ResourceSpans().At(0)
+				order, ok := rs.Resource().Attributes().Get("order")
+				require.True(t, ok)
+				assert.Equal(t, tt.orders[i], order.Int())
+			}
+
+			stats := b.GetStats()
+			assert.Equal(t, capacity, stats.TracesCount)
+		})
+	}
+}
+
+func TestBufferDisabledSignal(t *testing.T) {
+	for _, policy := range []EvictionPolicy{DropOldest, DropNewest, RejectNew} {
+		t.Run(string(policy), func(t *testing.T) {
+			b := NewWithPolicy(0, 3, 3, policy, DefaultDropSamples)
+
+			b.AddTraces(ptrace.NewTraces())
+			b.AddLogs(plog.NewLogs())
+
+			assert.Empty(t, b.GetRecentTraces(10, 0))
+			assert.Len(t, b.GetRecentLogs(10, 0), 1)
+
+			stats := b.GetStats()
+			assert.Equal(t, 0, stats.TracesCount)
+			assert.Equal(t, 0, stats.TracesCapacity)
+		})
+	}
+}
+
+func TestBufferDropStats(t *testing.T) {
+	// tracesWithServices returns a batch with one span per service
+	tracesWithServices := func(services ...string) ptrace.Traces {
+		td := ptrace.NewTraces()
+		for _, service := range services {
+			rs := td.ResourceSpans().AppendEmpty()
+			rs.Resource().Attributes().PutStr("service.name", service)
+			rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		}
+		return td
+	}
+
+	t.Run("reject_new", func(t *testing.T) {
+		b := NewWithPolicy(1, 1, 1, RejectNew, 2)
+		b.AddTraces(tracesWithServices("kept"))
+		b.AddTraces(tracesWithServices("cart", "checkout"))
+		b.AddTraces(tracesWithServices("payment"))
+		b.AddTraces(tracesWithServices("payment", "cart", "payment"))
+
+		drops := b.GetDropStats().Traces
+		assert.Equal(t, 3, drops.Batches)
+		assert.Equal(t, 6, drops.Items)
+		// Only the two most recent summaries are kept, oldest first
+		require.Len(t, drops.Recent, 2)
+		assert.Equal(t, []string{"payment"}, drops.Recent[0].Services)
+		assert.Equal(t, []string{"cart", "payment"}, drops.Recent[1].Services)
+		assert.Equal(t, 3, drops.Recent[1].Items)
+		assert.False(t, drops.Recent[1].DroppedAt.Before(drops.Recent[0].DroppedAt))
+
+		assert.Zero(t, b.GetDropStats().Logs.Batches)
+	})
+
+	t.Run("disabled_signal", func(t *testing.T) {
+		b := NewWithPolicy(3, 3, 0, DropOldest, DefaultDropSamples)
+		ld := plog.NewLogs()
+		ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		b.AddLogs(ld)
+
+		drops := b.GetDropStats().Logs
+		assert.Equal(t, 1, drops.Batches)
+		assert.Equal(t, 1, drops.Items)
+		require.Len(t, drops.Recent, 1)
+		assert.Equal(t, []string{"unknown"}, drops.Recent[0].Services)
+	})
+
+	t.Run("evictions_not_counted", func(t *testing.T) {
+		for _, policy := range []EvictionPolicy{DropOldest, DropNewest} {
+			b := NewWithPolicy(1, 1, 1, policy, DefaultDropSamples)
+			b.AddTraces(tracesWithServices("a"))
+			b.AddTraces(tracesWithServices("b"))
+			assert.Zero(t, b.GetDropStats().Traces.Batches, string(policy))
+		}
+	})
+
+	t.Run("no_samples", func(t *testing.T) {
+		b := NewWithPolicy(0, 0, 0, RejectNew, 0)
+		b.AddMetrics(pmetric.NewMetrics())
+
+		drops := b.GetDropStats().Metrics
+		assert.Equal(t, 1, drops.Batches)
+		assert.Empty(t, drops.Recent)
+	})
+}
+
+func TestBufferResize(t *testing.T) {
+	b := New(5, 5, 5)
+	for i := 0; i < 5; i++ {
+		b.AddLogs(plog.NewLogs())
+	}
+
+	// Shrinking keeps the most recent batches
+	b.Resize(5, 5, 2)
+	batches := b.GetRecentLogBatches(10, 0)
+	require.Len(t, batches, 2)
+	assert.Equal(t, uint64(3), batches[0].Seq)
+	assert.Equal(t, uint64(4), batches[1].Seq)
+
+	// Growing keeps everything and makes room for new batches
+	b.Resize(5, 5, 4)
+	for i := 0; i < 3; i++ {
+		b.AddLogs(plog.NewLogs())
+	}
+	batches = b.GetRecentLogBatches(10, 0)
+	require.Len(t, batches, 4)
+	assert.Equal(t, uint64(4), batches[0].Seq)
+	assert.Equal(t, uint64(7), batches[3].Seq)
+
+	// Resizing to 0 disables the signal
+	b.Resize(0, 5, 4)
+	b.AddTraces(ptrace.NewTraces())
+	assert.Empty(t, b.GetRecentTraces(10, 0))
+
+	stats := b.GetStats()
+	assert.Equal(t, 0, stats.TracesCapacity)
+	assert.Equal(t, 5, stats.MetricsCapacity)
+	assert.Equal(t, 4, stats.LogsCapacity)
+}
+
+func TestBufferLogBatchSequence(t *testing.T) {
+	b := New(3, 3, 3)
+
+	for i := 0; i < 5; i++ {
+		b.AddLogs(plog.NewLogs())
+	}
+
+	// Sequence numbers keep counting after the oldest batches are dropped
+	batches := b.GetRecentLogBatches(10, 0)
+	require.Len(t, batches, 3)
+	for i, batch := range batches {
+		assert.Equal(t, uint64(i+2), batch.Seq)
+	}
+
+	batches = b.GetRecentLogBatches(1, 2)
+	require.Len(t, batches, 1)
+	assert.Equal(t, uint64(4), batches[0].Seq)
+}
+
+func TestBufferBatchMetadata(t *testing.T) {
+	b := New(5, 5, 5)
+
+	before := time.Now()
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	kept := spans.AppendEmpty()
+	kept.SetTraceID(pcommon.TraceID([16]byte{1}))
+	evicted := spans.AppendEmpty()
+	evicted.SetTraceID(pcommon.TraceID([16]byte{2}))
+	b.AddTraces(td)
+
+	md := pmetric.NewMetrics()
+	dps := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints()
+	dps.AppendEmpty()
+	dps.AppendEmpty()
+	dps.AppendEmpty()
+	b.AddMetrics(md)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	b.AddLogs(ld)
+
+	traceBatches := b.GetRecentTraceBatches(10, 0)
+	require.Len(t, traceBatches, 1)
+	assert.Equal(t, 2, traceBatches[0].Items)
+	receivedAt := traceBatches[0].ReceivedAt
+	assert.False(t, receivedAt.Before(before))
+
+	metricBatches := b.GetRecentMetricBatches(10, 0)
+	require.Len(t, metricBatches, 1)
+	assert.Equal(t, 3, metricBatches[0].Items)
+
+	logBatches := b.GetRecentLogBatches(10, 0)
+	require.Len(t, logBatches, 1)
+	assert.Equal(t, 1, logBatches[0].Items)
+
+	// Evicting a trace recounts the items but keeps the received time
+	require.Equal(t, 1, b.EvictTrace(evicted.TraceID().String()))
+	traceBatches = b.GetRecentTraceBatches(10, 0)
+	require.Len(t, traceBatches, 1)
+	assert.Equal(t, 1, traceBatches[0].Items)
+	assert.True(t, receivedAt.Equal(traceBatches[0].ReceivedAt))
+}
+
 func TestBufferEmptyGet(t *testing.T) {
 	b := New(5, 5, 5)
 
@@ -201,6 +423,46 @@ func TestBufferEmptyGet(t *testing.T) {
 	assert.Equal(t, 0, stats.LogsCount)
 }
 
+func TestBufferEvictTrace(t *testing.T) {
+	b := New(10, 10, 10)
+
+	evicted := pcommon.TraceID([16]byte{1})
+	other := pcommon.TraceID([16]byte{2})
+
+	newBatch := func(ids ...pcommon.TraceID) ptrace.Traces {
+		td := ptrace.NewTraces()
+		for _, id := range ids {
+			span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			span.SetTraceID(id)
+		}
+		return td
+	}
+
+	onlyEvicted := newBatch(evicted, evicted)
+	mixed := newBatch(evicted, other)
+	untouched := newBatch(other)
+	b.AddTraces(onlyEvicted)
+	b.AddTraces(mixed)
+	b.AddTraces(untouched)
+
+	removed := b.EvictTrace(strings.ToUpper(evicted.String()))
+	assert.Equal(t, 3, removed)
+
+	traces := b.GetRecentTraces(10, 0)
+	require.Len(t, traces, 2)
+
+	// Mixed batch is rewritten without the evicted spans or empty resources
+	require.Equal(t, 1, traces[0].SpanCount())
+	require.Equal(t, 1, traces[0].ResourceSpans().Len())
+	assert.Equal(t, other, traces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+	assert.Equal(t, 1, traces[1].SpanCount())
+
+	// Batches handed out before eviction are not mutated
+	assert.Equal(t, 2, mixed.SpanCount())
+
+	assert.Equal(t, 0, b.EvictTrace(evicted.String()))
+}
+
 func BenchmarkBufferAdd(b *testing.B) {
 	buf := New(1000, 1000, 1000)
 	td := ptrace.NewTraces()
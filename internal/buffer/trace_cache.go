@@ -0,0 +1,158 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package buffer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// TraceCache assembles the spans of each trace independently of the batch
+// buffer, so a trace whose early batches were evicted can still be returned
+// whole. A trace stays open while spans keep arriving and expires ttl after its
+// last span was added. When the cache holds maxTraces traces, adding a new one
+// drops the trace that has been idle the longest.
+type TraceCache struct {
+	ttl       time.Duration
+	maxTraces int
+	now       func() time.Time
+
+	mu     sync.Mutex
+	traces map[string]*list.Element
+	// order holds *cachedTrace values, least recently updated first
+	order *list.List
+}
+
+// cachedTrace holds the spans of one trace with their resources and scopes
+type cachedTrace struct {
+	traceID   string
+	traces    ptrace.Traces
+	spanCount int
+	updatedAt time.Time
+}
+
+// NewTraceCache creates a cache keeping up to maxTraces traces until they
+// have been idle for ttl
+func NewTraceCache(ttl time.Duration, maxTraces int) *TraceCache {
+	return &TraceCache{
+		ttl:       ttl,
+		maxTraces: maxTraces,
+		now:       time.Now,
+		traces:    make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// Add copies the spans of td into the traces they belong to
+func (c *TraceCache) Add(td ptrace.Traces) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.expire(now)
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			// One scope per cached trace present in this scope, created on its
+			// first span. Keyed by entry since a full cache may drop a trace and
+			// create it again within the loop.
+			scopes := make(map[*cachedTrace]ptrace.ScopeSpans)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+
+				entry := c.entry(span.TraceID().String(), now)
+				scope, ok := scopes[entry]
+				if !ok {
+					cachedRS := entry.traces.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(cachedRS.Resource())
+					cachedRS.SetSchemaUrl(rs.SchemaUrl())
+					scope = cachedRS.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(scope.Scope())
+					scope.SetSchemaUrl(ss.SchemaUrl())
+					scopes[entry] = scope
+				}
+				span.CopyTo(scope.Spans().AppendEmpty())
+				entry.spanCount++
+			}
+		}
+	}
+}
+
+// entry returns the cached trace for traceID, creating it if needed, and marks
+// it as updated at now. Must be called with c.mu held.
+func (c *TraceCache) entry(traceID string, now time.Time) *cachedTrace {
+	if elem, ok := c.traces[traceID]; ok {
+		entry := elem.Value.(*cachedTrace)
+		entry.updatedAt = now
+		c.order.MoveToBack(elem)
+		return entry
+	}
+
+	if c.order.Len() >= c.maxTraces {
+		c.remove(c.order.Front())
+	}
+	entry := &cachedTrace{traceID: traceID, traces: ptrace.NewTraces(), updatedAt: now}
+	c.traces[traceID] = c.order.PushBack(entry)
+	return entry
+}
+
+// Get returns a copy of the spans cached for traceID, and false if the trace
+// is not cached or has expired
+func (c *TraceCache) Get(traceID string) (ptrace.Traces, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(c.now())
+	elem, ok := c.traces[traceID]
+	if !ok {
+		return ptrace.Traces{}, false
+	}
+	td := ptrace.NewTraces()
+	elem.Value.(*cachedTrace).traces.CopyTo(td)
+	return td, true
+}
+
+// Evict removes a trace from the cache and returns the number of spans removed
+func (c *TraceCache) Evict(traceID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.traces[traceID]
+	if !ok {
+		return 0
+	}
+	spanCount := elem.Value.(*cachedTrace).spanCount
+	c.remove(elem)
+	return spanCount
+}
+
+// Len returns the number of cached traces
+func (c *TraceCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(c.now())
+	return c.order.Len()
+}
+
+// expire removes the traces idle for ttl or longer. Must be called with c.mu held.
+func (c *TraceCache) expire(now time.Time) {
+	for elem := c.order.Front(); elem != nil; elem = c.order.Front() {
+		if now.Sub(elem.Value.(*cachedTrace).updatedAt) < c.ttl {
+			return
+		}
+		c.remove(elem)
+	}
+}
+
+// remove drops a trace. Must be called with c.mu held.
+func (c *TraceCache) remove(elem *list.Element) {
+	delete(c.traces, elem.Value.(*cachedTrace).traceID)
+	c.order.Remove(elem)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// cacheTestTraces creates a batch with one span per trace ID, all in the given service
+func cacheTestTraces(service string, traceIDs ...byte) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", service)
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("test-scope")
+	for _, id := range traceIDs {
+		span := ss.Spans().AppendEmpty()
+		span.SetTraceID(pcommon.TraceID([16]byte{id}))
+		span.SetName(service)
+	}
+	return td
+}
+
+func cacheTestTraceID(id byte) string {
+	return pcommon.TraceID([16]byte{id}).String()
+}
+
+func TestTraceCacheAssemblesTraces(t *testing.T) {
+	c := NewTraceCache(time.Minute, 10)
+
+	c.Add(cacheTestTraces("frontend", 1, 2))
+	c.Add(cacheTestTraces("checkout", 1))
+	assert.Equal(t, 2, c.Len())
+
+	td, ok := c.Get(cacheTestTraceID(1))
+	require.True(t, ok)
+	require.Equal(t, 2, td.SpanCount())
+	require.Equal(t, 2, td.ResourceSpans().Len())
+	for i, service := range []string{"frontend", "checkout"} {
+		rs := td.ResourceSpans().At(i)
+		name, _ := rs.Resource().Attributes().Get("service.name")
+		assert.Equal(t, service, name.Str())
+		assert.Equal(t, "test-scope", rs.ScopeSpans().At(0).Scope().Name())
+		assert.Equal(t, service, rs.ScopeSpans().At(0).Spans().At(0).Name())
+	}
+
+	td, ok = c.Get(cacheTestTraceID(2))
+	require.True(t, ok)
+	assert.Equal(t, 1, td.SpanCount())
+
+	_, ok = c.Get(cacheTestTraceID(3))
+	assert.False(t, ok)
+}
+
+func TestTraceCacheGetReturnsCopy(t *testing.T) {
+	c := NewTraceCache(time.Minute, 10)
+	c.Add(cacheTestTraces("frontend", 1))
+
+	td, ok := c.Get(cacheTestTraceID(1))
+	require.True(t, ok)
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+
+	td, ok = c.Get(cacheTestTraceID(1))
+	require.True(t, ok)
+	assert.Equal(t, 1, td.SpanCount())
+}
+
+func TestTraceCacheTTL(t *testing.T) {
+	c := NewTraceCache(time.Minute, 10)
+	now := time.Unix(1700000000, 0)
+	c.now = func() time.Time { return now }
+
+	c.Add(cacheTestTraces("frontend", 1, 2))
+	now = now.Add(40 * time.Second)
+	// A new span keeps trace 1 open
+	c.Add(cacheTestTraces("checkout", 1))
+
+	now = now.Add(30 * time.Second)
+	_, ok := c.Get(cacheTestTraceID(2))
+	assert.False(t, ok, "trace 2 has been idle longer than the ttl")
+	td, ok := c.Get(cacheTestTraceID(1))
+	require.True(t, ok)
+	assert.Equal(t, 2, td.SpanCount())
+
+	now = now.Add(30 * time.Second)
+	_, ok = c.Get(cacheTestTraceID(1))
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestTraceCacheMaxTraces(t *testing.T) {
+	c := NewTraceCache(time.Minute, 2)
+	now := time.Unix(1700000000, 0)
+	c.now = func() time.Time { return now }
+
+	c.Add(cacheTestTraces("frontend", 1))
+	now = now.Add(time.Second)
+	c.Add(cacheTestTraces("frontend", 2))
+	now = now.Add(time.Second)
+	// Trace 1 is updated, so trace 2 is now idle the longest
+	c.Add(cacheTestTraces("checkout", 1))
+	now = now.Add(time.Second)
+	c.Add(cacheTestTraces("frontend", 3))
+
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.Get(cacheTestTraceID(2))
+	assert.False(t, ok)
+	for _, id := range []byte{1, 3} {
+		_, ok := c.Get(cacheTestTraceID(id))
+		assert.True(t, ok, "trace %d should be cached", id)
+	}
+
+	t.Run("single_trace", func(t *testing.T) {
+		c := NewTraceCache(time.Minute, 1)
+		c.Add(cacheTestTraces("frontend", 1, 2, 1))
+
+		assert.Equal(t, 1, c.Len())
+		td, ok := c.Get(cacheTestTraceID(1))
+		require.True(t, ok)
+		assert.Equal(t, 1, td.SpanCount(), "spans added before the trace was dropped are gone")
+	})
+}
+
+func TestTraceCacheEvict(t *testing.T) {
+	c := NewTraceCache(time.Minute, 10)
+	c.Add(cacheTestTraces("frontend", 1, 1, 2))
+
+	assert.Equal(t, 2, c.Evict(cacheTestTraceID(1)))
+	assert.Equal(t, 0, c.Evict(cacheTestTraceID(1)))
+	_, ok := c.Get(cacheTestTraceID(1))
+	assert.False(t, ok)
+	_, ok = c.Get(cacheTestTraceID(2))
+	assert.True(t, ok)
+}
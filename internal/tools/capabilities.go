@@ -0,0 +1,71 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListCapabilitiesInput struct {
+	Group string `json:"group,omitempty" jsonschema:"Only list the tools of this group (config discovery telemetry). Omit for all registered tools"`
+}
+
+// ToolCapability describes a registered tool
+type ToolCapability struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Description string `json:"description"`
+	// ReadOnly is the tool's read-only hint: it does not change the buffer or
+	// a proposed config
+	ReadOnly     bool `json:"read_only"`
+	InputSchema  any  `json:"input_schema"`
+	OutputSchema any  `json:"output_schema,omitempty"`
+}
+
+type ListCapabilitiesOutput struct {
+	Count int              `json:"count"`
+	Tools []ToolCapability `json:"tools"`
+}
+
+// RegisterListCapabilities registers the list_capabilities tool
+func RegisterListCapabilities(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ListCapabilitiesInput, ListCapabilitiesOutput](server, &mcp.Tool{
+		Name:        "list_capabilities",
+		Description: "List every tool registered on this MCP server with its group, description, read-only hint and input and output JSON schemas, sorted by name. Only tools enabled by the extension config are included. Use to generate documentation or to get a catalog grouped like the enabled_tools config.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ListCapabilitiesInput) (*mcp.CallToolResult, ListCapabilitiesOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		registered := ext.GetRegisteredTools()
+		groups := ext.GetToolGroups()
+		output := ListCapabilitiesOutput{Tools: make([]ToolCapability, 0, len(registered))}
+		for _, tool := range registered {
+			if input.Group != "" && groups[tool.Name] != input.Group {
+				continue
+			}
+			capability := ToolCapability{
+				Name:         tool.Name,
+				Title:        tool.Title,
+				Group:        groups[tool.Name],
+				Description:  tool.Description,
+				InputSchema:  tool.InputSchema,
+				OutputSchema: tool.OutputSchema,
+			}
+			if tool.Annotations != nil {
+				capability.ReadOnly = tool.Annotations.ReadOnlyHint
+			}
+			output.Tools = append(output.Tools, capability)
+		}
+		sort.Slice(output.Tools, func(i, j int) bool { return output.Tools[i].Name < output.Tools[j].Name })
+		output.Count = len(output.Tools)
+
+		return nil, output, nil
+	})
+}
@@ -9,11 +9,14 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/service"
+	"go.yaml.in/yaml/v3"
 )
 
 type ListAvailableComponentsInput struct {
@@ -126,52 +129,61 @@ func RegisterGetComponentSchema(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetComponentSchemaInput) (*mcp.CallToolResult, GetComponentSchemaOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
-		// Validate kind
-		compKind, err := parseComponentKind(input.Kind)
+		output, err := getComponentSchema(ext, input.Kind, input.ComponentType)
 		if err != nil {
 			return nil, GetComponentSchemaOutput{}, err
 		}
+		return nil, output, nil
+	})
+}
 
-		// Parse component type
-		compType, err := component.NewType(input.ComponentType)
-		if err != nil {
-			return nil, GetComponentSchemaOutput{}, fmt.Errorf("invalid component type: %w", err)
-		}
+// getComponentSchema reflects on the default config of a component type
+func getComponentSchema(ext ExtensionContext, kind, componentType string) (GetComponentSchemaOutput, error) {
+	// Validate kind
+	compKind, err := parseComponentKind(kind)
+	if err != nil {
+		return GetComponentSchemaOutput{}, err
+	}
 
-		// Get factory
-		componentFactory := ext.GetComponentFactory()
-		if componentFactory == nil {
-			return nil, GetComponentSchemaOutput{}, errors.New("host does not provide ComponentFactory capability - cannot retrieve factory")
-		}
+	// Parse component type
+	compType, err := component.NewType(componentType)
+	if err != nil {
+		return GetComponentSchemaOutput{}, fmt.Errorf("invalid component type: %w", err)
+	}
 
-		factory := componentFactory.GetFactory(compKind, compType)
-		if factory == nil {
-			return nil, GetComponentSchemaOutput{}, fmt.Errorf("factory not found for %s/%s", input.Kind, input.ComponentType)
-		}
+	// Get factory
+	componentFactory := ext.GetComponentFactory()
+	if componentFactory == nil {
+		return GetComponentSchemaOutput{}, errors.New("host does not provide ComponentFactory capability - cannot retrieve factory")
+	}
 
-		// Get default config
-		defaultCfg := factory.CreateDefaultConfig()
-		if defaultCfg == nil {
-			return nil, GetComponentSchemaOutput{}, errors.New("factory returned nil default config")
-		}
+	factory := componentFactory.GetFactory(compKind, compType)
+	if factory == nil {
+		return GetComponentSchemaOutput{}, fmt.Errorf("factory not found for %s/%s", kind, componentType)
+	}
 
-		// Marshal config to map using collector's encoding logic
-		schema, err := marshalConfigSchema(defaultCfg)
-		if err != nil {
-			return nil, GetComponentSchemaOutput{}, fmt.Errorf("failed to marshal config schema: %w", err)
-		}
+	// Get default config
+	defaultCfg := factory.CreateDefaultConfig()
+	if defaultCfg == nil {
+		return GetComponentSchemaOutput{}, errors.New("factory returned nil default config")
+	}
 
-		// Get the config type name
-		cfgType := reflect.TypeOf(defaultCfg)
-		configTypeName := cfgType.String()
+	// Marshal config to map using collector's encoding logic
+	schema, err := marshalConfigSchema(defaultCfg)
+	if err != nil {
+		return GetComponentSchemaOutput{}, fmt.Errorf("failed to marshal config schema: %w", err)
+	}
 
-		return nil, GetComponentSchemaOutput{
-			ComponentType: input.ComponentType,
-			Kind:          input.Kind,
-			ConfigType:    configTypeName,
-			Schema:        schema,
-		}, nil
-	})
+	// Get the config type name
+	cfgType := reflect.TypeOf(defaultCfg)
+	configTypeName := cfgType.String()
+
+	return GetComponentSchemaOutput{
+		ComponentType: componentType,
+		Kind:          kind,
+		ConfigType:    configTypeName,
+		Schema:        schema,
+	}, nil
 }
 
 type GetFactoryInfoInput struct {
@@ -198,108 +210,231 @@ func RegisterGetFactoryInfo(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetFactoryInfoInput) (*mcp.CallToolResult, GetFactoryInfoOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
-		// Validate kind
-		compKind, err := parseComponentKind(input.Kind)
+		output, err := getFactoryInfo(ext, input.Kind, input.ComponentType)
 		if err != nil {
 			return nil, GetFactoryInfoOutput{}, err
 		}
+		return nil, output, nil
+	})
+}
 
-		// Parse component type
-		compType, err := component.NewType(input.ComponentType)
-		if err != nil {
-			return nil, GetFactoryInfoOutput{}, fmt.Errorf("invalid component type: %w", err)
+// getFactoryInfo returns factory metadata for a component type
+func getFactoryInfo(ext ExtensionContext, kind, componentType string) (GetFactoryInfoOutput, error) {
+	// Validate kind
+	compKind, err := parseComponentKind(kind)
+	if err != nil {
+		return GetFactoryInfoOutput{}, err
+	}
+
+	// Parse component type
+	compType, err := component.NewType(componentType)
+	if err != nil {
+		return GetFactoryInfoOutput{}, fmt.Errorf("invalid component type: %w", err)
+	}
+
+	// Get version from module infos
+	version := "unknown"
+	moduleInfos := ext.GetModuleInfos()
+	if moduleInfos != nil {
+		var moduleInfo service.ModuleInfo
+		var found bool
+		switch compKind {
+		case component.KindReceiver:
+			moduleInfo, found = moduleInfos.Receiver[compType]
+		case component.KindProcessor:
+			moduleInfo, found = moduleInfos.Processor[compType]
+		case component.KindExporter:
+			moduleInfo, found = moduleInfos.Exporter[compType]
+		case component.KindConnector:
+			moduleInfo, found = moduleInfos.Connector[compType]
+		case component.KindExtension:
+			moduleInfo, found = moduleInfos.Extension[compType]
 		}
 
-		// Get version from module infos
-		version := "unknown"
-		moduleInfos := ext.GetModuleInfos()
-		if moduleInfos != nil {
-			var moduleInfo service.ModuleInfo
-			var found bool
-			switch compKind {
-			case component.KindReceiver:
-				moduleInfo, found = moduleInfos.Receiver[compType]
-			case component.KindProcessor:
-				moduleInfo, found = moduleInfos.Processor[compType]
-			case component.KindExporter:
-				moduleInfo, found = moduleInfos.Exporter[compType]
-			case component.KindConnector:
-				moduleInfo, found = moduleInfos.Connector[compType]
-			case component.KindExtension:
-				moduleInfo, found = moduleInfos.Extension[compType]
-			}
+		if found {
+			version = moduleInfo.BuilderRef
+		}
+	}
+
+	// Try to get factory
+	componentFactory := ext.GetComponentFactory()
+	if componentFactory == nil {
+		return GetFactoryInfoOutput{
+			Type:      componentType,
+			Kind:      kind,
+			Version:   version,
+			Available: moduleInfos != nil && version != "unknown",
+		}, nil
+	}
+
+	factory := componentFactory.GetFactory(compKind, compType)
+	if factory == nil {
+		return GetFactoryInfoOutput{
+			Type:      componentType,
+			Kind:      kind,
+			Version:   version,
+			Available: false,
+		}, nil
+	}
 
-			if found {
-				version = moduleInfo.BuilderRef
+	// Get stability level
+	stabilityLevel := "unknown"
+	if factory.Type() == compType {
+		// Factory exists, try to get stability via type assertion
+		// Different factory types have different stability methods
+		switch compKind {
+		case component.KindReceiver:
+			if rf, ok := factory.(interface {
+				ReceiverStability() component.StabilityLevel
+			}); ok {
+				stabilityLevel = rf.ReceiverStability().String()
+			}
+		case component.KindProcessor:
+			if pf, ok := factory.(interface {
+				ProcessorStability() component.StabilityLevel
+			}); ok {
+				stabilityLevel = pf.ProcessorStability().String()
+			}
+		case component.KindExporter:
+			if ef, ok := factory.(interface {
+				ExporterStability() component.StabilityLevel
+			}); ok {
+				stabilityLevel = ef.ExporterStability().String()
+			}
+		case component.KindConnector:
+			if cf, ok := factory.(interface {
+				ConnectorStability() component.StabilityLevel
+			}); ok {
+				stabilityLevel = cf.ConnectorStability().String()
+			}
+		case component.KindExtension:
+			if ef, ok := factory.(interface {
+				ExtensionStability() component.StabilityLevel
+			}); ok {
+				stabilityLevel = ef.ExtensionStability().String()
 			}
 		}
+	}
 
-		// Try to get factory
-		componentFactory := ext.GetComponentFactory()
-		if componentFactory == nil {
-			return nil, GetFactoryInfoOutput{
-				Type:      input.ComponentType,
-				Kind:      input.Kind,
-				Version:   version,
-				Available: moduleInfos != nil && version != "unknown",
-			}, nil
+	return GetFactoryInfoOutput{
+		Type:           componentType,
+		Kind:           kind,
+		StabilityLevel: stabilityLevel,
+		Version:        version,
+		Available:      true,
+	}, nil
+}
+
+type GenerateComponentConfigInput struct {
+	Kind          string `json:"kind" jsonschema:"Component kind (receiver, processor, exporter, connector, extension),required"`
+	ComponentType string `json:"component_type" jsonschema:"Component type (e.g. 'otlp', 'batch', 'debug'),required"`
+	Name          string `json:"name,omitempty" jsonschema:"Optional instance name, giving the component ID '<type>/<name>' (e.g. 'otlp/internal')"`
+}
+
+type GenerateComponentConfigOutput struct {
+	Kind          string `json:"kind"`
+	ComponentType string `json:"component_type"`
+	ComponentID   string `json:"component_id"`
+	ConfigType    string `json:"config_type"`
+	YAML          string `json:"yaml"`
+}
+
+// RegisterGenerateComponentConfig registers the generate_component_config tool
+func RegisterGenerateComponentConfig(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GenerateComponentConfigInput, GenerateComponentConfigOutput](server, &mcp.Tool{
+		Name:        "generate_component_config",
+		Description: "Generate a ready-to-edit YAML config snippet for a component type from its default config: the component under its section (e.g. receivers), with every field by its mapstructure name at its default value, nested as in the collector config. Durations are written as strings (e.g. 30s) and fields without a default are marked with a comment. Edit the values, remove the ones left at their default, and check the result with validate_config_section.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GenerateComponentConfigInput) (*mcp.CallToolResult, GenerateComponentConfigOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		schema, err := getComponentSchema(ext, input.Kind, input.ComponentType)
+		if err != nil {
+			return nil, GenerateComponentConfigOutput{}, err
 		}
 
-		factory := componentFactory.GetFactory(compKind, compType)
-		if factory == nil {
-			return nil, GetFactoryInfoOutput{
-				Type:      input.ComponentType,
-				Kind:      input.Kind,
-				Version:   version,
-				Available: false,
-			}, nil
+		componentID := input.ComponentType
+		if input.Name != "" {
+			componentID += "/" + input.Name
+		}
+		var id component.ID
+		if err := id.UnmarshalText([]byte(componentID)); err != nil {
+			return nil, GenerateComponentConfigOutput{}, fmt.Errorf("invalid component name: %w", err)
 		}
 
-		// Get stability level
-		stabilityLevel := "unknown"
-		if factory.Type() == compType {
-			// Factory exists, try to get stability via type assertion
-			// Different factory types have different stability methods
-			switch compKind {
-			case component.KindReceiver:
-				if rf, ok := factory.(interface {
-					ReceiverStability() component.StabilityLevel
-				}); ok {
-					stabilityLevel = rf.ReceiverStability().String()
-				}
-			case component.KindProcessor:
-				if pf, ok := factory.(interface {
-					ProcessorStability() component.StabilityLevel
-				}); ok {
-					stabilityLevel = pf.ProcessorStability().String()
-				}
-			case component.KindExporter:
-				if ef, ok := factory.(interface {
-					ExporterStability() component.StabilityLevel
-				}); ok {
-					stabilityLevel = ef.ExporterStability().String()
-				}
-			case component.KindConnector:
-				if cf, ok := factory.(interface {
-					ConnectorStability() component.StabilityLevel
-				}); ok {
-					stabilityLevel = cf.ConnectorStability().String()
-				}
-			case component.KindExtension:
-				if ef, ok := factory.(interface {
-					ExtensionStability() component.StabilityLevel
-				}); ok {
-					stabilityLevel = ef.ExtensionStability().String()
-				}
-			}
+		section := &yaml.Node{Kind: yaml.MappingNode}
+		section.Content = append(section.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: componentID},
+			defaultConfigNode(schema.Schema))
+		root := &yaml.Node{
+			Kind:        yaml.MappingNode,
+			HeadComment: fmt.Sprintf("Default config of the %s %s (%s)", input.ComponentType, input.Kind, schema.ConfigType),
+		}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: input.Kind + "s"},
+			section)
+
+		var buf strings.Builder
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}); err != nil {
+			return nil, GenerateComponentConfigOutput{}, fmt.Errorf("failed to encode config: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, GenerateComponentConfigOutput{}, fmt.Errorf("failed to encode config: %w", err)
 		}
 
-		return nil, GetFactoryInfoOutput{
-			Type:           input.ComponentType,
-			Kind:           input.Kind,
-			StabilityLevel: stabilityLevel,
-			Version:        version,
-			Available:      true,
+		return textResult(buf.String()), GenerateComponentConfigOutput{
+			Kind:          input.Kind,
+			ComponentType: input.ComponentType,
+			ComponentID:   componentID,
+			ConfigType:    schema.ConfigType,
+			YAML:          buf.String(),
 		}, nil
 	})
 }
+
+// defaultConfigNode converts a default config value as marshaled by confmap to
+// a YAML node. Map keys are sorted, durations are written in their string form
+// and values without a default are commented.
+func defaultConfigNode(value any) *yaml.Node {
+	switch v := value.(type) {
+	case map[string]any:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if len(v) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+				defaultConfigNode(v[key]))
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if len(v) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for _, item := range v {
+			node.Content = append(node.Content, defaultConfigNode(item))
+		}
+		return node
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null", LineComment: "no default"}
+	case time.Duration:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: v.String(), LineComment: "duration"}
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(value)}
+	}
+	return node
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+type GetComponentEndpointsInput struct {
+	ComponentID string `json:"component_id" jsonschema:"Configured component ID (e.g. 'otlp' 'otlp/internal' 'prometheus'),required"`
+	Kind        string `json:"kind,omitempty" jsonschema:"Component kind (receiver or extension),receiver"`
+}
+
+// ComponentEndpoint is an endpoint or address field of a component's effective
+// config
+type ComponentEndpoint struct {
+	// Key is the path of the field in the component config, e.g.
+	// protocols::grpc::endpoint
+	Key string `json:"key"`
+	// Protocol is the protocol section holding the field (e.g. grpc http), empty
+	// for top-level fields
+	Protocol string `json:"protocol,omitempty"`
+	Endpoint string `json:"endpoint"`
+	// Default is set when the value is the factory default rather than
+	// configured
+	Default bool `json:"default"`
+}
+
+type GetComponentEndpointsOutput struct {
+	Kind        string              `json:"kind"`
+	ComponentID string              `json:"component_id"`
+	Endpoints   []ComponentEndpoint `json:"endpoints"`
+}
+
+// endpointFields are the config field names holding a listen address
+var endpointFields = map[string]bool{
+	"endpoint":       true,
+	"address":        true,
+	"listen_address": true,
+}
+
+// RegisterGetComponentEndpoints registers the get_component_endpoints tool
+func RegisterGetComponentEndpoints(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetComponentEndpointsInput, GetComponentEndpointsOutput](server, &mcp.Tool{
+		Name:        "get_component_endpoints",
+		Description: "Get the endpoints a configured receiver (or extension) listens on, from its effective config: the factory default config merged with the configured values, so endpoints left at their default are included and marked as such. Components with several protocols (e.g. otlp grpc and http) return one endpoint per protocol. Use when debugging connectivity instead of reading the raw config.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetComponentEndpointsInput) (*mcp.CallToolResult, GetComponentEndpointsOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		if input.ComponentID == "" {
+			return nil, GetComponentEndpointsOutput{}, errors.New("component_id is required")
+		}
+		kind := input.Kind
+		if kind == "" {
+			kind = "receiver"
+		}
+		if kind != "receiver" && kind != "extension" {
+			return nil, GetComponentEndpointsOutput{}, fmt.Errorf("invalid kind: %s (must be receiver or extension)", input.Kind)
+		}
+		compKind, err := parseComponentKind(kind)
+		if err != nil {
+			return nil, GetComponentEndpointsOutput{}, err
+		}
+
+		var id component.ID
+		if err := id.UnmarshalText([]byte(input.ComponentID)); err != nil {
+			return nil, GetComponentEndpointsOutput{}, fmt.Errorf("invalid component ID: %w", err)
+		}
+
+		conf := ext.GetCollectorConf()
+		if conf == nil {
+			return nil, GetComponentEndpointsOutput{}, NewConfigError("get_component_endpoints", "", ErrConfigNotAvailable)
+		}
+		key := kind + "s::" + input.ComponentID
+		if !conf.IsSet(key) {
+			return nil, GetComponentEndpointsOutput{}, NewConfigError("get_component_endpoints", input.ComponentID, ErrComponentNotFound)
+		}
+		configured, err := conf.Sub(key)
+		if err != nil {
+			return nil, GetComponentEndpointsOutput{}, NewConfigError("get_component_endpoints", input.ComponentID, err)
+		}
+
+		componentFactory := ext.GetComponentFactory()
+		if componentFactory == nil {
+			return nil, GetComponentEndpointsOutput{}, errors.New("host does not provide ComponentFactory capability - cannot retrieve factory")
+		}
+		factory := componentFactory.GetFactory(compKind, id.Type())
+		if factory == nil {
+			return nil, GetComponentEndpointsOutput{}, fmt.Errorf("factory not found for %s/%s", kind, id.Type())
+		}
+
+		// Unmarshal the configured values over the default config, as the
+		// collector does when it builds the component
+		cfg := factory.CreateDefaultConfig()
+		if err := configured.Unmarshal(cfg); err != nil {
+			return nil, GetComponentEndpointsOutput{}, fmt.Errorf("failed to resolve config of %s: %w", input.ComponentID, err)
+		}
+		effective, err := marshalConfigSchema(cfg)
+		if err != nil {
+			return nil, GetComponentEndpointsOutput{}, err
+		}
+
+		endpoints := make([]ComponentEndpoint, 0)
+		findEndpoints("", effective, configured, &endpoints)
+		sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Key < endpoints[j].Key })
+
+		return nil, GetComponentEndpointsOutput{
+			Kind:        kind,
+			ComponentID: input.ComponentID,
+			Endpoints:   endpoints,
+		}, nil
+	})
+}
+
+// findEndpoints appends the non-empty endpoint fields below key of an
+// effective config, checking configured to tell configured values from
+// defaults
+func findEndpoints(key string, value any, configured *confmap.Conf, endpoints *[]ComponentEndpoint) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	for k, v := range m {
+		child := joinConfigKey(key, k)
+		if endpoint, ok := v.(string); ok && endpointFields[k] && endpoint != "" {
+			*endpoints = append(*endpoints, ComponentEndpoint{
+				Key:      child,
+				Protocol: endpointProtocol(key),
+				Endpoint: endpoint,
+				Default:  !configured.IsSet(child),
+			})
+			continue
+		}
+		findEndpoints(child, v, configured, endpoints)
+	}
+}
+
+// endpointProtocol names the protocol of the section holding an endpoint field:
+// the key below protocols, or else the innermost section
+func endpointProtocol(section string) string {
+	if section == "" {
+		return ""
+	}
+	parts := strings.Split(section, confmap.KeyDelimiter)
+	for i, part := range parts[:len(parts)-1] {
+		if part == "protocols" {
+			return parts[i+1]
+		}
+	}
+	return parts[len(parts)-1]
+}
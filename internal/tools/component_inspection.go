@@ -0,0 +1,89 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/component"
+)
+
+type InspectComponentInput struct {
+	Kind        string `json:"kind" jsonschema:"Component kind (receiver, processor, exporter, connector, extension),required"`
+	ComponentID string `json:"component_id" jsonschema:"Component ID (e.g. 'otlp' 'otlp/custom' 'batch'),required"`
+}
+
+type InspectComponentOutput struct {
+	Kind          string                    `json:"kind"`
+	ComponentID   string                    `json:"component_id"`
+	ComponentType string                    `json:"component_type"`
+	Config        map[string]any            `json:"config,omitempty"`
+	Schema        *GetComponentSchemaOutput `json:"schema,omitempty"`
+	FactoryInfo   *GetFactoryInfoOutput     `json:"factory_info,omitempty"`
+	Status        *ComponentStatus          `json:"status,omitempty"`
+	Unavailable   map[string]string         `json:"unavailable,omitempty"`
+}
+
+// RegisterInspectComponent registers the inspect_component tool
+func RegisterInspectComponent(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[InspectComponentInput, InspectComponentOutput](server, &mcp.Tool{
+		Name:        "inspect_component",
+		Description: "Get the full picture of a component in one call: its configuration, config schema, factory info and runtime status. Parts that cannot be resolved are listed under 'unavailable' with the reason.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input InspectComponentInput) (*mcp.CallToolResult, InspectComponentOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		if _, err := parseComponentKind(input.Kind); err != nil {
+			return nil, InspectComponentOutput{}, err
+		}
+
+		var id component.ID
+		if err := id.UnmarshalText([]byte(input.ComponentID)); err != nil {
+			return nil, InspectComponentOutput{}, fmt.Errorf("invalid component ID: %w", err)
+		}
+
+		output := InspectComponentOutput{
+			Kind:          input.Kind,
+			ComponentID:   input.ComponentID,
+			ComponentType: id.Type().String(),
+			Unavailable:   make(map[string]string),
+		}
+
+		if cfg, err := getComponentConfig(ext, input.Kind, input.ComponentID); err != nil {
+			output.Unavailable["config"] = err.Error()
+		} else {
+			output.Config = cfg
+		}
+
+		if schema, err := getComponentSchema(ext, input.Kind, output.ComponentType); err != nil {
+			output.Unavailable["schema"] = err.Error()
+		} else {
+			output.Schema = &schema
+		}
+
+		if info, err := getFactoryInfo(ext, input.Kind, output.ComponentType); err != nil {
+			output.Unavailable["factory_info"] = err.Error()
+		} else {
+			output.FactoryInfo = &info
+		}
+
+		if conf := ext.GetCollectorConf(); conf == nil {
+			output.Unavailable["status"] = ErrConfigNotAvailable.Error()
+		} else if statuses, _ := componentStatuses(conf, input.Kind, input.ComponentID); len(statuses) == 0 {
+			output.Unavailable["status"] = ErrComponentNotFound.Error()
+		} else {
+			output.Status = &statuses[0]
+		}
+
+		if len(output.Unavailable) == 0 {
+			output.Unavailable = nil
+		}
+
+		return nil, output, nil
+	})
+}
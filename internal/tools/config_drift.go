@@ -0,0 +1,153 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+type ConfigDriftInput struct {
+	Baseline map[string]any `json:"baseline" jsonschema:"Known-good collector configuration to compare the running configuration against,required"`
+	Section  string         `json:"section,omitempty" jsonschema:"Only compare this top-level section (receivers processors exporters connectors extensions service). Omit for the whole config"`
+}
+
+// ConfigChange is a key whose value differs between the baseline and the
+// running config. A subtree present on one side only is reported once, at its
+// root key.
+type ConfigChange struct {
+	Key     string `json:"key"`
+	Section string `json:"section"`
+	// Change is added (only in the running config), removed (only in the
+	// baseline) or changed
+	Change string `json:"change"`
+	// Severity is high for pipeline changes and added or removed components,
+	// medium for changed component settings and low otherwise
+	Severity string `json:"severity"`
+	Baseline any    `json:"baseline,omitempty"`
+	Running  any    `json:"running,omitempty"`
+}
+
+type ConfigDriftOutput struct {
+	Drifted     bool           `json:"drifted"`
+	ChangeCount int            `json:"change_count"`
+	Severities  map[string]int `json:"severities,omitempty"`
+	Changes     []ConfigChange `json:"changes"`
+}
+
+// componentSections are the top-level sections holding component instances
+var componentSections = map[string]bool{
+	"receivers":  true,
+	"processors": true,
+	"exporters":  true,
+	"connectors": true,
+	"extensions": true,
+}
+
+// RegisterConfigDrift registers the config_drift tool
+func RegisterConfigDrift(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ConfigDriftInput, ConfigDriftOutput](server, &mcp.Tool{
+		Name:        "config_drift",
+		Description: "Compare the running collector configuration against a known-good baseline config and list every key that was added, removed or changed, sorted by key. Each change has its top-level section and a severity: high for service pipeline changes and added or removed components, medium for changed component settings, low otherwise (e.g. service telemetry). Use to check a live collector against its intended state.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ConfigDriftInput) (*mcp.CallToolResult, ConfigDriftOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		if input.Baseline == nil {
+			return nil, ConfigDriftOutput{}, errors.New("baseline is required")
+		}
+		conf := ext.GetCollectorConf()
+		if conf == nil {
+			return nil, ConfigDriftOutput{}, NewConfigError("config_drift", "", ErrConfigNotAvailable)
+		}
+
+		// Round trip the baseline through confmap so both sides use the same
+		// key separators and value types
+		baseline := confmap.NewFromStringMap(input.Baseline).ToStringMap()
+		running := conf.ToStringMap()
+		if input.Section != "" {
+			baseline = map[string]any{input.Section: baseline[input.Section]}
+			running = map[string]any{input.Section: running[input.Section]}
+		}
+
+		changes := make([]ConfigChange, 0)
+		diffConfig("", baseline, running, &changes)
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+		output := ConfigDriftOutput{Drifted: len(changes) > 0, ChangeCount: len(changes), Changes: changes}
+		if output.Drifted {
+			output.Severities = make(map[string]int)
+			for _, change := range changes {
+				output.Severities[change.Severity]++
+			}
+		}
+		return nil, output, nil
+	})
+}
+
+// diffConfig appends the differences between the baseline and running values
+// at key. Maps are compared key by key; other values, lists included, are
+// compared whole. Nil and empty maps are equal, as a component without
+// settings may be either.
+func diffConfig(key string, baseline, running any, changes *[]ConfigChange) {
+	baselineMap, baselineIsMap := baseline.(map[string]any)
+	runningMap, runningIsMap := running.(map[string]any)
+	if (baselineIsMap || baseline == nil) && (runningIsMap || running == nil) {
+		for k, v := range baselineMap {
+			child := joinConfigKey(key, k)
+			if rv, ok := runningMap[k]; ok {
+				diffConfig(child, v, rv, changes)
+			} else {
+				*changes = append(*changes, newConfigChange(child, "removed", v, nil))
+			}
+		}
+		for k, v := range runningMap {
+			if _, ok := baselineMap[k]; !ok {
+				*changes = append(*changes, newConfigChange(joinConfigKey(key, k), "added", nil, v))
+			}
+		}
+		return
+	}
+
+	if fmt.Sprint(baseline) != fmt.Sprint(running) {
+		*changes = append(*changes, newConfigChange(key, "changed", baseline, running))
+	}
+}
+
+// newConfigChange classifies a change by its section and severity
+func newConfigChange(key, change string, baseline, running any) ConfigChange {
+	parts := strings.Split(key, confmap.KeyDelimiter)
+	severity := "low"
+	switch {
+	case parts[0] == "service" && len(parts) > 1 && (parts[1] == "pipelines" || parts[1] == "extensions"):
+		severity = "high"
+	case componentSections[parts[0]] && len(parts) <= 2 && change != "changed":
+		severity = "high"
+	case componentSections[parts[0]]:
+		severity = "medium"
+	}
+	return ConfigChange{
+		Key:      key,
+		Section:  parts[0],
+		Change:   change,
+		Severity: severity,
+		Baseline: baseline,
+		Running:  running,
+	}
+}
+
+func joinConfigKey(key, child string) string {
+	if key == "" {
+		return child
+	}
+	return key + confmap.KeyDelimiter + child
+}
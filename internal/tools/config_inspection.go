@@ -5,20 +5,44 @@ package tools
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type GetConfigInput struct {
-	Section string `json:"section,omitempty" jsonschema:"Configuration section to retrieve (receivers processors exporters connectors extensions service telemetry). Omit for full config"`
+	Section         string `json:"section,omitempty" jsonschema:"Configuration section to retrieve (receivers processors exporters connectors extensions service telemetry). Omit for full config"`
+	CheckReferences bool   `json:"check_references,omitempty" jsonschema:"Wrap the config as {resolved unresolved_references config}, listing values that still hold a ${...} reference,false"`
 }
 
+// GetConfigOutput is the collector configuration with its resolution status,
+// returned by get_config when check_references is set
+type GetConfigOutput struct {
+	// Resolved is true when no value still holds a ${...} reference
+	Resolved             bool                  `json:"resolved"`
+	UnresolvedReferences []UnresolvedReference `json:"unresolved_references,omitempty"`
+	Config               any                   `json:"config"`
+}
+
+// UnresolvedReference is a config value that still holds a ${...} reference
+type UnresolvedReference struct {
+	Key       string `json:"key"`
+	Reference string `json:"reference"`
+}
+
+// configReferencePattern matches a confmap provider or env reference such as
+// ${env:FOO} or ${file:/path}, with the dollar signs preceding it
+var configReferencePattern = regexp.MustCompile(`(\$*)(\$\{[^}]+\})`)
+
 // RegisterGetConfig registers the get_config tool
 func RegisterGetConfig(server *mcp.Server, ext ExtensionContext) {
 	mcp.AddTool[GetConfigInput, any](server, &mcp.Tool{
 		Name:        "get_config",
-		Description: "Get the current collector configuration. Returns JSON with ALL defaults expanded (including zero values, empty strings, etc.). When writing configs, omit fields set to their default values to keep YAML concise. Time durations are in nanoseconds (e.g., 30000000000 = 30s).",
+		Description: "Get the current collector configuration. Returns JSON with ALL defaults expanded (including zero values, empty strings, etc.). When writing configs, omit fields set to their default values to keep YAML concise. Time durations are in nanoseconds (e.g., 30000000000 = 30s). The collector delivers the config after resolving ${env:...} and other provider references, so values are the ones it runs with. Set check_references to wrap the config with resolved and the unresolved_references still holding a ${...} reference; escaped $${...} literals are not reported.",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
@@ -30,60 +54,120 @@ func RegisterGetConfig(server *mcp.Server, ext ExtensionContext) {
 			return nil, nil, NewConfigError("get_config", "", ErrConfigNotAvailable)
 		}
 
+		var result any
 		if input.Section == "" {
 			// Return full config
-			return nil, conf.ToStringMap(), nil
+			result = conf.ToStringMap()
+		} else {
+			// Return specific section
+			result = conf.Get(input.Section)
+			if result == nil {
+				return nil, nil, NewConfigError("get_config", input.Section, ErrSectionNotFound)
+			}
 		}
-
-		// Return specific section
-		result := conf.Get(input.Section)
-		if result == nil {
-			return nil, nil, NewConfigError("get_config", input.Section, ErrSectionNotFound)
+		if !input.CheckReferences {
+			return nil, result, nil
 		}
-		return nil, result, nil
+
+		output := GetConfigOutput{Config: result}
+		findUnresolvedReferences(input.Section, result, &output.UnresolvedReferences)
+		output.Resolved = len(output.UnresolvedReferences) == 0
+		return nil, output, nil
 	})
 }
 
+// findUnresolvedReferences appends the string values under key that still hold
+// a ${...} reference, in key order. A reference preceded by an odd number of
+// dollar signs is an escaped literal: $$ stands for $.
+func findUnresolvedReferences(key string, value any, refs *[]UnresolvedReference) {
+	switch v := value.(type) {
+	case string:
+		for _, match := range configReferencePattern.FindAllStringSubmatch(v, -1) {
+			if len(match[1])%2 == 1 {
+				continue
+			}
+			*refs = append(*refs, UnresolvedReference{Key: key, Reference: match[2]})
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := k
+			if key != "" {
+				child = key + "::" + k
+			}
+			findUnresolvedReferences(child, v[k], refs)
+		}
+	case []any:
+		for i, item := range v {
+			findUnresolvedReferences(fmt.Sprintf("%s::%d", key, i), item, refs)
+		}
+	}
+}
+
 type GetComponentConfigInput struct {
-	ComponentID string `json:"component_id" jsonschema:"Component ID (e.g. 'otlp' 'otlp/custom' 'batch'),required"`
-	Kind        string `json:"kind" jsonschema:"Component kind (receiver processor exporter connector extension),required"`
+	ComponentID string `json:"component_id" jsonschema:"Component ID (e.g. 'otlp' 'otlp/custom' 'batch'). For kind 'service' use 'telemetry' or a subsection such as 'telemetry::logs',required"`
+	Kind        string `json:"kind" jsonschema:"Component kind (receiver processor exporter connector extension service),required"`
 }
 
 // RegisterGetComponentConfig registers the get_component_config tool
 func RegisterGetComponentConfig(server *mcp.Server, ext ExtensionContext) {
 	mcp.AddTool[GetComponentConfigInput, any](server, &mcp.Tool{
 		Name:        "get_component_config",
-		Description: "Get configuration for a specific component instance. Returns the effective configuration as reported by the collector.",
+		Description: "Get configuration for a specific component instance. Returns the effective configuration as reported by the collector. Use kind 'service' with component_id 'telemetry' (or 'telemetry::logs', 'telemetry::metrics', 'telemetry::traces') to inspect the collector's self-telemetry settings.",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetComponentConfigInput) (*mcp.CallToolResult, any, error) { //nolint:revive // ctx unused but kept for interface compatibility
-		conf := ext.GetCollectorConf()
-		if conf == nil {
-			return nil, nil, NewConfigError("get_component_config", "", ErrConfigNotAvailable)
-		}
-
-		section := input.Kind + "s"
-		subConf, err := conf.Sub(section + "::" + input.ComponentID)
+		cfg, err := getComponentConfig(ext, input.Kind, input.ComponentID)
 		if err != nil {
-			return nil, nil, NewConfigError("get_component_config", input.ComponentID, ErrComponentNotFound)
+			return nil, nil, err
 		}
-		if subConf == nil {
-			return nil, nil, NewConfigError("get_component_config", input.ComponentID, ErrComponentNotFound)
-		}
-
-		return nil, subConf.ToStringMap(), nil
+		return nil, cfg, nil
 	})
 }
 
+// getComponentConfig returns the configuration of a single component instance
+func getComponentConfig(ext ExtensionContext, kind, componentID string) (map[string]any, error) {
+	conf := ext.GetCollectorConf()
+	if conf == nil {
+		return nil, NewConfigError("get_component_config", "", ErrConfigNotAvailable)
+	}
+
+	key := kind + "s::" + componentID
+	if kind == "service" {
+		// The service section is not a component list, address its subsections directly
+		key = "service::" + componentID
+	}
+	if !conf.IsSet(key) {
+		return nil, NewConfigError("get_component_config", componentID, ErrComponentNotFound)
+	}
+
+	subConf, err := conf.Sub(key)
+	if err != nil {
+		return nil, NewConfigError("get_component_config", componentID, ErrComponentNotFound)
+	}
+	if subConf == nil {
+		return nil, NewConfigError("get_component_config", componentID, ErrComponentNotFound)
+	}
+
+	return subConf.ToStringMap(), nil
+}
+
 type ListConfiguredComponentsInput struct {
 	Kind string `json:"kind,omitempty" jsonschema:"Filter by component kind (receiver processor exporter connector extension). Omit for all"`
 }
 
 type ListConfiguredComponentsOutput struct {
 	Components map[string][]string `json:"components"`
+	// Warnings lists config sections skipped because they are not maps of
+	// components
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // RegisterListConfiguredComponents registers the list_configured_components tool
@@ -103,6 +187,7 @@ func RegisterListConfiguredComponents(server *mcp.Server, ext ExtensionContext)
 		}
 
 		result := make(map[string][]string)
+		var warnings []string
 
 		kinds := []string{"receivers", "processors", "exporters", "connectors", "extensions"}
 		if input.Kind != "" {
@@ -115,16 +200,19 @@ func RegisterListConfiguredComponents(server *mcp.Server, ext ExtensionContext)
 				continue
 			}
 
-			if sectionMap, ok := section.(map[string]any); ok {
-				components := make([]string, 0, len(sectionMap))
-				for id := range sectionMap {
-					components = append(components, id)
-				}
-				result[strings.TrimSuffix(kind, "s")] = components
+			sectionMap, ok := section.(map[string]any)
+			if !ok {
+				warnings = append(warnings, configTypeWarning(kind, section, "a map of components"))
+				continue
+			}
+			components := make([]string, 0, len(sectionMap))
+			for id := range sectionMap {
+				components = append(components, id)
 			}
+			result[strings.TrimSuffix(kind, "s")] = components
 		}
 
-		return nil, ListConfiguredComponentsOutput{Components: result}, nil
+		return nil, ListConfiguredComponentsOutput{Components: result, Warnings: warnings}, nil
 	})
 }
 
@@ -157,6 +245,41 @@ func RegisterGetPipelineConfig(server *mcp.Server, ext ExtensionContext) {
 	})
 }
 
+type GetConfigValueInput struct {
+	Key string `json:"key" jsonschema:"Config key path with '::' separators (e.g. 'receivers::otlp::protocols::grpc::endpoint' 'service::telemetry::logs::level'),required"`
+}
+
+type GetConfigValueOutput struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// RegisterGetConfigValue registers the get_config_value tool
+func RegisterGetConfigValue(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetConfigValueInput, GetConfigValueOutput](server, &mcp.Tool{
+		Name:        "get_config_value",
+		Description: "Get a single value of the current collector configuration by its key path, e.g. 'receivers::otlp::protocols::grpc::endpoint'. Cheaper than get_config when only one setting is needed. The value may be a scalar, list or map with defaults expanded; durations are in nanoseconds.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetConfigValueInput) (*mcp.CallToolResult, GetConfigValueOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		if input.Key == "" {
+			return nil, GetConfigValueOutput{}, errors.New("key is required")
+		}
+		conf := ext.GetCollectorConf()
+		if conf == nil {
+			return nil, GetConfigValueOutput{}, NewConfigError("get_config_value", "", ErrConfigNotAvailable)
+		}
+
+		if !conf.IsSet(input.Key) {
+			return nil, GetConfigValueOutput{}, NewConfigError("get_config_value", input.Key, ErrKeyNotFound)
+		}
+		return nil, GetConfigValueOutput{Key: input.Key, Value: conf.Get(input.Key)}, nil
+	})
+}
+
 // Helper function to create a bool pointer
 func boolPtr(b bool) *bool {
 	return &b
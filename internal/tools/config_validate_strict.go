@@ -0,0 +1,136 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+	"go.opentelemetry.io/collector/service/hostcapabilities"
+)
+
+// componentKinds are the component kinds of a collector config, in the order
+// their sections are validated
+var componentKinds = []string{"receiver", "processor", "exporter", "connector", "extension"}
+
+type ValidateConfigStrictInput struct {
+	Config map[string]any `json:"config" jsonschema:"Complete configuration to validate, or only its component sections,required"`
+}
+
+// ComponentValidation is the outcome of building and validating the config of
+// one component
+type ComponentValidation struct {
+	Kind        string `json:"kind"`
+	ComponentID string `json:"component_id"`
+	Valid       bool   `json:"valid"`
+	// Error is the unmarshal or Validate error, or the reason the component
+	// could not be validated (e.g. an unknown component type)
+	Error string `json:"error,omitempty"`
+}
+
+type ValidateConfigStrictOutput struct {
+	Valid        bool                  `json:"valid"`
+	Message      string                `json:"message"`
+	InvalidCount int                   `json:"invalid_count"`
+	Components   []ComponentValidation `json:"components"`
+}
+
+// RegisterValidateConfigStrict registers the validate_config_strict tool
+func RegisterValidateConfigStrict(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ValidateConfigStrictInput, ValidateConfigStrictOutput](server, &mcp.Tool{
+		Name:        "validate_config_strict",
+		Description: "Validate the components of a proposed configuration against the collector's component factories: for every receiver, processor, exporter, connector and extension, the factory's default config is created, the provided settings are unmarshaled onto it and the component's Validate() is run, as the collector does on startup. Returns the result per component, reporting unknown component types, unknown fields and invalid values. Pipelines are not checked; use validate_config for the overall structure.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ValidateConfigStrictInput) (*mcp.CallToolResult, ValidateConfigStrictOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		if input.Config == nil {
+			return nil, ValidateConfigStrictOutput{}, errors.New("config cannot be nil")
+		}
+		componentFactory := ext.GetComponentFactory()
+		if componentFactory == nil {
+			return nil, ValidateConfigStrictOutput{}, errors.New("host does not provide ComponentFactory capability - cannot validate against factories")
+		}
+
+		output := ValidateConfigStrictOutput{Components: []ComponentValidation{}}
+		for _, kind := range componentKinds {
+			section := validKindsMap()[kind]
+			value, ok := input.Config[section]
+			if !ok || value == nil {
+				continue
+			}
+			components, ok := value.(map[string]any)
+			if !ok {
+				return nil, ValidateConfigStrictOutput{}, fmt.Errorf("invalid %s section: must map component IDs to their settings", section)
+			}
+
+			ids := make([]string, 0, len(components))
+			for id := range components {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			for _, id := range ids {
+				result := ComponentValidation{Kind: kind, ComponentID: id, Valid: true}
+				if err := validateComponentConfig(componentFactory, kind, id, components[id]); err != nil {
+					result.Valid = false
+					result.Error = err.Error()
+					output.InvalidCount++
+				}
+				output.Components = append(output.Components, result)
+			}
+		}
+
+		output.Valid = output.InvalidCount == 0
+		switch {
+		case len(output.Components) == 0:
+			output.Message = "Configuration has no components to validate"
+		case output.Valid:
+			output.Message = fmt.Sprintf("All %d components are valid", len(output.Components))
+		default:
+			output.Message = fmt.Sprintf("%d of %d components are invalid", output.InvalidCount, len(output.Components))
+		}
+		return nil, output, nil
+	})
+}
+
+// validateComponentConfig builds the config of a component from its factory's
+// default config and settings, and validates it
+func validateComponentConfig(componentFactory hostcapabilities.ComponentFactory, kind, id string, settings any) error {
+	var componentID component.ID
+	if err := componentID.UnmarshalText([]byte(id)); err != nil {
+		return fmt.Errorf("invalid component ID: %w", err)
+	}
+	compKind, err := parseComponentKind(kind)
+	if err != nil {
+		return err
+	}
+	factory := componentFactory.GetFactory(compKind, componentID.Type())
+	if factory == nil {
+		return fmt.Errorf("unknown %s type %q: no factory available", kind, componentID.Type())
+	}
+
+	var conf *confmap.Conf
+	switch s := settings.(type) {
+	case nil:
+		conf = confmap.New()
+	case map[string]any:
+		conf = confmap.NewFromStringMap(s)
+	default:
+		return errors.New("settings must be a map")
+	}
+
+	cfg := factory.CreateDefaultConfig()
+	if err := conf.Unmarshal(cfg); err != nil {
+		return err
+	}
+	return xconfmap.Validate(cfg)
+}
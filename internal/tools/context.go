@@ -4,6 +4,9 @@
 package tools
 
 import (
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -31,7 +34,47 @@ type ExtensionContext interface {
 	GetRecentTraces(limit, offset int) []ptrace.Traces
 	GetRecentMetrics(limit, offset int) []pmetric.Metrics
 	GetRecentLogs(limit, offset int) []plog.Logs
+	GetLogBatches(limit, offset int) []LogBatch
+	AddTraces(td ptrace.Traces)
+	AddMetrics(md pmetric.Metrics)
+	AddLogs(ld plog.Logs)
+	GetBatchInfos(signal string, limit, offset int) []BatchInfo
+	GetBatchTimes(signal string, limit, offset int) []BatchInfo
+	GetSignalDrops(signal string) SignalDrops
 	GetBufferStats() BufferStats
+	EvictTrace(traceID string) int
+	GetReadiness() Readiness
+
+	// GetCachedTrace returns the spans of a trace from the trace assembly
+	// cache, and false when the trace is not cached or the cache is disabled
+	GetCachedTrace(traceID string) (ptrace.Traces, bool)
+
+	// Query limits. The max query limit is always positive; 0 disables the
+	// response byte and trace span limits
+	GetMaxQueryLimit() int
+	GetMaxResponseBytes() int
+	GetMaxTraceSpans() int
+
+	// Default limits used when a tool call omits limit
+	GetDefaultQueryLimit() int
+	GetDefaultRecentLimit() int
+
+	// Time zone rendered timestamps are shown in
+	GetLocation() *time.Location
+
+	// Directory export_buffer and import_buffer dump files live in, empty
+	// when file dumps are disabled
+	GetDumpDir() string
+
+	// IsRedactedAttribute reports whether the values of an attribute key are
+	// sensitive and must be redacted in rendered telemetry
+	IsRedactedAttribute(key string) bool
+
+	// Group (config, discovery, telemetry) of every tool the extension can register, by tool name
+	GetToolGroups() map[string]string
+
+	// Tools registered on the MCP server, with their annotations and schemas
+	GetRegisteredTools() []*mcp.Tool
 }
 
 // BufferStats mirrors the internal buffer stats
@@ -43,3 +86,45 @@ type BufferStats struct {
 	LogsCount       int
 	LogsCapacity    int
 }
+
+// LogBatch is a buffered log batch with the sequence number the buffer assigned to it
+type LogBatch struct {
+	Seq  uint64
+	Logs plog.Logs
+}
+
+// BatchInfo describes a buffered batch of a signal without its data
+type BatchInfo struct {
+	Seq        uint64
+	ReceivedAt time.Time
+	// Items is the number of spans, data points or log records in the batch
+	Items int
+	// SizeBytes is the size of the batch encoded as OTLP protobuf; 0 when
+	// returned by GetBatchTimes
+	SizeBytes int
+}
+
+// SignalDrops counts the batches a signal's buffer rejected because buffering
+// is disabled or the buffer was full under the reject_new policy
+type SignalDrops struct {
+	Batches int
+	Items   int
+	// Recent summarizes the most recently rejected batches, oldest first
+	Recent []DroppedBatch
+}
+
+// DroppedBatch summarizes a rejected batch
+type DroppedBatch struct {
+	DroppedAt time.Time
+	Items     int
+	Services  []string
+}
+
+// Readiness records when the extension first received its configuration and
+// each telemetry signal. Zero times mean nothing has been received yet.
+type Readiness struct {
+	FirstConfigAt  time.Time
+	FirstTracesAt  time.Time
+	FirstMetricsAt time.Time
+	FirstLogsAt    time.Time
+}
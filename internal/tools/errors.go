@@ -14,12 +14,18 @@ var (
 	ErrSectionNotFound    = errors.New("configuration section not found")
 	ErrComponentNotFound  = errors.New("component not found")
 	ErrPipelineNotFound   = errors.New("pipeline not found")
+	ErrKeyNotFound        = errors.New("configuration key not found")
 
 	// Buffer errors
 	ErrBufferEmpty    = errors.New("telemetry buffer is empty")
+	ErrBufferDisabled = errors.New("buffering is disabled")
 	ErrInvalidLimit   = errors.New("limit must be positive")
 	ErrInvalidOffset  = errors.New("offset must be non-negative")
+	ErrInvalidMaxSize = errors.New("max_response_bytes must be non-negative")
+	ErrInvalidDelim   = errors.New("csv_delimiter must be a single character other than a quote or line break")
 	ErrMetricNotFound = errors.New("metric not found")
+	ErrInvalidTraceID = errors.New("trace ID must be 32 hex characters")
+	ErrInvalidLogID   = errors.New("log ID must have the form <batch>-<resource>-<scope>-<record>")
 
 	// Host errors
 	ErrHostNotAvailable  = errors.New("component host not yet available")
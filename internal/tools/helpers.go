@@ -4,9 +4,20 @@
 package tools
 
 import (
+	"context"
+	"encoding/csv"
 	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 // parseComponentKind validates and parses a component kind string into a component.Kind
@@ -37,3 +48,323 @@ func validKindsMap() map[string]string {
 		"extension": "extensions",
 	}
 }
+
+// resolveLimit applies the tool default to an unset limit and clamps it to the
+// configured maximum
+func resolveLimit(ext ExtensionContext, limit, defaultLimit int) (int, error) {
+	if limit < 0 {
+		return 0, ErrInvalidLimit
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if maxLimit := ext.GetMaxQueryLimit(); maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+	return limit, nil
+}
+
+// responseTruncatedMarker ends rendered output cut at the response size limit
+const responseTruncatedMarker = "\n[output truncated, refine your query]\n"
+
+// resolveMaxResponseBytes returns the size limit of a call's rendered output:
+// requested when set, but never above the configured max_response_bytes.
+// 0 means unlimited.
+func resolveMaxResponseBytes(ext ExtensionContext, requested int) (int, error) {
+	if requested < 0 {
+		return 0, ErrInvalidMaxSize
+	}
+	limit := ext.GetMaxResponseBytes()
+	if requested > 0 && (limit == 0 || requested < limit) {
+		limit = requested
+	}
+	return limit, nil
+}
+
+// truncateOutput cuts text to at most limit bytes including marker, at the
+// last line break that fits so table rows stay whole. A marker longer than
+// limit is itself cut. It reports whether text was cut; a limit of 0 keeps
+// text whole.
+func truncateOutput(text string, limit int, marker string) (string, bool) {
+	if limit <= 0 || len(text) <= limit {
+		return text, false
+	}
+	if len(marker) >= limit {
+		return marker[:limit], true
+	}
+	keep := max(limit-len(marker), 0)
+	if i := strings.LastIndexByte(text[:keep], '\n'); i >= 0 {
+		keep = i + 1
+	} else {
+		for keep > 0 && !utf8.RuneStart(text[keep]) {
+			keep--
+		}
+	}
+	return text[:keep] + marker, true
+}
+
+// newCSVWriter creates a CSV writer separating fields with delimiter, a comma
+// when empty. The delimiter must be a single character other than a quote or
+// line break.
+func newCSVWriter(w io.Writer, delimiter string) (*csv.Writer, error) {
+	csvWriter := csv.NewWriter(w)
+	if delimiter == "" {
+		return csvWriter, nil
+	}
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size != len(delimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDelim, delimiter)
+	}
+	csvWriter.Comma = r
+	return csvWriter, nil
+}
+
+// textResult returns a tool result carrying text as a content block, so MCP
+// clients render markdown or CSV output natively. The SDK still fills the
+// structured content from the tool's output struct.
+func textResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}
+}
+
+// scanInterrupted reports whether a scan over the buffer should stop because
+// ctx was cancelled or timed out. Scan tools then return what they collected
+// so far with Truncated set in their output rather than failing, so large
+// buffers still yield partial results.
+func scanInterrupted(ctx context.Context) bool {
+	return ctx.Err() != nil
+}
+
+// redactedValue replaces the values of sensitive attributes in rendered
+// telemetry
+const redactedValue = "[REDACTED]"
+
+// redactValue returns the value of attribute key, or redactedValue when redact
+// matches the key
+func redactValue(key, value string, redact func(key string) bool) string {
+	if redact != nil && redact(key) {
+		return redactedValue
+	}
+	return value
+}
+
+// redactAttributes replaces the values of the keys of attrs matched by redact
+// in place
+func redactAttributes(attrs pcommon.Map, redact func(key string) bool) {
+	var keys []string
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		if redact(k) {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	for _, k := range keys {
+		attrs.PutStr(k, redactedValue)
+	}
+}
+
+// redactTraces redacts the resource, span, event and link attributes of td in
+// place. td must be a copy, never a buffered batch.
+func redactTraces(td ptrace.Traces, redact func(key string) bool) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		redactAttributes(rs.Resource().Attributes(), redact)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				redactAttributes(span.Attributes(), redact)
+				for l := 0; l < span.Events().Len(); l++ {
+					redactAttributes(span.Events().At(l).Attributes(), redact)
+				}
+				for l := 0; l < span.Links().Len(); l++ {
+					redactAttributes(span.Links().At(l).Attributes(), redact)
+				}
+			}
+		}
+	}
+}
+
+// redactMetrics redacts the resource, data point and exemplar attributes of md
+// in place. md must be a copy, never a buffered batch.
+func redactMetrics(md pmetric.Metrics, redact func(key string) bool) {
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		redactAttributes(rm.Resource().Attributes(), redact)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				forEachDataPoint(metrics.At(k), func(attrs pcommon.Map, _ pcommon.Timestamp) bool {
+					redactAttributes(attrs, redact)
+					return true
+				})
+				forEachExemplar(metrics.At(k), func(exemplar pmetric.Exemplar) {
+					redactAttributes(exemplar.FilteredAttributes(), redact)
+				})
+			}
+		}
+	}
+}
+
+// redactLogs redacts the resource and log record attributes of ld in place. ld
+// must be a copy, never a buffered batch.
+func redactLogs(ld plog.Logs, redact func(key string) bool) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		redactAttributes(rl.Resource().Attributes(), redact)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				redactAttributes(records.At(k).Attributes(), redact)
+			}
+		}
+	}
+}
+
+// checkBufferEnabled returns ErrBufferDisabled if the buffer of a signal
+// ("traces", "metrics" or "logs") was configured with a size of 0
+func checkBufferEnabled(ext ExtensionContext, signal string) error {
+	stats := ext.GetBufferStats()
+	capacity := map[string]int{
+		"traces":  stats.TracesCapacity,
+		"metrics": stats.MetricsCapacity,
+		"logs":    stats.LogsCapacity,
+	}[signal]
+	if capacity == 0 {
+		return fmt.Errorf("%w for %s (%s_buffer_size is 0)", ErrBufferDisabled, signal, signal)
+	}
+	return nil
+}
+
+// validateOffset rejects negative offsets
+func validateOffset(offset int) error {
+	if offset < 0 {
+		return ErrInvalidOffset
+	}
+	return nil
+}
+
+// parseTimeRange parses optional RFC3339 start and end bounds. A zero timestamp
+// means the range is open on that side.
+func parseTimeRange(start, end string) (pcommon.Timestamp, pcommon.Timestamp, error) {
+	var from, to pcommon.Timestamp
+	if start != "" {
+		t, err := time.Parse(time.RFC3339Nano, start)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid start_time: %w", err)
+		}
+		from = pcommon.NewTimestampFromTime(t)
+	}
+	if end != "" {
+		t, err := time.Parse(time.RFC3339Nano, end)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid end_time: %w", err)
+		}
+		to = pcommon.NewTimestampFromTime(t)
+	}
+	if from != 0 && to != 0 && to < from {
+		return 0, 0, fmt.Errorf("end_time %s is before start_time %s", end, start)
+	}
+	return from, to, nil
+}
+
+// inTimeRange reports whether ts falls within the bounds returned by parseTimeRange
+func inTimeRange(ts, from, to pcommon.Timestamp) bool {
+	if from != 0 && ts < from {
+		return false
+	}
+	if to != 0 && ts > to {
+		return false
+	}
+	return true
+}
+
+// resolveLocation returns the time zone to render timestamps in: the IANA zone
+// named by timezone, or the configured one when timezone is empty
+func resolveLocation(ext ExtensionContext, timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return ext.GetLocation(), nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
+
+// attributePrefixFilter returns a predicate keeping the attribute keys that
+// start with one of include, all when include is empty, unless they start with
+// one of exclude. It returns nil, keeping every key, when both are empty.
+func attributePrefixFilter(include, exclude []string) func(key string) bool {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	return func(key string) bool {
+		for _, prefix := range exclude {
+			if strings.HasPrefix(key, prefix) {
+				return false
+			}
+		}
+		if len(include) == 0 {
+			return true
+		}
+		for _, prefix := range include {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// formatTimestamp formats ts with layout in loc, UTC when loc is nil
+func formatTimestamp(ts pcommon.Timestamp, loc *time.Location, layout string) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return ts.AsTime().In(loc).Format(layout)
+}
+
+// timeBounds tracks the earliest and latest of the timestamps added to it.
+// Zero timestamps are ignored.
+type timeBounds struct {
+	earliest, latest pcommon.Timestamp
+}
+
+func (b *timeBounds) add(ts pcommon.Timestamp) {
+	if ts == 0 {
+		return
+	}
+	if b.earliest == 0 || ts < b.earliest {
+		b.earliest = ts
+	}
+	if ts > b.latest {
+		b.latest = ts
+	}
+}
+
+// format returns the bounds as RFC3339 timestamps in UTC, empty when no
+// timestamp was added
+func (b *timeBounds) format() (string, string) {
+	return formatSearchTimestamp(b.earliest), formatSearchTimestamp(b.latest)
+}
+
+// configTypeWarning describes a config value at key that does not have the
+// expected structure, e.g. "receivers is a list, expected a map of components"
+func configTypeWarning(key string, value any, expected string) string {
+	var kind string
+	switch value.(type) {
+	case map[string]any:
+		kind = "map"
+	case []any:
+		kind = "list"
+	case string:
+		kind = "string"
+	case bool:
+		kind = "bool"
+	case int, int64, uint64, float64:
+		kind = "number"
+	default:
+		kind = fmt.Sprintf("%T", value)
+	}
+	return fmt.Sprintf("%s is a %s, expected %s", key, kind, expected)
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+type ValidateOTTLInput struct {
+	Context   string `json:"context" jsonschema:"OTTL context (span, datapoint, log, resource),required"`
+	Statement string `json:"statement" jsonschema:"OTTL statement or condition to validate,required"`
+	Condition bool   `json:"condition,omitempty" jsonschema:"Parse the input as a condition (as used by the filter processor) instead of a statement,false"`
+}
+
+type OTTLErrorPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Offset int `json:"offset"`
+}
+
+type ValidateOTTLOutput struct {
+	Valid    bool               `json:"valid"`
+	Context  string             `json:"context"`
+	Error    string             `json:"error,omitempty"`
+	Position *OTTLErrorPosition `json:"position,omitempty"`
+}
+
+// RegisterValidateOTTL registers the validate_ottl tool
+func RegisterValidateOTTL(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ValidateOTTLInput, ValidateOTTLOutput](server, &mcp.Tool{
+		Name:        "validate_ottl",
+		Description: "Validate an OTTL statement or condition against the collector's OTTL parser with the standard function set. Use before putting statements into transform or filter processor configs. Syntax errors include the line/column position.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ValidateOTTLInput) (*mcp.CallToolResult, ValidateOTTLOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		logger := ext.GetLogger()
+		if logger == nil {
+			logger = zap.NewNop()
+		}
+		settings := component.TelemetrySettings{Logger: logger}
+
+		var err error
+		switch input.Context {
+		case "span":
+			err = validateOTTL(ottlspan.NewParser, settings, input.Statement, input.Condition)
+		case "datapoint":
+			err = validateOTTL(ottldatapoint.NewParser, settings, input.Statement, input.Condition)
+		case "log":
+			err = validateOTTL(ottllog.NewParser, settings, input.Statement, input.Condition)
+		case "resource":
+			err = validateOTTL(ottlresource.NewParser, settings, input.Statement, input.Condition)
+		default:
+			return nil, ValidateOTTLOutput{}, fmt.Errorf("invalid OTTL context: %s (must be span, datapoint, log, or resource)", input.Context)
+		}
+
+		output := ValidateOTTLOutput{
+			Valid:   err == nil,
+			Context: input.Context,
+		}
+		if err != nil {
+			output.Error = err.Error()
+
+			// Syntax errors carry the position reported by the grammar
+			var syntaxErr participle.Error
+			if errors.As(err, &syntaxErr) {
+				pos := syntaxErr.Position()
+				output.Position = &OTTLErrorPosition{
+					Line:   pos.Line,
+					Column: pos.Column,
+					Offset: pos.Offset,
+				}
+			}
+		}
+
+		return nil, output, nil
+	})
+}
+
+// validateOTTL parses a statement or condition with the parser of a single OTTL context
+func validateOTTL[K any](
+	newParser func(map[string]ottl.Factory[K], component.TelemetrySettings, ...ottl.Option[K]) (ottl.Parser[K], error),
+	settings component.TelemetrySettings,
+	statement string,
+	condition bool,
+) error {
+	parser, err := newParser(ottlfuncs.StandardFuncs[K](), settings)
+	if err != nil {
+		return fmt.Errorf("failed to create OTTL parser: %w", err)
+	}
+
+	if condition {
+		_, err = parser.ParseCondition(statement)
+	} else {
+		_, err = parser.ParseStatement(statement)
+	}
+	return err
+}
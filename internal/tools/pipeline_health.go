@@ -0,0 +1,212 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type GetPipelineHealthInput struct {
+	PipelineID string `json:"pipeline_id,omitempty" jsonschema:"Pipeline ID (e.g. 'traces', 'metrics/prod'). Omit for all pipelines"`
+	StaleAfter string `json:"stale_after,omitempty" jsonschema:"Report a pipeline as stalled when the newest buffered batch of its signal was received longer ago than this Go duration (e.g. '1m' '15m'),5m"`
+}
+
+// defaultStaleAfter is how long ago the newest batch of a signal may have been
+// received for its pipelines to still count as flowing
+const defaultStaleAfter = 5 * time.Minute
+
+// PipelineHealth cross-references a configured pipeline with the buffered
+// telemetry of its signal
+type PipelineHealth struct {
+	PipelineID string `json:"pipeline_id"`
+	Signal     string `json:"signal"`
+	// ExportsToMCP is set when the pipeline exports to an mcp connector, so the
+	// buffer directly reflects its traffic rather than that of a sibling pipeline
+	ExportsToMCP    bool   `json:"exports_to_mcp"`
+	BufferedBatches int    `json:"buffered_batches"`
+	BufferedItems   int    `json:"buffered_items"`
+	LatestTimestamp string `json:"latest_timestamp,omitempty"`
+	// LastReceivedAt is when the newest buffered batch of the signal was received
+	LastReceivedAt string `json:"last_received_at,omitempty"`
+	// "flowing" when the newest batch was received within stale_after,
+	// "stalled" when it is older, "possibly_idle" when nothing is buffered or
+	// "unknown" when the signal is not buffered
+	Status string `json:"status"`
+}
+
+type GetPipelineHealthOutput struct {
+	Pipelines []PipelineHealth `json:"pipelines"`
+	Count     int              `json:"count"`
+	Truncated bool             `json:"truncated,omitempty"`
+}
+
+// signalVolume is the buffered telemetry of one signal
+type signalVolume struct {
+	buffered bool
+	batches  int
+	items    int
+	latest   pcommon.Timestamp
+	// newest is when the newest batch was received
+	newest time.Time
+}
+
+// RegisterGetPipelineHealth registers the get_pipeline_health tool
+func RegisterGetPipelineHealth(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetPipelineHealthInput, GetPipelineHealthOutput](server, &mcp.Tool{
+		Name:        "get_pipeline_health",
+		Description: "Check whether configured pipelines are flowing data by cross-referencing each pipeline's signal (from its ID) with the buffered telemetry of that signal. Reports buffered batches, items (spans, log records or data points) the latest timestamp and when the newest batch was received. Pipelines are flowing when that batch was received within stale_after (default 5m), stalled when it is older and possibly_idle when nothing is buffered. The buffer only holds telemetry routed through an mcp connector, see exports_to_mcp.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetPipelineHealthInput) (*mcp.CallToolResult, GetPipelineHealthOutput, error) {
+		conf := ext.GetCollectorConf()
+		if conf == nil {
+			return nil, GetPipelineHealthOutput{}, errors.New("collector configuration not available")
+		}
+
+		pipelinesMap, _ := conf.Get("service::pipelines").(map[string]any)
+		ids := make([]string, 0, len(pipelinesMap))
+		for pipelineID := range pipelinesMap {
+			if input.PipelineID != "" && pipelineID != input.PipelineID {
+				continue
+			}
+			ids = append(ids, pipelineID)
+		}
+		sort.Strings(ids)
+
+		staleAfter := defaultStaleAfter
+		if input.StaleAfter != "" {
+			var err error
+			staleAfter, err = time.ParseDuration(input.StaleAfter)
+			if err != nil || staleAfter <= 0 {
+				return nil, GetPipelineHealthOutput{}, fmt.Errorf("invalid stale_after: %s (must be a positive duration such as 5m)", input.StaleAfter)
+			}
+		}
+		now := time.Now()
+
+		output := GetPipelineHealthOutput{Pipelines: make([]PipelineHealth, 0, len(ids))}
+		volumes := make(map[string]signalVolume)
+		for _, pipelineID := range ids {
+			signal, _, _ := strings.Cut(pipelineID, "/")
+			volume, ok := volumes[signal]
+			if !ok {
+				var truncated bool
+				volume, truncated = bufferedVolume(ctx, ext, signal)
+				output.Truncated = output.Truncated || truncated
+				volumes[signal] = volume
+			}
+
+			health := PipelineHealth{
+				PipelineID:      pipelineID,
+				Signal:          signal,
+				BufferedBatches: volume.batches,
+				BufferedItems:   volume.items,
+				LatestTimestamp: formatSearchTimestamp(volume.latest),
+				LastReceivedAt:  formatReadinessTime(volume.newest),
+				Status:          "unknown",
+			}
+			if pipelineMap, ok := pipelinesMap[pipelineID].(map[string]any); ok {
+				exporters, _ := pipelineMap["exporters"].([]any)
+				for _, exporter := range exporters {
+					if id, ok := exporter.(string); ok && strings.Split(id, "/")[0] == "mcp" {
+						health.ExportsToMCP = true
+					}
+				}
+			}
+			switch {
+			case !volume.buffered:
+			case volume.batches == 0:
+				health.Status = "possibly_idle"
+			case now.Sub(volume.newest) <= staleAfter:
+				health.Status = "flowing"
+			default:
+				health.Status = "stalled"
+			}
+
+			output.Pipelines = append(output.Pipelines, health)
+		}
+		output.Count = len(output.Pipelines)
+
+		return nil, output, nil
+	})
+}
+
+// bufferedVolume counts the buffered batches and items of a signal, the
+// latest item timestamp and when the newest batch was received. Signals
+// without an enabled buffer are reported as not buffered. The returned bool
+// reports whether ctx ended the scan early.
+func bufferedVolume(ctx context.Context, ext ExtensionContext, signal string) (signalVolume, bool) {
+	var volume signalVolume
+	if signal != "traces" && signal != "metrics" && signal != "logs" {
+		return volume, false
+	}
+	if checkBufferEnabled(ext, signal) != nil {
+		return volume, false
+	}
+	volume.buffered = true
+	for _, info := range ext.GetBatchTimes(signal, 10000, 0) {
+		volume.newest = maxTime(volume.newest, info.ReceivedAt)
+	}
+
+	observe := func(ts pcommon.Timestamp) {
+		volume.items++
+		if ts > volume.latest {
+			volume.latest = ts
+		}
+	}
+
+	truncated := false
+	switch signal {
+	case "traces":
+		traces := ext.GetRecentTraces(10000, 0)
+		volume.batches = len(traces)
+		truncated = forEachSpan(ctx, traces, func(_ string, span ptrace.Span) {
+			observe(span.EndTimestamp())
+		})
+	case "logs":
+		logs := ext.GetRecentLogs(10000, 0)
+		volume.batches = len(logs)
+		truncated = forEachLogRecord(ctx, logs, func(_ string, lr plog.LogRecord) {
+			ts := lr.Timestamp()
+			if ts == 0 {
+				ts = lr.ObservedTimestamp()
+			}
+			observe(ts)
+		})
+	case "metrics":
+		metrics := ext.GetRecentMetrics(10000, 0)
+		volume.batches = len(metrics)
+		for _, md := range metrics {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
+			}
+			for i := 0; i < md.ResourceMetrics().Len(); i++ {
+				sms := md.ResourceMetrics().At(i).ScopeMetrics()
+				for j := 0; j < sms.Len(); j++ {
+					ms := sms.At(j).Metrics()
+					for k := 0; k < ms.Len(); k++ {
+						forEachDataPoint(ms.At(k), func(_ pcommon.Map, ts pcommon.Timestamp) bool {
+							observe(ts)
+							return true
+						})
+					}
+				}
+			}
+		}
+	}
+	return volume, truncated
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterTroubleshootPipelinePrompt registers the troubleshoot_pipeline prompt
+func RegisterTroubleshootPipelinePrompt(server *mcp.Server) {
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "troubleshoot_pipeline",
+		Title:       "Troubleshoot a collector pipeline",
+		Description: "Step-by-step instructions for diagnosing a pipeline: its configuration, the status of its components and the telemetry it recently carried.",
+		Arguments: []*mcp.PromptArgument{
+			{
+				Name:        "pipeline_id",
+				Description: "Pipeline ID (e.g. 'traces' 'metrics/prod')",
+				Required:    true,
+			},
+		},
+	}, func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		pipelineID := strings.TrimSpace(req.Params.Arguments["pipeline_id"])
+		if pipelineID == "" {
+			return nil, errors.New("pipeline_id is required")
+		}
+
+		return &mcp.GetPromptResult{
+			Description: fmt.Sprintf("Troubleshoot the %s pipeline", pipelineID),
+			Messages: []*mcp.PromptMessage{
+				{
+					Role:    "user",
+					Content: &mcp.TextContent{Text: troubleshootPipelineText(pipelineID)},
+				},
+			},
+		}, nil
+	})
+}
+
+// troubleshootPipelineText returns the instructions of the troubleshoot_pipeline prompt
+func troubleshootPipelineText(pipelineID string) string {
+	// The signal is the pipeline type, e.g. "metrics" for "metrics/prod"
+	signal, _, _ := strings.Cut(pipelineID, "/")
+	queryTool := "query_" + signal
+	switch signal {
+	case "traces", "metrics", "logs":
+	default:
+		queryTool = "query_traces, query_metrics or query_logs"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Diagnose the OpenTelemetry Collector pipeline `%s`. Use the MCP tools in this order and report what you find after each step:\n\n", pipelineID)
+	sb.WriteString("1. Call `get_pipeline_metrics` without arguments to list all pipelines and confirm the pipeline exists.\n")
+	fmt.Fprintf(&sb, "2. Call `get_pipeline_config` with pipeline_id `%s` to get its receivers, processors and exporters.\n", pipelineID)
+	sb.WriteString("3. Call `get_component_status` for each of those components and note any that are not running or not configured.\n")
+	fmt.Fprintf(&sb, "4. Call `get_telemetry_summary` and `%s` to check whether telemetry recently flowed through the collector for this signal.\n\n", queryTool)
+	sb.WriteString("Finish with a short diagnosis: the most likely problem, the evidence for it, and a suggested configuration change.")
+	return sb.String()
+}
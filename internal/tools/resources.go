@@ -0,0 +1,62 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.yaml.in/yaml/v3"
+)
+
+// URIs of the resources exposing the running collector configuration
+const (
+	ConfigYAMLResourceURI = "otelcol://config.yaml"
+	ConfigJSONResourceURI = "otelcol://config.json"
+)
+
+// RegisterConfigResources registers the running collector configuration as YAML
+// and JSON resources
+func RegisterConfigResources(server *mcp.Server, ext ExtensionContext) {
+	server.AddResource(&mcp.Resource{
+		URI:         ConfigYAMLResourceURI,
+		Name:        "collector_config_yaml",
+		Title:       "Collector configuration (YAML)",
+		Description: "The running collector configuration as YAML, with all defaults expanded. Re-read it to see a reloaded configuration.",
+		MIMEType:    "application/yaml",
+	}, configResourceHandler(ext, "application/yaml", yaml.Marshal))
+
+	server.AddResource(&mcp.Resource{
+		URI:         ConfigJSONResourceURI,
+		Name:        "collector_config_json",
+		Title:       "Collector configuration (JSON)",
+		Description: "The running collector configuration as JSON, with all defaults expanded, as returned by the get_config tool. Re-read it to see a reloaded configuration.",
+		MIMEType:    "application/json",
+	}, configResourceHandler(ext, "application/json", func(v any) ([]byte, error) {
+		return json.MarshalIndent(v, "", "  ")
+	}))
+}
+
+// configResourceHandler returns a handler reading the current collector
+// configuration encoded with marshal
+func configResourceHandler(ext ExtensionContext, mimeType string, marshal func(any) ([]byte, error)) mcp.ResourceHandler {
+	return func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		conf := ext.GetCollectorConf()
+		if conf == nil {
+			return nil, NewConfigError("read_resource", "", ErrConfigNotAvailable)
+		}
+
+		data, err := marshal(conf.ToStringMap())
+		if err != nil {
+			return nil, NewConfigError("read_resource", "", err)
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: mimeType, Text: string(data)},
+			},
+		}, nil
+	}
+}
@@ -6,8 +6,13 @@ package tools
 import (
 	"context"
 	"errors"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/confmap"
 )
 
 type GetComponentStatusInput struct {
@@ -24,6 +29,9 @@ type ComponentStatus struct {
 type GetComponentStatusOutput struct {
 	Components []ComponentStatus `json:"components"`
 	Count      int               `json:"count"`
+	// Warnings lists config sections skipped because they are not maps of
+	// components
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // RegisterGetComponentStatus registers the get_component_status tool
@@ -42,43 +50,56 @@ func RegisterGetComponentStatus(server *mcp.Server, ext ExtensionContext) {
 			return nil, GetComponentStatusOutput{}, errors.New("collector configuration not available")
 		}
 
-		components := []ComponentStatus{}
+		components, warnings := componentStatuses(conf, input.Kind, input.ComponentID)
 
-		// Get configured components
-		kinds := []string{"receivers", "processors", "exporters", "connectors", "extensions"}
-		if input.Kind != "" {
-			kinds = []string{input.Kind + "s"}
+		return nil, GetComponentStatusOutput{
+			Components: components,
+			Count:      len(components),
+			Warnings:   warnings,
+		}, nil
+	})
+}
+
+// componentStatuses lists configured components, optionally filtered by kind
+// and ID, with a warning per section that is not a map of components
+func componentStatuses(conf *confmap.Conf, kind, componentID string) ([]ComponentStatus, []string) {
+	components := []ComponentStatus{}
+	var warnings []string
+
+	// Get configured components
+	kinds := []string{"receivers", "processors", "exporters", "connectors", "extensions"}
+	if kind != "" {
+		kinds = []string{kind + "s"}
+	}
+
+	for _, sectionName := range kinds {
+		section := conf.Get(sectionName)
+		if section == nil {
+			continue
 		}
 
-		for _, kind := range kinds {
-			section := conf.Get(kind)
-			if section == nil {
+		sectionMap, ok := section.(map[string]any)
+		if !ok {
+			warnings = append(warnings, configTypeWarning(sectionName, section, "a map of components"))
+			continue
+		}
+		for id := range sectionMap {
+			// Filter by component ID if specified
+			if componentID != "" && id != componentID {
 				continue
 			}
 
-			if sectionMap, ok := section.(map[string]any); ok {
-				for id := range sectionMap {
-					// Filter by component ID if specified
-					if input.ComponentID != "" && id != input.ComponentID {
-						continue
-					}
-
-					// All configured components are considered "running" since the collector is running
-					// Component-level health status would require collector v0.136.0+ componentstatus API
-					components = append(components, ComponentStatus{
-						ID:     id,
-						Kind:   kind[:len(kind)-1], // Remove trailing 's'
-						Status: "running",
-					})
-				}
-			}
+			// All configured components are considered "running" since the collector is running
+			// Component-level health status would require collector v0.136.0+ componentstatus API
+			components = append(components, ComponentStatus{
+				ID:     id,
+				Kind:   sectionName[:len(sectionName)-1], // Remove trailing 's'
+				Status: "running",
+			})
 		}
+	}
 
-		return nil, GetComponentStatusOutput{
-			Components: components,
-			Count:      len(components),
-		}, nil
-	})
+	return components, warnings
 }
 
 type GetPipelineMetricsInput struct {
@@ -96,6 +117,9 @@ type PipelineMetrics struct {
 type GetPipelineMetricsOutput struct {
 	Pipelines []PipelineMetrics `json:"pipelines"`
 	Count     int               `json:"count"`
+	// Warnings lists pipeline config that is not structured as expected, e.g.
+	// a pipeline that is not a map or a component list that is not a list
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // RegisterGetPipelineMetrics registers the get_pipeline_metrics tool
@@ -124,44 +148,63 @@ func RegisterGetPipelineMetrics(server *mcp.Server, ext ExtensionContext) {
 			}, nil
 		}
 
-		if pipelinesMap, ok := pipelinesConf.(map[string]any); ok {
-			for pipelineID, pipelineConfig := range pipelinesMap {
-				// Filter by pipeline ID if specified
-				if input.PipelineID != "" && pipelineID != input.PipelineID {
-					continue
-				}
+		pipelinesMap, ok := pipelinesConf.(map[string]any)
+		if !ok {
+			return nil, GetPipelineMetricsOutput{
+				Pipelines: pipelines,
+				Count:     0,
+				Warnings:  []string{configTypeWarning("service::pipelines", pipelinesConf, "a map of pipelines")},
+			}, nil
+		}
 
-				metrics := PipelineMetrics{
-					PipelineID: pipelineID,
-					Status:     "running", // If collector is running, pipeline is running
-				}
+		var warnings []string
+		for pipelineID, pipelineConfig := range pipelinesMap {
+			// Filter by pipeline ID if specified
+			if input.PipelineID != "" && pipelineID != input.PipelineID {
+				continue
+			}
 
-				if pipelineMap, ok := pipelineConfig.(map[string]any); ok {
-					// Count receivers
-					if receivers, ok := pipelineMap["receivers"].([]any); ok {
-						metrics.ReceiverCount = len(receivers)
-					}
-					// Count processors
-					if processors, ok := pipelineMap["processors"].([]any); ok {
-						metrics.ProcessorCount = len(processors)
-					}
-					// Count exporters
-					if exporters, ok := pipelineMap["exporters"].([]any); ok {
-						metrics.ExporterCount = len(exporters)
-					}
-				}
+			metrics := PipelineMetrics{
+				PipelineID: pipelineID,
+				Status:     "running", // If collector is running, pipeline is running
+			}
 
-				pipelines = append(pipelines, metrics)
+			key := "service::pipelines::" + pipelineID
+			pipelineMap, ok := pipelineConfig.(map[string]any)
+			if !ok && pipelineConfig != nil {
+				warnings = append(warnings, configTypeWarning(key, pipelineConfig, "a map with receivers, processors and exporters"))
 			}
+			metrics.ReceiverCount = countPipelineComponents(pipelineMap, key, "receivers", &warnings)
+			metrics.ProcessorCount = countPipelineComponents(pipelineMap, key, "processors", &warnings)
+			metrics.ExporterCount = countPipelineComponents(pipelineMap, key, "exporters", &warnings)
+
+			pipelines = append(pipelines, metrics)
 		}
+		sort.Strings(warnings)
 
 		return nil, GetPipelineMetricsOutput{
 			Pipelines: pipelines,
 			Count:     len(pipelines),
+			Warnings:  warnings,
 		}, nil
 	})
 }
 
+// countPipelineComponents returns the length of a component list of a pipeline,
+// adding a warning when it is set but not a list
+func countPipelineComponents(pipeline map[string]any, key, list string, warnings *[]string) int {
+	value, ok := pipeline[list]
+	if !ok || value == nil {
+		return 0
+	}
+	components, ok := value.([]any)
+	if !ok {
+		*warnings = append(*warnings, configTypeWarning(key+"::"+list, value, "a list of component IDs"))
+		return 0
+	}
+	return len(components)
+}
+
 type GetExtensionsOutput struct {
 	Count      int      `json:"count"`
 	Extensions []string `json:"extensions"`
@@ -195,3 +238,80 @@ func RegisterGetExtensions(server *mcp.Server, ext ExtensionContext) {
 		}, nil
 	})
 }
+
+type ComponentCounts struct {
+	Receivers  int `json:"receivers"`
+	Processors int `json:"processors"`
+	Exporters  int `json:"exporters"`
+	Connectors int `json:"connectors"`
+	Extensions int `json:"extensions"`
+}
+
+type GetCollectorInfoOutput struct {
+	GoVersion          string            `json:"go_version"`
+	OS                 string            `json:"os"`
+	Arch               string            `json:"arch"`
+	NumCPU             int               `json:"num_cpu"`
+	MainModule         string            `json:"main_module,omitempty"`
+	MainVersion        string            `json:"main_version,omitempty"`
+	CollectorVersion   string            `json:"collector_version,omitempty"`
+	BuildSettings      map[string]string `json:"build_settings,omitempty"`
+	BuildInfoAvailable bool              `json:"build_info_available"`
+	Components         *ComponentCounts  `json:"components,omitempty"`
+}
+
+// collectorCoreModule is the dependency used to report the collector version
+const collectorCoreModule = "go.opentelemetry.io/collector/component"
+
+// RegisterGetCollectorInfo registers the get_collector_info tool
+func RegisterGetCollectorInfo(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_collector_info",
+		Description: "Get information about the running collector build: Go version, OS/arch, main module and collector core version, VCS build settings and the number of available components per kind",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input any) (*mcp.CallToolResult, GetCollectorInfoOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		output := GetCollectorInfoOutput{
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			NumCPU:    runtime.NumCPU(),
+		}
+
+		// Build info is missing for binaries built without module support
+		if info, ok := debug.ReadBuildInfo(); ok {
+			output.BuildInfoAvailable = true
+			output.MainModule = info.Main.Path
+			output.MainVersion = info.Main.Version
+			for _, dep := range info.Deps {
+				if dep.Path == collectorCoreModule {
+					output.CollectorVersion = dep.Version
+					break
+				}
+			}
+			for _, setting := range info.Settings {
+				if strings.HasPrefix(setting.Key, "vcs.") || setting.Key == "GOOS" || setting.Key == "GOARCH" {
+					if output.BuildSettings == nil {
+						output.BuildSettings = make(map[string]string)
+					}
+					output.BuildSettings[setting.Key] = setting.Value
+				}
+			}
+		}
+
+		if moduleInfos := ext.GetModuleInfos(); moduleInfos != nil {
+			output.Components = &ComponentCounts{
+				Receivers:  len(moduleInfos.Receiver),
+				Processors: len(moduleInfos.Processor),
+				Exporters:  len(moduleInfos.Exporter),
+				Connectors: len(moduleInfos.Connector),
+				Extensions: len(moduleInfos.Extension),
+			}
+		}
+
+		return nil, output, nil
+	})
+}
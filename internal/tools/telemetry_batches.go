@@ -0,0 +1,92 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListBatchesInput struct {
+	Signal string `json:"signal" jsonschema:"Signal whose buffer to list (traces metrics logs),required"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"Maximum number of batches to return,50"`
+	Offset int    `json:"offset,omitempty" jsonschema:"Number of batches to skip from the oldest,0"`
+}
+
+// BufferBatch describes one buffered batch. Index 0 is the oldest batch in the
+// buffer and the next to be evicted under the drop_oldest policy.
+type BufferBatch struct {
+	Index      int    `json:"index"`
+	Seq        uint64 `json:"seq"`
+	ReceivedAt string `json:"received_at"`
+	Items      int    `json:"items"`
+	SizeBytes  int    `json:"size_bytes"`
+}
+
+type ListBatchesOutput struct {
+	Signal     string        `json:"signal"`
+	BatchCount int           `json:"batch_count"`
+	Capacity   int           `json:"capacity"`
+	Batches    []BufferBatch `json:"batches"`
+}
+
+// RegisterListBatches registers the list_batches tool
+func RegisterListBatches(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ListBatchesInput, ListBatchesOutput](server, &mcp.Tool{
+		Name:        "list_batches",
+		Description: "List the batches in a signal's buffer from oldest to newest with their index, sequence number, received time, item count (spans, data points or log records) and approximate size as OTLP protobuf. Use to understand what the buffer holds and when batches will be evicted.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ListBatchesInput) (*mcp.CallToolResult, ListBatchesOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		signal := strings.ToLower(strings.TrimSpace(input.Signal))
+		stats := ext.GetBufferStats()
+		var count, capacity int
+		switch signal {
+		case "traces":
+			count, capacity = stats.TracesCount, stats.TracesCapacity
+		case "metrics":
+			count, capacity = stats.MetricsCount, stats.MetricsCapacity
+		case "logs":
+			count, capacity = stats.LogsCount, stats.LogsCapacity
+		default:
+			return nil, ListBatchesOutput{}, fmt.Errorf("invalid signal: %s (must be traces, logs, or metrics)", input.Signal)
+		}
+		if err := checkBufferEnabled(ext, signal); err != nil {
+			return nil, ListBatchesOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, 50)
+		if err != nil {
+			return nil, ListBatchesOutput{}, err
+		}
+		if err := validateOffset(input.Offset); err != nil {
+			return nil, ListBatchesOutput{}, err
+		}
+
+		infos := ext.GetBatchInfos(signal, limit, input.Offset)
+		batches := make([]BufferBatch, len(infos))
+		for i, info := range infos {
+			batches[i] = BufferBatch{
+				Index:      input.Offset + i,
+				Seq:        info.Seq,
+				ReceivedAt: info.ReceivedAt.UTC().Format(time.RFC3339Nano),
+				Items:      info.Items,
+				SizeBytes:  info.SizeBytes,
+			}
+		}
+
+		return nil, ListBatchesOutput{
+			Signal:     signal,
+			BatchCount: count,
+			Capacity:   capacity,
+			Batches:    batches,
+		}, nil
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type BreakdownSpansInput struct {
+	SpanName     string `json:"span_name" jsonschema:"Span name to break down (exact match),required"`
+	AttributeKey string `json:"attribute_key" jsonschema:"Span attribute key to group the spans by (e.g. 'http.status_code' 'db.name'),required"`
+	ServiceName  string `json:"service_name,omitempty" jsonschema:"Only count spans of this service"`
+	Durations    bool   `json:"durations,omitempty" jsonschema:"Add the average and p95 duration of the spans of each value,false"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"Maximum number of values to return (most frequent first),100"`
+}
+
+// SpanBreakdownValue counts the spans carrying one attribute value
+type SpanBreakdownValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+	// AvgDurationMs and P95DurationMs are only set when durations were requested
+	AvgDurationMs float64 `json:"avg_duration_ms,omitempty"`
+	P95DurationMs float64 `json:"p95_duration_ms,omitempty"`
+}
+
+type BreakdownSpansOutput struct {
+	SpanName     string `json:"span_name"`
+	AttributeKey string `json:"attribute_key"`
+	// SpanCount is the number of spans with the name, Missing the ones of them
+	// without the attribute
+	SpanCount     int                  `json:"span_count"`
+	Missing       int                  `json:"missing"`
+	TotalDistinct int                  `json:"total_distinct"`
+	Values        []SpanBreakdownValue `json:"values"`
+	Truncated     bool                 `json:"truncated,omitempty"`
+}
+
+// RegisterBreakdownSpans registers the breakdown_spans tool
+func RegisterBreakdownSpans(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[BreakdownSpansInput, BreakdownSpansOutput](server, &mcp.Tool{
+		Name:        "breakdown_spans",
+		Description: "Break down the buffered spans of one operation (span name) by the values of a span attribute, e.g. the http.status_code values GET /checkout returned, with the span count per value, most frequent first. Optionally add the average and p95 duration per value. Spans without the attribute are counted in missing.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input BreakdownSpansInput) (*mcp.CallToolResult, BreakdownSpansOutput, error) {
+		if input.SpanName == "" {
+			return nil, BreakdownSpansOutput{}, errors.New("span_name is required")
+		}
+		if input.AttributeKey == "" {
+			return nil, BreakdownSpansOutput{}, errors.New("attribute_key is required")
+		}
+		if err := checkBufferEnabled(ext, "traces"); err != nil {
+			return nil, BreakdownSpansOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, BreakdownSpansOutput{}, err
+		}
+
+		output := BreakdownSpansOutput{SpanName: input.SpanName, AttributeKey: input.AttributeKey}
+		durations := make(map[string][]time.Duration)
+		output.Truncated = forEachSpan(ctx, ext.GetRecentTraces(10000, 0), func(serviceName string, span ptrace.Span) {
+			if span.Name() != input.SpanName {
+				return
+			}
+			if input.ServiceName != "" && serviceName != input.ServiceName {
+				return
+			}
+			output.SpanCount++
+			value, ok := span.Attributes().Get(input.AttributeKey)
+			if !ok {
+				output.Missing++
+				return
+			}
+			duration := time.Duration(span.EndTimestamp()) - time.Duration(span.StartTimestamp())
+			key := redactValue(input.AttributeKey, value.AsString(), ext.IsRedactedAttribute)
+			durations[key] = append(durations[key], duration)
+		})
+
+		output.TotalDistinct = len(durations)
+		output.Values = make([]SpanBreakdownValue, 0, len(durations))
+		for value, spanDurations := range durations {
+			breakdown := SpanBreakdownValue{Value: value, Count: len(spanDurations)}
+			if input.Durations {
+				var total time.Duration
+				for _, d := range spanDurations {
+					total += d
+				}
+				slices.Sort(spanDurations)
+				breakdown.AvgDurationMs = float64(total) / float64(len(spanDurations)) / 1e6
+				breakdown.P95DurationMs = float64(durationPercentile(spanDurations, 95)) / 1e6
+			}
+			output.Values = append(output.Values, breakdown)
+		}
+		sort.Slice(output.Values, func(i, j int) bool {
+			if output.Values[i].Count != output.Values[j].Count {
+				return output.Values[i].Count > output.Values[j].Count
+			}
+			return output.Values[i].Value < output.Values[j].Value
+		})
+		if len(output.Values) > limit {
+			output.Values = output.Values[:limit]
+		}
+
+		return nil, output, nil
+	})
+}
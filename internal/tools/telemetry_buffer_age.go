@@ -0,0 +1,171 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// bufferAgeBounds are the upper bounds of the buffer age histogram buckets;
+// a last bucket holds everything older
+var bufferAgeBounds = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour}
+
+type GetBufferAgeInput struct {
+	Signal string `json:"signal,omitempty" jsonschema:"Signal to report (traces metrics logs). Omit for every signal with buffering enabled"`
+}
+
+// BufferAgeBucket counts the buffered batches and items received within an age
+// range, e.g. 5m-15m ago
+type BufferAgeBucket struct {
+	Bucket  string `json:"bucket"`
+	Batches int    `json:"batches"`
+	Items   int    `json:"items"`
+}
+
+// SignalBufferAge describes the age spread of a signal's buffer by the time
+// its batches were received
+type SignalBufferAge struct {
+	Signal   string `json:"signal"`
+	Batches  int    `json:"batches"`
+	Capacity int    `json:"capacity"`
+	Items    int    `json:"items"`
+	// Oldest and Newest are the received times of the oldest and newest
+	// batch, in UTC
+	Oldest string `json:"oldest,omitempty"`
+	Newest string `json:"newest,omitempty"`
+	// Span is the time between the oldest and newest batch, the window of
+	// telemetry the buffer currently holds
+	Span      string            `json:"span,omitempty"`
+	Histogram []BufferAgeBucket `json:"histogram"`
+}
+
+type GetBufferAgeOutput struct {
+	Signals []SignalBufferAge `json:"signals"`
+}
+
+// RegisterGetBufferAge registers the get_buffer_age tool
+func RegisterGetBufferAge(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetBufferAgeInput, GetBufferAgeOutput](server, &mcp.Tool{
+		Name:        "get_buffer_age",
+		Description: "Report the age spread of the buffered telemetry per signal: the received times of the oldest and newest batch, the window between them and a histogram of batch and item (span, data point, log record) counts by age (<1m 1m-5m 5m-15m 15m-1h 1h-6h >=6h). Use to tune buffer sizes: a full buffer holding only minutes of telemetry turns over fast.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetBufferAgeInput) (*mcp.CallToolResult, GetBufferAgeOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		stats := ext.GetBufferStats()
+		capacities := map[string]int{
+			"traces":  stats.TracesCapacity,
+			"metrics": stats.MetricsCapacity,
+			"logs":    stats.LogsCapacity,
+		}
+
+		signals := []string{"traces", "metrics", "logs"}
+		if input.Signal != "" {
+			signal := strings.ToLower(strings.TrimSpace(input.Signal))
+			if _, ok := capacities[signal]; !ok {
+				return nil, GetBufferAgeOutput{}, fmt.Errorf("invalid signal: %s (must be traces, logs, or metrics)", input.Signal)
+			}
+			if err := checkBufferEnabled(ext, signal); err != nil {
+				return nil, GetBufferAgeOutput{}, err
+			}
+			signals = []string{signal}
+		}
+
+		now := time.Now()
+		output := GetBufferAgeOutput{Signals: []SignalBufferAge{}}
+		for _, signal := range signals {
+			capacity := capacities[signal]
+			if capacity == 0 {
+				continue
+			}
+			output.Signals = append(output.Signals, signalBufferAge(signal, capacity, ext.GetBatchTimes(signal, capacity, 0), now))
+		}
+
+		return nil, output, nil
+	})
+}
+
+// signalBufferAge summarizes the received times of a signal's batches as of
+// now
+func signalBufferAge(signal string, capacity int, infos []BatchInfo, now time.Time) SignalBufferAge {
+	age := SignalBufferAge{
+		Signal:    signal,
+		Batches:   len(infos),
+		Capacity:  capacity,
+		Histogram: make([]BufferAgeBucket, len(bufferAgeBounds)+1),
+	}
+	for i := range age.Histogram {
+		age.Histogram[i].Bucket = bufferAgeBucketLabel(i)
+	}
+	if len(infos) == 0 {
+		return age
+	}
+
+	oldest, newest := infos[0].ReceivedAt, infos[0].ReceivedAt
+	for _, info := range infos {
+		age.Items += info.Items
+		oldest = minTime(oldest, info.ReceivedAt)
+		newest = maxTime(newest, info.ReceivedAt)
+
+		bucket := len(bufferAgeBounds)
+		for i, bound := range bufferAgeBounds {
+			if now.Sub(info.ReceivedAt) < bound {
+				bucket = i
+				break
+			}
+		}
+		age.Histogram[bucket].Batches++
+		age.Histogram[bucket].Items += info.Items
+	}
+	age.Oldest = oldest.UTC().Format(time.RFC3339Nano)
+	age.Newest = newest.UTC().Format(time.RFC3339Nano)
+	age.Span = newest.Sub(oldest).String()
+	return age
+}
+
+// bufferAgeBucketLabel names bucket i of the buffer age histogram
+func bufferAgeBucketLabel(i int) string {
+	switch {
+	case i == 0:
+		return "<" + shortDuration(bufferAgeBounds[0])
+	case i == len(bufferAgeBounds):
+		return ">=" + shortDuration(bufferAgeBounds[i-1])
+	default:
+		return shortDuration(bufferAgeBounds[i-1]) + "-" + shortDuration(bufferAgeBounds[i])
+	}
+}
+
+// shortDuration formats whole minutes and hours without trailing zero units,
+// e.g. 5m instead of 5m0s
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type CheckCorrelationInput struct {
+	ServiceName string `json:"service_name,omitempty" jsonschema:"Only check spans and logs of this service"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of example orphaned trace IDs and uncorrelated spans to return,20"`
+}
+
+// UncorrelatedSpan is a buffered span that no buffered log references
+type UncorrelatedSpan struct {
+	TraceID string `json:"trace_id"`
+	SpanID  string `json:"span_id"`
+	Name    string `json:"name"`
+	Service string `json:"service"`
+}
+
+type CheckCorrelationOutput struct {
+	LogCount                int `json:"log_count"`
+	LogsWithTraceContext    int `json:"logs_with_trace_context"`
+	LogsWithoutTraceContext int `json:"logs_without_trace_context"`
+	// Logs with trace context whose trace ID matches no buffered span
+	OrphanedLogs int `json:"orphaned_logs"`
+
+	SpanCount        int `json:"span_count"`
+	SpansWithLogs    int `json:"spans_with_logs"`
+	SpansWithoutLogs int `json:"spans_without_logs"`
+
+	OrphanedTraceIDs  []string           `json:"orphaned_trace_ids,omitempty"`
+	UncorrelatedSpans []UncorrelatedSpan `json:"uncorrelated_spans,omitempty"`
+
+	// Counts only cover the batches scanned before the request timed out
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RegisterCheckCorrelation registers the check_correlation tool
+func RegisterCheckCorrelation(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[CheckCorrelationInput, CheckCorrelationOutput](server, &mcp.Tool{
+		Name:        "check_correlation",
+		Description: "Check trace-context propagation between buffered logs and spans to find instrumentation gaps. Reports logs with and without trace context, logs whose trace ID matches no buffered span, and spans that no log references by span ID, with examples of each.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input CheckCorrelationInput) (*mcp.CallToolResult, CheckCorrelationOutput, error) {
+		limit, err := resolveLimit(ext, input.Limit, 20)
+		if err != nil {
+			return nil, CheckCorrelationOutput{}, err
+		}
+
+		var output CheckCorrelationOutput
+
+		// Collect buffered trace IDs and spans. Trace IDs are collected for all
+		// services since a log may belong to a trace started elsewhere.
+		traceIDs := make(map[string]bool)
+		var spans []UncorrelatedSpan
+		output.Truncated = forEachSpan(ctx, ext.GetRecentTraces(10000, 0), func(serviceName string, span ptrace.Span) {
+			traceIDs[span.TraceID().String()] = true
+			if input.ServiceName != "" && serviceName != input.ServiceName {
+				return
+			}
+			spans = append(spans, UncorrelatedSpan{
+				TraceID: span.TraceID().String(),
+				SpanID:  span.SpanID().String(),
+				Name:    span.Name(),
+				Service: serviceName,
+			})
+		})
+
+		loggedSpans := make(map[string]bool)
+		seenOrphans := make(map[string]bool)
+		truncated := forEachLogRecord(ctx, ext.GetRecentLogs(10000, 0), func(serviceName string, lr plog.LogRecord) {
+			if !lr.SpanID().IsEmpty() {
+				loggedSpans[lr.SpanID().String()] = true
+			}
+			if input.ServiceName != "" && serviceName != input.ServiceName {
+				return
+			}
+
+			output.LogCount++
+			if lr.TraceID().IsEmpty() {
+				output.LogsWithoutTraceContext++
+				return
+			}
+			output.LogsWithTraceContext++
+
+			traceID := lr.TraceID().String()
+			if traceIDs[traceID] {
+				return
+			}
+			output.OrphanedLogs++
+			if !seenOrphans[traceID] && len(output.OrphanedTraceIDs) < limit {
+				output.OrphanedTraceIDs = append(output.OrphanedTraceIDs, traceID)
+			}
+			seenOrphans[traceID] = true
+		})
+		output.Truncated = output.Truncated || truncated
+
+		output.SpanCount = len(spans)
+		for _, span := range spans {
+			if loggedSpans[span.SpanID] {
+				output.SpansWithLogs++
+				continue
+			}
+			output.SpansWithoutLogs++
+			if len(output.UncorrelatedSpans) < limit {
+				output.UncorrelatedSpans = append(output.UncorrelatedSpans, span)
+			}
+		}
+
+		return nil, output, nil
+	})
+}
+
+// forEachSpan calls fn with every span in traces and the service that emitted
+// it, checking for cancellation once per batch. It returns true if ctx ended
+// the scan before every batch was visited.
+func forEachSpan(ctx context.Context, traces []ptrace.Traces, fn func(serviceName string, span ptrace.Span)) bool {
+	for _, td := range traces {
+		if scanInterrupted(ctx) {
+			return true
+		}
+
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			rs := td.ResourceSpans().At(i)
+			serviceName := "unknown"
+			if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+				serviceName = sn.AsString()
+			}
+
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				spans := rs.ScopeSpans().At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					fn(serviceName, spans.At(k))
+				}
+			}
+		}
+	}
+	return false
+}
+
+// forEachLogRecord calls fn with every log record in logs and the service that
+// emitted it, checking for cancellation once per batch. It returns true if ctx
+// ended the scan before every batch was visited.
+func forEachLogRecord(ctx context.Context, logs []plog.Logs, fn func(serviceName string, lr plog.LogRecord)) bool {
+	for _, ld := range logs {
+		if scanInterrupted(ctx) {
+			return true
+		}
+
+		for i := 0; i < ld.ResourceLogs().Len(); i++ {
+			rl := ld.ResourceLogs().At(i)
+			serviceName := "unknown"
+			if sn, ok := rl.Resource().Attributes().Get("service.name"); ok {
+				serviceName = sn.AsString()
+			}
+
+			for j := 0; j < rl.ScopeLogs().Len(); j++ {
+				records := rl.ScopeLogs().At(j).LogRecords()
+				for k := 0; k < records.Len(); k++ {
+					fn(serviceName, records.At(k))
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetDroppedTelemetryInput struct {
+	Signal string `json:"signal,omitempty" jsonschema:"Signal to report (traces metrics logs). Omit for all signals"`
+}
+
+// DroppedSignal reports the telemetry a signal's buffer rejected
+type DroppedSignal struct {
+	Signal string `json:"signal"`
+	// Capacity 0 means buffering is disabled and every batch is rejected
+	Capacity       int                  `json:"capacity"`
+	DroppedBatches int                  `json:"dropped_batches"`
+	DroppedItems   int                  `json:"dropped_items"`
+	Samples        []DroppedBatchSample `json:"samples,omitempty"`
+}
+
+// DroppedBatchSample summarizes one of the most recently rejected batches
+type DroppedBatchSample struct {
+	DroppedAt string   `json:"dropped_at"`
+	Items     int      `json:"items"`
+	Services  []string `json:"services"`
+}
+
+type GetDroppedTelemetryOutput struct {
+	Signals []DroppedSignal `json:"signals"`
+}
+
+// RegisterGetDroppedTelemetry registers the get_dropped_telemetry tool
+func RegisterGetDroppedTelemetry(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetDroppedTelemetryInput, GetDroppedTelemetryOutput](server, &mcp.Tool{
+		Name:        "get_dropped_telemetry",
+		Description: "Report how many batches and items (spans, data points or log records) each signal's buffer rejected since startup, because buffering is disabled (capacity 0) or the buffer was full under the reject_new eviction policy, with summaries (time, items, services) of the most recently rejected batches. Use when tuning buffer capacities. Batches evicted by drop_oldest or drop_newest are not counted.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetDroppedTelemetryInput) (*mcp.CallToolResult, GetDroppedTelemetryOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		stats := ext.GetBufferStats()
+		capacities := map[string]int{
+			"traces":  stats.TracesCapacity,
+			"metrics": stats.MetricsCapacity,
+			"logs":    stats.LogsCapacity,
+		}
+
+		signals := []string{"traces", "metrics", "logs"}
+		if input.Signal != "" {
+			signal := strings.ToLower(strings.TrimSpace(input.Signal))
+			if _, ok := capacities[signal]; !ok {
+				return nil, GetDroppedTelemetryOutput{}, fmt.Errorf("invalid signal: %s (must be traces, logs, or metrics)", input.Signal)
+			}
+			signals = []string{signal}
+		}
+
+		output := GetDroppedTelemetryOutput{Signals: make([]DroppedSignal, 0, len(signals))}
+		for _, signal := range signals {
+			drops := ext.GetSignalDrops(signal)
+			dropped := DroppedSignal{
+				Signal:         signal,
+				Capacity:       capacities[signal],
+				DroppedBatches: drops.Batches,
+				DroppedItems:   drops.Items,
+			}
+			for _, batch := range drops.Recent {
+				dropped.Samples = append(dropped.Samples, DroppedBatchSample{
+					DroppedAt: batch.DroppedAt.UTC().Format(time.RFC3339Nano),
+					Items:     batch.Items,
+					Services:  batch.Services,
+				})
+			}
+			output.Signals = append(output.Signals, dropped)
+		}
+
+		return nil, output, nil
+	})
+}
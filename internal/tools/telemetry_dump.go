@@ -0,0 +1,500 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// dumpSignals are the signals of a buffer dump, in the order they are written
+var dumpSignals = []string{"traces", "metrics", "logs"}
+
+type ExportBufferInput struct {
+	Format string `json:"format,omitempty" jsonschema:"Encoding: json (OTLP/JSON, one batch per line) or proto (OTLP protobuf, each batch prefixed with its 4-byte big-endian length),json"`
+	Path   string `json:"path,omitempty" jsonschema:"Directory relative to the configured dump_dir to write one file per signal to (traces.jsonl or traces.pb etc). Existing files are not overwritten. Omit to return the files base64 encoded"`
+}
+
+// BufferDumpSignal describes the dump of one signal's buffer
+type BufferDumpSignal struct {
+	Signal  string `json:"signal"`
+	Batches int    `json:"batches"`
+	// Items is the number of spans, data points or log records
+	Items int    `json:"items"`
+	File  string `json:"file,omitempty"`
+	// Data is the base64 encoded file content when no path was given
+	Data string `json:"data,omitempty"`
+}
+
+type ExportBufferOutput struct {
+	Format  string             `json:"format"`
+	Signals []BufferDumpSignal `json:"signals"`
+}
+
+type ImportBufferInput struct {
+	Format string `json:"format,omitempty" jsonschema:"Encoding of the dump as written by export_buffer: json or proto,json"`
+	Path   string `json:"path,omitempty" jsonschema:"Directory relative to the configured dump_dir containing the files written by export_buffer. Missing signal files are skipped"`
+
+	Traces  string `json:"traces,omitempty" jsonschema:"Base64 encoded traces data returned by export_buffer"`
+	Metrics string `json:"metrics,omitempty" jsonschema:"Base64 encoded metrics data returned by export_buffer"`
+	Logs    string `json:"logs,omitempty" jsonschema:"Base64 encoded logs data returned by export_buffer"`
+}
+
+type ImportBufferOutput struct {
+	Format  string             `json:"format"`
+	Signals []BufferDumpSignal `json:"signals"`
+}
+
+// bufferDump holds the decoded batches of a dump, oldest first
+type bufferDump struct {
+	traces  []ptrace.Traces
+	metrics []pmetric.Metrics
+	logs    []plog.Logs
+}
+
+// RegisterExportBuffer registers the export_buffer tool
+func RegisterExportBuffer(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ExportBufferInput, ExportBufferOutput](server, &mcp.Tool{
+		Name:        "export_buffer",
+		Description: "Dump every buffered batch of all signals as OTLP, oldest first, to share a reproduction. Writes traces, metrics and logs files to path below the configured dump_dir, never overwriting existing files, or returns them base64 encoded. Values of redacted attributes are replaced in the dump. The json format is one OTLP/JSON batch per line, as read by the otlpjsonfile receiver; proto prefixes each OTLP protobuf batch with its 4-byte big-endian length. Load a dump with import_buffer.",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(false),
+			OpenWorldHint:   boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ExportBufferInput) (*mcp.CallToolResult, ExportBufferOutput, error) {
+		format, err := parseDumpFormat(input.Format)
+		if err != nil {
+			return nil, ExportBufferOutput{}, err
+		}
+		var root *os.Root
+		if input.Path != "" {
+			root, err = openDumpDir(ext, input.Path)
+			if err != nil {
+				return nil, ExportBufferOutput{}, err
+			}
+			defer root.Close()
+			if err := mkdirInRoot(root, input.Path); err != nil {
+				return nil, ExportBufferOutput{}, fmt.Errorf("failed to create dump directory: %w", err)
+			}
+			// Check every file up front so a refused dump writes nothing
+			for _, signal := range dumpSignals {
+				name := filepath.Join(input.Path, dumpFileName(signal, format))
+				if _, err := root.Stat(name); err == nil {
+					return nil, ExportBufferOutput{}, fmt.Errorf("refusing to overwrite existing dump file %s", name)
+				}
+			}
+		}
+
+		output := ExportBufferOutput{Format: format, Signals: make([]BufferDumpSignal, 0, len(dumpSignals))}
+		for _, signal := range dumpSignals {
+			if ctx.Err() != nil {
+				return nil, ExportBufferOutput{}, ctx.Err()
+			}
+			batches, items, err := marshalSignal(ext, signal, format)
+			if err != nil {
+				return nil, ExportBufferOutput{}, err
+			}
+			dumped := BufferDumpSignal{Signal: signal, Batches: len(batches), Items: items}
+			if len(batches) > 0 {
+				data := encodeDump(batches, format)
+				if input.Path == "" {
+					dumped.Data = base64.StdEncoding.EncodeToString(data)
+				} else {
+					name := filepath.Join(input.Path, dumpFileName(signal, format))
+					if err := writeDumpFile(root, name, data); err != nil {
+						return nil, ExportBufferOutput{}, fmt.Errorf("failed to write %s dump: %w", signal, err)
+					}
+					dumped.File = filepath.Join(ext.GetDumpDir(), name)
+				}
+			}
+			output.Signals = append(output.Signals, dumped)
+		}
+
+		return nil, output, nil
+	})
+}
+
+// RegisterImportBuffer registers the import_buffer tool
+func RegisterImportBuffer(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ImportBufferInput, ImportBufferOutput](server, &mcp.Tool{
+		Name:        "import_buffer",
+		Description: "Load a dump written by export_buffer into the buffers, from the files in path or from base64 data. Each dumped batch is added as one batch, so buffer capacities and the eviction policy apply as for received telemetry. The whole dump is decoded before anything is added. Only affects the buffer, imported telemetry is not sent through the pipelines.",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(false),
+			OpenWorldHint:   boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ImportBufferInput) (*mcp.CallToolResult, ImportBufferOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		format, err := parseDumpFormat(input.Format)
+		if err != nil {
+			return nil, ImportBufferOutput{}, err
+		}
+
+		data, files, err := readDump(ext, input, format)
+		if err != nil {
+			return nil, ImportBufferOutput{}, err
+		}
+
+		var dump bufferDump
+		for _, signal := range dumpSignals {
+			raw, ok := data[signal]
+			if !ok {
+				continue
+			}
+			if err := checkBufferEnabled(ext, signal); err != nil {
+				return nil, ImportBufferOutput{}, err
+			}
+			batches, err := DecodeDump(raw, format)
+			if err != nil {
+				return nil, ImportBufferOutput{}, fmt.Errorf("invalid %s dump: %w", signal, err)
+			}
+			if err := dump.unmarshal(signal, batches, format); err != nil {
+				return nil, ImportBufferOutput{}, fmt.Errorf("invalid %s dump: %w", signal, err)
+			}
+		}
+
+		output := ImportBufferOutput{Format: format, Signals: make([]BufferDumpSignal, 0, len(data))}
+		for _, signal := range dumpSignals {
+			if _, ok := data[signal]; !ok {
+				continue
+			}
+			batches, items := dump.add(ext, signal)
+			output.Signals = append(output.Signals, BufferDumpSignal{
+				Signal:  signal,
+				Batches: batches,
+				Items:   items,
+				File:    files[signal],
+			})
+		}
+
+		return nil, output, nil
+	})
+}
+
+// parseDumpFormat validates a dump format, defaulting to json
+func parseDumpFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		return "json", nil
+	case "json", "proto":
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid format: %s (must be json or proto)", format)
+	}
+}
+
+// dumpFileName returns the name of a signal's file in a dump directory
+func dumpFileName(signal, format string) string {
+	if format == "proto" {
+		return signal + ".pb"
+	}
+	return signal + ".jsonl"
+}
+
+// marshalSignal encodes every buffered batch of a signal, oldest first, and
+// returns them with the number of spans, data points or log records
+func marshalSignal(ext ExtensionContext, signal, format string) ([][]byte, int, error) {
+	stats := ext.GetBufferStats()
+	var batches [][]byte
+	items := 0
+	switch signal {
+	case "traces":
+		var marshaler ptrace.Marshaler = &ptrace.JSONMarshaler{}
+		if format == "proto" {
+			marshaler = &ptrace.ProtoMarshaler{}
+		}
+		for _, td := range ext.GetRecentTraces(stats.TracesCapacity, 0) {
+			data, err := marshaler.MarshalTraces(redactedTraces(ext, td))
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to marshal traces: %w", err)
+			}
+			batches = append(batches, data)
+			items += td.SpanCount()
+		}
+	case "metrics":
+		var marshaler pmetric.Marshaler = &pmetric.JSONMarshaler{}
+		if format == "proto" {
+			marshaler = &pmetric.ProtoMarshaler{}
+		}
+		for _, md := range ext.GetRecentMetrics(stats.MetricsCapacity, 0) {
+			data, err := marshaler.MarshalMetrics(redactedMetrics(ext, md))
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to marshal metrics: %w", err)
+			}
+			batches = append(batches, data)
+			items += md.DataPointCount()
+		}
+	case "logs":
+		var marshaler plog.Marshaler = &plog.JSONMarshaler{}
+		if format == "proto" {
+			marshaler = &plog.ProtoMarshaler{}
+		}
+		for _, ld := range ext.GetRecentLogs(stats.LogsCapacity, 0) {
+			data, err := marshaler.MarshalLogs(redactedLogs(ext, ld))
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to marshal logs: %w", err)
+			}
+			batches = append(batches, data)
+			items += ld.LogRecordCount()
+		}
+	}
+	return batches, items, nil
+}
+
+// unmarshal decodes the encoded batches of a signal into the dump
+func (d *bufferDump) unmarshal(signal string, batches [][]byte, format string) error {
+	switch signal {
+	case "traces":
+		var unmarshaler ptrace.Unmarshaler = &ptrace.JSONUnmarshaler{}
+		if format == "proto" {
+			unmarshaler = &ptrace.ProtoUnmarshaler{}
+		}
+		for _, data := range batches {
+			td, err := unmarshaler.UnmarshalTraces(data)
+			if err != nil {
+				return err
+			}
+			d.traces = append(d.traces, td)
+		}
+	case "metrics":
+		var unmarshaler pmetric.Unmarshaler = &pmetric.JSONUnmarshaler{}
+		if format == "proto" {
+			unmarshaler = &pmetric.ProtoUnmarshaler{}
+		}
+		for _, data := range batches {
+			md, err := unmarshaler.UnmarshalMetrics(data)
+			if err != nil {
+				return err
+			}
+			d.metrics = append(d.metrics, md)
+		}
+	case "logs":
+		var unmarshaler plog.Unmarshaler = &plog.JSONUnmarshaler{}
+		if format == "proto" {
+			unmarshaler = &plog.ProtoUnmarshaler{}
+		}
+		for _, data := range batches {
+			ld, err := unmarshaler.UnmarshalLogs(data)
+			if err != nil {
+				return err
+			}
+			d.logs = append(d.logs, ld)
+		}
+	}
+	return nil
+}
+
+// add adds the dumped batches of a signal to the buffer and returns the number
+// of batches and items added
+func (d *bufferDump) add(ext ExtensionContext, signal string) (int, int) {
+	items := 0
+	switch signal {
+	case "traces":
+		for _, td := range d.traces {
+			items += td.SpanCount()
+			ext.AddTraces(td)
+		}
+		return len(d.traces), items
+	case "metrics":
+		for _, md := range d.metrics {
+			items += md.DataPointCount()
+			ext.AddMetrics(md)
+		}
+		return len(d.metrics), items
+	case "logs":
+		for _, ld := range d.logs {
+			items += ld.LogRecordCount()
+			ext.AddLogs(ld)
+		}
+		return len(d.logs), items
+	}
+	return 0, 0
+}
+
+// encodeDump joins encoded batches into a signal's file content
+func encodeDump(batches [][]byte, format string) []byte {
+	var buf bytes.Buffer
+	for _, data := range batches {
+		if format == "proto" {
+			_ = binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+			buf.Write(data)
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// DecodeDump splits a signal's dump file content, as written by export_buffer,
+// into encoded batches
+func DecodeDump(data []byte, format string) ([][]byte, error) {
+	var batches [][]byte
+	if format == "proto" {
+		for len(data) > 0 {
+			if len(data) < 4 {
+				return nil, errors.New("truncated length prefix")
+			}
+			size := binary.BigEndian.Uint32(data)
+			data = data[4:]
+			if uint64(size) > uint64(len(data)) {
+				return nil, fmt.Errorf("batch of %d bytes exceeds the remaining %d bytes", size, len(data))
+			}
+			batches = append(batches, data[:size])
+			data = data[size:]
+		}
+		return batches, nil
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			batches = append(batches, line)
+		}
+	}
+	return batches, nil
+}
+
+// readDump returns the raw content per signal of the dump referenced by input,
+// and the files it was read from
+func readDump(ext ExtensionContext, input ImportBufferInput, format string) (map[string][]byte, map[string]string, error) {
+	encoded := map[string]string{"traces": input.Traces, "metrics": input.Metrics, "logs": input.Logs}
+	hasData := input.Traces != "" || input.Metrics != "" || input.Logs != ""
+	data := make(map[string][]byte)
+	files := make(map[string]string)
+
+	switch {
+	case input.Path != "" && hasData:
+		return nil, nil, errors.New("specify either path or base64 data, not both")
+	case input.Path != "":
+		root, err := openDumpDir(ext, input.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer root.Close()
+		for _, signal := range dumpSignals {
+			name := filepath.Join(input.Path, dumpFileName(signal, format))
+			raw, err := readDumpFile(root, name)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read %s dump: %w", signal, err)
+			}
+			data[signal] = raw
+			files[signal] = filepath.Join(ext.GetDumpDir(), name)
+		}
+		if len(data) == 0 {
+			return nil, nil, fmt.Errorf("no %s dump files found in %s", format, input.Path)
+		}
+	case hasData:
+		for signal, value := range encoded {
+			if value == "" {
+				continue
+			}
+			raw, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid %s data: %w", signal, err)
+			}
+			data[signal] = raw
+		}
+	default:
+		return nil, nil, errors.New("path or base64 data is required")
+	}
+	return data, files, nil
+}
+
+// openDumpDir checks that path is a directory below the configured dump_dir
+// and opens dump_dir as a root, so dump file access cannot escape it through
+// symlinks either
+func openDumpDir(ext ExtensionContext, path string) (*os.Root, error) {
+	dumpDir := ext.GetDumpDir()
+	if dumpDir == "" {
+		return nil, errors.New("file dumps are disabled: set dump_dir in the extension config or omit path to use base64 data")
+	}
+	if !filepath.IsLocal(path) || slices.Contains(strings.Split(filepath.ToSlash(path), "/"), "..") {
+		return nil, fmt.Errorf("invalid path %q: must be relative to dump_dir and must not contain '..'", path)
+	}
+	root, err := os.OpenRoot(dumpDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dump_dir: %w", err)
+	}
+	return root, nil
+}
+
+// mkdirInRoot creates dir and its missing parents below root
+func mkdirInRoot(root *os.Root, dir string) error {
+	current := ""
+	for _, part := range strings.Split(filepath.Clean(dir), string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		if err := root.Mkdir(current, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDumpFile creates name below root, failing when it already exists
+func writeDumpFile(root *os.Root, name string, data []byte) error {
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readDumpFile reads name below root
+func readDumpFile(root *os.Root, name string) ([]byte, error) {
+	f, err := root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// redactedTraces returns a copy of td with the values of redacted attributes
+// replaced, leaving the buffered batch untouched
+func redactedTraces(ext ExtensionContext, td ptrace.Traces) ptrace.Traces {
+	redacted := ptrace.NewTraces()
+	td.CopyTo(redacted)
+	redactTraces(redacted, ext.IsRedactedAttribute)
+	return redacted
+}
+
+// redactedMetrics returns a copy of md with the values of redacted attributes
+// replaced, leaving the buffered batch untouched
+func redactedMetrics(ext ExtensionContext, md pmetric.Metrics) pmetric.Metrics {
+	redacted := pmetric.NewMetrics()
+	md.CopyTo(redacted)
+	redactMetrics(redacted, ext.IsRedactedAttribute)
+	return redacted
+}
+
+// redactedLogs returns a copy of ld with the values of redacted attributes
+// replaced, leaving the buffered batch untouched
+func redactedLogs(ext ExtensionContext, ld plog.Logs) plog.Logs {
+	redacted := plog.NewLogs()
+	ld.CopyTo(redacted)
+	redactLogs(redacted, ext.IsRedactedAttribute)
+	return redacted
+}
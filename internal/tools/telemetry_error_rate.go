@@ -0,0 +1,159 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// maxErrorRateBuckets bounds the number of buckets of an error rate series
+const maxErrorRateBuckets = 1000
+
+type ErrorRateTimeseriesInput struct {
+	ServiceName string `json:"service_name" jsonschema:"Service whose spans are counted,required"`
+	Bucket      string `json:"bucket,omitempty" jsonschema:"Bucket size as a Go duration (e.g. '30s' '1m' '5m'),1m"`
+	StartTime   string `json:"start_time,omitempty" jsonschema:"Only count spans starting at or after this time (RFC3339)"`
+	EndTime     string `json:"end_time,omitempty" jsonschema:"Only count spans starting at or before this time (RFC3339)"`
+}
+
+// ErrorRateBucket counts the spans starting in [Start, End)
+type ErrorRateBucket struct {
+	Start     string  `json:"start"`
+	End       string  `json:"end"`
+	Spans     int     `json:"spans"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+type ErrorRateTimeseriesOutput struct {
+	ServiceName string  `json:"service_name"`
+	Bucket      string  `json:"bucket"`
+	TotalSpans  int     `json:"total_spans"`
+	ErrorSpans  int     `json:"error_spans"`
+	ErrorRate   float64 `json:"error_rate"`
+	// Buckets run from the bucket of the earliest span to the bucket of the
+	// latest, including buckets without spans
+	Buckets []ErrorRateBucket `json:"buckets"`
+	// SkippedSpans counts spans of the service left out because their start
+	// timestamp is unset or out of range
+	SkippedSpans int  `json:"skipped_spans,omitempty"`
+	Truncated    bool `json:"truncated,omitempty"`
+}
+
+// RegisterErrorRateTimeseries registers the error_rate_timeseries tool
+func RegisterErrorRateTimeseries(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ErrorRateTimeseriesInput, ErrorRateTimeseriesOutput](server, &mcp.Tool{
+		Name:        "error_rate_timeseries",
+		Description: "Compute the error rate trend of a service: its buffered spans are grouped into fixed time buckets by start time and each bucket reports the span count, error span count (status Error) and error rate. Buckets are aligned to multiples of the bucket size and empty buckets are included. Spans without a valid start timestamp are skipped and counted in skipped_spans. Use to confirm when an incident started and when it was resolved.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ErrorRateTimeseriesInput) (*mcp.CallToolResult, ErrorRateTimeseriesOutput, error) {
+		if input.ServiceName == "" {
+			return nil, ErrorRateTimeseriesOutput{}, errors.New("service_name is required")
+		}
+		if err := checkBufferEnabled(ext, "traces"); err != nil {
+			return nil, ErrorRateTimeseriesOutput{}, err
+		}
+		bucketInput := input.Bucket
+		if bucketInput == "" {
+			bucketInput = "1m"
+		}
+		bucket, err := time.ParseDuration(bucketInput)
+		if err != nil || bucket <= 0 {
+			return nil, ErrorRateTimeseriesOutput{}, fmt.Errorf("invalid bucket: %s (must be a positive duration such as 1m)", input.Bucket)
+		}
+		from, to, err := parseTimeRange(input.StartTime, input.EndTime)
+		if err != nil {
+			return nil, ErrorRateTimeseriesOutput{}, err
+		}
+
+		output := ErrorRateTimeseriesOutput{ServiceName: input.ServiceName, Bucket: bucket.String()}
+		counts := make(map[int64]*ErrorRateBucket)
+		var first, last int64
+
+		for _, td := range ext.GetRecentTraces(10000, 0) {
+			if scanInterrupted(ctx) {
+				output.Truncated = true
+				break
+			}
+
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				rs := td.ResourceSpans().At(i)
+				serviceName := "unknown"
+				if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				if serviceName != input.ServiceName {
+					continue
+				}
+
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					spans := rs.ScopeSpans().At(j).Spans()
+					for k := 0; k < spans.Len(); k++ {
+						span := spans.At(k)
+						if span.StartTimestamp() == 0 || uint64(span.StartTimestamp()) > math.MaxInt64 {
+							output.SkippedSpans++
+							continue
+						}
+						if !inTimeRange(span.StartTimestamp(), from, to) {
+							continue
+						}
+
+						index := int64(span.StartTimestamp()) / int64(bucket)
+						if len(counts) == 0 || index < first {
+							first = index
+						}
+						if len(counts) == 0 || index > last {
+							last = index
+						}
+						b := counts[index]
+						if b == nil {
+							b = &ErrorRateBucket{}
+							counts[index] = b
+						}
+						b.Spans++
+						output.TotalSpans++
+						if span.Status().Code() == ptrace.StatusCodeError {
+							b.Errors++
+							output.ErrorSpans++
+						}
+					}
+				}
+			}
+		}
+
+		output.Buckets = []ErrorRateBucket{}
+		if len(counts) == 0 {
+			return nil, output, nil
+		}
+		if last-first >= maxErrorRateBuckets {
+			return nil, ErrorRateTimeseriesOutput{}, fmt.Errorf("the spans cover %d buckets of %s, more than %d: use a larger bucket or a time range", last-first+1, bucket, maxErrorRateBuckets)
+		}
+
+		output.ErrorRate = float64(output.ErrorSpans) / float64(output.TotalSpans)
+		for index := first; index <= last; index++ {
+			b := ErrorRateBucket{}
+			if counted, ok := counts[index]; ok {
+				b = *counted
+				b.ErrorRate = float64(b.Errors) / float64(b.Spans)
+			}
+			start := time.Unix(0, index*int64(bucket)).UTC()
+			b.Start = start.Format(time.RFC3339Nano)
+			b.End = start.Add(bucket).Format(time.RFC3339Nano)
+			output.Buckets = append(output.Buckets, b)
+		}
+
+		return nil, output, nil
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+type ListSpanEventsInput struct {
+	Name        string `json:"name,omitempty" jsonschema:"Event name to match (case-insensitive). Use '*' for all events,exception"`
+	ServiceName string `json:"service_name,omitempty" jsonschema:"Filter by service name"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of events to return,50"`
+}
+
+type SpanEvent struct {
+	TraceID             string `json:"trace_id"`
+	SpanID              string `json:"span_id"`
+	SpanName            string `json:"span_name"`
+	Service             string `json:"service"`
+	Timestamp           string `json:"timestamp"`
+	Name                string `json:"name"`
+	ExceptionType       string `json:"exception_type,omitempty"`
+	ExceptionMessage    string `json:"exception_message,omitempty"`
+	ExceptionStacktrace string `json:"exception_stacktrace,omitempty"`
+	Attributes          string `json:"attributes,omitempty"`
+}
+
+type ListSpanEventsOutput struct {
+	EventCount int         `json:"event_count"`
+	Events     []SpanEvent `json:"events"`
+	Truncated  bool        `json:"truncated,omitempty"`
+}
+
+// exceptionAttributes are reported as dedicated fields rather than in Attributes
+var exceptionAttributes = map[string]bool{
+	"exception.type":       true,
+	"exception.message":    true,
+	"exception.stacktrace": true,
+}
+
+// RegisterListSpanEvents registers the list_span_events tool
+func RegisterListSpanEvents(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ListSpanEventsInput, ListSpanEventsOutput](server, &mcp.Tool{
+		Name:        "list_span_events",
+		Description: "List span events across all buffered traces, by default recorded exceptions. Each event includes its trace/span ID, timestamp, exception type/message and a truncated stacktrace. Use get_trace_by_id or get_span_by_id to drill down.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ListSpanEventsInput) (*mcp.CallToolResult, ListSpanEventsOutput, error) {
+		limit, err := resolveLimit(ext, input.Limit, 50)
+		if err != nil {
+			return nil, ListSpanEventsOutput{}, err
+		}
+		name := input.Name
+		if name == "" {
+			name = "exception"
+		}
+
+		events := make([]SpanEvent, 0)
+		truncated := false
+		for _, td := range ext.GetRecentTraces(10000, 0) {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
+			}
+
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				rs := td.ResourceSpans().At(i)
+				serviceName := "unknown"
+				if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+
+				if input.ServiceName != "" && serviceName != input.ServiceName {
+					continue
+				}
+
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					spans := rs.ScopeSpans().At(j).Spans()
+					for k := 0; k < spans.Len(); k++ {
+						span := spans.At(k)
+						for l := 0; l < span.Events().Len(); l++ {
+							if len(events) >= limit {
+								return nil, ListSpanEventsOutput{EventCount: len(events), Events: events}, nil
+							}
+
+							event := span.Events().At(l)
+							if name != "*" && !strings.EqualFold(event.Name(), name) {
+								continue
+							}
+
+							se := SpanEvent{
+								TraceID:   span.TraceID().String(),
+								SpanID:    span.SpanID().String(),
+								SpanName:  span.Name(),
+								Service:   serviceName,
+								Timestamp: time.Unix(0, int64(event.Timestamp())).UTC().Format(time.RFC3339Nano),
+								Name:      event.Name(),
+							}
+							if v, ok := event.Attributes().Get("exception.type"); ok {
+								se.ExceptionType = redactValue("exception.type", v.AsString(), ext.IsRedactedAttribute)
+							}
+							if v, ok := event.Attributes().Get("exception.message"); ok {
+								se.ExceptionMessage = redactValue("exception.message", v.AsString(), ext.IsRedactedAttribute)
+							}
+							if v, ok := event.Attributes().Get("exception.stacktrace"); ok {
+								se.ExceptionStacktrace = truncateString(redactValue("exception.stacktrace", v.AsString(), ext.IsRedactedAttribute), 500)
+							}
+
+							var others []string
+							event.Attributes().Range(func(k string, v pcommon.Value) bool {
+								if !exceptionAttributes[k] {
+									others = append(others, k+"="+redactValue(k, v.AsString(), ext.IsRedactedAttribute))
+								}
+								return true
+							})
+							se.Attributes = truncateString(strings.Join(others, ";"), 100)
+
+							events = append(events, se)
+						}
+					}
+				}
+			}
+		}
+
+		return nil, ListSpanEventsOutput{EventCount: len(events), Events: events, Truncated: truncated}, nil
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type EvictTraceInput struct {
+	TraceID string `json:"trace_id" jsonschema:"Full trace ID (32 hex characters) to remove from the buffer,required"`
+}
+
+type EvictTraceOutput struct {
+	TraceID      string `json:"trace_id"`
+	SpansRemoved int    `json:"spans_removed"`
+	Evicted      bool   `json:"evicted"`
+}
+
+// RegisterEvictTrace registers the evict_trace tool
+func RegisterEvictTrace(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[EvictTraceInput, EvictTraceOutput](server, &mcp.Tool{
+		Name:        "evict_trace",
+		Description: "Remove every span of a trace from the in-memory buffer (e.g. a trace that captured PII). Batches that only contain the trace are dropped, batches shared with other traces are rewritten without its spans. Only affects the buffer, not telemetry already forwarded by the pipeline.",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+			IdempotentHint:  true,
+			OpenWorldHint:   boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input EvictTraceInput) (*mcp.CallToolResult, EvictTraceOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+		traceID := strings.ToLower(input.TraceID)
+		if b, err := hex.DecodeString(traceID); err != nil || len(b) != 16 {
+			return nil, EvictTraceOutput{}, ErrInvalidTraceID
+		}
+
+		removed := ext.EvictTrace(traceID)
+		return nil, EvictTraceOutput{
+			TraceID:      traceID,
+			SpansRemoved: removed,
+			Evicted:      removed > 0,
+		}, nil
+	})
+}
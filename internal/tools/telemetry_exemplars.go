@@ -0,0 +1,250 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type GetExemplarTracesInput struct {
+	MetricName  string `json:"metric_name" jsonschema:"Metric whose data point exemplars to follow (exact match),required"`
+	ServiceName string `json:"service_name,omitempty" jsonschema:"Only use data points of this service"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of exemplar traces to return,20"`
+}
+
+// ExemplarTrace is a trace referenced by exemplars of a metric, described by
+// its first exemplar and, when its spans are still buffered, a summary of the
+// trace
+type ExemplarTrace struct {
+	TraceID string `json:"trace_id"`
+	SpanID  string `json:"span_id,omitempty"`
+	// Exemplars is the number of exemplars referencing the trace
+	Exemplars int     `json:"exemplars"`
+	Value     float64 `json:"value"`
+	Timestamp string  `json:"timestamp,omitempty"`
+	// Available is set when spans of the trace are in the buffer or the trace
+	// cache; the fields below are only set then
+	Available  bool   `json:"available"`
+	SpanCount  int    `json:"span_count,omitempty"`
+	RootSpan   string `json:"root_span,omitempty"`
+	Service    string `json:"service,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+	ErrorSpans int    `json:"error_spans,omitempty"`
+}
+
+type GetExemplarTracesOutput struct {
+	MetricName string `json:"metric_name"`
+	Found      bool   `json:"found"`
+	// ExemplarCount counts the exemplars with a trace ID
+	ExemplarCount  int             `json:"exemplar_count"`
+	TraceCount     int             `json:"trace_count"`
+	AvailableCount int             `json:"available_count"`
+	Traces         []ExemplarTrace `json:"traces"`
+	Truncated      bool            `json:"truncated,omitempty"`
+}
+
+// exemplarTraceSummary accumulates the spans of an exemplar trace
+type exemplarTraceSummary struct {
+	spans      int
+	start, end pcommon.Timestamp
+	errors     int
+	// root is the span without parent, or the earliest span when the root
+	// is not buffered
+	rootName, rootService string
+	rootStart             pcommon.Timestamp
+	hasRoot               bool
+}
+
+func (s *exemplarTraceSummary) add(span ptrace.Span, serviceName string) {
+	s.spans++
+	if s.start == 0 || span.StartTimestamp() < s.start {
+		s.start = span.StartTimestamp()
+	}
+	s.end = max(s.end, span.EndTimestamp())
+	if span.Status().Code() == ptrace.StatusCodeError {
+		s.errors++
+	}
+	if s.hasRoot {
+		return
+	}
+	isRoot := span.ParentSpanID().IsEmpty()
+	if isRoot || s.spans == 1 || span.StartTimestamp() < s.rootStart {
+		s.rootName, s.rootService, s.rootStart = span.Name(), serviceName, span.StartTimestamp()
+		s.hasRoot = isRoot
+	}
+}
+
+// RegisterGetExemplarTraces registers the get_exemplar_traces tool
+func RegisterGetExemplarTraces(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetExemplarTracesInput, GetExemplarTracesOutput](server, &mcp.Tool{
+		Name:        "get_exemplar_traces",
+		Description: "Follow the exemplars of a metric to their traces: collects the trace IDs of the exemplars on the metric's data points (Sum, Gauge, Histogram, ExponentialHistogram) and resolves them against the trace buffer and trace cache. Returns one entry per trace with its exemplar value and, when still available, the span count, root span, service, duration and error spans. Use get_trace_by_id to drill down.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetExemplarTracesInput) (*mcp.CallToolResult, GetExemplarTracesOutput, error) {
+		if input.MetricName == "" {
+			return nil, GetExemplarTracesOutput{}, errors.New("metric_name is required")
+		}
+		if err := checkBufferEnabled(ext, "metrics"); err != nil {
+			return nil, GetExemplarTracesOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, 20)
+		if err != nil {
+			return nil, GetExemplarTracesOutput{}, err
+		}
+
+		output := GetExemplarTracesOutput{MetricName: input.MetricName}
+		traces := make(map[pcommon.TraceID]*ExemplarTrace)
+		var order []pcommon.TraceID
+		for _, md := range ext.GetRecentMetrics(10000, 0) {
+			if scanInterrupted(ctx) {
+				output.Truncated = true
+				break
+			}
+
+			for i := 0; i < md.ResourceMetrics().Len(); i++ {
+				rm := md.ResourceMetrics().At(i)
+				if input.ServiceName != "" {
+					sn, ok := rm.Resource().Attributes().Get("service.name")
+					if !ok || sn.AsString() != input.ServiceName {
+						continue
+					}
+				}
+
+				for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+					metrics := rm.ScopeMetrics().At(j).Metrics()
+					for k := 0; k < metrics.Len(); k++ {
+						metric := metrics.At(k)
+						if metric.Name() != input.MetricName {
+							continue
+						}
+						output.Found = true
+
+						forEachExemplar(metric, func(exemplar pmetric.Exemplar) {
+							traceID := exemplar.TraceID()
+							if traceID.IsEmpty() {
+								return
+							}
+							output.ExemplarCount++
+							if trace, ok := traces[traceID]; ok {
+								trace.Exemplars++
+								return
+							}
+							value := exemplar.DoubleValue()
+							if exemplar.ValueType() == pmetric.ExemplarValueTypeInt {
+								value = float64(exemplar.IntValue())
+							}
+							traces[traceID] = &ExemplarTrace{
+								TraceID:   traceID.String(),
+								SpanID:    exemplar.SpanID().String(),
+								Exemplars: 1,
+								Value:     value,
+								Timestamp: formatSearchTimestamp(exemplar.Timestamp()),
+							}
+							order = append(order, traceID)
+						})
+					}
+				}
+			}
+		}
+
+		output.TraceCount = len(order)
+		if len(order) > limit {
+			order = order[:limit]
+		}
+		output.Traces = make([]ExemplarTrace, 0, len(order))
+
+		summaries := make(map[pcommon.TraceID]*exemplarTraceSummary, len(order))
+		for _, traceID := range order {
+			summaries[traceID] = &exemplarTraceSummary{}
+		}
+		addSpans := func(td ptrace.Traces) {
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				rs := td.ResourceSpans().At(i)
+				serviceName := "unknown"
+				if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					spans := rs.ScopeSpans().At(j).Spans()
+					for k := 0; k < spans.Len(); k++ {
+						if summary, ok := summaries[spans.At(k).TraceID()]; ok {
+							summary.add(spans.At(k), serviceName)
+						}
+					}
+				}
+			}
+		}
+		if len(order) > 0 && ext.GetBufferStats().TracesCapacity > 0 {
+			for _, td := range ext.GetRecentTraces(10000, 0) {
+				if scanInterrupted(ctx) {
+					output.Truncated = true
+					break
+				}
+				addSpans(td)
+			}
+		}
+
+		for _, traceID := range order {
+			trace := traces[traceID]
+			summary := summaries[traceID]
+			// The trace cache still holds traces whose batches were evicted
+			if summary.spans == 0 {
+				if cached, ok := ext.GetCachedTrace(trace.TraceID); ok {
+					addSpans(cached)
+				}
+			}
+			if summary.spans > 0 {
+				output.AvailableCount++
+				trace.Available = true
+				trace.SpanCount = summary.spans
+				trace.RootSpan = summary.rootName
+				trace.Service = summary.rootService
+				trace.Duration = formatDuration(summary.end.AsTime().Sub(summary.start.AsTime()))
+				trace.ErrorSpans = summary.errors
+			}
+			output.Traces = append(output.Traces, *trace)
+		}
+
+		return nil, output, nil
+	})
+}
+
+// forEachExemplar calls fn with every exemplar of the data points of a metric.
+// Summary data points carry no exemplars.
+func forEachExemplar(metric pmetric.Metric, fn func(pmetric.Exemplar)) {
+	each := func(exemplars pmetric.ExemplarSlice) {
+		for i := 0; i < exemplars.Len(); i++ {
+			fn(exemplars.At(i))
+		}
+	}
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < metric.Gauge().DataPoints().Len(); i++ {
+			each(metric.Gauge().DataPoints().At(i).Exemplars())
+		}
+	case pmetric.MetricTypeSum:
+		for i := 0; i < metric.Sum().DataPoints().Len(); i++ {
+			each(metric.Sum().DataPoints().At(i).Exemplars())
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < metric.Histogram().DataPoints().Len(); i++ {
+			each(metric.Histogram().DataPoints().At(i).Exemplars())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		for i := 0; i < metric.ExponentialHistogram().DataPoints().Len(); i++ {
+			each(metric.ExponentialHistogram().DataPoints().At(i).Exemplars())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type ExportTraceOTLPInput struct {
+	TraceID string `json:"trace_id" jsonschema:"Full trace ID (32 hex characters) to export,required"`
+}
+
+type ExportTraceOTLPOutput struct {
+	TraceID   string `json:"trace_id"`
+	SpanCount int    `json:"span_count"`
+	OTLPJSON  string `json:"otlp_json,omitempty"`
+}
+
+// RegisterExportTraceOTLP registers the export_trace_otlp tool
+func RegisterExportTraceOTLP(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ExportTraceOTLPInput, ExportTraceOTLPOutput](server, &mcp.Tool{
+		Name:        "export_trace_otlp",
+		Description: "Export every buffered span of a trace as OTLP/JSON, including resource and scope information. Unlike the markdown and CSV views the export is lossless, except for the values of redacted attributes, and can be fed into other OTLP tooling. span_count is 0 if the trace is not buffered.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ExportTraceOTLPInput) (*mcp.CallToolResult, ExportTraceOTLPOutput, error) {
+		traceID := strings.ToLower(input.TraceID)
+		b, err := hex.DecodeString(traceID)
+		if err != nil || len(b) != 16 {
+			return nil, ExportTraceOTLPOutput{}, ErrInvalidTraceID
+		}
+		id := pcommon.TraceID(b)
+
+		export := ptrace.NewTraces()
+		spanCount := 0
+		for _, td := range ext.GetRecentTraces(10000, 0) {
+			if ctx.Err() != nil {
+				return nil, ExportTraceOTLPOutput{}, ctx.Err()
+			}
+			spanCount += appendTraceSpans(export, td, id)
+		}
+
+		if spanCount == 0 {
+			return nil, ExportTraceOTLPOutput{TraceID: traceID}, nil
+		}
+
+		redactTraces(export, ext.IsRedactedAttribute)
+		data, err := (&ptrace.JSONMarshaler{}).MarshalTraces(export)
+		if err != nil {
+			return nil, ExportTraceOTLPOutput{}, fmt.Errorf("failed to marshal trace: %w", err)
+		}
+
+		return nil, ExportTraceOTLPOutput{
+			TraceID:   traceID,
+			SpanCount: spanCount,
+			OTLPJSON:  string(data),
+		}, nil
+	})
+}
+
+// appendTraceSpans copies the spans of td belonging to traceID into dest,
+// together with their resource and scope, and returns the number copied
+func appendTraceSpans(dest, td ptrace.Traces, traceID pcommon.TraceID) int {
+	copied := 0
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		var destRS ptrace.ResourceSpans
+		hasResource := false
+
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			var destSS ptrace.ScopeSpans
+			hasScope := false
+
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				if span.TraceID() != traceID {
+					continue
+				}
+				if !hasResource {
+					destRS = dest.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(destRS.Resource())
+					destRS.SetSchemaUrl(rs.SchemaUrl())
+					hasResource = true
+				}
+				if !hasScope {
+					destSS = destRS.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(destSS.Scope())
+					destSS.SetSchemaUrl(ss.SchemaUrl())
+					hasScope = true
+				}
+				span.CopyTo(destSS.Spans().AppendEmpty())
+				copied++
+			}
+		}
+	}
+	return copied
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type FindTracesByAttributeInput struct {
+	AttributeKey string `json:"attribute_key" jsonschema:"Attribute key to look for on resources and spans (e.g. 'order.id' 'enduser.id'),required"`
+	Value        string `json:"value" jsonschema:"Attribute value to match (exact match on the value as a string),required"`
+	StartTime    string `json:"start_time,omitempty" jsonschema:"Only match spans starting at or after this time (RFC3339)"`
+	EndTime      string `json:"end_time,omitempty" jsonschema:"Only match spans starting at or before this time (RFC3339)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"Maximum number of traces to return,20"`
+}
+
+// TraceAttributeMatch is a trace holding a span with the attribute, described
+// by the first matching span
+type TraceAttributeMatch struct {
+	TraceID  string `json:"trace_id"`
+	SpanID   string `json:"span_id"`
+	SpanName string `json:"span_name"`
+	Service  string `json:"service"`
+	// Level is where the attribute was found: resource or span
+	Level         string `json:"level"`
+	Timestamp     string `json:"timestamp,omitempty"`
+	MatchingSpans int    `json:"matching_spans"`
+}
+
+type FindTracesByAttributeOutput struct {
+	AttributeKey string                `json:"attribute_key"`
+	Value        string                `json:"value"`
+	TotalTraces  int                   `json:"total_traces"`
+	Traces       []TraceAttributeMatch `json:"traces"`
+	Truncated    bool                  `json:"truncated,omitempty"`
+}
+
+// RegisterFindTracesByAttribute registers the find_traces_by_attribute tool
+func RegisterFindTracesByAttribute(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[FindTracesByAttributeInput, FindTracesByAttributeOutput](server, &mcp.Tool{
+		Name:        "find_traces_by_attribute",
+		Description: "Find the traces containing a span with an attribute value, e.g. the trace of an order ID or user ID, when the trace ID is not known. Matches span attributes and the resource attributes of the span. Returns one entry per trace with the first matching span's name and service and the number of matching spans, in buffer order. Use get_trace_by_id to drill down.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input FindTracesByAttributeInput) (*mcp.CallToolResult, FindTracesByAttributeOutput, error) {
+		if input.AttributeKey == "" {
+			return nil, FindTracesByAttributeOutput{}, errors.New("attribute_key is required")
+		}
+		if input.Value == "" {
+			return nil, FindTracesByAttributeOutput{}, errors.New("value is required")
+		}
+		if err := checkBufferEnabled(ext, "traces"); err != nil {
+			return nil, FindTracesByAttributeOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, 20)
+		if err != nil {
+			return nil, FindTracesByAttributeOutput{}, err
+		}
+		from, to, err := parseTimeRange(input.StartTime, input.EndTime)
+		if err != nil {
+			return nil, FindTracesByAttributeOutput{}, err
+		}
+
+		output := FindTracesByAttributeOutput{AttributeKey: input.AttributeKey, Value: input.Value}
+		matches := make(map[string]*TraceAttributeMatch)
+		var order []string
+		for _, td := range ext.GetRecentTraces(10000, 0) {
+			if scanInterrupted(ctx) {
+				output.Truncated = true
+				break
+			}
+
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				rs := td.ResourceSpans().At(i)
+				serviceName := "unknown"
+				if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				resourceMatches := false
+				if v, ok := rs.Resource().Attributes().Get(input.AttributeKey); ok && v.AsString() == input.Value {
+					resourceMatches = true
+				}
+
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					spans := rs.ScopeSpans().At(j).Spans()
+					for k := 0; k < spans.Len(); k++ {
+						span := spans.At(k)
+						if !inTimeRange(span.StartTimestamp(), from, to) {
+							continue
+						}
+						level := "resource"
+						if !resourceMatches {
+							v, ok := span.Attributes().Get(input.AttributeKey)
+							if !ok || v.AsString() != input.Value {
+								continue
+							}
+							level = "span"
+						}
+
+						traceID := span.TraceID().String()
+						if match, ok := matches[traceID]; ok {
+							match.MatchingSpans++
+							continue
+						}
+						matches[traceID] = &TraceAttributeMatch{
+							TraceID:       traceID,
+							SpanID:        span.SpanID().String(),
+							SpanName:      span.Name(),
+							Service:       serviceName,
+							Level:         level,
+							Timestamp:     formatSearchTimestamp(span.StartTimestamp()),
+							MatchingSpans: 1,
+						}
+						order = append(order, traceID)
+					}
+				}
+			}
+		}
+
+		output.TotalTraces = len(order)
+		if len(order) > limit {
+			order = order[:limit]
+		}
+		output.Traces = make([]TraceAttributeMatch, 0, len(order))
+		for _, traceID := range order {
+			output.Traces = append(output.Traces, *matches[traceID])
+		}
+
+		return nil, output, nil
+	})
+}
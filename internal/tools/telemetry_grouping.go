@@ -0,0 +1,744 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type GetMetricsByResourceInput struct {
+	GroupBy string `json:"group_by,omitempty" jsonschema:"Resource attribute key to group by (e.g. 'service.name' 'host.name' 'k8s.pod.name'),service.name"`
+}
+
+type ResourceMetric struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	Unit            string `json:"unit,omitempty"`
+	LatestValue     string `json:"latest_value"`
+	LatestTimestamp string `json:"latest_timestamp,omitempty"`
+	DataPointCount  int    `json:"data_point_count"`
+}
+
+type MetricResourceGroup struct {
+	Value       string           `json:"value"`
+	MetricCount int              `json:"metric_count"`
+	Metrics     []ResourceMetric `json:"metrics"`
+}
+
+type GetMetricsByResourceOutput struct {
+	GroupBy    string                `json:"group_by"`
+	GroupCount int                   `json:"group_count"`
+	Groups     []MetricResourceGroup `json:"groups"`
+	Truncated  bool                  `json:"truncated,omitempty"`
+}
+
+// RegisterGetMetricsByResource registers the get_metrics_by_resource tool
+func RegisterGetMetricsByResource(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetMetricsByResourceInput, GetMetricsByResourceOutput](server, &mcp.Tool{
+		Name:        "get_metrics_by_resource",
+		Description: "Group buffered metrics by a resource attribute (default service.name) and list the metric names with their latest value per group. Resources without the attribute are grouped under 'unknown'.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetMetricsByResourceInput) (*mcp.CallToolResult, GetMetricsByResourceOutput, error) {
+		groupBy := input.GroupBy
+		if groupBy == "" {
+			groupBy = "service.name"
+		}
+
+		// group value -> metric name -> latest observation
+		groups := make(map[string]map[string]*ResourceMetric)
+		latest := make(map[*ResourceMetric]pcommon.Timestamp)
+		truncated := false
+
+		for _, md := range ext.GetRecentMetrics(10000, 0) {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
+			}
+
+			for i := 0; i < md.ResourceMetrics().Len(); i++ {
+				rm := md.ResourceMetrics().At(i)
+				groupValue := "unknown"
+				if v, ok := rm.Resource().Attributes().Get(groupBy); ok {
+					groupValue = redactValue(groupBy, v.AsString(), ext.IsRedactedAttribute)
+				}
+
+				metrics, ok := groups[groupValue]
+				if !ok {
+					metrics = make(map[string]*ResourceMetric)
+					groups[groupValue] = metrics
+				}
+
+				for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+					sm := rm.ScopeMetrics().At(j)
+					for k := 0; k < sm.Metrics().Len(); k++ {
+						metric := sm.Metrics().At(k)
+						value, ts, count := latestDataPoint(metric)
+
+						entry, ok := metrics[metric.Name()]
+						if !ok {
+							entry = &ResourceMetric{
+								Name:        metric.Name(),
+								Type:        metric.Type().String(),
+								Unit:        metric.Unit(),
+								LatestValue: "-",
+							}
+							metrics[metric.Name()] = entry
+						}
+						entry.DataPointCount += count
+						if count > 0 && ts >= latest[entry] {
+							latest[entry] = ts
+							entry.LatestValue = value
+							entry.LatestTimestamp = time.Unix(0, int64(ts)).UTC().Format(time.RFC3339Nano)
+						}
+					}
+				}
+			}
+		}
+
+		output := GetMetricsByResourceOutput{
+			GroupBy:   groupBy,
+			Groups:    make([]MetricResourceGroup, 0, len(groups)),
+			Truncated: truncated,
+		}
+		for groupValue, metrics := range groups {
+			group := MetricResourceGroup{
+				Value:   groupValue,
+				Metrics: make([]ResourceMetric, 0, len(metrics)),
+			}
+			for _, m := range metrics {
+				group.Metrics = append(group.Metrics, *m)
+			}
+			sort.Slice(group.Metrics, func(i, j int) bool {
+				return group.Metrics[i].Name < group.Metrics[j].Name
+			})
+			group.MetricCount = len(group.Metrics)
+			output.Groups = append(output.Groups, group)
+		}
+		sort.Slice(output.Groups, func(i, j int) bool {
+			return output.Groups[i].Value < output.Groups[j].Value
+		})
+		output.GroupCount = len(output.Groups)
+
+		return nil, output, nil
+	})
+}
+
+// latestDataPoint returns the formatted value and timestamp of the most recent
+// data point of a metric along with its data point count
+func latestDataPoint(metric pmetric.Metric) (string, pcommon.Timestamp, int) {
+	var value string
+	var ts pcommon.Timestamp
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		var dps pmetric.NumberDataPointSlice
+		if metric.Type() == pmetric.MetricTypeSum {
+			dps = metric.Sum().DataPoints()
+		} else {
+			dps = metric.Gauge().DataPoints()
+		}
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); i == 0 || dp.Timestamp() >= ts {
+				ts = dp.Timestamp()
+				value = formatNumberDataPoint(dp)
+			}
+		}
+		return value, ts, dps.Len()
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); i == 0 || dp.Timestamp() >= ts {
+				ts = dp.Timestamp()
+				value = formatCountSum(dp.Count(), dp.Sum())
+			}
+		}
+		return value, ts, dps.Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); i == 0 || dp.Timestamp() >= ts {
+				ts = dp.Timestamp()
+				value = formatCountSum(dp.Count(), dp.Sum())
+			}
+		}
+		return value, ts, dps.Len()
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if dp := dps.At(i); i == 0 || dp.Timestamp() >= ts {
+				ts = dp.Timestamp()
+				value = formatCountSum(dp.Count(), dp.Sum())
+			}
+		}
+		return value, ts, dps.Len()
+	}
+	return value, ts, 0
+}
+
+type ListSpanNamesInput struct {
+	ServiceName string `json:"service_name,omitempty" jsonschema:"Only count spans from this service"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of span names to return (most frequent first),100"`
+}
+
+type SpanNameFacet struct {
+	Name     string   `json:"name"`
+	Count    int      `json:"count"`
+	Services []string `json:"services"`
+}
+
+type ListSpanNamesOutput struct {
+	TotalNames int             `json:"total_names"`
+	SpanNames  []SpanNameFacet `json:"span_names"`
+	Truncated  bool            `json:"truncated,omitempty"`
+}
+
+// RegisterListSpanNames registers the list_span_names tool
+func RegisterListSpanNames(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ListSpanNamesInput, ListSpanNamesOutput](server, &mcp.Tool{
+		Name:        "list_span_names",
+		Description: "List the distinct span names in the buffer with their span counts and the services they appear in, most frequent first. Use to discover names before filtering with query_traces.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ListSpanNamesInput) (*mcp.CallToolResult, ListSpanNamesOutput, error) {
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, ListSpanNamesOutput{}, err
+		}
+
+		counts := make(map[string]int)
+		services := make(map[string]map[string]struct{})
+		truncated := false
+
+		for _, td := range ext.GetRecentTraces(10000, 0) {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
+			}
+
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				rs := td.ResourceSpans().At(i)
+				serviceName := "unknown"
+				if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+
+				if input.ServiceName != "" && serviceName != input.ServiceName {
+					continue
+				}
+
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					spans := rs.ScopeSpans().At(j).Spans()
+					for k := 0; k < spans.Len(); k++ {
+						name := spans.At(k).Name()
+						counts[name]++
+						if services[name] == nil {
+							services[name] = make(map[string]struct{})
+						}
+						services[name][serviceName] = struct{}{}
+					}
+				}
+			}
+		}
+
+		facets := make([]SpanNameFacet, 0, len(counts))
+		for name, count := range counts {
+			facet := SpanNameFacet{
+				Name:     name,
+				Count:    count,
+				Services: make([]string, 0, len(services[name])),
+			}
+			for service := range services[name] {
+				facet.Services = append(facet.Services, service)
+			}
+			sort.Strings(facet.Services)
+			facets = append(facets, facet)
+		}
+		sort.Slice(facets, func(i, j int) bool {
+			if facets[i].Count != facets[j].Count {
+				return facets[i].Count > facets[j].Count
+			}
+			return facets[i].Name < facets[j].Name
+		})
+
+		output := ListSpanNamesOutput{TotalNames: len(facets), Truncated: truncated}
+		if len(facets) > limit {
+			facets = facets[:limit]
+		}
+		output.SpanNames = facets
+
+		return nil, output, nil
+	})
+}
+
+type ListInstrumentationScopesInput struct {
+	ServiceName string `json:"service_name,omitempty" jsonschema:"Only count telemetry from this service"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of scopes to return (most telemetry first),100"`
+}
+
+// InstrumentationScopeFacet counts the buffered telemetry an instrumentation
+// scope produced per signal
+type InstrumentationScopeFacet struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version,omitempty"`
+	Spans    int      `json:"spans"`
+	Logs     int      `json:"logs"`
+	Metrics  int      `json:"metrics"`
+	Services []string `json:"services"`
+}
+
+type ListInstrumentationScopesOutput struct {
+	TotalScopes int                         `json:"total_scopes"`
+	Scopes      []InstrumentationScopeFacet `json:"scopes"`
+	Truncated   bool                        `json:"truncated,omitempty"`
+}
+
+// RegisterListInstrumentationScopes registers the list_instrumentation_scopes tool
+func RegisterListInstrumentationScopes(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[ListInstrumentationScopesInput, ListInstrumentationScopesOutput](server, &mcp.Tool{
+		Name:        "list_instrumentation_scopes",
+		Description: "List the instrumentation scopes (libraries) that produced buffered telemetry, by scope name and version, with span, log record and metric counts and the services using them. Use to audit instrumentation and spot missing or noisy libraries.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input ListInstrumentationScopesInput) (*mcp.CallToolResult, ListInstrumentationScopesOutput, error) {
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, ListInstrumentationScopesOutput{}, err
+		}
+
+		type scopeKey struct{ name, version string }
+		facets := make(map[scopeKey]*InstrumentationScopeFacet)
+		services := make(map[scopeKey]map[string]struct{})
+		truncated := false
+		facetFor := func(scope pcommon.InstrumentationScope, serviceName string) *InstrumentationScopeFacet {
+			key := scopeKey{scope.Name(), scope.Version()}
+			if facets[key] == nil {
+				facets[key] = &InstrumentationScopeFacet{Name: key.name, Version: key.version}
+				services[key] = make(map[string]struct{})
+			}
+			services[key][serviceName] = struct{}{}
+			return facets[key]
+		}
+
+		for _, td := range ext.GetRecentTraces(10000, 0) {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
+			}
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				rs := td.ResourceSpans().At(i)
+				serviceName := "unknown"
+				if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				if input.ServiceName != "" && serviceName != input.ServiceName {
+					continue
+				}
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					ss := rs.ScopeSpans().At(j)
+					facetFor(ss.Scope(), serviceName).Spans += ss.Spans().Len()
+				}
+			}
+		}
+
+		for _, ld := range ext.GetRecentLogs(10000, 0) {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
+			}
+			for i := 0; i < ld.ResourceLogs().Len(); i++ {
+				rl := ld.ResourceLogs().At(i)
+				serviceName := "unknown"
+				if sn, ok := rl.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				if input.ServiceName != "" && serviceName != input.ServiceName {
+					continue
+				}
+				for j := 0; j < rl.ScopeLogs().Len(); j++ {
+					sl := rl.ScopeLogs().At(j)
+					facetFor(sl.Scope(), serviceName).Logs += sl.LogRecords().Len()
+				}
+			}
+		}
+
+		for _, md := range ext.GetRecentMetrics(10000, 0) {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
+			}
+			for i := 0; i < md.ResourceMetrics().Len(); i++ {
+				rm := md.ResourceMetrics().At(i)
+				serviceName := "unknown"
+				if sn, ok := rm.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				if input.ServiceName != "" && serviceName != input.ServiceName {
+					continue
+				}
+				for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+					sm := rm.ScopeMetrics().At(j)
+					facetFor(sm.Scope(), serviceName).Metrics += sm.Metrics().Len()
+				}
+			}
+		}
+
+		scopes := make([]InstrumentationScopeFacet, 0, len(facets))
+		for key, facet := range facets {
+			facet.Services = make([]string, 0, len(services[key]))
+			for service := range services[key] {
+				facet.Services = append(facet.Services, service)
+			}
+			sort.Strings(facet.Services)
+			scopes = append(scopes, *facet)
+		}
+		sort.Slice(scopes, func(i, j int) bool {
+			ti := scopes[i].Spans + scopes[i].Logs + scopes[i].Metrics
+			tj := scopes[j].Spans + scopes[j].Logs + scopes[j].Metrics
+			if ti != tj {
+				return ti > tj
+			}
+			if scopes[i].Name != scopes[j].Name {
+				return scopes[i].Name < scopes[j].Name
+			}
+			return scopes[i].Version < scopes[j].Version
+		})
+
+		output := ListInstrumentationScopesOutput{TotalScopes: len(scopes), Truncated: truncated}
+		if len(scopes) > limit {
+			scopes = scopes[:limit]
+		}
+		output.Scopes = scopes
+
+		return nil, output, nil
+	})
+}
+
+type GetAttributeValuesInput struct {
+	Signal   string `json:"signal" jsonschema:"Signal to scan (traces metrics logs),required"`
+	Key      string `json:"key" jsonschema:"Attribute key (e.g. 'http.route' 'k8s.namespace.name'),required"`
+	Resource bool   `json:"resource,omitempty" jsonschema:"Look up the key in resource attributes instead of span, log record or data point attributes,false"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"Maximum number of values to return (most frequent first),100"`
+}
+
+type AttributeValueFacet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type GetAttributeValuesOutput struct {
+	Signal string `json:"signal"`
+	Key    string `json:"key"`
+	// Matched is the number of spans, log records or data points with the key
+	Matched       int                   `json:"matched"`
+	TotalDistinct int                   `json:"total_distinct"`
+	Values        []AttributeValueFacet `json:"values"`
+	Truncated     bool                  `json:"truncated,omitempty"`
+}
+
+// RegisterGetAttributeValues registers the get_attribute_values tool
+func RegisterGetAttributeValues(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetAttributeValuesInput, GetAttributeValuesOutput](server, &mcp.Tool{
+		Name:        "get_attribute_values",
+		Description: "List the distinct values of one attribute key across buffered spans, log records or data points (or their resources) with the number of items carrying each value, most frequent first. total_distinct reports the full cardinality when the values are capped by limit. Use to discover values before filtering on an attribute.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetAttributeValuesInput) (*mcp.CallToolResult, GetAttributeValuesOutput, error) {
+		signal := strings.ToLower(strings.TrimSpace(input.Signal))
+		switch signal {
+		case "traces", "metrics", "logs":
+		default:
+			return nil, GetAttributeValuesOutput{}, fmt.Errorf("invalid signal: %s (must be traces, logs, or metrics)", input.Signal)
+		}
+		if input.Key == "" {
+			return nil, GetAttributeValuesOutput{}, errors.New("key is required")
+		}
+		if err := checkBufferEnabled(ext, signal); err != nil {
+			return nil, GetAttributeValuesOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, GetAttributeValuesOutput{}, err
+		}
+
+		output := GetAttributeValuesOutput{Signal: signal, Key: input.Key}
+		counts := make(map[string]int)
+		// record counts n items carrying attrs
+		record := func(attrs pcommon.Map, n int) {
+			if value, ok := attrs.Get(input.Key); ok && n > 0 {
+				counts[redactValue(input.Key, value.AsString(), ext.IsRedactedAttribute)] += n
+				output.Matched += n
+			}
+		}
+
+		switch signal {
+		case "traces":
+			for _, td := range ext.GetRecentTraces(10000, 0) {
+				if scanInterrupted(ctx) {
+					output.Truncated = true
+					break
+				}
+				for i := 0; i < td.ResourceSpans().Len(); i++ {
+					rs := td.ResourceSpans().At(i)
+					for j := 0; j < rs.ScopeSpans().Len(); j++ {
+						spans := rs.ScopeSpans().At(j).Spans()
+						if input.Resource {
+							record(rs.Resource().Attributes(), spans.Len())
+							continue
+						}
+						for k := 0; k < spans.Len(); k++ {
+							record(spans.At(k).Attributes(), 1)
+						}
+					}
+				}
+			}
+		case "logs":
+			for _, ld := range ext.GetRecentLogs(10000, 0) {
+				if scanInterrupted(ctx) {
+					output.Truncated = true
+					break
+				}
+				for i := 0; i < ld.ResourceLogs().Len(); i++ {
+					rl := ld.ResourceLogs().At(i)
+					for j := 0; j < rl.ScopeLogs().Len(); j++ {
+						records := rl.ScopeLogs().At(j).LogRecords()
+						if input.Resource {
+							record(rl.Resource().Attributes(), records.Len())
+							continue
+						}
+						for k := 0; k < records.Len(); k++ {
+							record(records.At(k).Attributes(), 1)
+						}
+					}
+				}
+			}
+		case "metrics":
+			for _, md := range ext.GetRecentMetrics(10000, 0) {
+				if scanInterrupted(ctx) {
+					output.Truncated = true
+					break
+				}
+				for i := 0; i < md.ResourceMetrics().Len(); i++ {
+					rm := md.ResourceMetrics().At(i)
+					for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+						metrics := rm.ScopeMetrics().At(j).Metrics()
+						for k := 0; k < metrics.Len(); k++ {
+							forEachDataPoint(metrics.At(k), func(attrs pcommon.Map, _ pcommon.Timestamp) bool {
+								if input.Resource {
+									attrs = rm.Resource().Attributes()
+								}
+								record(attrs, 1)
+								return true
+							})
+						}
+					}
+				}
+			}
+		}
+
+		values := make([]AttributeValueFacet, 0, len(counts))
+		for value, count := range counts {
+			values = append(values, AttributeValueFacet{Value: value, Count: count})
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Count != values[j].Count {
+				return values[i].Count > values[j].Count
+			}
+			return values[i].Value < values[j].Value
+		})
+
+		output.TotalDistinct = len(values)
+		if len(values) > limit {
+			values = values[:limit]
+		}
+		output.Values = values
+
+		return nil, output, nil
+	})
+}
+
+type DescribeResourcesInput struct {
+	Signal  string `json:"signal,omitempty" jsonschema:"Signal to scan (traces metrics logs). Omit for all signals"`
+	Samples int    `json:"samples,omitempty" jsonschema:"Maximum number of sample values per key (shared by the most resources first),5"`
+}
+
+// ResourceAttributeSummary describes one resource attribute key across the
+// distinct buffered resources
+type ResourceAttributeSummary struct {
+	Key string `json:"key"`
+	// Resources is the number of distinct resources carrying the key
+	Resources      int      `json:"resources"`
+	DistinctValues int      `json:"distinct_values"`
+	Samples        []string `json:"samples"`
+	Signals        []string `json:"signals"`
+}
+
+type DescribeResourcesOutput struct {
+	// ResourceCount is the number of distinct resources (attribute sets)
+	ResourceCount int                        `json:"resource_count"`
+	Attributes    []ResourceAttributeSummary `json:"attributes"`
+	Truncated     bool                       `json:"truncated,omitempty"`
+}
+
+// RegisterDescribeResources registers the describe_resources tool
+func RegisterDescribeResources(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[DescribeResourcesInput, DescribeResourcesOutput](server, &mcp.Tool{
+		Name:        "describe_resources",
+		Description: "Inventory the resource attributes of all buffered traces, metrics and logs: every key with the number of distinct resources carrying it, its number of distinct values, sample values and the signals it appears in. Resources repeated across batches are counted once. Use to see which services, environments and hosts the collector observes.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input DescribeResourcesInput) (*mcp.CallToolResult, DescribeResourcesOutput, error) {
+		signals := []string{"traces", "metrics", "logs"}
+		if input.Signal != "" {
+			signal := strings.ToLower(strings.TrimSpace(input.Signal))
+			switch signal {
+			case "traces", "metrics", "logs":
+			default:
+				return nil, DescribeResourcesOutput{}, fmt.Errorf("invalid signal: %s (must be traces, logs, or metrics)", input.Signal)
+			}
+			signals = []string{signal}
+		}
+		if input.Samples < 0 {
+			return nil, DescribeResourcesOutput{}, fmt.Errorf("samples must be non-negative, got %d", input.Samples)
+		}
+		samples := input.Samples
+		if samples == 0 {
+			samples = 5
+		}
+
+		type keyStats struct {
+			resources int
+			values    map[string]int
+			signals   map[string]bool
+		}
+		keys := make(map[string]*keyStats)
+		resources := make(map[string]bool)
+		seen := make(map[string]bool)
+		output := DescribeResourcesOutput{}
+
+		// visit records a resource the first time it is seen for a signal; its
+		// values are only counted the first time it is seen at all
+		visit := func(signal string, res pcommon.Resource) {
+			resKey := attributesKey(res.Attributes())
+			if seen[signal+"|"+resKey] {
+				return
+			}
+			seen[signal+"|"+resKey] = true
+			first := !resources[resKey]
+			resources[resKey] = true
+
+			res.Attributes().Range(func(k string, v pcommon.Value) bool {
+				stats, ok := keys[k]
+				if !ok {
+					stats = &keyStats{values: make(map[string]int), signals: make(map[string]bool)}
+					keys[k] = stats
+				}
+				stats.signals[signal] = true
+				if first {
+					stats.resources++
+					stats.values[redactValue(k, v.AsString(), ext.IsRedactedAttribute)]++
+				}
+				return true
+			})
+		}
+
+		for _, signal := range signals {
+			if output.Truncated {
+				break
+			}
+			switch signal {
+			case "traces":
+				for _, td := range ext.GetRecentTraces(10000, 0) {
+					if scanInterrupted(ctx) {
+						output.Truncated = true
+						break
+					}
+					for i := 0; i < td.ResourceSpans().Len(); i++ {
+						visit(signal, td.ResourceSpans().At(i).Resource())
+					}
+				}
+			case "metrics":
+				for _, md := range ext.GetRecentMetrics(10000, 0) {
+					if scanInterrupted(ctx) {
+						output.Truncated = true
+						break
+					}
+					for i := 0; i < md.ResourceMetrics().Len(); i++ {
+						visit(signal, md.ResourceMetrics().At(i).Resource())
+					}
+				}
+			case "logs":
+				for _, ld := range ext.GetRecentLogs(10000, 0) {
+					if scanInterrupted(ctx) {
+						output.Truncated = true
+						break
+					}
+					for i := 0; i < ld.ResourceLogs().Len(); i++ {
+						visit(signal, ld.ResourceLogs().At(i).Resource())
+					}
+				}
+			}
+		}
+
+		output.ResourceCount = len(resources)
+		output.Attributes = make([]ResourceAttributeSummary, 0, len(keys))
+		for key, stats := range keys {
+			values := make([]AttributeValueFacet, 0, len(stats.values))
+			for value, count := range stats.values {
+				values = append(values, AttributeValueFacet{Value: value, Count: count})
+			}
+			sort.Slice(values, func(i, j int) bool {
+				if values[i].Count != values[j].Count {
+					return values[i].Count > values[j].Count
+				}
+				return values[i].Value < values[j].Value
+			})
+
+			summary := ResourceAttributeSummary{
+				Key:            key,
+				Resources:      stats.resources,
+				DistinctValues: len(values),
+				Samples:        make([]string, 0, min(len(values), samples)),
+			}
+			for _, value := range values[:min(len(values), samples)] {
+				summary.Samples = append(summary.Samples, value.Value)
+			}
+			for _, signal := range []string{"traces", "metrics", "logs"} {
+				if stats.signals[signal] {
+					summary.Signals = append(summary.Signals, signal)
+				}
+			}
+			output.Attributes = append(output.Attributes, summary)
+		}
+		sort.Slice(output.Attributes, func(i, j int) bool {
+			return output.Attributes[i].Key < output.Attributes[j].Key
+		})
+
+		return nil, output, nil
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// defaultLatencyBucketsMs are the bucket boundaries used when none are given,
+// roughly exponential from 1ms to 30s
+var defaultLatencyBucketsMs = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 30000}
+
+// latencyBarWidth is the width in characters of the largest bucket's bar
+const latencyBarWidth = 40
+
+type LatencyHistogramInput struct {
+	ServiceName string    `json:"service_name" jsonschema:"Service whose spans are counted,required"`
+	SpanName    string    `json:"span_name,omitempty" jsonschema:"Only count spans with this name"`
+	BucketsMs   []float64 `json:"buckets_ms,omitempty" jsonschema:"Increasing bucket upper bounds in milliseconds. Omit for exponential buckets from 1ms to 30s"`
+}
+
+// LatencyBucket counts spans whose duration is in [LowerMs, UpperMs). The last
+// bucket has no upper bound.
+type LatencyBucket struct {
+	Label   string   `json:"label"`
+	LowerMs float64  `json:"lower_ms"`
+	UpperMs *float64 `json:"upper_ms,omitempty"`
+	Count   int      `json:"count"`
+}
+
+type LatencyHistogramOutput struct {
+	ServiceName string          `json:"service_name"`
+	SpanName    string          `json:"span_name,omitempty"`
+	SpanCount   int             `json:"span_count"`
+	Buckets     []LatencyBucket `json:"buckets"`
+	Markdown    string          `json:"markdown"`
+	Truncated   bool            `json:"truncated,omitempty"`
+}
+
+// RegisterLatencyHistogram registers the latency_histogram tool
+func RegisterLatencyHistogram(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[LatencyHistogramInput, LatencyHistogramOutput](server, &mcp.Tool{
+		Name:        "latency_histogram",
+		Description: "Count a service's buffered spans into latency buckets and render the distribution as a markdown bar chart with raw counts. Optionally restrict to one span name and pass custom bucket bounds in milliseconds.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input LatencyHistogramInput) (*mcp.CallToolResult, LatencyHistogramOutput, error) {
+		if input.ServiceName == "" {
+			return nil, LatencyHistogramOutput{}, errors.New("service_name is required")
+		}
+		bounds := input.BucketsMs
+		if len(bounds) == 0 {
+			bounds = defaultLatencyBucketsMs
+		}
+		for i, bound := range bounds {
+			if bound <= 0 || (i > 0 && bound <= bounds[i-1]) {
+				return nil, LatencyHistogramOutput{}, errors.New("buckets_ms must be positive and strictly increasing")
+			}
+		}
+
+		buckets := newLatencyBuckets(bounds)
+		spanCount := 0
+		truncated := forEachSpan(ctx, ext.GetRecentTraces(10000, 0), func(serviceName string, span ptrace.Span) {
+			if serviceName != input.ServiceName {
+				return
+			}
+			if input.SpanName != "" && span.Name() != input.SpanName {
+				return
+			}
+
+			buckets[latencyBucketIndex(bounds, span)].Count++
+			spanCount++
+		})
+
+		return nil, LatencyHistogramOutput{
+			ServiceName: input.ServiceName,
+			SpanName:    input.SpanName,
+			SpanCount:   spanCount,
+			Buckets:     buckets,
+			Markdown:    renderLatencyHistogram(input.ServiceName, input.SpanName, spanCount, buckets),
+			Truncated:   truncated,
+		}, nil
+	})
+}
+
+// newLatencyBuckets creates one bucket below each bound plus an unbounded last bucket
+func newLatencyBuckets(bounds []float64) []LatencyBucket {
+	buckets := make([]LatencyBucket, 0, len(bounds)+1)
+	lower := 0.0
+	for _, bound := range bounds {
+		upper := bound
+		buckets = append(buckets, LatencyBucket{
+			Label:   fmt.Sprintf("%s - %s", formatLatencyMs(lower), formatLatencyMs(upper)),
+			LowerMs: lower,
+			UpperMs: &upper,
+		})
+		lower = upper
+	}
+	return append(buckets, LatencyBucket{
+		Label:   ">= " + formatLatencyMs(lower),
+		LowerMs: lower,
+	})
+}
+
+// latencyBucketIndex returns the index of the bucket of newLatencyBuckets(bounds)
+// holding the span's duration
+func latencyBucketIndex(bounds []float64, span ptrace.Span) int {
+	durationMs := float64(span.EndTimestamp()-span.StartTimestamp()) / 1e6
+	i := 0
+	for i < len(bounds) && durationMs >= bounds[i] {
+		i++
+	}
+	return i
+}
+
+func formatLatencyMs(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).String()
+}
+
+// renderLatencyHistogram renders buckets as a markdown table with block character bars
+func renderLatencyHistogram(serviceName, spanName string, spanCount int, buckets []LatencyBucket) string {
+	var sb strings.Builder
+
+	title := serviceName
+	if spanName != "" {
+		title += " / " + spanName
+	}
+	fmt.Fprintf(&sb, "# Latency histogram for `%s`\n\n", title)
+	if spanCount == 0 {
+		sb.WriteString("No spans found\n")
+		return sb.String()
+	}
+
+	maxCount := 0
+	for _, bucket := range buckets {
+		maxCount = max(maxCount, bucket.Count)
+	}
+
+	sb.WriteString("| Latency | Count | Distribution |\n")
+	sb.WriteString("|---------|-------|--------------|\n")
+	for _, bucket := range buckets {
+		width := bucket.Count * latencyBarWidth / maxCount
+		if bucket.Count > 0 && width == 0 {
+			width = 1
+		}
+		fmt.Fprintf(&sb, "| %s | %d | %s |\n", bucket.Label, bucket.Count, strings.Repeat("█", width))
+	}
+	fmt.Fprintf(&sb, "\n**Total spans:** %d\n", spanCount)
+
+	return sb.String()
+}
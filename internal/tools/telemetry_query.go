@@ -5,25 +5,32 @@ package tools
 
 import (
 	"context"
-	"encoding/csv"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 type TracesInput struct {
 	Limit  int `json:"limit,omitempty" jsonschema:"Maximum number of trace batches to return,10"`
 	Offset int `json:"offset,omitempty" jsonschema:"Number of trace batches to skip,0"`
+
+	RootOnly bool `json:"root_only,omitempty" jsonschema:"Only return root spans (no parent in the buffered trace), one row per request entry point,false"`
+
+	CSVDelimiter string `json:"csv_delimiter,omitempty" jsonschema:"Single character separating CSV fields, e.g. a tab for TSV or ';'. Omit for a comma"`
+	CSVHeader    *bool  `json:"csv_header,omitempty" jsonschema:"Write the CSV header row. Disable to append the rows to earlier output,true"`
 }
 
 type TracesOutput struct {
-	Count  int      `json:"count"`
-	Traces []string `json:"traces"`
-	CSV    string   `json:"csv"`
+	Count     int      `json:"count"`
+	Traces    []string `json:"traces"`
+	CSV       string   `json:"csv"`
+	Truncated bool     `json:"truncated,omitempty"`
 }
 
 // RegisterGetRecentTraces registers the get_recent_traces tool
@@ -36,27 +43,50 @@ func RegisterGetRecentTraces(server *mcp.Server, ext ExtensionContext) {
 			IdempotentHint: true,
 			OpenWorldHint:  boolPtr(false),
 		},
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, input TracesInput) (*mcp.CallToolResult, TracesOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
-		limit := input.Limit
-		if limit == 0 {
-			limit = 10
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input TracesInput) (*mcp.CallToolResult, TracesOutput, error) {
+		if err := checkBufferEnabled(ext, "traces"); err != nil {
+			return nil, TracesOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultRecentLimit())
+		if err != nil {
+			return nil, TracesOutput{}, err
+		}
+		if err := validateOffset(input.Offset); err != nil {
+			return nil, TracesOutput{}, err
 		}
 
 		traces := ext.GetRecentTraces(limit, input.Offset)
 
+		// A parent may be buffered in a batch outside the requested page, so
+		// roots are found over the whole buffer
+		var rootSpans map[string]bool
+		truncated := false
+		if input.RootOnly {
+			_, rootSpans, truncated = findRootSpans(ctx, ext.GetRecentTraces(10000, 0), "")
+		}
+
 		// Build CSV output using encoding/csv
 		var buf strings.Builder
-		w := csv.NewWriter(&buf)
+		w, err := newCSVWriter(&buf, input.CSVDelimiter)
+		if err != nil {
+			return nil, TracesOutput{}, err
+		}
 
 		// Write header
-		if err := w.Write([]string{"trace_id", "span_id", "parent_span_id", "span_name", "service_name", "start_time", "end_time", "duration_ms", "status_code", "span_kind"}); err != nil {
-			return nil, TracesOutput{}, fmt.Errorf("failed to write CSV header: %w", err)
+		if input.CSVHeader == nil || *input.CSVHeader {
+			if err := w.Write([]string{"trace_id", "span_id", "parent_span_id", "span_name", "service_name", "start_time", "end_time", "duration_ms", "status_code", "span_kind"}); err != nil {
+				return nil, TracesOutput{}, fmt.Errorf("failed to write CSV header: %w", err)
+			}
 		}
 
 		summaries := []string{}
 		spanCount := 0
 
 		for _, td := range traces {
+			if truncated {
+				break
+			}
+
 			for i := 0; i < td.ResourceSpans().Len(); i++ {
 				rs := td.ResourceSpans().At(i)
 				serviceName := "unknown"
@@ -68,14 +98,17 @@ func RegisterGetRecentTraces(server *mcp.Server, ext ExtensionContext) {
 					ss := rs.ScopeSpans().At(j)
 					for k := 0; k < ss.Spans().Len(); k++ {
 						span := ss.Spans().At(k)
-						spanCount++
-
 						traceID := span.TraceID().String()
 						spanID := span.SpanID().String()
+						if input.RootOnly && !rootSpans[traceID+"/"+spanID] {
+							continue
+						}
+						spanCount++
+
 						parentSpanID := span.ParentSpanID().String()
 						spanName := span.Name()
-						startTime := time.Unix(0, int64(span.StartTimestamp())).Format(time.RFC3339)
-						endTime := time.Unix(0, int64(span.EndTimestamp())).Format(time.RFC3339)
+						startTime := formatTimestamp(span.StartTimestamp(), ext.GetLocation(), time.RFC3339)
+						endTime := formatTimestamp(span.EndTimestamp(), ext.GetLocation(), time.RFC3339)
 						durationMs := fmt.Sprintf("%.2f", float64(span.EndTimestamp()-span.StartTimestamp())/1e6)
 						statusCode := span.Status().Code().String()
 						spanKind := span.Kind().String()
@@ -94,13 +127,18 @@ func RegisterGetRecentTraces(server *mcp.Server, ext ExtensionContext) {
 		}
 
 		if spanCount > 0 {
-			summaries = append(summaries, fmt.Sprintf("Total spans: %d across %d batches", spanCount, len(traces)))
+			label := "spans"
+			if input.RootOnly {
+				label = "root spans"
+			}
+			summaries = append(summaries, fmt.Sprintf("Total %s: %d across %d batches", label, spanCount, len(traces)))
 		}
 
 		return nil, TracesOutput{
-			Count:  len(traces),
-			Traces: summaries,
-			CSV:    buf.String(),
+			Count:     len(traces),
+			Traces:    summaries,
+			CSV:       buf.String(),
+			Truncated: truncated,
 		}, nil
 	})
 }
@@ -111,10 +149,12 @@ type MetricsInput struct {
 }
 
 type MetricSummary struct {
-	Name  string `json:"name"`
-	Type  string `json:"type"`
-	Unit  string `json:"unit"`
-	Count int    `json:"count"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Temporality string `json:"temporality,omitempty"`
+	IsMonotonic bool   `json:"is_monotonic,omitempty"`
+	Unit        string `json:"unit"`
+	Count       int    `json:"count"`
 }
 
 type MetricDataPoint struct {
@@ -146,9 +186,12 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input MetricsInput) (*mcp.CallToolResult, MetricsOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
-		limit := input.Limit
-		if limit == 0 {
-			limit = 10
+		if err := checkBufferEnabled(ext, "metrics"); err != nil {
+			return nil, MetricsOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultRecentLimit())
+		if err != nil {
+			return nil, MetricsOutput{}, err
 		}
 
 		metrics := ext.GetRecentMetrics(limit, 0)
@@ -169,11 +212,14 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 							if summary, exists := metricMap[name]; exists {
 								summary.Count++
 							} else {
+								temporality, monotonic := metricTemporality(metric)
 								metricMap[name] = &MetricSummary{
-									Name:  name,
-									Type:  metric.Type().String(),
-									Unit:  metric.Unit(),
-									Count: 1,
+									Name:        name,
+									Type:        metric.Type().String(),
+									Temporality: temporality,
+									IsMonotonic: monotonic,
+									Unit:        metric.Unit(),
+									Count:       1,
 								}
 							}
 						}
@@ -204,7 +250,7 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 				if resourceAttrs == nil {
 					resourceAttrs = make(map[string]string)
 					rm.Resource().Attributes().Range(func(k string, v pcommon.Value) bool {
-						resourceAttrs[k] = v.AsString()
+						resourceAttrs[k] = redactValue(k, v.AsString(), ext.IsRedactedAttribute)
 						return true
 					})
 				}
@@ -228,12 +274,12 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 								dp := dps.At(l)
 								attrs := make(map[string]string)
 								dp.Attributes().Range(func(k string, v pcommon.Value) bool {
-									attrs[k] = v.AsString()
+									attrs[k] = redactValue(k, v.AsString(), ext.IsRedactedAttribute)
 									return true
 								})
 								dataPoints = append(dataPoints, MetricDataPoint{
 									Value:      formatNumberDataPoint(dp),
-									Timestamp:  time.Unix(0, int64(dp.Timestamp())).Format(time.RFC3339),
+									Timestamp:  formatTimestamp(dp.Timestamp(), ext.GetLocation(), time.RFC3339),
 									Attributes: attrs,
 								})
 							}
@@ -243,12 +289,12 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 								dp := dps.At(l)
 								attrs := make(map[string]string)
 								dp.Attributes().Range(func(k string, v pcommon.Value) bool {
-									attrs[k] = v.AsString()
+									attrs[k] = redactValue(k, v.AsString(), ext.IsRedactedAttribute)
 									return true
 								})
 								dataPoints = append(dataPoints, MetricDataPoint{
 									Value:      formatNumberDataPoint(dp),
-									Timestamp:  time.Unix(0, int64(dp.Timestamp())).Format(time.RFC3339),
+									Timestamp:  formatTimestamp(dp.Timestamp(), ext.GetLocation(), time.RFC3339),
 									Attributes: attrs,
 								})
 							}
@@ -258,12 +304,27 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 								dp := dps.At(l)
 								attrs := make(map[string]string)
 								dp.Attributes().Range(func(k string, v pcommon.Value) bool {
-									attrs[k] = v.AsString()
+									attrs[k] = redactValue(k, v.AsString(), ext.IsRedactedAttribute)
 									return true
 								})
 								dataPoints = append(dataPoints, MetricDataPoint{
 									Value:      fmt.Sprintf("count=%d,sum=%.2f", dp.Count(), dp.Sum()),
-									Timestamp:  time.Unix(0, int64(dp.Timestamp())).Format(time.RFC3339),
+									Timestamp:  formatTimestamp(dp.Timestamp(), ext.GetLocation(), time.RFC3339),
+									Attributes: attrs,
+								})
+							}
+						case pmetric.MetricTypeExponentialHistogram:
+							dps := metric.ExponentialHistogram().DataPoints()
+							for l := 0; l < dps.Len(); l++ {
+								dp := dps.At(l)
+								attrs := make(map[string]string)
+								dp.Attributes().Range(func(k string, v pcommon.Value) bool {
+									attrs[k] = redactValue(k, v.AsString(), ext.IsRedactedAttribute)
+									return true
+								})
+								dataPoints = append(dataPoints, MetricDataPoint{
+									Value:      fmt.Sprintf("count=%d,sum=%.2f", dp.Count(), dp.Sum()),
+									Timestamp:  formatTimestamp(dp.Timestamp(), ext.GetLocation(), time.RFC3339),
 									Attributes: attrs,
 								})
 							}
@@ -273,12 +334,12 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 								dp := dps.At(l)
 								attrs := make(map[string]string)
 								dp.Attributes().Range(func(k string, v pcommon.Value) bool {
-									attrs[k] = v.AsString()
+									attrs[k] = redactValue(k, v.AsString(), ext.IsRedactedAttribute)
 									return true
 								})
 								dataPoints = append(dataPoints, MetricDataPoint{
 									Value:      fmt.Sprintf("count=%d,sum=%.2f", dp.Count(), dp.Sum()),
-									Timestamp:  time.Unix(0, int64(dp.Timestamp())).Format(time.RFC3339),
+									Timestamp:  formatTimestamp(dp.Timestamp(), ext.GetLocation(), time.RFC3339),
 									Attributes: attrs,
 								})
 							}
@@ -306,6 +367,9 @@ func RegisterGetRecentMetrics(server *mcp.Server, ext ExtensionContext) {
 type LogsInput struct {
 	Limit  int `json:"limit,omitempty" jsonschema:"Maximum number of log batches to return,10"`
 	Offset int `json:"offset,omitempty" jsonschema:"Number of log batches to skip,0"`
+
+	CSVDelimiter string `json:"csv_delimiter,omitempty" jsonschema:"Single character separating CSV fields, e.g. a tab for TSV or ';'. Omit for a comma"`
+	CSVHeader    *bool  `json:"csv_header,omitempty" jsonschema:"Write the CSV header row. Disable to append the rows to earlier output,true"`
 }
 
 type LogsOutput struct {
@@ -325,20 +389,31 @@ func RegisterGetRecentLogs(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input LogsInput) (*mcp.CallToolResult, LogsOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
-		limit := input.Limit
-		if limit == 0 {
-			limit = 10
+		if err := checkBufferEnabled(ext, "logs"); err != nil {
+			return nil, LogsOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultRecentLimit())
+		if err != nil {
+			return nil, LogsOutput{}, err
+		}
+		if err := validateOffset(input.Offset); err != nil {
+			return nil, LogsOutput{}, err
 		}
 
 		logs := ext.GetRecentLogs(limit, input.Offset)
 
 		// Build CSV output using encoding/csv
 		var buf strings.Builder
-		w := csv.NewWriter(&buf)
+		w, err := newCSVWriter(&buf, input.CSVDelimiter)
+		if err != nil {
+			return nil, LogsOutput{}, err
+		}
 
 		// Write header
-		if err := w.Write([]string{"timestamp", "severity", "body", "resource_attrs", "log_attrs"}); err != nil {
-			return nil, LogsOutput{}, fmt.Errorf("failed to write CSV header: %w", err)
+		if input.CSVHeader == nil || *input.CSVHeader {
+			if err := w.Write([]string{"timestamp", "severity", "body", "resource_attrs", "log_attrs"}); err != nil {
+				return nil, LogsOutput{}, fmt.Errorf("failed to write CSV header: %w", err)
+			}
 		}
 
 		summaries := []string{}
@@ -347,7 +422,7 @@ func RegisterGetRecentLogs(server *mcp.Server, ext ExtensionContext) {
 		for _, ld := range logs {
 			for i := 0; i < ld.ResourceLogs().Len(); i++ {
 				rl := ld.ResourceLogs().At(i)
-				resourceAttrs := formatAttributes(rl.Resource().Attributes())
+				resourceAttrs := formatAttributes(rl.Resource().Attributes(), nil, ext.IsRedactedAttribute)
 
 				for j := 0; j < rl.ScopeLogs().Len(); j++ {
 					sl := rl.ScopeLogs().At(j)
@@ -355,10 +430,10 @@ func RegisterGetRecentLogs(server *mcp.Server, ext ExtensionContext) {
 						logRecord := sl.LogRecords().At(k)
 						logCount++
 
-						timestamp := time.Unix(0, int64(logRecord.Timestamp())).Format(time.RFC3339)
+						timestamp := formatTimestamp(logRecord.Timestamp(), ext.GetLocation(), time.RFC3339)
 						severity := logRecord.SeverityText()
 						body := logRecord.Body().AsString()
-						logAttrs := formatAttributes(logRecord.Attributes())
+						logAttrs := formatAttributes(logRecord.Attributes(), nil, ext.IsRedactedAttribute)
 
 						// encoding/csv handles escaping automatically
 						if err := w.Write([]string{timestamp, severity, body, resourceAttrs, logAttrs}); err != nil {
@@ -386,56 +461,218 @@ func RegisterGetRecentLogs(server *mcp.Server, ext ExtensionContext) {
 	})
 }
 
+type TelemetrySummaryInput struct {
+	IncludeDistributions bool `json:"include_distributions,omitempty" jsonschema:"Scan the buffer to break down logs by severity and spans by status,false"`
+	IncludeSpanLatency   bool `json:"include_span_latency,omitempty" jsonschema:"Scan the buffered spans to count them into coarse latency buckets (<10ms <100ms <1s <10s >=10s),false"`
+	IncludeBytes         bool `json:"include_bytes,omitempty" jsonschema:"Size every buffered batch as OTLP protobuf to report bytes_used per signal,false"`
+}
+
+// summaryLatencyBucketsMs are the bucket bounds of the summary span latency
+// histogram
+var summaryLatencyBucketsMs = []float64{10, 100, 1000, 10000}
+
 type TelemetrySummaryOutput struct {
+	// "cold" until the first telemetry of any signal has been buffered, then "warm"
+	BufferStatus   string `json:"buffer_status"`
+	ConfigReceived bool   `json:"config_received"`
+	FirstConfigAt  string `json:"first_config_at,omitempty"`
+
 	Traces  BufferInfo `json:"traces"`
 	Metrics BufferInfo `json:"metrics"`
 	Logs    BufferInfo `json:"logs"`
+
+	// Only populated when include_distributions is set
+	LogsBySeverity map[string]int `json:"logs_by_severity,omitempty"`
+	SpansByStatus  map[string]int `json:"spans_by_status,omitempty"`
+	// Only populated when include_span_latency is set
+	SpanLatency []LatencyBucket `json:"span_latency,omitempty"`
+	// Set when the distributions only cover part of the buffer because the
+	// request timed out
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 type BufferInfo struct {
-	Count    int `json:"count"`
-	Capacity int `json:"capacity"`
+	Count           int    `json:"count"`
+	Capacity        int    `json:"capacity"`
+	Disabled        bool   `json:"disabled,omitempty"`
+	FirstReceivedAt string `json:"first_received_at,omitempty"`
+	// BytesUsed estimates the memory the buffer holds as the OTLP protobuf
+	// size of its batches; the in-memory pdata representation is larger.
+	// Only populated when include_bytes is set
+	BytesUsed int `json:"bytes_used,omitempty"`
 }
 
 // RegisterGetTelemetrySummary registers the get_telemetry_summary tool
 func RegisterGetTelemetrySummary(server *mcp.Server, ext ExtensionContext) {
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_telemetry_summary",
-		Description: "Get statistics about buffered telemetry",
+		Description: "Get statistics about buffered telemetry. Set include_bytes to also report the estimated bytes each signal's buffer holds (serialized OTLP protobuf size, computed by sizing every buffered batch), include_distributions to scan the buffer for log severity and span status breakdowns, and include_span_latency for a coarse latency histogram of all buffered spans (<10ms, <100ms, <1s, <10s, >=10s).",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
 			OpenWorldHint:  boolPtr(false),
 		},
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, input any) (*mcp.CallToolResult, TelemetrySummaryOutput, error) { //nolint:revive // ctx unused but kept for interface compatibility
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input TelemetrySummaryInput) (*mcp.CallToolResult, TelemetrySummaryOutput, error) {
 		stats := ext.GetBufferStats()
+		readiness := ext.GetReadiness()
 
-		return nil, TelemetrySummaryOutput{
+		output := TelemetrySummaryOutput{
+			BufferStatus:   "cold",
+			ConfigReceived: !readiness.FirstConfigAt.IsZero(),
+			FirstConfigAt:  formatReadinessTime(readiness.FirstConfigAt),
 			Traces: BufferInfo{
-				Count:    stats.TracesCount,
-				Capacity: stats.TracesCapacity,
+				Count:           stats.TracesCount,
+				Capacity:        stats.TracesCapacity,
+				Disabled:        stats.TracesCapacity == 0,
+				FirstReceivedAt: formatReadinessTime(readiness.FirstTracesAt),
 			},
 			Metrics: BufferInfo{
-				Count:    stats.MetricsCount,
-				Capacity: stats.MetricsCapacity,
+				Count:           stats.MetricsCount,
+				Capacity:        stats.MetricsCapacity,
+				Disabled:        stats.MetricsCapacity == 0,
+				FirstReceivedAt: formatReadinessTime(readiness.FirstMetricsAt),
 			},
 			Logs: BufferInfo{
-				Count:    stats.LogsCount,
-				Capacity: stats.LogsCapacity,
+				Count:           stats.LogsCount,
+				Capacity:        stats.LogsCapacity,
+				Disabled:        stats.LogsCapacity == 0,
+				FirstReceivedAt: formatReadinessTime(readiness.FirstLogsAt),
 			},
-		}, nil
+		}
+		if !readiness.FirstTracesAt.IsZero() || !readiness.FirstMetricsAt.IsZero() || !readiness.FirstLogsAt.IsZero() {
+			output.BufferStatus = "warm"
+		}
+		if input.IncludeBytes {
+			output.Traces.BytesUsed = bufferBytes(ext, "traces", stats.TracesCapacity)
+			output.Metrics.BytesUsed = bufferBytes(ext, "metrics", stats.MetricsCapacity)
+			output.Logs.BytesUsed = bufferBytes(ext, "logs", stats.LogsCapacity)
+		}
+
+		if !input.IncludeDistributions && !input.IncludeSpanLatency {
+			return nil, output, nil
+		}
+
+		// Both span breakdowns are computed in a single pass over the spans
+		if input.IncludeDistributions {
+			output.SpansByStatus = map[string]int{
+				ptrace.StatusCodeOk.String():    0,
+				ptrace.StatusCodeError.String(): 0,
+				ptrace.StatusCodeUnset.String(): 0,
+			}
+		}
+		if input.IncludeSpanLatency {
+			output.SpanLatency = newLatencyBuckets(summaryLatencyBucketsMs)
+		}
+		output.Truncated = forEachSpan(ctx, ext.GetRecentTraces(10000, 0), func(_ string, span ptrace.Span) {
+			if output.SpansByStatus != nil {
+				output.SpansByStatus[span.Status().Code().String()]++
+			}
+			if output.SpanLatency != nil {
+				output.SpanLatency[latencyBucketIndex(summaryLatencyBucketsMs, span)].Count++
+			}
+		})
+
+		if !input.IncludeDistributions {
+			return nil, output, nil
+		}
+
+		output.LogsBySeverity = make(map[string]int)
+		for _, ld := range ext.GetRecentLogs(10000, 0) {
+			if scanInterrupted(ctx) {
+				output.Truncated = true
+				break
+			}
+
+			for i := 0; i < ld.ResourceLogs().Len(); i++ {
+				rl := ld.ResourceLogs().At(i)
+				for j := 0; j < rl.ScopeLogs().Len(); j++ {
+					sl := rl.ScopeLogs().At(j)
+					for k := 0; k < sl.LogRecords().Len(); k++ {
+						output.LogsBySeverity[severityBucket(sl.LogRecords().At(k))]++
+					}
+				}
+			}
+		}
+
+		return nil, output, nil
 	})
 }
 
+// bufferBytes sums the serialized size of the buffered batches of a signal
+func bufferBytes(ext ExtensionContext, signal string, capacity int) int {
+	if capacity == 0 {
+		return 0
+	}
+	total := 0
+	for _, info := range ext.GetBatchInfos(signal, capacity, 0) {
+		total += info.SizeBytes
+	}
+	return total
+}
+
+// formatReadinessTime formats t as RFC3339, or returns "" for the zero time
+func formatReadinessTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// severityTextBuckets maps the upper-cased severity texts severityBucket
+// recognizes to their bucket
+var severityTextBuckets = map[string]string{
+	"TRACE":       "TRACE",
+	"DEBUG":       "DEBUG",
+	"INFO":        "INFO",
+	"INFORMATION": "INFO",
+	"WARN":        "WARN",
+	"WARNING":     "WARN",
+	"ERROR":       "ERROR",
+	"ERR":         "ERROR",
+	"FATAL":       "FATAL",
+	"CRITICAL":    "FATAL",
+}
+
+// severityBucket maps a log record to one of TRACE/DEBUG/INFO/WARN/ERROR/FATAL
+// using the severity number, falling back to the severity text when unset.
+// Records whose text is not a known level are bucketed as UNSPECIFIED
+func severityBucket(lr plog.LogRecord) string {
+	switch num := lr.SeverityNumber(); {
+	case num >= plog.SeverityNumberFatal:
+		return "FATAL"
+	case num >= plog.SeverityNumberError:
+		return "ERROR"
+	case num >= plog.SeverityNumberWarn:
+		return "WARN"
+	case num >= plog.SeverityNumberInfo:
+		return "INFO"
+	case num >= plog.SeverityNumberDebug:
+		return "DEBUG"
+	case num >= plog.SeverityNumberTrace:
+		return "TRACE"
+	}
+
+	if bucket, ok := severityTextBuckets[strings.ToUpper(lr.SeverityText())]; ok {
+		return bucket
+	}
+	return "UNSPECIFIED"
+}
+
 // Helper functions
-func formatAttributes(attrs pcommon.Map) string {
+
+// formatAttributes formats attrs as key=value pairs joined by ";", leaving out
+// the keys rejected by keep and redacting the values of the keys matched by
+// redact
+func formatAttributes(attrs pcommon.Map, keep, redact func(key string) bool) string {
 	if attrs.Len() == 0 {
 		return ""
 	}
 
 	var parts []string
 	attrs.Range(func(k string, v pcommon.Value) bool {
-		parts = append(parts, fmt.Sprintf("%s=%s", k, v.AsString()))
+		if keep == nil || keep(k) {
+			parts = append(parts, fmt.Sprintf("%s=%s", k, redactValue(k, v.AsString(), redact)))
+		}
 		return true
 	})
 	return strings.Join(parts, ";")
@@ -451,3 +688,12 @@ func formatNumberDataPoint(dp pmetric.NumberDataPoint) string {
 		return "0"
 	}
 }
+
+// formatCountSum formats the count and sum of a histogram or summary data point
+// along with their average. The average is omitted when count is zero.
+func formatCountSum(count uint64, sum float64) string {
+	if count == 0 {
+		return fmt.Sprintf("count=0 sum=%.2f", sum)
+	}
+	return fmt.Sprintf("count=%d sum=%.2f avg=%.2f", count, sum, sum/float64(count))
+}
@@ -6,28 +6,78 @@ package tools
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 // QueryTracesInput provides flexible filtering for trace queries
 type QueryTracesInput struct {
+	Query string `json:"query,omitempty" jsonschema:"Filters as one string, e.g. 'service:checkout status:error duration>=500ms attr.http.route:/cart'. Keys (case-insensitive): service span trace root connector status duration (with >= or <=, both inclusive) attr.<key>. Quote values with spaces (span:\"GET /cart\"). Bare words match the span name. Combined with the other fields, which must not conflict"`
+
 	ServiceName string `json:"service_name,omitempty" jsonschema:"Filter by service name"`
+	ConnectorID string `json:"connector_id,omitempty" jsonschema:"Filter by the ID of the MCP connector that buffered the telemetry (e.g. 'mcp' 'mcp/prod')"`
+	ScopeName   string `json:"scope_name,omitempty" jsonschema:"Only return spans of instrumentation scopes whose name contains this text (case-insensitive), e.g. 'net/http'"`
 	SpanName    string `json:"span_name,omitempty" jsonschema:"Filter by span name (partial match)"`
 	TraceID     string `json:"trace_id,omitempty" jsonschema:"Filter by trace ID (partial match)"`
+
+	RootSpanName string `json:"root_span_name,omitempty" jsonschema:"Only return spans of traces whose root span (no parent in the buffered trace) matches this name (partial match), e.g. the entry point 'GET /checkout'"`
+	RootOnly     bool   `json:"root_only,omitempty" jsonschema:"Only return root spans (no parent in the buffered trace), one row per request entry point. With root_span_name, returns only the matching root spans instead of whole traces,false"`
+	MinSpans     int    `json:"min_spans,omitempty" jsonschema:"Only return spans of traces with at least this many buffered spans, e.g. 2 to skip single-span traces,0"`
+
+	// RootSpansOnly is a deprecated alias of RootOnly
+	RootSpansOnly bool `json:"root_spans_only,omitempty" jsonschema:"Deprecated alias of root_only,false"`
+
+	Attributes map[string]string `json:"attributes,omitempty" jsonschema:"Only return spans with these attribute values (exact match on the string form), looked up on the span and then its resource, e.g. {'http.route': '/cart'}"`
+
+	SlowerThanPercentile float64 `json:"slower_than_percentile,omitempty" jsonschema:"Only return spans slower than this percentile (e.g. 95) of the durations of all buffered spans with the same service and name, to find outliers relative to each operation's baseline. 0 disables,0"`
+
+	StatusMessage string `json:"status_message,omitempty" jsonschema:"Only return spans whose status message (the error detail) contains this text (case-insensitive), e.g. 'connection refused'"`
+
 	Status      string `json:"status,omitempty" jsonschema:"Filter by status (Ok, Error, Unset)"`
 	MinDuration string `json:"min_duration,omitempty" jsonschema:"Minimum span duration (e.g. '100ms', '1s')"`
 	MaxDuration string `json:"max_duration,omitempty" jsonschema:"Maximum span duration (e.g. '5s', '1m')"`
 	Detailed    bool   `json:"detailed,omitempty" jsonschema:"Return detailed information for each span,false"`
 	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of spans to return,100"`
 	Offset      int    `json:"offset,omitempty" jsonschema:"Number of spans to skip,0"`
+
+	MaxAttrLength *int     `json:"max_attr_length,omitempty" jsonschema:"Truncate rendered attributes to this many characters (0 disables truncation),40"`
+	AttributeKeys []string `json:"attribute_keys,omitempty" jsonschema:"Only show these span attribute keys in the given order (e.g. ['http.method' 'http.status_code']). Omit to show the first 5 attributes. attribute_include_prefixes and attribute_exclude_prefixes still apply"`
+
+	ResourceAttrKeys []string `json:"resource_attr_keys,omitempty" jsonschema:"Add a table column per resource attribute key (e.g. ['k8s.pod.name' 'k8s.namespace.name' 'host.name']), '-' when absent. Ignored when detailed is set"`
+
+	IncludeEvents bool `json:"include_events,omitempty" jsonschema:"Add a row per span event (e.g. exception or retry) below each span row with its name, time since the span start and attributes. Ignored when detailed is set, which always shows events,false"`
+
+	Timezone string `json:"timezone,omitempty" jsonschema:"IANA time zone to render timestamps in (e.g. 'Europe/Berlin' 'UTC'). Omit for the configured timezone"`
+
+	AttributeIncludePrefixes []string `json:"attribute_include_prefixes,omitempty" jsonschema:"Only render attributes whose key starts with one of these prefixes (e.g. ['http.' 'db.']). Does not change which records match"`
+	AttributeExcludePrefixes []string `json:"attribute_exclude_prefixes,omitempty" jsonschema:"Hide attributes whose key starts with one of these prefixes (e.g. ['telemetry.sdk.' 'process.']), even when an include prefix matches. Does not change which records match"`
+
+	MaxResponseBytes int `json:"max_response_bytes,omitempty" jsonschema:"Cut the rendered output at this many bytes, ending it with '[output truncated, refine your query]'. Omit for the configured limit, which larger values cannot exceed. With a limit the output is only returned as the text content block"`
 }
 
 type QueryTracesOutput struct {
-	SpanCount int    `json:"span_count"`
-	Markdown  string `json:"markdown"`
+	SpanCount int `json:"span_count"`
+	// TraceCount is the number of distinct traces of the returned spans
+	TraceCount int `json:"trace_count"`
+	// Markdown is omitted when max_response_bytes applies, leaving the text
+	// content block as the only copy of the output
+	Markdown  string `json:"markdown,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	// Earliest and Latest are the first and last start times of the returned
+	// spans, in UTC
+	Earliest string `json:"earliest,omitempty"`
+	Latest   string `json:"latest,omitempty"`
+	// OutputTruncated reports that the markdown was cut at max_response_bytes
+	OutputTruncated bool `json:"output_truncated,omitempty"`
 }
 
 // RegisterQueryTraces registers the query_traces tool
@@ -41,13 +91,39 @@ func RegisterQueryTraces(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input QueryTracesInput) (*mcp.CallToolResult, QueryTracesOutput, error) {
-		limit := input.Limit
-		if limit == 0 {
-			limit = 100
+		if err := checkBufferEnabled(ext, "traces"); err != nil {
+			return nil, QueryTracesOutput{}, err
+		}
+		if input.Query != "" {
+			if err := applyTraceQuery(&input, input.Query); err != nil {
+				return nil, QueryTracesOutput{}, err
+			}
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, QueryTracesOutput{}, err
+		}
+		if err := validateOffset(input.Offset); err != nil {
+			return nil, QueryTracesOutput{}, err
+		}
+		if input.MinSpans < 0 {
+			return nil, QueryTracesOutput{}, fmt.Errorf("min_spans must be non-negative, got %d", input.MinSpans)
+		}
+		if input.SlowerThanPercentile < 0 || input.SlowerThanPercentile >= 100 {
+			return nil, QueryTracesOutput{}, fmt.Errorf("slower_than_percentile must be between 0 and 100, got %g", input.SlowerThanPercentile)
+		}
+		maxAttrLen := attrLengthLimit(input.MaxAttrLength, 40)
+		loc, err := resolveLocation(ext, input.Timezone)
+		if err != nil {
+			return nil, QueryTracesOutput{}, err
+		}
+		keepAttribute := attributePrefixFilter(input.AttributeIncludePrefixes, input.AttributeExcludePrefixes)
+		maxResponseBytes, err := resolveMaxResponseBytes(ext, input.MaxResponseBytes)
+		if err != nil {
+			return nil, QueryTracesOutput{}, err
 		}
 
 		var minDuration, maxDuration time.Duration
-		var err error
 		if input.MinDuration != "" {
 			if minDuration, err = time.ParseDuration(input.MinDuration); err != nil {
 				minDuration = 0
@@ -59,15 +135,37 @@ func RegisterQueryTraces(server *mcp.Server, ext ExtensionContext) {
 			}
 		}
 
+		rootOnly := input.RootOnly || input.RootSpansOnly
+
 		traces := ext.GetRecentTraces(10000, 0)
+
+		// Root span criteria need the whole trace, so find the matching traces first
+		var rootTraces, rootSpans map[string]bool
+		truncated := false
+		if input.RootSpanName != "" || rootOnly {
+			rootTraces, rootSpans, truncated = findRootSpans(ctx, traces, input.RootSpanName)
+		}
+		// Likewise the span count of a trace is only known after a full pass
+		var traceSpanCounts map[string]int
+		if input.MinSpans > 1 && !truncated {
+			traceSpanCounts, truncated = countTraceSpans(ctx, traces)
+		}
+		// Percentile thresholds need the durations of every span of an operation
+		var nameThresholds map[spanOperation]time.Duration
+		if input.SlowerThanPercentile > 0 && !truncated {
+			nameThresholds, truncated = spanNamePercentiles(ctx, traces, input.SlowerThanPercentile)
+		}
+
 		var sb strings.Builder
-		writer := &TraceWriter{}
+		writer := &TraceWriter{Location: loc, KeepAttribute: keepAttribute, RedactAttribute: ext.IsRedactedAttribute}
 		spanCount := 0
+		traceIDs := make(map[pcommon.TraceID]bool)
+		var bounds timeBounds
 		skipped := 0
 
 		if !input.Detailed {
-			sb.WriteString("| Span | ID | Duration | Service | Status | Attributes |\n")
-			sb.WriteString("|------|-----|----------|---------|--------|------------|\n")
+			sb.WriteString("| Span | ID | Duration | Service | Status | Attributes |" + resourceColumnHeader(input.ResourceAttrKeys) + "\n")
+			sb.WriteString("|------|-----|----------|---------|--------|------------|" + resourceColumnSeparator(input.ResourceAttrKeys) + "\n")
 		}
 
 		for _, td := range traces {
@@ -75,8 +173,9 @@ func RegisterQueryTraces(server *mcp.Server, ext ExtensionContext) {
 				break
 			}
 
-			if ctx.Err() != nil {
-				return nil, QueryTracesOutput{}, ctx.Err()
+			if truncated || scanInterrupted(ctx) {
+				truncated = true
+				break
 			}
 
 			for i := 0; i < td.ResourceSpans().Len(); i++ {
@@ -94,12 +193,19 @@ func RegisterQueryTraces(server *mcp.Server, ext ExtensionContext) {
 					continue
 				}
 
+				if input.ConnectorID != "" && !resourceHasConnectorID(rs.Resource(), input.ConnectorID) {
+					continue
+				}
+
 				for j := 0; j < rs.ScopeSpans().Len(); j++ {
 					if spanCount >= limit {
 						break
 					}
 
 					ss := rs.ScopeSpans().At(j)
+					if input.ScopeName != "" && !scopeNameMatches(ss.Scope(), input.ScopeName) {
+						continue
+					}
 					for k := 0; k < ss.Spans().Len(); k++ {
 						if spanCount >= limit {
 							break
@@ -117,10 +223,31 @@ func RegisterQueryTraces(server *mcp.Server, ext ExtensionContext) {
 							continue
 						}
 
+						if rootTraces != nil {
+							if !rootTraces[traceID] {
+								continue
+							}
+							if rootOnly && !rootSpans[traceID+"/"+span.SpanID().String()] {
+								continue
+							}
+						}
+
+						if traceSpanCounts != nil && traceSpanCounts[traceID] < input.MinSpans {
+							continue
+						}
+
 						if input.Status != "" && span.Status().Code().String() != input.Status {
 							continue
 						}
 
+						if input.StatusMessage != "" && !strings.Contains(strings.ToLower(span.Status().Message()), strings.ToLower(input.StatusMessage)) {
+							continue
+						}
+
+						if len(input.Attributes) > 0 && !spanHasAttributes(span, rs.Resource(), input.Attributes) {
+							continue
+						}
+
 						startTime := time.Unix(0, int64(span.StartTimestamp()))
 						endTime := time.Unix(0, int64(span.EndTimestamp()))
 						duration := endTime.Sub(startTime)
@@ -133,26 +260,41 @@ func RegisterQueryTraces(server *mcp.Server, ext ExtensionContext) {
 							continue
 						}
 
+						if nameThresholds != nil && duration <= nameThresholds[spanOperation{service: serviceName, name: spanName}] {
+							continue
+						}
+
 						if skipped < input.Offset {
 							skipped++
 							continue
 						}
 
 						spanCount++
+						traceIDs[span.TraceID()] = true
+						bounds.add(span.StartTimestamp())
 
 						if input.Detailed {
-							writer.WriteSpanDetailed(&sb, span, serviceName, rs.Resource().Attributes())
+							writer.WriteSpanDetailed(&sb, span, serviceName, rs.Resource().Attributes(), input.AttributeKeys)
 						} else {
-							info := extractSpanInfo(span)
+							info := extractSpanInfo(span, writer.KeepAttribute, writer.RedactAttribute)
 							spanIDShort := info.spanID
 							if len(spanIDShort) > 8 {
 								spanIDShort = spanIDShort[:8]
 							}
 							durationStr := formatDuration(duration)
-							attrs := formatAttributesMap(info.attributes, 40)
+							var attrs string
+							if len(input.AttributeKeys) > 0 {
+								attrs = formatProjectedAttributes(span.Attributes(), input.AttributeKeys, maxAttrLen, writer.KeepAttribute, writer.RedactAttribute)
+							} else {
+								attrs = formatAttributesMap(info.attributes, maxAttrLen, nil, writer.RedactAttribute)
+							}
 
-							sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
-								spanName, spanIDShort, durationStr, serviceName, info.status, attrs))
+							sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |%s\n",
+								spanName, spanIDShort, durationStr, serviceName, info.statusLabel(), attrs,
+								resourceColumnCells(rs.Resource().Attributes(), input.ResourceAttrKeys, writer.RedactAttribute)))
+							if input.IncludeEvents {
+								writer.WriteSpanEventRows(&sb, span, maxAttrLen, len(input.ResourceAttrKeys))
+							}
 						}
 					}
 				}
@@ -163,12 +305,102 @@ func RegisterQueryTraces(server *mcp.Server, ext ExtensionContext) {
 		if spanCount == 0 {
 			markdown = "No spans found matching the criteria"
 		}
+		markdown, outputTruncated := truncateOutput(markdown, maxResponseBytes, responseTruncatedMarker)
+		earliest, latest := bounds.format()
+
+		output := QueryTracesOutput{
+			SpanCount:       spanCount,
+			TraceCount:      len(traceIDs),
+			Truncated:       truncated,
+			Earliest:        earliest,
+			Latest:          latest,
+			OutputTruncated: outputTruncated,
+		}
+		if maxResponseBytes == 0 {
+			output.Markdown = markdown
+		}
+		return textResult(markdown), output, nil
+	})
+}
 
-		return nil, QueryTracesOutput{
-			SpanCount: spanCount,
-			Markdown:  markdown,
-		}, nil
+// findRootSpans returns the IDs of traces with a root span whose name contains
+// name (case-insensitive), and the matching root spans keyed by "<trace>/<span>".
+// A span is a root if it has no parent or its parent is not buffered. An empty
+// name matches every root. The returned bool reports whether ctx ended the scan
+// early.
+func findRootSpans(ctx context.Context, traces []ptrace.Traces, name string) (map[string]bool, map[string]bool, bool) {
+	spanIDs := make(map[string]map[string]bool)
+	truncated := forEachSpan(ctx, traces, func(_ string, span ptrace.Span) {
+		traceID := span.TraceID().String()
+		if spanIDs[traceID] == nil {
+			spanIDs[traceID] = make(map[string]bool)
+		}
+		spanIDs[traceID][span.SpanID().String()] = true
+	})
+	if truncated {
+		return nil, nil, true
+	}
+
+	name = strings.ToLower(name)
+	traceIDs := make(map[string]bool)
+	rootSpans := make(map[string]bool)
+	truncated = forEachSpan(ctx, traces, func(_ string, span ptrace.Span) {
+		traceID := span.TraceID().String()
+		if !span.ParentSpanID().IsEmpty() && spanIDs[traceID][span.ParentSpanID().String()] {
+			return
+		}
+		if strings.Contains(strings.ToLower(span.Name()), name) {
+			traceIDs[traceID] = true
+			rootSpans[traceID+"/"+span.SpanID().String()] = true
+		}
 	})
+	return traceIDs, rootSpans, truncated
+}
+
+// countTraceSpans counts the buffered spans of each trace ID. The returned
+// bool reports whether ctx ended the scan early.
+func countTraceSpans(ctx context.Context, traces []ptrace.Traces) (map[string]int, bool) {
+	counts := make(map[string]int)
+	truncated := forEachSpan(ctx, traces, func(_ string, span ptrace.Span) {
+		counts[span.TraceID().String()]++
+	})
+	return counts, truncated
+}
+
+// spanOperation identifies the spans sharing a latency baseline: a span name
+// is only comparable within the service that emits it
+type spanOperation struct {
+	service string
+	name    string
+}
+
+// spanNamePercentiles returns the p-th percentile (nearest rank) of the
+// durations of the buffered spans of each service and span name. The returned
+// bool reports whether ctx ended the scan early.
+func spanNamePercentiles(ctx context.Context, traces []ptrace.Traces, p float64) (map[spanOperation]time.Duration, bool) {
+	durations := make(map[spanOperation][]time.Duration)
+	truncated := forEachSpan(ctx, traces, func(serviceName string, span ptrace.Span) {
+		op := spanOperation{service: serviceName, name: span.Name()}
+		duration := time.Duration(span.EndTimestamp()) - time.Duration(span.StartTimestamp())
+		durations[op] = append(durations[op], duration)
+	})
+	if truncated {
+		return nil, true
+	}
+
+	thresholds := make(map[spanOperation]time.Duration, len(durations))
+	for op, values := range durations {
+		slices.Sort(values)
+		thresholds[op] = durationPercentile(values, p)
+	}
+	return thresholds, false
+}
+
+// durationPercentile returns the p-th percentile (nearest rank) of sorted,
+// which must not be empty
+func durationPercentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
 }
 
 // QueryLogsInput provides flexible filtering for log queries
@@ -176,56 +408,132 @@ type QueryLogsInput struct {
 	SeverityText string `json:"severity_text,omitempty" jsonschema:"Filter by severity (INFO, WARN, ERROR, etc.)"`
 	Body         string `json:"body,omitempty" jsonschema:"Filter by log body (partial match)"`
 	ServiceName  string `json:"service_name,omitempty" jsonschema:"Filter by service name"`
+	ConnectorID  string `json:"connector_id,omitempty" jsonschema:"Filter by the ID of the MCP connector that buffered the telemetry (e.g. 'mcp' 'mcp/prod')"`
+	ScopeName    string `json:"scope_name,omitempty" jsonschema:"Only return logs of instrumentation scopes whose name contains this text (case-insensitive), e.g. 'net/http'"`
 	TraceID      string `json:"trace_id,omitempty" jsonschema:"Filter by trace ID (partial match)"`
 	SpanID       string `json:"span_id,omitempty" jsonschema:"Filter by span ID (partial match)"`
 	Detailed     bool   `json:"detailed,omitempty" jsonschema:"Return detailed information for each log,false"`
+	Format       string `json:"format,omitempty" jsonschema:"Output format: 'table' or 'plain' (one body per line, no table columns). Ignored when detailed is set,table"`
+	PlainPrefix  bool   `json:"plain_prefix,omitempty" jsonschema:"Prefix each plain line with '[severity] timestamp: ',false"`
 	Limit        int    `json:"limit,omitempty" jsonschema:"Maximum number of logs to return,100"`
 	Offset       int    `json:"offset,omitempty" jsonschema:"Number of logs to skip,0"`
+
+	SortBy    string `json:"sort_by,omitempty" jsonschema:"Sort matching logs by 'time' or 'severity' (severity number, then time) before offset and limit apply. Omit for buffer order"`
+	SortOrder string `json:"sort_order,omitempty" jsonschema:"Sort order for sort_by: 'desc' (most severe or most recent first) or 'asc',desc"`
+
+	MaxAttrLength *int `json:"max_attr_length,omitempty" jsonschema:"Truncate rendered attributes to this many characters (0 disables truncation),40"`
+
+	ResourceAttrKeys []string `json:"resource_attr_keys,omitempty" jsonschema:"Add a table column per resource attribute key (e.g. ['k8s.pod.name' 'k8s.namespace.name' 'host.name']), '-' when absent. Ignored for detailed and plain output"`
+
+	ResourceAttributes map[string]string `json:"resource_attributes,omitempty" jsonschema:"Only return logs whose resource has these attribute values (exact match on the string form), e.g. {'deployment.environment': 'staging'}"`
+
+	Timezone string `json:"timezone,omitempty" jsonschema:"IANA time zone to render timestamps in (e.g. 'Europe/Berlin' 'UTC'). Omit for the configured timezone"`
+
+	AttributeIncludePrefixes []string `json:"attribute_include_prefixes,omitempty" jsonschema:"Only render attributes whose key starts with one of these prefixes (e.g. ['http.' 'db.']). Does not change which records match"`
+	AttributeExcludePrefixes []string `json:"attribute_exclude_prefixes,omitempty" jsonschema:"Hide attributes whose key starts with one of these prefixes (e.g. ['telemetry.sdk.' 'process.']), even when an include prefix matches. Does not change which records match"`
+
+	MaxResponseBytes int `json:"max_response_bytes,omitempty" jsonschema:"Cut the rendered output at this many bytes, ending it with '[output truncated, refine your query]'. Omit for the configured limit, which larger values cannot exceed. With a limit the output is only returned as the text content block"`
 }
 
 type QueryLogsOutput struct {
-	LogCount int    `json:"log_count"`
-	Markdown string `json:"markdown"`
+	LogCount int `json:"log_count"`
+	// Markdown is omitted when max_response_bytes applies, leaving the text
+	// content block as the only copy of the output
+	Markdown  string `json:"markdown,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	// Earliest and Latest are the first and last timestamps of the returned
+	// logs (observed time when unset), in UTC
+	Earliest string `json:"earliest,omitempty"`
+	Latest   string `json:"latest,omitempty"`
+	// OutputTruncated reports that the markdown was cut at max_response_bytes
+	OutputTruncated bool `json:"output_truncated,omitempty"`
 }
 
 // RegisterQueryLogs registers the query_logs tool
 func RegisterQueryLogs(server *mcp.Server, ext ExtensionContext) {
 	mcp.AddTool[QueryLogsInput, QueryLogsOutput](server, &mcp.Tool{
 		Name:        "query_logs",
-		Description: "Query logs with flexible filtering. Returns matching logs in table format or detailed view.",
+		Description: "Query logs with flexible filtering. Returns matching logs in table format, detailed view, or plain format (one body per line) for token-efficient summarization.",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input QueryLogsInput) (*mcp.CallToolResult, QueryLogsOutput, error) {
-		limit := input.Limit
-		if limit == 0 {
-			limit = 100
+		if err := checkBufferEnabled(ext, "logs"); err != nil {
+			return nil, QueryLogsOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, QueryLogsOutput{}, err
+		}
+		if err := validateOffset(input.Offset); err != nil {
+			return nil, QueryLogsOutput{}, err
+		}
+		maxAttrLen := attrLengthLimit(input.MaxAttrLength, 40)
+		loc, err := resolveLocation(ext, input.Timezone)
+		if err != nil {
+			return nil, QueryLogsOutput{}, err
+		}
+		keepAttribute := attributePrefixFilter(input.AttributeIncludePrefixes, input.AttributeExcludePrefixes)
+		maxResponseBytes, err := resolveMaxResponseBytes(ext, input.MaxResponseBytes)
+		if err != nil {
+			return nil, QueryLogsOutput{}, err
 		}
 
-		logs := ext.GetRecentLogs(10000, 0)
+		var plain bool
+		switch strings.ToLower(input.Format) {
+		case "", "table":
+		case "plain":
+			plain = !input.Detailed
+		default:
+			return nil, QueryLogsOutput{}, fmt.Errorf("invalid format: %s (must be table or plain)", input.Format)
+		}
+		sortBy := strings.ToLower(input.SortBy)
+		if sortBy != "" && sortBy != "time" && sortBy != "severity" {
+			return nil, QueryLogsOutput{}, fmt.Errorf("invalid sort_by: %s (must be time or severity)", input.SortBy)
+		}
+		descending := true
+		switch strings.ToLower(input.SortOrder) {
+		case "", "desc":
+		case "asc":
+			descending = false
+		default:
+			return nil, QueryLogsOutput{}, fmt.Errorf("invalid sort_order: %s (must be asc or desc)", input.SortOrder)
+		}
+
+		batches := ext.GetLogBatches(10000, 0)
 		var sb strings.Builder
-		writer := &LogWriter{}
+		writer := &LogWriter{Location: loc, KeepAttribute: keepAttribute, RedactAttribute: ext.IsRedactedAttribute}
 		logCount := 0
+		var bounds timeBounds
 		skipped := 0
+		truncated := false
 
-		if !input.Detailed {
-			sb.WriteString("| Time | Severity | Service | Body | TraceID | Attributes |\n")
-			sb.WriteString("|------|----------|---------|------|---------|------------|\n")
+		// Without sorting, offset and limit apply during the scan. Sorting
+		// needs every match first, so they are applied after the sort.
+		var matches []logMatch
+		full := func() bool { return sortBy == "" && len(matches) >= limit }
+
+		if !input.Detailed && !plain {
+			sb.WriteString("| ID | Time | Severity | Service | Body | TraceID | Attributes |" + resourceColumnHeader(input.ResourceAttrKeys) + "\n")
+			sb.WriteString("|----|------|----------|---------|------|---------|------------|" + resourceColumnSeparator(input.ResourceAttrKeys) + "\n")
 		}
 
-		for _, ld := range logs {
-			if logCount >= limit {
+		for _, batch := range batches {
+			if full() {
 				break
 			}
 
-			if ctx.Err() != nil {
-				return nil, QueryLogsOutput{}, ctx.Err()
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
 			}
 
+			ld := batch.Logs
+
 			for i := 0; i < ld.ResourceLogs().Len(); i++ {
-				if logCount >= limit {
+				if full() {
 					break
 				}
 
@@ -239,14 +547,25 @@ func RegisterQueryLogs(server *mcp.Server, ext ExtensionContext) {
 					continue
 				}
 
+				if input.ConnectorID != "" && !resourceHasConnectorID(rl.Resource(), input.ConnectorID) {
+					continue
+				}
+
+				if !resourceHasAttributes(rl.Resource(), input.ResourceAttributes) {
+					continue
+				}
+
 				for j := 0; j < rl.ScopeLogs().Len(); j++ {
-					if logCount >= limit {
+					if full() {
 						break
 					}
 
 					sl := rl.ScopeLogs().At(j)
+					if input.ScopeName != "" && !scopeNameMatches(sl.Scope(), input.ScopeName) {
+						continue
+					}
 					for k := 0; k < sl.LogRecords().Len(); k++ {
-						if logCount >= limit {
+						if full() {
 							break
 						}
 
@@ -271,32 +590,92 @@ func RegisterQueryLogs(server *mcp.Server, ext ExtensionContext) {
 							continue
 						}
 
-						if skipped < input.Offset {
+						if sortBy == "" && skipped < input.Offset {
 							skipped++
 							continue
 						}
 
-						logCount++
-
-						if input.Detailed {
-							writer.WriteLogDetailed(&sb, lr, serviceName, rl.Resource().Attributes())
-						} else {
-							writer.WriteLogSummary(&sb, lr, serviceName)
-						}
+						matches = append(matches, logMatch{
+							record:      lr,
+							id:          logRecordID(batch.Seq, i, j, k),
+							serviceName: serviceName,
+							resource:    rl.Resource().Attributes(),
+						})
 					}
 				}
 			}
 		}
 
+		if sortBy != "" {
+			sortLogMatches(matches, sortBy == "severity", descending)
+			matches = matches[min(max(input.Offset, 0), len(matches)):]
+			matches = matches[:min(limit, len(matches))]
+		}
+
+		for _, m := range matches {
+			lr := m.record
+			logCount++
+			bounds.add(m.timestamp())
+
+			switch {
+			case input.Detailed:
+				writer.WriteLogDetailed(&sb, lr, m.id, m.serviceName, m.resource)
+			case plain:
+				writer.WriteLogPlain(&sb, lr, input.PlainPrefix)
+			default:
+				writer.WriteLogSummary(&sb, lr, m.id, m.serviceName, maxAttrLen, m.resource, input.ResourceAttrKeys)
+			}
+		}
+
 		markdown := sb.String()
 		if logCount == 0 {
 			markdown = "No logs found matching the criteria"
 		}
+		markdown, outputTruncated := truncateOutput(markdown, maxResponseBytes, responseTruncatedMarker)
+		earliest, latest := bounds.format()
+
+		output := QueryLogsOutput{
+			LogCount:        logCount,
+			Truncated:       truncated,
+			Earliest:        earliest,
+			Latest:          latest,
+			OutputTruncated: outputTruncated,
+		}
+		if maxResponseBytes == 0 {
+			output.Markdown = markdown
+		}
+		return textResult(markdown), output, nil
+	})
+}
+
+// logMatch is a log record matched by query_logs with what its row needs
+type logMatch struct {
+	record      plog.LogRecord
+	id          string
+	serviceName string
+	resource    pcommon.Map
+}
+
+// timestamp returns the time of the record, its observed time when unset
+func (m logMatch) timestamp() pcommon.Timestamp {
+	if m.record.Timestamp() != 0 {
+		return m.record.Timestamp()
+	}
+	return m.record.ObservedTimestamp()
+}
 
-		return nil, QueryLogsOutput{
-			LogCount: logCount,
-			Markdown: markdown,
-		}, nil
+// sortLogMatches orders matches by time, or by severity number with time as
+// the tiebreaker
+func sortLogMatches(matches []logMatch, bySeverity, descending bool) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if descending {
+			a, b = b, a
+		}
+		if bySeverity && a.record.SeverityNumber() != b.record.SeverityNumber() {
+			return a.record.SeverityNumber() < b.record.SeverityNumber()
+		}
+		return a.timestamp() < b.timestamp()
 	})
 }
 
@@ -304,40 +683,132 @@ func RegisterQueryLogs(server *mcp.Server, ext ExtensionContext) {
 type QueryMetricsInput struct {
 	MetricName  string `json:"metric_name,omitempty" jsonschema:"Filter by metric name (partial match)"`
 	ServiceName string `json:"service_name,omitempty" jsonschema:"Filter by service name"`
-	MetricType  string `json:"metric_type,omitempty" jsonschema:"Filter by metric type (Sum, Gauge, Histogram, Summary)"`
+	ConnectorID string `json:"connector_id,omitempty" jsonschema:"Filter by the ID of the MCP connector that buffered the telemetry (e.g. 'mcp' 'mcp/prod')"`
+	ScopeName   string `json:"scope_name,omitempty" jsonschema:"Only return metrics of instrumentation scopes whose name contains this text (case-insensitive), e.g. 'net/http'"`
+	MetricType  string `json:"metric_type,omitempty" jsonschema:"Filter by metric type (Sum, Gauge, Histogram, ExponentialHistogram, Summary). Comma-separated for multiple types, case-insensitive"`
+	Temporality string `json:"temporality,omitempty" jsonschema:"Filter by aggregation temporality (Cumulative or Delta), case-insensitive. Gauges and Summaries have none and are excluded"`
 	Detailed    bool   `json:"detailed,omitempty" jsonschema:"Return detailed information for each metric,false"`
+	Format      string `json:"format,omitempty" jsonschema:"Output format: 'table' or 'csv' (one row per data point with metric_name type unit service timestamp value count sum attributes, returned in csv). Ignored when detailed is set,table"`
 	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of metrics to return,100"`
 	Offset      int    `json:"offset,omitempty" jsonschema:"Number of metrics to skip,0"`
+
+	CSVDelimiter string `json:"csv_delimiter,omitempty" jsonschema:"Single character separating fields of csv format output, e.g. a tab for TSV or ';'. Omit for a comma"`
+	CSVHeader    *bool  `json:"csv_header,omitempty" jsonschema:"Write the header row of csv format output. Disable to append the rows to earlier output,true"`
+
+	ResourceAttributes map[string]string `json:"resource_attributes,omitempty" jsonschema:"Only return metrics whose resource has these attribute values (exact match on the string form), e.g. {'deployment.environment': 'staging'}"`
+
+	AttributeKeys []string `json:"attribute_keys,omitempty" jsonschema:"Data point attribute keys written to their own columns of csv format output, after attributes and in the given order (e.g. ['http.route' 'http.response.status_code']). Cells are empty when a data point lacks the attribute"`
+
+	DataPointSelection string `json:"data_point_selection,omitempty" jsonschema:"Data points shown per metric in the table: 'first', 'last' (latest timestamp, the current value) or 'all' (one row each). Ignored for detailed and csv output,last"`
+
+	MinValue   *float64 `json:"min_value,omitempty" jsonschema:"Only keep data points whose value is at least this. Metrics without matching data points are skipped"`
+	MaxValue   *float64 `json:"max_value,omitempty" jsonschema:"Only keep data points whose value is at most this. Metrics without matching data points are skipped"`
+	ValueField string   `json:"value_field,omitempty" jsonschema:"Value compared by min_value/max_value for Histogram, ExponentialHistogram and Summary data points (count or sum),count"`
+
+	MaxAttrLength *int `json:"max_attr_length,omitempty" jsonschema:"Truncate rendered attributes to this many characters (0 disables truncation),50"`
+
+	Timezone string `json:"timezone,omitempty" jsonschema:"IANA time zone to render timestamps in (e.g. 'Europe/Berlin' 'UTC'). Omit for the configured timezone"`
+
+	AttributeIncludePrefixes []string `json:"attribute_include_prefixes,omitempty" jsonschema:"Only render attributes whose key starts with one of these prefixes (e.g. ['http.' 'db.']). Does not change which records match"`
+	AttributeExcludePrefixes []string `json:"attribute_exclude_prefixes,omitempty" jsonschema:"Hide attributes whose key starts with one of these prefixes (e.g. ['telemetry.sdk.' 'process.']), even when an include prefix matches. Does not change which records match"`
+
+	MaxResponseBytes int `json:"max_response_bytes,omitempty" jsonschema:"Cut the rendered output at this many bytes, ending it with '[output truncated, refine your query]'. Omit for the configured limit, which larger values cannot exceed. With a limit the output is only returned as the text content block"`
 }
 
 type QueryMetricsOutput struct {
-	MetricCount int    `json:"metric_count"`
-	Markdown    string `json:"markdown"`
+	MetricCount int `json:"metric_count"`
+	// Markdown and CSV are omitted when max_response_bytes applies, leaving
+	// the text content block as the only copy of the output
+	Markdown  string `json:"markdown,omitempty"`
+	CSV       string `json:"csv,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	// OutputTruncated reports that the markdown or CSV was cut at
+	// max_response_bytes. CSV is cut after a whole row, without a marker.
+	OutputTruncated bool `json:"output_truncated,omitempty"`
 }
 
 // RegisterQueryMetrics registers the query_metrics tool
 func RegisterQueryMetrics(server *mcp.Server, ext ExtensionContext) {
 	mcp.AddTool[QueryMetricsInput, QueryMetricsOutput](server, &mcp.Tool{
 		Name:        "query_metrics",
-		Description: "Query metrics with flexible filtering. Returns matching metrics in table format or detailed view.",
+		Description: "Query metrics with flexible filtering. Returns matching metrics in table format or detailed view. The Type column includes the aggregation temporality and monotonicity of Sums and Histograms.",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input QueryMetricsInput) (*mcp.CallToolResult, QueryMetricsOutput, error) {
-		limit := input.Limit
-		if limit == 0 {
-			limit = 100
+		if err := checkBufferEnabled(ext, "metrics"); err != nil {
+			return nil, QueryMetricsOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, QueryMetricsOutput{}, err
+		}
+		if err := validateOffset(input.Offset); err != nil {
+			return nil, QueryMetricsOutput{}, err
+		}
+		maxAttrLen := attrLengthLimit(input.MaxAttrLength, 50)
+		metricTypes := parseMetricTypes(input.MetricType)
+
+		temporality := strings.ToLower(input.Temporality)
+		switch temporality {
+		case "", "cumulative", "delta":
+		default:
+			return nil, QueryMetricsOutput{}, fmt.Errorf("invalid temporality: %s (must be Cumulative or Delta)", input.Temporality)
+		}
+
+		valueFilter := input.MinValue != nil || input.MaxValue != nil
+		valueField := strings.ToLower(input.ValueField)
+		switch valueField {
+		case "":
+			valueField = "count"
+		case "count", "sum":
+		default:
+			return nil, QueryMetricsOutput{}, fmt.Errorf("invalid value_field: %s (must be count or sum)", input.ValueField)
+		}
+
+		selection, err := parseDataPointSelection(input.DataPointSelection)
+		if err != nil {
+			return nil, QueryMetricsOutput{}, err
+		}
+		loc, err := resolveLocation(ext, input.Timezone)
+		if err != nil {
+			return nil, QueryMetricsOutput{}, err
+		}
+		keepAttribute := attributePrefixFilter(input.AttributeIncludePrefixes, input.AttributeExcludePrefixes)
+		maxResponseBytes, err := resolveMaxResponseBytes(ext, input.MaxResponseBytes)
+		if err != nil {
+			return nil, QueryMetricsOutput{}, err
+		}
+
+		var csvOutput bool
+		switch strings.ToLower(input.Format) {
+		case "", "table":
+		case "csv":
+			csvOutput = !input.Detailed
+		default:
+			return nil, QueryMetricsOutput{}, fmt.Errorf("invalid format: %s (must be table or csv)", input.Format)
 		}
 
 		metricsData := ext.GetRecentMetrics(10000, 0)
-		var sb strings.Builder
-		writer := &MetricWriter{}
+		var sb, csvBuf strings.Builder
+		csvWriter, err := newCSVWriter(&csvBuf, input.CSVDelimiter)
+		if err != nil {
+			return nil, QueryMetricsOutput{}, err
+		}
+		writer := &MetricWriter{Location: loc, KeepAttribute: keepAttribute, RedactAttribute: ext.IsRedactedAttribute, AttributeColumns: input.AttributeKeys}
 		metricCount := 0
 		skipped := 0
+		truncated := false
 
-		if !input.Detailed {
+		if csvOutput {
+			if input.CSVHeader == nil || *input.CSVHeader {
+				if err := csvWriter.Write(writer.CSVHeader()); err != nil {
+					return nil, QueryMetricsOutput{}, fmt.Errorf("failed to write CSV header: %w", err)
+				}
+			}
+		} else if !input.Detailed {
 			sb.WriteString("| Metric | Type | Service | Unit | Value | Attributes |\n")
 			sb.WriteString("|--------|------|---------|------|-------|------------|\n")
 		}
@@ -347,8 +818,9 @@ func RegisterQueryMetrics(server *mcp.Server, ext ExtensionContext) {
 				break
 			}
 
-			if ctx.Err() != nil {
-				return nil, QueryMetricsOutput{}, ctx.Err()
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
 			}
 
 			for i := 0; i < md.ResourceMetrics().Len(); i++ {
@@ -366,12 +838,23 @@ func RegisterQueryMetrics(server *mcp.Server, ext ExtensionContext) {
 					continue
 				}
 
+				if input.ConnectorID != "" && !resourceHasConnectorID(rm.Resource(), input.ConnectorID) {
+					continue
+				}
+
+				if !resourceHasAttributes(rm.Resource(), input.ResourceAttributes) {
+					continue
+				}
+
 				for j := 0; j < rm.ScopeMetrics().Len(); j++ {
 					if metricCount >= limit {
 						break
 					}
 
 					sm := rm.ScopeMetrics().At(j)
+					if input.ScopeName != "" && !scopeNameMatches(sm.Scope(), input.ScopeName) {
+						continue
+					}
 					for k := 0; k < sm.Metrics().Len(); k++ {
 						if metricCount >= limit {
 							break
@@ -384,10 +867,23 @@ func RegisterQueryMetrics(server *mcp.Server, ext ExtensionContext) {
 							continue
 						}
 
-						if input.MetricType != "" && metric.Type().String() != input.MetricType {
+						if len(metricTypes) > 0 && !metricTypes[strings.ToLower(metric.Type().String())] {
 							continue
 						}
 
+						if temporality != "" {
+							if t, _ := metricTemporality(metric); strings.ToLower(t) != temporality {
+								continue
+							}
+						}
+
+						if valueFilter {
+							var ok bool
+							if metric, ok = filterDataPointsByValue(metric, input.MinValue, input.MaxValue, valueField); !ok {
+								continue
+							}
+						}
+
 						if skipped < input.Offset {
 							skipped++
 							continue
@@ -395,24 +891,184 @@ func RegisterQueryMetrics(server *mcp.Server, ext ExtensionContext) {
 
 						metricCount++
 
-						if input.Detailed {
+						switch {
+						case input.Detailed:
 							writer.WriteMetricDetailed(&sb, metric, serviceName, rm.Resource().Attributes())
-						} else {
-							writer.WriteMetricSummary(&sb, metric, serviceName)
+						case csvOutput:
+							if err := writer.WriteMetricCSV(csvWriter, metric, serviceName); err != nil {
+								return nil, QueryMetricsOutput{}, fmt.Errorf("failed to write CSV row: %w", err)
+							}
+						default:
+							writer.WriteMetricSummary(&sb, metric, serviceName, maxAttrLen, selection)
 						}
 					}
 				}
 			}
 		}
 
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return nil, QueryMetricsOutput{}, fmt.Errorf("CSV writer error: %w", err)
+		}
+
 		markdown := sb.String()
 		if metricCount == 0 {
 			markdown = "No metrics found matching the criteria"
 		}
+		markdown, markdownTruncated := truncateOutput(markdown, maxResponseBytes, responseTruncatedMarker)
+		csvText, csvTruncated := truncateOutput(csvBuf.String(), maxResponseBytes, "")
+		text := markdown
+		if csvOutput && metricCount > 0 {
+			text = csvText
+		}
 
-		return nil, QueryMetricsOutput{
-			MetricCount: metricCount,
-			Markdown:    markdown,
-		}, nil
+		output := QueryMetricsOutput{
+			MetricCount:     metricCount,
+			Truncated:       truncated,
+			OutputTruncated: markdownTruncated || csvTruncated,
+		}
+		if maxResponseBytes == 0 {
+			output.Markdown = markdown
+			output.CSV = csvText
+		}
+		return textResult(text), output, nil
 	})
 }
+
+// attrLengthLimit resolves the optional max_attr_length input, falling back to
+// the tool's default when it is not set
+func attrLengthLimit(maxAttrLength *int, defaultLen int) int {
+	if maxAttrLength == nil {
+		return defaultLen
+	}
+	return *maxAttrLength
+}
+
+// parseMetricTypes turns a comma-separated metric_type filter into a set of
+// lower-cased type names
+func parseMetricTypes(metricType string) map[string]bool {
+	types := make(map[string]bool)
+	for _, t := range strings.Split(metricType, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types[strings.ToLower(t)] = true
+		}
+	}
+	return types
+}
+
+// formatProjectedAttributes formats only the given attribute keys accepted by
+// keep (all when nil), in order, truncated to maxLen characters (0 disables
+// truncation). Values of keys for which redact returns true are replaced.
+func formatProjectedAttributes(attrs pcommon.Map, keys []string, maxLen int, keep, redact func(key string) bool) string {
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if keep != nil && !keep(k) {
+			continue
+		}
+		if v, ok := attrs.Get(k); ok {
+			parts = append(parts, k+"="+redactValue(k, v.AsString(), redact))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+
+	result := strings.Join(parts, " ")
+	if maxLen > 0 && len(result) > maxLen {
+		result = result[:maxLen] + "..."
+	}
+	return result
+}
+
+// spanHasAttributes reports whether every attribute in want has the given
+// string value on the span or, when the span lacks the key, on its resource
+func spanHasAttributes(span ptrace.Span, res pcommon.Resource, want map[string]string) bool {
+	for key, value := range want {
+		v, ok := span.Attributes().Get(key)
+		if !ok {
+			v, ok = res.Attributes().Get(key)
+		}
+		if !ok || v.AsString() != value {
+			return false
+		}
+	}
+	return true
+}
+
+// connectorIDAttribute is the resource attribute the MCP connector tags buffered
+// telemetry with. This must match the attribute set by the connector.
+const connectorIDAttribute = "mcp.connector.id"
+
+// resourceHasConnectorID reports whether a resource was buffered by the given connector
+func resourceHasConnectorID(res pcommon.Resource, connectorID string) bool {
+	v, ok := res.Attributes().Get(connectorIDAttribute)
+	return ok && v.AsString() == connectorID
+}
+
+// resourceHasAttributes reports whether every attribute in want has the given
+// string value on the resource
+func resourceHasAttributes(res pcommon.Resource, want map[string]string) bool {
+	for key, value := range want {
+		v, ok := res.Attributes().Get(key)
+		if !ok || v.AsString() != value {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeNameMatches reports whether the instrumentation scope name contains
+// filter, ignoring case
+func scopeNameMatches(scope pcommon.InstrumentationScope, filter string) bool {
+	return strings.Contains(strings.ToLower(scope.Name()), strings.ToLower(filter))
+}
+
+// filterDataPointsByValue returns a copy of metric holding only the data points
+// whose value lies within [minValue, maxValue], and whether any remained.
+// Histogram and summary data points are compared by their count or sum.
+func filterDataPointsByValue(metric pmetric.Metric, minValue, maxValue *float64, valueField string) (pmetric.Metric, bool) {
+	inRange := func(v float64) bool {
+		return (minValue == nil || v >= *minValue) && (maxValue == nil || v <= *maxValue)
+	}
+	countOrSum := func(count uint64, sum float64) float64 {
+		if valueField == "sum" {
+			return sum
+		}
+		return float64(count)
+	}
+	numberValue := func(dp pmetric.NumberDataPoint) float64 {
+		if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+			return float64(dp.IntValue())
+		}
+		return dp.DoubleValue()
+	}
+
+	filtered := pmetric.NewMetric()
+	metric.CopyTo(filtered)
+
+	remaining := 0
+	switch filtered.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := filtered.Gauge().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return !inRange(numberValue(dp)) })
+		remaining = dps.Len()
+	case pmetric.MetricTypeSum:
+		dps := filtered.Sum().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return !inRange(numberValue(dp)) })
+		remaining = dps.Len()
+	case pmetric.MetricTypeHistogram:
+		dps := filtered.Histogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool { return !inRange(countOrSum(dp.Count(), dp.Sum())) })
+		remaining = dps.Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := filtered.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool { return !inRange(countOrSum(dp.Count(), dp.Sum())) })
+		remaining = dps.Len()
+	case pmetric.MetricTypeSummary:
+		dps := filtered.Summary().DataPoints()
+		dps.RemoveIf(func(dp pmetric.SummaryDataPoint) bool { return !inRange(countOrSum(dp.Count(), dp.Sum())) })
+		remaining = dps.Len()
+	}
+
+	return filtered, remaining > 0
+}
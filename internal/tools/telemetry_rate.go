@@ -0,0 +1,248 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type MetricRateInput struct {
+	MetricName  string            `json:"metric_name" jsonschema:"Name of a cumulative Sum metric (counter),required"`
+	ServiceName string            `json:"service_name,omitempty" jsonschema:"Only use data points of this service"`
+	Attributes  map[string]string `json:"attributes,omitempty" jsonschema:"Only use data points with these attribute values (exact match on the string form), e.g. {'http.route': '/cart'}"`
+}
+
+// RateInterval is the rate of a series between two consecutive data points
+type RateInterval struct {
+	Start string  `json:"start"`
+	End   string  `json:"end"`
+	Delta float64 `json:"delta"`
+	// RatePerSecond is Delta divided by the interval length in seconds
+	RatePerSecond float64 `json:"rate_per_second"`
+}
+
+// MetricRateSeries is the rate of one time series, identified by its service
+// and data point attributes
+type MetricRateSeries struct {
+	ServiceName string            `json:"service_name"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	// ResourceAttributes tell apart series of instances sharing a service name
+	ResourceAttributes map[string]string `json:"resource_attributes,omitempty"`
+	DataPoints         int               `json:"data_points"`
+	Intervals          []RateInterval    `json:"intervals"`
+	// AverageRatePerSecond is the total delta divided by the total length of
+	// the intervals, so reset intervals are excluded
+	AverageRatePerSecond float64 `json:"average_rate_per_second"`
+	// Resets counts decreases of a monotonic counter, whose intervals are skipped
+	Resets int `json:"resets,omitempty"`
+}
+
+type MetricRateOutput struct {
+	MetricName string             `json:"metric_name"`
+	Unit       string             `json:"unit,omitempty"`
+	Monotonic  bool               `json:"monotonic"`
+	Series     []MetricRateSeries `json:"series"`
+	Truncated  bool               `json:"truncated,omitempty"`
+}
+
+// ratePoint is a buffered data point of a counter series
+type ratePoint struct {
+	ts    pcommon.Timestamp
+	value float64
+}
+
+// RegisterMetricRate registers the metric_rate tool
+func RegisterMetricRate(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[MetricRateInput, MetricRateOutput](server, &mcp.Tool{
+		Name:        "metric_rate",
+		Description: "Compute the per-second rate of a cumulative Sum metric (counter) between consecutive buffered data points, per time series (resource and data point attributes), with the average rate of each series. For monotonic counters a decreasing value is a counter reset: the interval is skipped and counted in resets. Use attributes to select the series of interest.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input MetricRateInput) (*mcp.CallToolResult, MetricRateOutput, error) {
+		if input.MetricName == "" {
+			return nil, MetricRateOutput{}, errors.New("metric_name is required")
+		}
+		if err := checkBufferEnabled(ext, "metrics"); err != nil {
+			return nil, MetricRateOutput{}, err
+		}
+
+		output := MetricRateOutput{MetricName: input.MetricName}
+		points := make(map[string][]ratePoint)
+		series := make(map[string]*MetricRateSeries)
+		found := false
+
+		for _, md := range ext.GetRecentMetrics(10000, 0) {
+			if scanInterrupted(ctx) {
+				output.Truncated = true
+				break
+			}
+
+			for i := 0; i < md.ResourceMetrics().Len(); i++ {
+				rm := md.ResourceMetrics().At(i)
+				serviceName := "unknown"
+				if sn, ok := rm.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				if input.ServiceName != "" && serviceName != input.ServiceName {
+					continue
+				}
+
+				for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+					metrics := rm.ScopeMetrics().At(j).Metrics()
+					for k := 0; k < metrics.Len(); k++ {
+						metric := metrics.At(k)
+						if metric.Name() != input.MetricName {
+							continue
+						}
+						if metric.Type() != pmetric.MetricTypeSum {
+							return nil, MetricRateOutput{}, fmt.Errorf("metric %s is a %s, metric_rate requires a cumulative Sum", input.MetricName, metric.Type())
+						}
+						sum := metric.Sum()
+						if sum.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+							return nil, MetricRateOutput{}, fmt.Errorf("metric %s has %s temporality, metric_rate requires a cumulative Sum", input.MetricName, sum.AggregationTemporality())
+						}
+						if !found {
+							found = true
+							output.Unit = metric.Unit()
+							output.Monotonic = sum.IsMonotonic()
+						}
+
+						dps := sum.DataPoints()
+						for l := 0; l < dps.Len(); l++ {
+							dp := dps.At(l)
+							if !attributesMatch(dp.Attributes(), input.Attributes) {
+								continue
+							}
+							key := seriesKey(rm.Resource().Attributes(), dp.Attributes())
+							if series[key] == nil {
+								series[key] = &MetricRateSeries{
+									ServiceName:        serviceName,
+									Attributes:         attributesStrings(dp.Attributes(), ext.IsRedactedAttribute),
+									ResourceAttributes: attributesStrings(rm.Resource().Attributes(), ext.IsRedactedAttribute),
+								}
+							}
+							value := dp.DoubleValue()
+							if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+								value = float64(dp.IntValue())
+							}
+							points[key] = append(points[key], ratePoint{ts: dp.Timestamp(), value: value})
+						}
+					}
+				}
+			}
+		}
+
+		output.Series = make([]MetricRateSeries, 0, len(series))
+		for key, s := range series {
+			computeRates(s, points[key], output.Monotonic)
+			output.Series = append(output.Series, *s)
+		}
+		sort.Slice(output.Series, func(i, j int) bool {
+			if output.Series[i].ServiceName != output.Series[j].ServiceName {
+				return output.Series[i].ServiceName < output.Series[j].ServiceName
+			}
+			if a, b := attributesLabel(output.Series[i].Attributes), attributesLabel(output.Series[j].Attributes); a != b {
+				return a < b
+			}
+			return attributesLabel(output.Series[i].ResourceAttributes) < attributesLabel(output.Series[j].ResourceAttributes)
+		})
+
+		return nil, output, nil
+	})
+}
+
+// computeRates fills the intervals and average rate of a series from its data
+// points. Points with a timestamp already seen, e.g. batches buffered twice,
+// are ignored.
+func computeRates(s *MetricRateSeries, points []ratePoint, monotonic bool) {
+	sort.SliceStable(points, func(i, j int) bool { return points[i].ts < points[j].ts })
+	s.DataPoints = len(points)
+	s.Intervals = []RateInterval{}
+
+	var totalDelta float64
+	var totalTime time.Duration
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		elapsed := time.Duration(cur.ts - prev.ts)
+		if elapsed <= 0 {
+			continue
+		}
+		delta := cur.value - prev.value
+		if monotonic && delta < 0 {
+			s.Resets++
+			continue
+		}
+
+		s.Intervals = append(s.Intervals, RateInterval{
+			Start:         time.Unix(0, int64(prev.ts)).UTC().Format(time.RFC3339Nano),
+			End:           time.Unix(0, int64(cur.ts)).UTC().Format(time.RFC3339Nano),
+			Delta:         delta,
+			RatePerSecond: delta / elapsed.Seconds(),
+		})
+		totalDelta += delta
+		totalTime += elapsed
+	}
+	if totalTime > 0 {
+		s.AverageRatePerSecond = totalDelta / totalTime.Seconds()
+	}
+}
+
+// attributesMatch reports whether attrs holds every wanted value, compared by
+// their string form
+func attributesMatch(attrs pcommon.Map, want map[string]string) bool {
+	for key, value := range want {
+		v, ok := attrs.Get(key)
+		if !ok || v.AsString() != value {
+			return false
+		}
+	}
+	return true
+}
+
+// attributesStrings returns the string form of attrs, nil if empty, redacting
+// the values of the keys matched by redact
+func attributesStrings(attrs pcommon.Map, redact func(key string) bool) map[string]string {
+	if attrs.Len() == 0 {
+		return nil
+	}
+	result := make(map[string]string, attrs.Len())
+	attrs.Range(func(key string, value pcommon.Value) bool {
+		result[key] = redactValue(key, value.AsString(), redact)
+		return true
+	})
+	return result
+}
+
+// attributesKey identifies a series by its attributes independent of their order
+func attributesKey(attrs pcommon.Map) string {
+	return attributesLabel(attributesStrings(attrs, nil))
+}
+
+// seriesKey identifies a metric series by its full resource and data point
+// attributes, so instances sharing a service name stay separate series
+func seriesKey(resource, attrs pcommon.Map) string {
+	return attributesKey(resource) + "|" + attributesKey(attrs)
+}
+
+// attributesLabel renders attributes as sorted key=value pairs
+func attributesLabel(attrs map[string]string) string {
+	pairs := make([]string, 0, len(attrs))
+	for key, value := range attrs {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -5,15 +5,17 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/collector/pdata/pcommon"
-	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
@@ -28,6 +30,7 @@ type SearchTracesOutput struct {
 	SpanCount int      `json:"span_count"`
 	TraceIDs  []string `json:"trace_ids"`
 	Spans     []string `json:"spans"`
+	Truncated bool     `json:"truncated,omitempty"`
 }
 
 // RegisterSearchTraces registers the search_traces tool
@@ -41,15 +44,19 @@ func RegisterSearchTraces(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input SearchTracesInput) (*mcp.CallToolResult, SearchTracesOutput, error) {
-		limit := input.Limit
-		if limit == 0 {
-			limit = 100
+		if err := checkBufferEnabled(ext, "traces"); err != nil {
+			return nil, SearchTracesOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, SearchTracesOutput{}, err
 		}
 
 		traces := ext.GetRecentTraces(1000, 0) // Get a large batch to search
 		spans := []string{}
 		traceIDMap := make(map[string]bool)
 		spanCount := 0
+		truncated := false
 
 		for _, td := range traces {
 			if spanCount >= limit {
@@ -57,8 +64,9 @@ func RegisterSearchTraces(server *mcp.Server, ext ExtensionContext) {
 			}
 
 			// Check for context cancellation
-			if ctx.Err() != nil {
-				return nil, SearchTracesOutput{}, ctx.Err()
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
 			}
 
 			for i := 0; i < td.ResourceSpans().Len(); i++ {
@@ -125,6 +133,7 @@ func RegisterSearchTraces(server *mcp.Server, ext ExtensionContext) {
 			SpanCount: spanCount,
 			TraceIDs:  traceIDs,
 			Spans:     spans,
+			Truncated: truncated,
 		}, nil
 	})
 }
@@ -137,8 +146,9 @@ type SearchLogsInput struct {
 }
 
 type SearchLogsOutput struct {
-	LogCount int    `json:"log_count"`
-	Markdown string `json:"markdown"`
+	LogCount  int    `json:"log_count"`
+	Markdown  string `json:"markdown"`
+	Truncated bool   `json:"truncated,omitempty"`
 }
 
 // RegisterSearchLogs registers the search_logs tool
@@ -152,14 +162,18 @@ func RegisterSearchLogs(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input SearchLogsInput) (*mcp.CallToolResult, SearchLogsOutput, error) {
-		limit := input.Limit
-		if limit == 0 {
-			limit = 100
+		if err := checkBufferEnabled(ext, "logs"); err != nil {
+			return nil, SearchLogsOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, SearchLogsOutput{}, err
 		}
 
 		logs := ext.GetRecentLogs(1000, 0) // Get a large batch to search
 		var sb strings.Builder
 		logCount := 0
+		truncated := false
 
 		// Table header
 		sb.WriteString("| Time | Severity | Service | Body | TraceID | Attributes |\n")
@@ -171,8 +185,9 @@ func RegisterSearchLogs(server *mcp.Server, ext ExtensionContext) {
 			}
 
 			// Check for context cancellation
-			if ctx.Err() != nil {
-				return nil, SearchLogsOutput{}, ctx.Err()
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
 			}
 
 			for i := 0; i < ld.ResourceLogs().Len(); i++ {
@@ -219,8 +234,7 @@ func RegisterSearchLogs(server *mcp.Server, ext ExtensionContext) {
 						logCount++
 
 						// Format timestamp
-						timestamp := time.Unix(0, int64(lr.Timestamp()))
-						timeStr := timestamp.Format("15:04:05.000")
+						timeStr := formatTimestamp(lr.Timestamp(), ext.GetLocation(), "15:04:05.000")
 
 						// Get trace ID if present
 						traceID := lr.TraceID().String()
@@ -230,7 +244,7 @@ func RegisterSearchLogs(server *mcp.Server, ext ExtensionContext) {
 						}
 
 						// Format attributes
-						attrs := formatAttributes(lr.Attributes())
+						attrs := formatAttributes(lr.Attributes(), nil, ext.IsRedactedAttribute)
 						if attrs == "" {
 							attrs = "-"
 						} else if len(attrs) > 40 {
@@ -258,8 +272,9 @@ func RegisterSearchLogs(server *mcp.Server, ext ExtensionContext) {
 		}
 
 		return nil, SearchLogsOutput{
-			LogCount: logCount,
-			Markdown: markdown,
+			LogCount:  logCount,
+			Markdown:  markdown,
+			Truncated: truncated,
 		}, nil
 	})
 }
@@ -268,11 +283,14 @@ type SearchMetricsInput struct {
 	MetricName  string `json:"metric_name,omitempty" jsonschema:"Filter by metric name (partial match)"`
 	ServiceName string `json:"service_name,omitempty" jsonschema:"Filter by service name"`
 	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of metrics to return,100"`
+
+	DataPointSelection string `json:"data_point_selection,omitempty" jsonschema:"Data points shown per metric: 'first', 'last' (latest timestamp, the current value) or 'all' (one row each),last"`
 }
 
 type SearchMetricsOutput struct {
 	MetricCount int    `json:"metric_count"`
 	Markdown    string `json:"markdown"`
+	Truncated   bool   `json:"truncated,omitempty"`
 }
 
 // RegisterSearchMetrics registers the search_metrics tool
@@ -286,14 +304,22 @@ func RegisterSearchMetrics(server *mcp.Server, ext ExtensionContext) {
 			OpenWorldHint:  boolPtr(false),
 		},
 	}, func(ctx context.Context, _ *mcp.CallToolRequest, input SearchMetricsInput) (*mcp.CallToolResult, SearchMetricsOutput, error) {
-		limit := input.Limit
-		if limit == 0 {
-			limit = 100
+		if err := checkBufferEnabled(ext, "metrics"); err != nil {
+			return nil, SearchMetricsOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, ext.GetDefaultQueryLimit())
+		if err != nil {
+			return nil, SearchMetricsOutput{}, err
+		}
+		selection, err := parseDataPointSelection(input.DataPointSelection)
+		if err != nil {
+			return nil, SearchMetricsOutput{}, err
 		}
 
 		metricsData := ext.GetRecentMetrics(1000, 0) // Get a large batch to search
 		var sb strings.Builder
 		metricCount := 0
+		truncated := false
 
 		// Table header
 		sb.WriteString("| Metric | Type | Service | Unit | Value | Attributes |\n")
@@ -305,8 +331,9 @@ func RegisterSearchMetrics(server *mcp.Server, ext ExtensionContext) {
 			}
 
 			// Check for context cancellation
-			if ctx.Err() != nil {
-				return nil, SearchMetricsOutput{}, ctx.Err()
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
 			}
 
 			for i := 0; i < md.ResourceMetrics().Len(); i++ {
@@ -346,56 +373,34 @@ func RegisterSearchMetrics(server *mcp.Server, ext ExtensionContext) {
 
 						metricCount++
 
-						// Extract value summary based on type
-						valueStr := "-"
-						attrStr := "-"
-
-						switch metric.Type() {
-						case pmetric.MetricTypeSum:
-							sum := metric.Sum()
-							if sum.DataPoints().Len() > 0 {
-								dp := sum.DataPoints().At(0)
-								valueStr = fmt.Sprintf("%.2f", dp.DoubleValue())
-								attrStr = formatAttributes(dp.Attributes())
-							}
-						case pmetric.MetricTypeGauge:
-							gauge := metric.Gauge()
-							if gauge.DataPoints().Len() > 0 {
-								dp := gauge.DataPoints().At(0)
-								valueStr = fmt.Sprintf("%.2f", dp.DoubleValue())
-								attrStr = formatAttributes(dp.Attributes())
-							}
-						case pmetric.MetricTypeHistogram:
-							hist := metric.Histogram()
-							if hist.DataPoints().Len() > 0 {
-								dp := hist.DataPoints().At(0)
-								valueStr = fmt.Sprintf("count=%d sum=%.2f", dp.Count(), dp.Sum())
-								attrStr = formatAttributes(dp.Attributes())
+						indices := selectDataPoints(metric, selection)
+						if len(indices) == 0 {
+							sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | - | - |\n",
+								metricName,
+								metric.Type().String(),
+								serviceName,
+								unitLabel(metric.Unit())))
+						}
+						for _, idx := range indices {
+							valueStr, attrs := summarizeDataPoint(metric, idx)
+							attrStr := formatAttributes(attrs, nil, ext.IsRedactedAttribute)
+
+							// Truncate attributes
+							if len(attrStr) > 50 {
+								attrStr = attrStr[:50] + "..."
 							}
-						case pmetric.MetricTypeSummary:
-							summ := metric.Summary()
-							if summ.DataPoints().Len() > 0 {
-								dp := summ.DataPoints().At(0)
-								valueStr = fmt.Sprintf("count=%d sum=%.2f", dp.Count(), dp.Sum())
-								attrStr = formatAttributes(dp.Attributes())
+							if attrStr == "" {
+								attrStr = "-"
 							}
-						}
 
-						// Truncate attributes
-						if len(attrStr) > 50 {
-							attrStr = attrStr[:50] + "..."
+							sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+								metricName,
+								metric.Type().String(),
+								serviceName,
+								unitLabel(metric.Unit()),
+								valueStr,
+								attrStr))
 						}
-						if attrStr == "" {
-							attrStr = "-"
-						}
-
-						sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
-							metricName,
-							metric.Type().String(),
-							serviceName,
-							metric.Unit(),
-							valueStr,
-							attrStr))
 					}
 				}
 			}
@@ -409,12 +414,30 @@ func RegisterSearchMetrics(server *mcp.Server, ext ExtensionContext) {
 		return nil, SearchMetricsOutput{
 			MetricCount: metricCount,
 			Markdown:    markdown,
+			Truncated:   truncated,
 		}, nil
 	})
 }
 
 type GetTraceByIDInput struct {
-	TraceID string `json:"trace_id" jsonschema:"Full trace ID to retrieve,required"`
+	TraceID         string `json:"trace_id" jsonschema:"Full trace ID to retrieve,required"`
+	CollapseRepeats bool   `json:"collapse_repeats,omitempty" jsonschema:"Render consecutive sibling spans with the same name (retry loops N+1 queries) as one row annotated with ×N and their total duration,false"`
+	MaxDepth        int    `json:"max_depth,omitempty" jsonschema:"Only render spans up to this depth (1 renders the roots only); the spans below are summarized in one row per span at the cap. 0 renders all,0"`
+	Format          string `json:"format,omitempty" jsonschema:"Output format: 'waterfall' (markdown table) or 'json' (flat span array with parent references in spans, for trace viewers). collapse_repeats and max_depth only apply to the waterfall,waterfall"`
+}
+
+// TraceSpan is a span of the flat span array returned by get_trace_by_id with
+// format json
+type TraceSpan struct {
+	SpanID       string `json:"span_id"`
+	ParentSpanID string `json:"parent_span_id,omitempty"`
+	Name         string `json:"name"`
+	StartTime    int64  `json:"start_time_unix_nano"`
+	EndTime      int64  `json:"end_time_unix_nano"`
+	Status       string `json:"status"`
+	Kind         string `json:"kind"`
+	// Attributes holds the same key attributes (up to 5) as the waterfall
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 type GetTraceByIDOutput struct {
@@ -422,6 +445,15 @@ type GetTraceByIDOutput struct {
 	SpanCount int    `json:"span_count"`
 	Markdown  string `json:"markdown"`
 	Found     bool   `json:"found"`
+	// FromCache is set when the spans came from the trace assembly cache
+	// instead of the buffer
+	FromCache bool `json:"from_cache,omitempty"`
+	// Truncated is set when the trace has more spans than max_trace_spans.
+	// Only the first spans found are rendered and TotalSpans counts them all.
+	Truncated  bool `json:"truncated,omitempty"`
+	TotalSpans int  `json:"total_spans,omitempty"`
+	// Spans holds the spans with format json, parents before their children
+	Spans []TraceSpan `json:"spans,omitempty"`
 }
 
 // spanInfo holds span data for waterfall rendering
@@ -435,13 +467,32 @@ type spanInfo struct {
 	kind       string
 	attributes map[string]string
 	children   []*spanInfo
+
+	statusMessage string
+	// repeats is the number of consecutive same-named siblings a collapsed row
+	// stands for, 0 for a single span
+	repeats int
+}
+
+// statusMessageLen is the number of characters of an error status message
+// shown in table rows
+const statusMessageLen = 40
+
+// statusLabel returns the status code for a table cell, followed by the
+// truncated status message for error spans
+func (s *spanInfo) statusLabel() string {
+	if s.status != ptrace.StatusCodeError.String() || s.statusMessage == "" {
+		return s.status
+	}
+	message := strings.NewReplacer("\r\n", " ", "\n", " ", "|", "\\|").Replace(s.statusMessage)
+	return s.status + ": " + truncateString(message, statusMessageLen)
 }
 
 // RegisterGetTraceByID registers the get_trace_by_id tool
 func RegisterGetTraceByID(server *mcp.Server, ext ExtensionContext) {
 	mcp.AddTool[GetTraceByIDInput, GetTraceByIDOutput](server, &mcp.Tool{
 		Name:        "get_trace_by_id",
-		Description: "Get a specific trace by trace ID. Returns all spans for the trace.",
+		Description: "Get a specific trace by trace ID. Returns all spans for the trace. When the trace assembly cache is enabled, a cached trace is returned from it, including spans whose batches were already evicted from the buffer.",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
@@ -451,11 +502,24 @@ func RegisterGetTraceByID(server *mcp.Server, ext ExtensionContext) {
 		if input.TraceID == "" {
 			return nil, GetTraceByIDOutput{}, errors.New("trace_id is required")
 		}
+		format := strings.ToLower(input.Format)
+		if format != "" && format != "waterfall" && format != "json" {
+			return nil, GetTraceByIDOutput{}, fmt.Errorf("invalid format: %s (must be waterfall or json)", input.Format)
+		}
 
-		traces := ext.GetRecentTraces(1000, 0) // Get all recent traces
+		// The trace assembly cache holds every span of a trace still open or
+		// recently closed, even those of batches already evicted from the
+		// buffer, so the buffer is only copied on a cache miss
+		cached, fromCache := ext.GetCachedTrace(input.TraceID)
+		traces := []ptrace.Traces{cached}
+		if !fromCache {
+			traces = ext.GetRecentTraces(1000, 0) // Get all recent traces
+		}
 		spanMap := make(map[string]*spanInfo)
 		var traceStartTime time.Time
 		found := false
+		maxSpans := ext.GetMaxTraceSpans()
+		totalSpans := 0
 
 		// Collect all spans for this trace
 		for _, td := range traces {
@@ -475,7 +539,11 @@ func RegisterGetTraceByID(server *mcp.Server, ext ExtensionContext) {
 						// Match exact trace ID
 						if traceID == input.TraceID {
 							found = true
-							info := extractSpanInfo(span)
+							totalSpans++
+							if maxSpans > 0 && len(spanMap) >= maxSpans {
+								continue
+							}
+							info := extractSpanInfo(span, nil, ext.IsRedactedAttribute)
 							spanMap[info.spanID] = info
 
 							// Track earliest start time as trace start
@@ -489,7 +557,7 @@ func RegisterGetTraceByID(server *mcp.Server, ext ExtensionContext) {
 		}
 
 		if !found {
-			return nil, GetTraceByIDOutput{
+			return textResult("Trace not found"), GetTraceByIDOutput{
 				TraceID:   input.TraceID,
 				SpanCount: 0,
 				Markdown:  "Trace not found",
@@ -500,23 +568,257 @@ func RegisterGetTraceByID(server *mcp.Server, ext ExtensionContext) {
 		// Build tree structure
 		rootSpans := buildSpanTree(spanMap)
 
-		// Render as markdown waterfall
-		markdown := renderTraceWaterfall(rootSpans, traceStartTime)
-
-		return nil, GetTraceByIDOutput{
+		output := GetTraceByIDOutput{
 			TraceID:   input.TraceID,
 			SpanCount: len(spanMap),
-			Markdown:  markdown,
 			Found:     true,
+			FromCache: fromCache,
+		}
+		if maxSpans > 0 && totalSpans > maxSpans {
+			output.Truncated = true
+			output.TotalSpans = totalSpans
+		}
+
+		if format == "json" {
+			output.Spans = make([]TraceSpan, 0, len(spanMap))
+			flattenSpanTree(rootSpans, &output.Spans)
+			data, err := json.Marshal(output.Spans)
+			if err != nil {
+				return nil, GetTraceByIDOutput{}, fmt.Errorf("failed to encode spans: %w", err)
+			}
+			return textResult(string(data)), output, nil
+		}
+
+		// Render as markdown waterfall
+		markdown := renderTraceWaterfall(rootSpans, traceStartTime, waterfallOptions{
+			collapse: input.CollapseRepeats,
+			maxDepth: input.MaxDepth,
+		})
+		if output.Truncated {
+			markdown += fmt.Sprintf("\n*Trace truncated: showing %d of %d spans (max_trace_spans)*\n", len(spanMap), totalSpans)
+		}
+		output.Markdown = markdown
+
+		return textResult(markdown), output, nil
+	})
+}
+
+type GetSpanByIDInput struct {
+	SpanID  string `json:"span_id" jsonschema:"Full span ID to retrieve,required"`
+	TraceID string `json:"trace_id,omitempty" jsonschema:"Trace ID the span belongs to. Needed when the span ID occurs in several traces"`
+}
+
+type GetSpanByIDOutput struct {
+	SpanID            string   `json:"span_id"`
+	TraceID           string   `json:"trace_id,omitempty"`
+	Found             bool     `json:"found"`
+	Ambiguous         bool     `json:"ambiguous,omitempty"`
+	CandidateTraceIDs []string `json:"candidate_trace_ids,omitempty"`
+	Markdown          string   `json:"markdown"`
+}
+
+// RegisterGetSpanByID registers the get_span_by_id tool
+func RegisterGetSpanByID(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetSpanByIDInput, GetSpanByIDOutput](server, &mcp.Tool{
+		Name:        "get_span_by_id",
+		Description: "Get the detailed view of a single span (attributes, events, links, timing) by span ID, optionally scoped to a trace ID. If the span ID occurs in several traces and no trace ID is given, the candidate trace IDs are returned instead.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetSpanByIDInput) (*mcp.CallToolResult, GetSpanByIDOutput, error) {
+		if input.SpanID == "" {
+			return nil, GetSpanByIDOutput{}, errors.New("span_id is required")
+		}
+
+		type spanMatch struct {
+			span          ptrace.Span
+			serviceName   string
+			resourceAttrs pcommon.Map
+		}
+
+		// First match per trace, in trace discovery order
+		matches := make(map[string]spanMatch)
+		var traceIDs []string
+
+		for _, td := range ext.GetRecentTraces(10000, 0) {
+			if ctx.Err() != nil {
+				return nil, GetSpanByIDOutput{}, ctx.Err()
+			}
+
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				rs := td.ResourceSpans().At(i)
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					spans := rs.ScopeSpans().At(j).Spans()
+					for k := 0; k < spans.Len(); k++ {
+						span := spans.At(k)
+						if !strings.EqualFold(span.SpanID().String(), input.SpanID) {
+							continue
+						}
+						traceID := span.TraceID().String()
+						if input.TraceID != "" && !strings.EqualFold(traceID, input.TraceID) {
+							continue
+						}
+						if _, ok := matches[traceID]; ok {
+							continue
+						}
+
+						serviceName := "unknown"
+						if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+							serviceName = sn.AsString()
+						}
+						matches[traceID] = spanMatch{
+							span:          span,
+							serviceName:   serviceName,
+							resourceAttrs: rs.Resource().Attributes(),
+						}
+						traceIDs = append(traceIDs, traceID)
+					}
+				}
+			}
+		}
+
+		output := GetSpanByIDOutput{SpanID: input.SpanID, TraceID: input.TraceID}
+		switch len(traceIDs) {
+		case 0:
+			output.Markdown = "Span not found"
+		case 1:
+			m := matches[traceIDs[0]]
+			var sb strings.Builder
+			writer := &TraceWriter{Location: ext.GetLocation(), RedactAttribute: ext.IsRedactedAttribute}
+			writer.WriteSpanDetailed(&sb, m.span, m.serviceName, m.resourceAttrs, nil)
+
+			output.TraceID = traceIDs[0]
+			output.Found = true
+			output.Markdown = sb.String()
+		default:
+			output.Ambiguous = true
+			output.CandidateTraceIDs = traceIDs
+			output.Markdown = fmt.Sprintf("Span ID found in %d traces, pass trace_id to select one", len(traceIDs))
+		}
+
+		return nil, output, nil
+	})
+}
+
+type GetLogByIDInput struct {
+	LogID string `json:"log_id" jsonschema:"Log ID as shown in query_logs output (e.g. '42-0-0-3'),required"`
+}
+
+type GetLogByIDOutput struct {
+	LogID    string `json:"log_id"`
+	Markdown string `json:"markdown"`
+	Found    bool   `json:"found"`
+}
+
+// RegisterGetLogByID registers the get_log_by_id tool
+func RegisterGetLogByID(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetLogByIDInput, GetLogByIDOutput](server, &mcp.Tool{
+		Name:        "get_log_by_id",
+		Description: "Get the full detailed view of a single log record by the ID shown in query_logs or get_logs_for_trace output. IDs stay valid until the record's batch is evicted from the buffer.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetLogByIDInput) (*mcp.CallToolResult, GetLogByIDOutput, error) {
+		seq, ri, si, li, err := parseLogRecordID(input.LogID)
+		if err != nil {
+			return nil, GetLogByIDOutput{}, err
+		}
+
+		for _, batch := range ext.GetLogBatches(10000, 0) {
+			if ctx.Err() != nil {
+				return nil, GetLogByIDOutput{}, ctx.Err()
+			}
+			if batch.Seq != seq {
+				continue
+			}
+
+			ld := batch.Logs
+			if ri >= ld.ResourceLogs().Len() {
+				break
+			}
+			rl := ld.ResourceLogs().At(ri)
+			if si >= rl.ScopeLogs().Len() {
+				break
+			}
+			records := rl.ScopeLogs().At(si).LogRecords()
+			if li >= records.Len() {
+				break
+			}
+
+			serviceName := "unknown"
+			if sn, ok := rl.Resource().Attributes().Get("service.name"); ok {
+				serviceName = sn.AsString()
+			}
+
+			var sb strings.Builder
+			writer := &LogWriter{Location: ext.GetLocation(), RedactAttribute: ext.IsRedactedAttribute}
+			writer.WriteLogDetailed(&sb, records.At(li), input.LogID, serviceName, rl.Resource().Attributes())
+
+			return nil, GetLogByIDOutput{
+				LogID:    input.LogID,
+				Markdown: sb.String(),
+				Found:    true,
+			}, nil
+		}
+
+		return nil, GetLogByIDOutput{
+			LogID:    input.LogID,
+			Markdown: "Log not found",
+			Found:    false,
 		}, nil
 	})
 }
 
+// logRecordID builds the ID of a log record from the sequence number of its
+// batch and its resource, scope and record indices
+func logRecordID(seq uint64, resourceIdx, scopeIdx, recordIdx int) string {
+	return fmt.Sprintf("%d-%d-%d-%d", seq, resourceIdx, scopeIdx, recordIdx)
+}
+
+// parseLogRecordID is the inverse of logRecordID
+func parseLogRecordID(id string) (uint64, int, int, int, error) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidLogID, id)
+	}
+	seq, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidLogID, id)
+	}
+	var indices [3]int
+	for i, part := range parts[1:] {
+		if indices[i], err = strconv.Atoi(part); err != nil || indices[i] < 0 {
+			return 0, 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidLogID, id)
+		}
+	}
+	return seq, indices[0], indices[1], indices[2], nil
+}
+
 type FindRelatedTelemetryInput struct {
 	TraceID string `json:"trace_id,omitempty" jsonschema:"Trace ID to find related telemetry"`
 	SpanID  string `json:"span_id,omitempty" jsonschema:"Span ID to find related telemetry"`
 }
 
+// LinkedSpan is a span link crossing the boundary of the requested trace
+type LinkedSpan struct {
+	// Relationship is "links_to_trace" when a span of another trace links to
+	// the requested trace, or "linked_from_trace" when a span of the requested
+	// trace links to another trace
+	Relationship string `json:"relationship"`
+	// The span holding the link
+	TraceID string `json:"trace_id"`
+	SpanID  string `json:"span_id"`
+	Name    string `json:"name"`
+	Service string `json:"service"`
+	// The span the link points to, which may not be buffered
+	LinkedTraceID string `json:"linked_trace_id"`
+	LinkedSpanID  string `json:"linked_span_id"`
+}
+
 type FindRelatedTelemetryOutput struct {
 	TraceID     string   `json:"trace_id,omitempty"`
 	SpanCount   int      `json:"span_count"`
@@ -525,13 +827,17 @@ type FindRelatedTelemetryOutput struct {
 	Spans       []string `json:"spans,omitempty"`
 	Logs        []string `json:"logs,omitempty"`
 	Metrics     []string `json:"metrics,omitempty"`
+	Truncated   bool     `json:"truncated,omitempty"`
+
+	// Only populated when a trace ID is given
+	LinkedSpans []LinkedSpan `json:"linked_spans,omitempty"`
 }
 
 // RegisterFindRelatedTelemetry registers the find_related_telemetry tool
 func RegisterFindRelatedTelemetry(server *mcp.Server, ext ExtensionContext) {
 	mcp.AddTool[FindRelatedTelemetryInput, FindRelatedTelemetryOutput](server, &mcp.Tool{
 		Name:        "find_related_telemetry",
-		Description: "Find related telemetry (logs, metrics) based on trace context. Correlates logs and metrics with trace/span IDs.",
+		Description: "Find related telemetry (logs, metrics) based on trace context. Correlates logs and metrics with trace/span IDs. Given a trace ID, also returns linked_spans: spans of other traces whose span links reference the trace, and spans of the trace linking to other traces (e.g. messaging consumers and producers, batch jobs).",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
@@ -548,78 +854,165 @@ func RegisterFindRelatedTelemetry(server *mcp.Server, ext ExtensionContext) {
 
 		// Find related spans if trace ID is provided
 		if input.TraceID != "" {
-			traces := ext.GetRecentTraces(1000, 0)
-			for _, td := range traces {
-				// Check for context cancellation
-				if ctx.Err() != nil {
-					return nil, FindRelatedTelemetryOutput{}, ctx.Err()
+			output.Truncated = forEachSpan(ctx, ext.GetRecentTraces(1000, 0), func(serviceName string, span ptrace.Span) {
+				inTrace := span.TraceID().String() == input.TraceID
+				if inTrace {
+					output.SpanCount++
+					output.Spans = append(output.Spans, fmt.Sprintf("span_id=%s name=%s",
+						span.SpanID().String(), span.Name()))
 				}
 
-				for i := 0; i < td.ResourceSpans().Len(); i++ {
-					rs := td.ResourceSpans().At(i)
-					for j := 0; j < rs.ScopeSpans().Len(); j++ {
-						ss := rs.ScopeSpans().At(j)
-						for k := 0; k < ss.Spans().Len(); k++ {
-							span := ss.Spans().At(k)
-							if span.TraceID().String() == input.TraceID {
-								output.SpanCount++
-								if output.Spans == nil {
-									output.Spans = []string{}
-								}
-								output.Spans = append(output.Spans, fmt.Sprintf("span_id=%s name=%s",
-									span.SpanID().String(), span.Name()))
-							}
+				for i := 0; i < span.Links().Len(); i++ {
+					link := span.Links().At(i)
+					if link.TraceID() == span.TraceID() {
+						continue
+					}
+					relationship := "linked_from_trace"
+					if !inTrace {
+						if link.TraceID().String() != input.TraceID {
+							continue
 						}
+						relationship = "links_to_trace"
 					}
+					output.LinkedSpans = append(output.LinkedSpans, LinkedSpan{
+						Relationship:  relationship,
+						TraceID:       span.TraceID().String(),
+						SpanID:        span.SpanID().String(),
+						Name:          span.Name(),
+						Service:       serviceName,
+						LinkedTraceID: link.TraceID().String(),
+						LinkedSpanID:  link.SpanID().String(),
+					})
 				}
+			})
+		}
+
+		// Find related logs with a matching trace/span ID
+		truncated := forEachLogRecord(ctx, ext.GetRecentLogs(1000, 0), func(_ string, lr plog.LogRecord) {
+			matched := false
+			if input.TraceID != "" && lr.TraceID().String() == input.TraceID {
+				matched = true
+			}
+			if input.SpanID != "" && lr.SpanID().String() == input.SpanID {
+				matched = true
+			}
+
+			if matched {
+				output.LogCount++
+				output.Logs = append(output.Logs, fmt.Sprintf("severity=%s body=%s",
+					lr.SeverityText(), truncateString(lr.Body().AsString(), 60)))
 			}
+		})
+		output.Truncated = output.Truncated || truncated
+
+		// Note: Metrics typically don't have trace/span context in OTLP,
+		// so we can't easily correlate them without exemplars
+		// Leaving metric count at 0 for now
+
+		return nil, output, nil
+	})
+}
+
+type GetLogsForTraceInput struct {
+	TraceID  string `json:"trace_id" jsonschema:"Trace ID to get correlated logs for,required"`
+	Severity string `json:"severity,omitempty" jsonschema:"Only return logs of this severity (TRACE, DEBUG, INFO, WARN, ERROR, FATAL)"`
+}
+
+type GetLogsForTraceOutput struct {
+	TraceID   string `json:"trace_id"`
+	LogCount  int    `json:"log_count"`
+	Markdown  string `json:"markdown"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// traceLog holds a log record together with the resource it was emitted by
+type traceLog struct {
+	id            string
+	record        plog.LogRecord
+	serviceName   string
+	resourceAttrs pcommon.Map
+}
+
+// RegisterGetLogsForTrace registers the get_logs_for_trace tool
+func RegisterGetLogsForTrace(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetLogsForTraceInput, GetLogsForTraceOutput](server, &mcp.Tool{
+		Name:        "get_logs_for_trace",
+		Description: "Get all buffered logs correlated with a trace ID, sorted by timestamp, in detailed markdown format. Optionally filter by severity.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetLogsForTraceInput) (*mcp.CallToolResult, GetLogsForTraceOutput, error) {
+		if input.TraceID == "" {
+			return nil, GetLogsForTraceOutput{}, errors.New("trace_id is required")
 		}
 
-		// Find related logs
-		logs := ext.GetRecentLogs(1000, 0)
-		for _, ld := range logs {
-			// Check for context cancellation
-			if ctx.Err() != nil {
-				return nil, FindRelatedTelemetryOutput{}, ctx.Err()
+		var matches []traceLog
+		truncated := false
+		for _, batch := range ext.GetLogBatches(10000, 0) {
+			if scanInterrupted(ctx) {
+				truncated = true
+				break
 			}
 
+			ld := batch.Logs
+
 			for i := 0; i < ld.ResourceLogs().Len(); i++ {
 				rl := ld.ResourceLogs().At(i)
+				serviceName := "unknown"
+				if sn, ok := rl.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+
 				for j := 0; j < rl.ScopeLogs().Len(); j++ {
 					sl := rl.ScopeLogs().At(j)
 					for k := 0; k < sl.LogRecords().Len(); k++ {
 						lr := sl.LogRecords().At(k)
-
-						// Check if log has matching trace/span ID
-						logTraceID := lr.TraceID().String()
-						logSpanID := lr.SpanID().String()
-
-						matched := false
-						if input.TraceID != "" && logTraceID == input.TraceID {
-							matched = true
-						}
-						if input.SpanID != "" && logSpanID == input.SpanID {
-							matched = true
+						if !strings.EqualFold(lr.TraceID().String(), input.TraceID) {
+							continue
 						}
-
-						if matched {
-							output.LogCount++
-							if output.Logs == nil {
-								output.Logs = []string{}
-							}
-							output.Logs = append(output.Logs, fmt.Sprintf("severity=%s body=%s",
-								lr.SeverityText(), truncateString(lr.Body().AsString(), 60)))
+						if input.Severity != "" &&
+							!strings.EqualFold(severityBucket(lr), input.Severity) &&
+							!strings.EqualFold(lr.SeverityText(), input.Severity) {
+							continue
 						}
+						matches = append(matches, traceLog{
+							id:            logRecordID(batch.Seq, i, j, k),
+							record:        lr,
+							serviceName:   serviceName,
+							resourceAttrs: rl.Resource().Attributes(),
+						})
 					}
 				}
 			}
 		}
 
-		// Note: Metrics typically don't have trace/span context in OTLP,
-		// so we can't easily correlate them without exemplars
-		// Leaving metric count at 0 for now
+		if len(matches) == 0 {
+			return nil, GetLogsForTraceOutput{
+				TraceID:   input.TraceID,
+				Markdown:  "No logs found for trace",
+				Truncated: truncated,
+			}, nil
+		}
 
-		return nil, output, nil
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].record.Timestamp() < matches[j].record.Timestamp()
+		})
+
+		var sb strings.Builder
+		writer := &LogWriter{Location: ext.GetLocation(), RedactAttribute: ext.IsRedactedAttribute}
+		fmt.Fprintf(&sb, "# Logs for trace `%s`\n\n", input.TraceID)
+		for _, m := range matches {
+			writer.WriteLogDetailed(&sb, m.record, m.id, m.serviceName, m.resourceAttrs)
+		}
+
+		return nil, GetLogsForTraceOutput{
+			TraceID:   input.TraceID,
+			LogCount:  len(matches),
+			Markdown:  sb.String(),
+			Truncated: truncated,
+		}, nil
 	})
 }
 
@@ -633,8 +1026,10 @@ func truncateString(s string, maxLen int) string {
 	return string(runes[:maxLen]) + "..."
 }
 
-// extractSpanInfo extracts relevant span information for waterfall rendering
-func extractSpanInfo(span ptrace.Span) *spanInfo {
+// extractSpanInfo extracts relevant span information for waterfall rendering.
+// Only attribute keys accepted by keep (all when nil) count toward the
+// attribute cap, and the values of the keys matched by redact are redacted.
+func extractSpanInfo(span ptrace.Span, keep, redact func(key string) bool) *spanInfo {
 	info := &spanInfo{
 		spanID:     span.SpanID().String(),
 		parentID:   span.ParentSpanID().String(),
@@ -645,12 +1040,14 @@ func extractSpanInfo(span ptrace.Span) *spanInfo {
 		kind:       span.Kind().String(),
 		attributes: make(map[string]string),
 		children:   []*spanInfo{},
+
+		statusMessage: span.Status().Message(),
 	}
 
 	// Extract key attributes (limit to avoid overwhelming output)
 	span.Attributes().Range(func(k string, v pcommon.Value) bool {
-		if len(info.attributes) < 5 { // Limit to 5 key attributes
-			info.attributes[k] = v.AsString()
+		if len(info.attributes) < 5 && (keep == nil || keep(k)) { // Limit to 5 key attributes
+			info.attributes[k] = redactValue(k, v.AsString(), redact)
 		}
 		return true
 	})
@@ -702,26 +1099,97 @@ func sortChildren(span *spanInfo) {
 	}
 }
 
+// flattenSpanTree appends the spans of a tree depth first, parents before
+// their children
+func flattenSpanTree(spans []*spanInfo, flat *[]TraceSpan) {
+	for _, span := range spans {
+		*flat = append(*flat, TraceSpan{
+			SpanID:       span.spanID,
+			ParentSpanID: span.parentID,
+			Name:         span.name,
+			StartTime:    span.startTime.UnixNano(),
+			EndTime:      span.endTime.UnixNano(),
+			Status:       span.status,
+			Kind:         span.kind,
+			Attributes:   span.attributes,
+		})
+		flattenSpanTree(span.children, flat)
+	}
+}
+
+// waterfallOptions controls how renderTraceWaterfall renders the span tree
+type waterfallOptions struct {
+	// collapse renders runs of same-named siblings as one row
+	collapse bool
+	// maxDepth is the deepest level rendered, roots being level 1. Spans
+	// below it are summarized in one row. 0 renders all levels.
+	maxDepth int
+}
+
 // renderTraceWaterfall renders spans as a markdown table with tree structure
-func renderTraceWaterfall(roots []*spanInfo, traceStart time.Time) string {
+func renderTraceWaterfall(roots []*spanInfo, traceStart time.Time, opts waterfallOptions) string {
 	var sb strings.Builder
 
 	// Table header
 	sb.WriteString("| Span | ID | Duration | Start | Status | Attributes |\n")
 	sb.WriteString("|------|-----|----------|-------|--------|------------|\n")
 
+	if opts.collapse {
+		roots = collapseRepeats(roots)
+	}
+
 	// Render each root and its children
 	for _, root := range roots {
-		renderSpanRow(&sb, root, traceStart, "", true)
+		renderSpanRow(&sb, root, traceStart, "", true, 1, opts)
 	}
 
 	return sb.String()
 }
 
+// collapseRepeats replaces each run of consecutive spans with the same name by
+// one span starting with the run, lasting the total duration of its spans and
+// holding all their children. The run takes the ID and attributes of its first
+// span and is an error if any of its spans is.
+func collapseRepeats(spans []*spanInfo) []*spanInfo {
+	collapsed := make([]*spanInfo, 0, len(spans))
+	for i := 0; i < len(spans); {
+		j := i + 1
+		for j < len(spans) && spans[j].name == spans[i].name {
+			j++
+		}
+		if j-i == 1 {
+			collapsed = append(collapsed, spans[i])
+			i = j
+			continue
+		}
+
+		run := *spans[i]
+		run.repeats = j - i
+		run.children = nil
+		var total time.Duration
+		for _, span := range spans[i:j] {
+			total += span.endTime.Sub(span.startTime)
+			run.children = append(run.children, span.children...)
+			if span.status == ptrace.StatusCodeError.String() && run.status != span.status {
+				run.status = span.status
+				run.statusMessage = span.statusMessage
+			}
+		}
+		run.endTime = run.startTime.Add(total)
+		sort.SliceStable(run.children, func(a, b int) bool {
+			return run.children[a].startTime.Before(run.children[b].startTime)
+		})
+		collapsed = append(collapsed, &run)
+		i = j
+	}
+	return collapsed
+}
+
 // renderSpanRow renders a single span row with tree formatting
 // prefix contains only the indentation (│ and spaces from ancestors)
 // isLast indicates if this is the last child of its parent
-func renderSpanRow(sb *strings.Builder, span *spanInfo, traceStart time.Time, prefix string, isLast bool) {
+// depth is the level of the span, roots being level 1
+func renderSpanRow(sb *strings.Builder, span *spanInfo, traceStart time.Time, prefix string, isLast bool, depth int, opts waterfallOptions) {
 	// Calculate timing
 	duration := span.endTime.Sub(span.startTime)
 	startOffset := span.startTime.Sub(traceStart)
@@ -730,6 +1198,12 @@ func renderSpanRow(sb *strings.Builder, span *spanInfo, traceStart time.Time, pr
 	durationStr := formatDuration(duration)
 	startStr := fmt.Sprintf("%.3fs", startOffset.Seconds())
 
+	name := span.name
+	if span.repeats > 1 {
+		name = fmt.Sprintf("%s ×%d", name, span.repeats)
+		durationStr += " total"
+	}
+
 	// Truncate span ID for display
 	spanIDShort := span.spanID
 	if len(spanIDShort) > 8 {
@@ -737,7 +1211,7 @@ func renderSpanRow(sb *strings.Builder, span *spanInfo, traceStart time.Time, pr
 	}
 
 	// Format attributes
-	attrs := formatAttributesMap(span.attributes, 50)
+	attrs := formatAttributesMap(span.attributes, 50, nil, nil)
 
 	// Build the tree character for this span
 	treeChar := ""
@@ -753,30 +1227,54 @@ func renderSpanRow(sb *strings.Builder, span *spanInfo, traceStart time.Time, pr
 	fmt.Fprintf(sb, "| %s%s%s | %s | %s | %s | %s | %s |\n",
 		prefix,
 		treeChar,
-		span.name,
+		name,
 		spanIDShort,
 		durationStr,
 		startStr,
-		span.status,
+		span.statusLabel(),
 		attrs)
 
-	// Render children with updated indentation
-	for i, child := range span.children {
-		isChildLast := i == len(span.children)-1
-
-		// Build child's prefix (indentation only, no tree character)
-		childPrefix := prefix
-		if prefix != "" || len(span.children) > 0 {
-			// Add continuation or space based on whether this span has more siblings
-			if isLast {
-				childPrefix += "   "
-			} else {
-				childPrefix += "│  "
-			}
+	children := span.children
+	if opts.collapse {
+		children = collapseRepeats(children)
+	}
+
+	// Build the children's prefix (indentation only, no tree character)
+	childPrefix := prefix
+	if prefix != "" || len(children) > 0 {
+		// Add continuation or space based on whether this span has more siblings
+		if isLast {
+			childPrefix += "   "
+		} else {
+			childPrefix += "│  "
+		}
+	}
+
+	// At the depth cap the subtree is summarized in place of the children
+	if opts.maxDepth > 0 && depth >= opts.maxDepth && len(children) > 0 {
+		deeper := countDescendants(span)
+		noun := "spans"
+		if deeper == 1 {
+			noun = "span"
 		}
+		fmt.Fprintf(sb, "| %s└─ … %d deeper %s | | | | | |\n", childPrefix, deeper, noun)
+		return
+	}
+
+	// Render children with updated indentation
+	for i, child := range children {
+		isChildLast := i == len(children)-1
+		renderSpanRow(sb, child, traceStart, childPrefix, isChildLast, depth+1, opts)
+	}
+}
 
-		renderSpanRow(sb, child, traceStart, childPrefix, isChildLast)
+// countDescendants returns the number of spans below span
+func countDescendants(span *spanInfo) int {
+	count := 0
+	for _, child := range span.children {
+		count += 1 + countDescendants(child)
 	}
+	return count
 }
 
 // formatDuration formats duration in a human-readable way
@@ -793,22 +1291,25 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
-// formatAttributesMap formats attribute map as compact string
-func formatAttributesMap(attrs map[string]string, maxLen int) string {
-	if len(attrs) == 0 {
-		return "-"
-	}
-
+// formatAttributesMap formats attribute map as compact string, truncated to
+// maxLen characters (0 disables truncation). Values of the keys matched by
+// redact are redacted.
+func formatAttributesMap(attrs map[string]string, maxLen int, keep, redact func(key string) bool) string {
 	var parts []string
 	for k, v := range attrs {
-		parts = append(parts, fmt.Sprintf("%s=%s", k, v))
+		if keep == nil || keep(k) {
+			parts = append(parts, fmt.Sprintf("%s=%s", k, redactValue(k, v, redact)))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
 	}
 
 	// Sort for consistent output
 	sort.Strings(parts)
 
 	result := strings.Join(parts, " ")
-	if len(result) > maxLen {
+	if maxLen > 0 && len(result) > maxLen {
 		result = result[:maxLen] + "..."
 	}
 
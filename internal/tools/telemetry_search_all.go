@@ -0,0 +1,341 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type SearchAllInput struct {
+	Query     string   `json:"query" jsonschema:"Text to search for (case-insensitive substring),required"`
+	Signals   []string `json:"signals,omitempty" jsonschema:"Signals to search (traces logs metrics). Omit for all"`
+	StartTime string   `json:"start_time,omitempty" jsonschema:"Only include telemetry at or after this time (RFC3339)"`
+	EndTime   string   `json:"end_time,omitempty" jsonschema:"Only include telemetry at or before this time (RFC3339)"`
+	Limit     int      `json:"limit,omitempty" jsonschema:"Maximum number of matches to return per signal,20"`
+}
+
+// SearchMatch is a single piece of telemetry that matched a search_all query
+type SearchMatch struct {
+	Service   string `json:"service"`
+	Name      string `json:"name"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Field     string `json:"field"`
+	Value     string `json:"value"`
+	TraceID   string `json:"trace_id,omitempty"`
+	SpanID    string `json:"span_id,omitempty"`
+}
+
+type SearchAllOutput struct {
+	Query      string        `json:"query"`
+	MatchCount int           `json:"match_count"`
+	Traces     []SearchMatch `json:"traces,omitempty"`
+	Logs       []SearchMatch `json:"logs,omitempty"`
+	Metrics    []SearchMatch `json:"metrics,omitempty"`
+	Truncated  bool          `json:"truncated,omitempty"`
+}
+
+// RegisterSearchAll registers the search_all tool
+func RegisterSearchAll(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[SearchAllInput, SearchAllOutput](server, &mcp.Tool{
+		Name:        "search_all",
+		Description: "Search traces, logs and metrics for a text in one call. Matches span names and attributes, log bodies and attributes, and metric names and data point attributes. Each match reports the field that matched. Use as the entry point for open-ended investigations (e.g. an order ID), then drill down with the per-signal query tools.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input SearchAllInput) (*mcp.CallToolResult, SearchAllOutput, error) {
+		if input.Query == "" {
+			return nil, SearchAllOutput{}, errors.New("query is required")
+		}
+		limit, err := resolveLimit(ext, input.Limit, 20)
+		if err != nil {
+			return nil, SearchAllOutput{}, err
+		}
+		from, to, err := parseTimeRange(input.StartTime, input.EndTime)
+		if err != nil {
+			return nil, SearchAllOutput{}, err
+		}
+
+		signals := map[string]bool{"traces": len(input.Signals) == 0, "logs": len(input.Signals) == 0, "metrics": len(input.Signals) == 0}
+		for _, signal := range input.Signals {
+			signal = strings.ToLower(strings.TrimSpace(signal))
+			if _, ok := signals[signal]; !ok {
+				return nil, SearchAllOutput{}, fmt.Errorf("invalid signal: %s (must be traces, logs, or metrics)", signal)
+			}
+			signals[signal] = true
+		}
+
+		query := strings.ToLower(input.Query)
+		output := SearchAllOutput{Query: input.Query}
+
+		var truncated bool
+		if signals["traces"] {
+			output.Traces, truncated = searchAllTraces(ctx, ext, query, from, to, limit)
+			output.Truncated = output.Truncated || truncated
+		}
+		if signals["logs"] {
+			output.Logs, truncated = searchAllLogs(ctx, ext, query, from, to, limit)
+			output.Truncated = output.Truncated || truncated
+		}
+		if signals["metrics"] {
+			output.Metrics, truncated = searchAllMetrics(ctx, ext, query, from, to, limit)
+			output.Truncated = output.Truncated || truncated
+		}
+		output.MatchCount = len(output.Traces) + len(output.Logs) + len(output.Metrics)
+
+		return nil, output, nil
+	})
+}
+
+func searchAllTraces(ctx context.Context, ext ExtensionContext, query string, from, to pcommon.Timestamp, limit int) ([]SearchMatch, bool) {
+	var matches []SearchMatch
+
+	for _, td := range ext.GetRecentTraces(10000, 0) {
+		if scanInterrupted(ctx) {
+			return matches, true
+		}
+
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			rs := td.ResourceSpans().At(i)
+			serviceName := "unknown"
+			if sn, ok := rs.Resource().Attributes().Get("service.name"); ok {
+				serviceName = sn.AsString()
+			}
+
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				spans := rs.ScopeSpans().At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if len(matches) >= limit {
+						return matches, false
+					}
+
+					span := spans.At(k)
+					if !inTimeRange(span.StartTimestamp(), from, to) {
+						continue
+					}
+
+					field, value, ok := "name", span.Name(), strings.Contains(strings.ToLower(span.Name()), query)
+					if !ok {
+						field, value, ok = matchAttributes(span.Attributes(), query, ext.IsRedactedAttribute)
+					}
+					if !ok {
+						continue
+					}
+
+					matches = append(matches, SearchMatch{
+						Service:   serviceName,
+						Name:      span.Name(),
+						Timestamp: formatSearchTimestamp(span.StartTimestamp()),
+						Field:     field,
+						Value:     truncateString(value, 80),
+						TraceID:   span.TraceID().String(),
+						SpanID:    span.SpanID().String(),
+					})
+				}
+			}
+		}
+	}
+
+	return matches, false
+}
+
+func searchAllLogs(ctx context.Context, ext ExtensionContext, query string, from, to pcommon.Timestamp, limit int) ([]SearchMatch, bool) {
+	var matches []SearchMatch
+
+	for _, ld := range ext.GetRecentLogs(10000, 0) {
+		if scanInterrupted(ctx) {
+			return matches, true
+		}
+
+		for i := 0; i < ld.ResourceLogs().Len(); i++ {
+			rl := ld.ResourceLogs().At(i)
+			serviceName := "unknown"
+			if sn, ok := rl.Resource().Attributes().Get("service.name"); ok {
+				serviceName = sn.AsString()
+			}
+
+			for j := 0; j < rl.ScopeLogs().Len(); j++ {
+				records := rl.ScopeLogs().At(j).LogRecords()
+				for k := 0; k < records.Len(); k++ {
+					if len(matches) >= limit {
+						return matches, false
+					}
+
+					lr := records.At(k)
+					ts := lr.Timestamp()
+					if ts == 0 {
+						ts = lr.ObservedTimestamp()
+					}
+					if !inTimeRange(ts, from, to) {
+						continue
+					}
+
+					body := lr.Body().AsString()
+					field, value, ok := "body", body, strings.Contains(strings.ToLower(body), query)
+					if !ok {
+						field, value, ok = matchAttributes(lr.Attributes(), query, ext.IsRedactedAttribute)
+					}
+					if !ok {
+						continue
+					}
+
+					match := SearchMatch{
+						Service:   serviceName,
+						Name:      lr.SeverityText(),
+						Timestamp: formatSearchTimestamp(ts),
+						Field:     field,
+						Value:     truncateString(value, 80),
+					}
+					if !lr.TraceID().IsEmpty() {
+						match.TraceID = lr.TraceID().String()
+						match.SpanID = lr.SpanID().String()
+					}
+					matches = append(matches, match)
+				}
+			}
+		}
+	}
+
+	return matches, false
+}
+
+func searchAllMetrics(ctx context.Context, ext ExtensionContext, query string, from, to pcommon.Timestamp, limit int) ([]SearchMatch, bool) {
+	var matches []SearchMatch
+
+	for _, md := range ext.GetRecentMetrics(10000, 0) {
+		if scanInterrupted(ctx) {
+			return matches, true
+		}
+
+		for i := 0; i < md.ResourceMetrics().Len(); i++ {
+			rm := md.ResourceMetrics().At(i)
+			serviceName := "unknown"
+			if sn, ok := rm.Resource().Attributes().Get("service.name"); ok {
+				serviceName = sn.AsString()
+			}
+
+			for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+				metrics := rm.ScopeMetrics().At(j).Metrics()
+				for k := 0; k < metrics.Len(); k++ {
+					if len(matches) >= limit {
+						return matches, false
+					}
+
+					metric := metrics.At(k)
+					nameMatches := strings.Contains(strings.ToLower(metric.Name()), query)
+
+					// Report the first data point in range that matches by name or attributes
+					var match *SearchMatch
+					forEachDataPoint(metric, func(attrs pcommon.Map, ts pcommon.Timestamp) bool {
+						if !inTimeRange(ts, from, to) {
+							return true
+						}
+						field, value, ok := "name", metric.Name(), nameMatches
+						if !ok {
+							field, value, ok = matchAttributes(attrs, query, ext.IsRedactedAttribute)
+						}
+						if !ok {
+							return true
+						}
+						match = &SearchMatch{
+							Service:   serviceName,
+							Name:      metric.Name(),
+							Timestamp: formatSearchTimestamp(ts),
+							Field:     field,
+							Value:     truncateString(value, 80),
+						}
+						return false
+					})
+					if match != nil {
+						matches = append(matches, *match)
+					}
+				}
+			}
+		}
+	}
+
+	return matches, false
+}
+
+// matchAttributes returns the field and value of the first attribute whose key
+// or value contains the lowercase query. The values of keys matched by redact
+// are never searched, so matches cannot reveal them, and are returned redacted.
+func matchAttributes(attrs pcommon.Map, query string, redact func(key string) bool) (string, string, bool) {
+	var field, value string
+	found := false
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		if redact != nil && redact(k) {
+			if strings.Contains(strings.ToLower(k), query) {
+				field, value, found = "attributes."+k, redactedValue, true
+				return false
+			}
+			return true
+		}
+		s := v.AsString()
+		if strings.Contains(strings.ToLower(k), query) || strings.Contains(strings.ToLower(s), query) {
+			field, value, found = "attributes."+k, s, true
+			return false
+		}
+		return true
+	})
+	return field, value, found
+}
+
+// forEachDataPoint calls fn with the attributes and timestamp of every data
+// point of a metric until fn returns false
+func forEachDataPoint(metric pmetric.Metric, fn func(attrs pcommon.Map, ts pcommon.Timestamp) bool) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if !fn(dps.At(i).Attributes(), dps.At(i).Timestamp()) {
+				return
+			}
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if !fn(dps.At(i).Attributes(), dps.At(i).Timestamp()) {
+				return
+			}
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if !fn(dps.At(i).Attributes(), dps.At(i).Timestamp()) {
+				return
+			}
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if !fn(dps.At(i).Attributes(), dps.At(i).Timestamp()) {
+				return
+			}
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if !fn(dps.At(i).Attributes(), dps.At(i).Timestamp()) {
+				return
+			}
+		}
+	}
+}
+
+func formatSearchTimestamp(ts pcommon.Timestamp) string {
+	if ts == 0 {
+		return ""
+	}
+	return time.Unix(0, int64(ts)).UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type GetMetricSeriesDetailInput struct {
+	MetricName  string            `json:"metric_name" jsonschema:"Metric name (exact match),required"`
+	ServiceName string            `json:"service_name,omitempty" jsonschema:"Only return series of this service"`
+	Attributes  map[string]string `json:"attributes,omitempty" jsonschema:"Only return series with these attribute values (exact match on the string form), e.g. {'http.route': '/cart'}"`
+	Points      int               `json:"points,omitempty" jsonschema:"Number of latest data points returned per series,5"`
+	Limit       int               `json:"limit,omitempty" jsonschema:"Maximum number of series to return,20"`
+}
+
+// SeriesPoint is a data point of a series. Gauge and Sum points set Value,
+// Histogram, ExponentialHistogram and Summary points set Count and Sum.
+type SeriesPoint struct {
+	Timestamp string   `json:"timestamp"`
+	Value     *float64 `json:"value,omitempty"`
+	Count     *uint64  `json:"count,omitempty"`
+	Sum       *float64 `json:"sum,omitempty"`
+}
+
+// MetricSeriesDetail is one time series of a metric, identified by its service
+// and full data point attribute set
+type MetricSeriesDetail struct {
+	ServiceName string            `json:"service_name"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	// ResourceAttributes are the attributes of the series' resource, part of
+	// its identity
+	ResourceAttributes map[string]string `json:"resource_attributes,omitempty"`
+	DataPoints         int               `json:"data_points"`
+	// Latest holds the latest points, oldest first
+	Latest []SeriesPoint `json:"latest"`
+}
+
+type GetMetricSeriesDetailOutput struct {
+	MetricName  string               `json:"metric_name"`
+	Type        string               `json:"type,omitempty"`
+	Unit        string               `json:"unit,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Found       bool                 `json:"found"`
+	SeriesCount int                  `json:"series_count"`
+	Series      []MetricSeriesDetail `json:"series"`
+	Truncated   bool                 `json:"truncated,omitempty"`
+}
+
+// seriesPoint is a buffered data point of a series
+type seriesPoint struct {
+	ts    pcommon.Timestamp
+	point SeriesPoint
+}
+
+// RegisterGetMetricSeriesDetail registers the get_metric_series_detail tool
+func RegisterGetMetricSeriesDetail(server *mcp.Server, ext ExtensionContext) {
+	mcp.AddTool[GetMetricSeriesDetailInput, GetMetricSeriesDetailOutput](server, &mcp.Tool{
+		Name:        "get_metric_series_detail",
+		Description: "Get every time series of a metric, one per resource and data point attribute set, with the full untruncated attribute map, the resource attributes, the number of buffered points and the latest values with timestamps. Use to tell apart the series of a high-cardinality metric that query_metrics summarizes in one row.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  boolPtr(false),
+		},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, input GetMetricSeriesDetailInput) (*mcp.CallToolResult, GetMetricSeriesDetailOutput, error) {
+		if input.MetricName == "" {
+			return nil, GetMetricSeriesDetailOutput{}, errors.New("metric_name is required")
+		}
+		if err := checkBufferEnabled(ext, "metrics"); err != nil {
+			return nil, GetMetricSeriesDetailOutput{}, err
+		}
+		limit, err := resolveLimit(ext, input.Limit, 20)
+		if err != nil {
+			return nil, GetMetricSeriesDetailOutput{}, err
+		}
+		pointCount := input.Points
+		if pointCount <= 0 {
+			pointCount = 5
+		}
+
+		output := GetMetricSeriesDetailOutput{MetricName: input.MetricName}
+		points := make(map[string][]seriesPoint)
+		series := make(map[string]*MetricSeriesDetail)
+
+		for _, md := range ext.GetRecentMetrics(10000, 0) {
+			if scanInterrupted(ctx) {
+				output.Truncated = true
+				break
+			}
+
+			for i := 0; i < md.ResourceMetrics().Len(); i++ {
+				rm := md.ResourceMetrics().At(i)
+				serviceName := "unknown"
+				if sn, ok := rm.Resource().Attributes().Get("service.name"); ok {
+					serviceName = sn.AsString()
+				}
+				if input.ServiceName != "" && serviceName != input.ServiceName {
+					continue
+				}
+
+				for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+					metrics := rm.ScopeMetrics().At(j).Metrics()
+					for k := 0; k < metrics.Len(); k++ {
+						metric := metrics.At(k)
+						if metric.Name() != input.MetricName {
+							continue
+						}
+						if !output.Found {
+							output.Found = true
+							output.Type = metricTypeLabel(metric)
+							output.Unit = metric.Unit()
+							output.Description = metric.Description()
+						}
+
+						forEachSeriesPoint(metric, func(attrs pcommon.Map, ts pcommon.Timestamp, point SeriesPoint) {
+							if !attributesMatch(attrs, input.Attributes) {
+								return
+							}
+							key := seriesKey(rm.Resource().Attributes(), attrs)
+							if series[key] == nil {
+								series[key] = &MetricSeriesDetail{
+									ServiceName:        serviceName,
+									Attributes:         attributesStrings(attrs, ext.IsRedactedAttribute),
+									ResourceAttributes: attributesStrings(rm.Resource().Attributes(), ext.IsRedactedAttribute),
+								}
+							}
+							point.Timestamp = formatSearchTimestamp(ts)
+							points[key] = append(points[key], seriesPoint{ts: ts, point: point})
+						})
+					}
+				}
+			}
+		}
+
+		output.SeriesCount = len(series)
+		output.Series = make([]MetricSeriesDetail, 0, len(series))
+		for key, s := range series {
+			seriesPoints := points[key]
+			sort.SliceStable(seriesPoints, func(i, j int) bool { return seriesPoints[i].ts < seriesPoints[j].ts })
+			s.DataPoints = len(seriesPoints)
+			latest := seriesPoints[max(0, len(seriesPoints)-pointCount):]
+			s.Latest = make([]SeriesPoint, 0, len(latest))
+			for _, p := range latest {
+				s.Latest = append(s.Latest, p.point)
+			}
+			output.Series = append(output.Series, *s)
+		}
+		sort.Slice(output.Series, func(i, j int) bool {
+			if output.Series[i].ServiceName != output.Series[j].ServiceName {
+				return output.Series[i].ServiceName < output.Series[j].ServiceName
+			}
+			if a, b := attributesLabel(output.Series[i].Attributes), attributesLabel(output.Series[j].Attributes); a != b {
+				return a < b
+			}
+			return attributesLabel(output.Series[i].ResourceAttributes) < attributesLabel(output.Series[j].ResourceAttributes)
+		})
+		if len(output.Series) > limit {
+			output.Series = output.Series[:limit]
+		}
+
+		return nil, output, nil
+	})
+}
+
+// forEachSeriesPoint calls fn with the attributes, timestamp and value of every
+// data point of a metric
+func forEachSeriesPoint(metric pmetric.Metric, fn func(attrs pcommon.Map, ts pcommon.Timestamp, point SeriesPoint)) {
+	countSum := func(count uint64, sum float64) SeriesPoint {
+		return SeriesPoint{Count: &count, Sum: &sum}
+	}
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		var dps pmetric.NumberDataPointSlice
+		if metric.Type() == pmetric.MetricTypeSum {
+			dps = metric.Sum().DataPoints()
+		} else {
+			dps = metric.Gauge().DataPoints()
+		}
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			value := dp.DoubleValue()
+			if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+				value = float64(dp.IntValue())
+			}
+			fn(dp.Attributes(), dp.Timestamp(), SeriesPoint{Value: &value})
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), dps.At(i).Timestamp(), countSum(dps.At(i).Count(), dps.At(i).Sum()))
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), dps.At(i).Timestamp(), countSum(dps.At(i).Count(), dps.At(i).Sum()))
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), dps.At(i).Timestamp(), countSum(dps.At(i).Count(), dps.At(i).Sum()))
+		}
+	}
+}
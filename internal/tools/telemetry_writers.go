@@ -4,7 +4,11 @@
 package tools
 
 import (
+	"encoding/csv"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,11 +21,18 @@ import (
 // TraceWriter formats trace data in various output modes
 type TraceWriter struct {
 	traceStart time.Time
+	// Location is the time zone timestamps are rendered in, UTC when nil
+	Location *time.Location
+	// KeepAttribute decides which attribute keys are rendered, all when nil
+	KeepAttribute func(key string) bool
+	// RedactAttribute decides which attribute values are replaced by
+	// [REDACTED], none when nil
+	RedactAttribute func(key string) bool
 }
 
 // WriteSpanSummary writes a single span as a table row
 func (w *TraceWriter) WriteSpanSummary(sb *strings.Builder, span ptrace.Span, _, prefix string, isLast bool) {
-	info := extractSpanInfo(span)
+	info := extractSpanInfo(span, w.KeepAttribute, w.RedactAttribute)
 
 	duration := info.endTime.Sub(info.startTime)
 	startOffset := info.startTime.Sub(w.traceStart)
@@ -32,7 +43,7 @@ func (w *TraceWriter) WriteSpanSummary(sb *strings.Builder, span ptrace.Span, _,
 	if len(spanIDShort) > 8 {
 		spanIDShort = spanIDShort[:8]
 	}
-	attrs := formatAttributesMap(info.attributes, 50)
+	attrs := formatAttributesMap(info.attributes, 50, nil, w.RedactAttribute)
 
 	treeChar := ""
 	if prefix != "" {
@@ -44,11 +55,34 @@ func (w *TraceWriter) WriteSpanSummary(sb *strings.Builder, span ptrace.Span, _,
 	}
 
 	fmt.Fprintf(sb, "| %s%s%s | %s | %s | %s | %s | %s |\n",
-		prefix, treeChar, info.name, spanIDShort, durationStr, startStr, info.status, attrs)
+		prefix, treeChar, info.name, spanIDShort, durationStr, startStr, info.statusLabel(), attrs)
 }
 
-// WriteSpanDetailed writes full details of a span in markdown
-func (*TraceWriter) WriteSpanDetailed(sb *strings.Builder, span ptrace.Span, _ string, resourceAttrs pcommon.Map) {
+// WriteSpanEventRows writes the events of a span as indented rows of the
+// query_traces span table: the event name, its time since the span start in
+// the Duration column and its first 5 attributes accepted by KeepAttribute.
+// extraColumns is the number of
+// resource attribute columns, left empty.
+func (w *TraceWriter) WriteSpanEventRows(sb *strings.Builder, span ptrace.Span, maxAttrLen, extraColumns int) {
+	for i := 0; i < span.Events().Len(); i++ {
+		event := span.Events().At(i)
+		attrs := make(map[string]string)
+		event.Attributes().Range(func(k string, v pcommon.Value) bool {
+			if len(attrs) < 5 && (w.KeepAttribute == nil || w.KeepAttribute(k)) {
+				attrs[k] = v.AsString()
+			}
+			return true
+		})
+		offset := event.Timestamp().AsTime().Sub(span.StartTimestamp().AsTime())
+		fmt.Fprintf(sb, "| ↳ %s | | +%s | | | %s |%s\n",
+			event.Name(), formatDuration(offset), formatAttributesMap(attrs, maxAttrLen, nil, w.RedactAttribute),
+			strings.Repeat(" |", extraColumns))
+	}
+}
+
+// WriteSpanDetailed writes full details of a span in markdown. When attributeKeys
+// is set, the span attribute table only lists those keys in the given order.
+func (w *TraceWriter) WriteSpanDetailed(sb *strings.Builder, span ptrace.Span, _ string, resourceAttrs pcommon.Map, attributeKeys []string) {
 	fmt.Fprintf(sb, "## Span: %s\n\n", span.Name())
 	fmt.Fprintf(sb, "**Trace ID:** `%s`\n\n", span.TraceID().String())
 	fmt.Fprintf(sb, "**Span ID:** `%s`\n\n", span.SpanID().String())
@@ -63,31 +97,27 @@ func (*TraceWriter) WriteSpanDetailed(sb *strings.Builder, span ptrace.Span, _ s
 	startTime := time.Unix(0, int64(span.StartTimestamp()))
 	endTime := time.Unix(0, int64(span.EndTimestamp()))
 	duration := endTime.Sub(startTime)
-	fmt.Fprintf(sb, "**Start:** %s\n\n", startTime.Format(time.RFC3339Nano))
-	fmt.Fprintf(sb, "**End:** %s\n\n", endTime.Format(time.RFC3339Nano))
+	fmt.Fprintf(sb, "**Start:** %s\n\n", formatTimestamp(span.StartTimestamp(), w.Location, time.RFC3339Nano))
+	fmt.Fprintf(sb, "**End:** %s\n\n", formatTimestamp(span.EndTimestamp(), w.Location, time.RFC3339Nano))
 	fmt.Fprintf(sb, "**Duration:** %s\n\n", formatDuration(duration))
 
-	if span.Attributes().Len() > 0 {
+	if len(attributeKeys) > 0 {
 		sb.WriteString("### Span Attributes\n\n")
 		sb.WriteString("| Key | Value |\n")
 		sb.WriteString("|-----|-------|\n")
-		span.Attributes().Range(func(k string, v pcommon.Value) bool {
-			fmt.Fprintf(sb, "| %s | %s |\n", k, v.AsString())
-			return true
-		})
+		for _, k := range attributeKeys {
+			value := "-"
+			if v, ok := span.Attributes().Get(k); ok {
+				value = redactValue(k, v.AsString(), w.RedactAttribute)
+			}
+			fmt.Fprintf(sb, "| %s | %s |\n", k, value)
+		}
 		sb.WriteString("\n")
+	} else {
+		writeAttributeTable(sb, "Span Attributes", span.Attributes(), w.KeepAttribute, w.RedactAttribute)
 	}
 
-	if resourceAttrs.Len() > 0 {
-		sb.WriteString("### Resource Attributes\n\n")
-		sb.WriteString("| Key | Value |\n")
-		sb.WriteString("|-----|-------|\n")
-		resourceAttrs.Range(func(k string, v pcommon.Value) bool {
-			fmt.Fprintf(sb, "| %s | %s |\n", k, v.AsString())
-			return true
-		})
-		sb.WriteString("\n")
-	}
+	writeAttributeTable(sb, "Resource Attributes", resourceAttrs, w.KeepAttribute, w.RedactAttribute)
 
 	if span.Events().Len() > 0 {
 		sb.WriteString("### Events\n\n")
@@ -95,13 +125,12 @@ func (*TraceWriter) WriteSpanDetailed(sb *strings.Builder, span ptrace.Span, _ s
 		sb.WriteString("|------|------|------------|\n")
 		for i := 0; i < span.Events().Len(); i++ {
 			event := span.Events().At(i)
-			eventTime := time.Unix(0, int64(event.Timestamp()))
-			attrs := formatAttributes(event.Attributes())
+			attrs := formatAttributes(event.Attributes(), w.KeepAttribute, w.RedactAttribute)
 			if attrs == "" {
 				attrs = "-"
 			}
 			fmt.Fprintf(sb, "| %s | %s | %s |\n",
-				eventTime.Format("15:04:05.000"), event.Name(), attrs)
+				formatTimestamp(event.Timestamp(), w.Location, "15:04:05.000"), event.Name(), attrs)
 		}
 		sb.WriteString("\n")
 	}
@@ -112,7 +141,7 @@ func (*TraceWriter) WriteSpanDetailed(sb *strings.Builder, span ptrace.Span, _ s
 		sb.WriteString("|----------|---------|------------|\n")
 		for i := 0; i < span.Links().Len(); i++ {
 			link := span.Links().At(i)
-			attrs := formatAttributes(link.Attributes())
+			attrs := formatAttributes(link.Attributes(), w.KeepAttribute, w.RedactAttribute)
 			if attrs == "" {
 				attrs = "-"
 			}
@@ -128,12 +157,21 @@ func (*TraceWriter) WriteSpanDetailed(sb *strings.Builder, span ptrace.Span, _ s
 }
 
 // LogWriter formats log data in various output modes
-type LogWriter struct{}
+type LogWriter struct {
+	// Location is the time zone timestamps are rendered in, UTC when nil
+	Location *time.Location
+	// KeepAttribute decides which attribute keys are rendered, all when nil
+	KeepAttribute func(key string) bool
+	// RedactAttribute decides which attribute values are replaced by
+	// [REDACTED], none when nil
+	RedactAttribute func(key string) bool
+}
 
-// WriteLogSummary writes a single log as a table row
-func (*LogWriter) WriteLogSummary(sb *strings.Builder, lr plog.LogRecord, serviceName string) {
-	timestamp := time.Unix(0, int64(lr.Timestamp()))
-	timeStr := timestamp.Format("15:04:05.000")
+// WriteLogSummary writes a single log as a table row, followed by a column per
+// key of resourceKeys. Attributes longer than maxAttrLen are truncated; 0
+// disables truncation.
+func (w *LogWriter) WriteLogSummary(sb *strings.Builder, lr plog.LogRecord, id, serviceName string, maxAttrLen int, resourceAttrs pcommon.Map, resourceKeys []string) {
+	timeStr := formatTimestamp(lr.Timestamp(), w.Location, "15:04:05.000")
 
 	traceID := lr.TraceID().String()
 	traceIDShort := "-"
@@ -141,25 +179,58 @@ func (*LogWriter) WriteLogSummary(sb *strings.Builder, lr plog.LogRecord, servic
 		traceIDShort = traceID[:8] + "..."
 	}
 
-	attrs := formatAttributes(lr.Attributes())
+	attrs := formatAttributes(lr.Attributes(), w.KeepAttribute, w.RedactAttribute)
 	if attrs == "" {
 		attrs = "-"
-	} else if len(attrs) > 40 {
-		attrs = attrs[:40] + "..."
+	} else if maxAttrLen > 0 && len(attrs) > maxAttrLen {
+		attrs = attrs[:maxAttrLen] + "..."
 	}
 
 	body := truncateString(lr.Body().AsString(), 50)
 
-	fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s |\n",
-		timeStr, lr.SeverityText(), serviceName, body, traceIDShort, attrs)
+	fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s | %s |%s\n",
+		id, timeStr, lr.SeverityText(), serviceName, body, traceIDShort, attrs,
+		resourceColumnCells(resourceAttrs, resourceKeys, w.RedactAttribute))
 }
 
-// WriteLogDetailed writes full details of a log in markdown
-func (*LogWriter) WriteLogDetailed(sb *strings.Builder, lr plog.LogRecord, serviceName string, resourceAttrs pcommon.Map) {
-	timestamp := time.Unix(0, int64(lr.Timestamp()))
+// resourceColumnHeader returns the header cells of the optional resource
+// attribute columns, to append to a table header row
+func resourceColumnHeader(keys []string) string {
+	var sb strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&sb, " %s |", key)
+	}
+	return sb.String()
+}
 
+// resourceColumnSeparator returns the separator cells of the optional resource
+// attribute columns, to append to a table separator row
+func resourceColumnSeparator(keys []string) string {
+	return strings.Repeat("---|", len(keys))
+}
+
+// resourceColumnCells returns a row's cells for the optional resource attribute
+// columns, "-" when the resource lacks the key
+func resourceColumnCells(attrs pcommon.Map, keys []string, redact func(key string) bool) string {
+	var sb strings.Builder
+	for _, key := range keys {
+		value := "-"
+		if v, ok := attrs.Get(key); ok && v.AsString() != "" {
+			value = redactValue(key, v.AsString(), redact)
+		}
+		fmt.Fprintf(&sb, " %s |", value)
+	}
+	return sb.String()
+}
+
+// WriteLogDetailed writes full details of a log in markdown. The ID line is
+// omitted when id is empty.
+func (w *LogWriter) WriteLogDetailed(sb *strings.Builder, lr plog.LogRecord, id, serviceName string, resourceAttrs pcommon.Map) {
 	fmt.Fprintf(sb, "## Log Entry: %s\n\n", lr.SeverityText())
-	fmt.Fprintf(sb, "**Timestamp:** %s\n\n", timestamp.Format(time.RFC3339Nano))
+	if id != "" {
+		fmt.Fprintf(sb, "**Log ID:** `%s`\n\n", id)
+	}
+	fmt.Fprintf(sb, "**Timestamp:** %s\n\n", formatTimestamp(lr.Timestamp(), w.Location, time.RFC3339Nano))
 	fmt.Fprintf(sb, "**Severity:** %s (%d)\n\n", lr.SeverityText(), lr.SeverityNumber())
 	fmt.Fprintf(sb, "**Service:** %s\n\n", serviceName)
 
@@ -173,87 +244,331 @@ func (*LogWriter) WriteLogDetailed(sb *strings.Builder, lr plog.LogRecord, servi
 	sb.WriteString("### Body\n\n")
 	fmt.Fprintf(sb, "```\n%s\n```\n\n", lr.Body().AsString())
 
-	if lr.Attributes().Len() > 0 {
-		sb.WriteString("### Log Attributes\n\n")
-		sb.WriteString("| Key | Value |\n")
-		sb.WriteString("|-----|-------|\n")
-		lr.Attributes().Range(func(k string, v pcommon.Value) bool {
-			fmt.Fprintf(sb, "| %s | %s |\n", k, v.AsString())
-			return true
-		})
-		sb.WriteString("\n")
-	}
+	writeAttributeTable(sb, "Log Attributes", lr.Attributes(), w.KeepAttribute, w.RedactAttribute)
 
-	if resourceAttrs.Len() > 0 {
-		sb.WriteString("### Resource Attributes\n\n")
-		sb.WriteString("| Key | Value |\n")
-		sb.WriteString("|-----|-------|\n")
-		resourceAttrs.Range(func(k string, v pcommon.Value) bool {
-			fmt.Fprintf(sb, "| %s | %s |\n", k, v.AsString())
-			return true
-		})
-		sb.WriteString("\n")
-	}
+	writeAttributeTable(sb, "Resource Attributes", resourceAttrs, w.KeepAttribute, w.RedactAttribute)
 
 	sb.WriteString("---\n\n")
 }
 
+// WriteLogPlain writes a log body as a single line, optionally prefixed with
+// "[severity] timestamp: ". Newlines in the body are folded into spaces.
+func (w *LogWriter) WriteLogPlain(sb *strings.Builder, lr plog.LogRecord, prefix bool) {
+	if prefix {
+		fmt.Fprintf(sb, "[%s] %s: ", lr.SeverityText(), formatTimestamp(lr.Timestamp(), w.Location, time.RFC3339Nano))
+	}
+	body := strings.ReplaceAll(lr.Body().AsString(), "\r\n", " ")
+	sb.WriteString(strings.ReplaceAll(body, "\n", " "))
+	sb.WriteString("\n")
+}
+
 // MetricWriter formats metric data in various output modes
-type MetricWriter struct{}
+type MetricWriter struct {
+	// Location is the time zone timestamps are rendered in, UTC when nil
+	Location *time.Location
+	// KeepAttribute decides which attribute keys are rendered, all when nil
+	KeepAttribute func(key string) bool
+	// RedactAttribute decides which attribute values are replaced by
+	// [REDACTED], none when nil
+	RedactAttribute func(key string) bool
+	// AttributeColumns are data point attribute keys WriteMetricCSV writes to
+	// their own columns after attributes instead of into it
+	AttributeColumns []string
+}
+
+// WriteMetricSummary writes the data points of a metric chosen by selection
+// ("first", "last" or "all") as table rows. Attributes longer than maxAttrLen
+// are truncated; 0 disables truncation.
+func (w *MetricWriter) WriteMetricSummary(sb *strings.Builder, metric pmetric.Metric, serviceName string, maxAttrLen int, selection string) {
+	indices := selectDataPoints(metric, selection)
+	if len(indices) == 0 {
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | - | - |\n",
+			metric.Name(), metricTypeLabel(metric), serviceName, unitLabel(metric.Unit()))
+		return
+	}
+
+	for _, i := range indices {
+		valueStr, attrs := summarizeDataPoint(metric, i)
+		attrStr := formatAttributes(attrs, w.KeepAttribute, w.RedactAttribute)
+		if maxAttrLen > 0 && len(attrStr) > maxAttrLen {
+			attrStr = attrStr[:maxAttrLen] + "..."
+		}
+		if attrStr == "" {
+			attrStr = "-"
+		}
+
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s |\n",
+			metric.Name(), metricTypeLabel(metric), serviceName, unitLabel(metric.Unit()), valueStr, attrStr)
+	}
+}
+
+// Data point selections for metric summaries
+const (
+	dataPointsFirst = "first"
+	dataPointsLast  = "last"
+	dataPointsAll   = "all"
+)
+
+// parseDataPointSelection validates a data_point_selection input. It defaults
+// to "last" so summaries show the current value.
+func parseDataPointSelection(selection string) (string, error) {
+	switch s := strings.ToLower(strings.TrimSpace(selection)); s {
+	case "":
+		return dataPointsLast, nil
+	case dataPointsFirst, dataPointsLast, dataPointsAll:
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid data_point_selection: %s (must be first, last, or all)", selection)
+	}
+}
 
-// WriteMetricSummary writes a single metric as a table row
-func (*MetricWriter) WriteMetricSummary(sb *strings.Builder, metric pmetric.Metric, serviceName string) {
-	// Extract value summary based on type
-	valueStr := "-"
-	attrStr := "-"
+// selectDataPoints returns the indices of the data points of metric to
+// summarize. "last" picks the data point with the latest timestamp, which is
+// not necessarily the final one in the slice; ties go to the later one.
+func selectDataPoints(metric pmetric.Metric, selection string) []int {
+	var timestamps []pcommon.Timestamp
+	forEachDataPoint(metric, func(_ pcommon.Map, ts pcommon.Timestamp) bool {
+		timestamps = append(timestamps, ts)
+		return true
+	})
+	if len(timestamps) == 0 {
+		return nil
+	}
 
+	switch selection {
+	case dataPointsFirst:
+		return []int{0}
+	case dataPointsAll:
+		indices := make([]int, len(timestamps))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	default:
+		latest := 0
+		for i, ts := range timestamps {
+			if ts >= timestamps[latest] {
+				latest = i
+			}
+		}
+		return []int{latest}
+	}
+}
+
+// summarizeDataPoint returns the formatted value and the attributes of the
+// i-th data point of metric
+func summarizeDataPoint(metric pmetric.Metric, i int) (string, pcommon.Map) {
 	switch metric.Type() {
 	case pmetric.MetricTypeSum:
-		sum := metric.Sum()
-		if sum.DataPoints().Len() > 0 {
-			dp := sum.DataPoints().At(0)
-			valueStr = fmt.Sprintf("%.2f", dp.DoubleValue())
-			attrStr = formatAttributes(dp.Attributes())
-		}
+		dp := metric.Sum().DataPoints().At(i)
+		return formatNumberDataPoint(dp), dp.Attributes()
 	case pmetric.MetricTypeGauge:
-		gauge := metric.Gauge()
-		if gauge.DataPoints().Len() > 0 {
-			dp := gauge.DataPoints().At(0)
-			valueStr = fmt.Sprintf("%.2f", dp.DoubleValue())
-			attrStr = formatAttributes(dp.Attributes())
+		dp := metric.Gauge().DataPoints().At(i)
+		return formatNumberDataPoint(dp), dp.Attributes()
+	case pmetric.MetricTypeHistogram:
+		dp := metric.Histogram().DataPoints().At(i)
+		return formatCountSum(dp.Count(), dp.Sum()), dp.Attributes()
+	case pmetric.MetricTypeExponentialHistogram:
+		dp := metric.ExponentialHistogram().DataPoints().At(i)
+		valueStr := formatCountSum(dp.Count(), dp.Sum())
+		if dp.HasMin() {
+			valueStr += fmt.Sprintf(" min=%.2f", dp.Min())
+		}
+		if dp.HasMax() {
+			valueStr += fmt.Sprintf(" max=%.2f", dp.Max())
+		}
+		return valueStr, dp.Attributes()
+	case pmetric.MetricTypeSummary:
+		dp := metric.Summary().DataPoints().At(i)
+		return formatCountSum(dp.Count(), dp.Sum()), dp.Attributes()
+	}
+	return "-", pcommon.NewMap()
+}
+
+// metricCSVHeader is the header row written before WriteMetricCSV rows
+var metricCSVHeader = []string{"metric_name", "type", "unit", "service", "timestamp", "value", "count", "sum", "attributes"}
+
+// CSVHeader returns metricCSVHeader followed by the attribute columns
+func (mw *MetricWriter) CSVHeader() []string {
+	return append(slices.Clone(metricCSVHeader), mw.AttributeColumns...)
+}
+
+// WriteMetricCSV writes one CSV row per data point of a metric. Gauge and Sum
+// points fill value with their int or double value at full precision, while
+// Histogram, ExponentialHistogram and Summary points fill count and sum.
+// Attribute columns are empty when a data point lacks the attribute.
+func (mw *MetricWriter) WriteMetricCSV(w *csv.Writer, metric pmetric.Metric, serviceName string) error {
+	keep := mw.KeepAttribute
+	if len(mw.AttributeColumns) > 0 {
+		keep = func(key string) bool {
+			return !slices.Contains(mw.AttributeColumns, key) && (mw.KeepAttribute == nil || mw.KeepAttribute(key))
+		}
+	}
+	row := func(ts pcommon.Timestamp, value, count, sum string, attrs pcommon.Map) error {
+		record := []string{
+			metric.Name(), metric.Type().String(), metric.Unit(), serviceName,
+			formatTimestamp(ts, mw.Location, time.RFC3339Nano), value, count, sum, formatAttributes(attrs, keep, mw.RedactAttribute),
+		}
+		for _, key := range mw.AttributeColumns {
+			var cell string
+			if v, ok := attrs.Get(key); ok {
+				cell = redactValue(key, v.AsString(), mw.RedactAttribute)
+			}
+			record = append(record, cell)
+		}
+		return w.Write(record)
+	}
+	countSum := func(count uint64, sum float64) (string, string) {
+		return strconv.FormatUint(count, 10), strconv.FormatFloat(sum, 'g', -1, 64)
+	}
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		var dps pmetric.NumberDataPointSlice
+		if metric.Type() == pmetric.MetricTypeSum {
+			dps = metric.Sum().DataPoints()
+		} else {
+			dps = metric.Gauge().DataPoints()
+		}
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			value := strconv.FormatInt(dp.IntValue(), 10)
+			if dp.ValueType() == pmetric.NumberDataPointValueTypeDouble {
+				value = strconv.FormatFloat(dp.DoubleValue(), 'g', -1, 64)
+			}
+			if err := row(dp.Timestamp(), value, "", "", dp.Attributes()); err != nil {
+				return err
+			}
 		}
 	case pmetric.MetricTypeHistogram:
-		hist := metric.Histogram()
-		if hist.DataPoints().Len() > 0 {
-			dp := hist.DataPoints().At(0)
-			valueStr = fmt.Sprintf("count=%d sum=%.2f", dp.Count(), dp.Sum())
-			attrStr = formatAttributes(dp.Attributes())
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			count, sum := countSum(dp.Count(), dp.Sum())
+			if err := row(dp.Timestamp(), "", count, sum, dp.Attributes()); err != nil {
+				return err
+			}
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			count, sum := countSum(dp.Count(), dp.Sum())
+			if err := row(dp.Timestamp(), "", count, sum, dp.Attributes()); err != nil {
+				return err
+			}
 		}
 	case pmetric.MetricTypeSummary:
-		summ := metric.Summary()
-		if summ.DataPoints().Len() > 0 {
-			dp := summ.DataPoints().At(0)
-			valueStr = fmt.Sprintf("count=%d sum=%.2f", dp.Count(), dp.Sum())
-			attrStr = formatAttributes(dp.Attributes())
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			count, sum := countSum(dp.Count(), dp.Sum())
+			if err := row(dp.Timestamp(), "", count, sum, dp.Attributes()); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
 
-	if len(attrStr) > 50 {
-		attrStr = attrStr[:50] + "..."
+// metricTemporality returns the aggregation temporality of Sum, Histogram and
+// ExponentialHistogram metrics and whether a Sum is monotonic. Gauges and
+// Summaries have no temporality and return "".
+func metricTemporality(metric pmetric.Metric) (string, bool) {
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		return metric.Sum().AggregationTemporality().String(), metric.Sum().IsMonotonic()
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().AggregationTemporality().String(), false
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().AggregationTemporality().String(), false
 	}
-	if attrStr == "" {
-		attrStr = "-"
+	return "", false
+}
+
+// metricTypeLabel returns the metric type annotated with its temporality and
+// monotonicity, e.g. "Sum (Delta, monotonic)"
+func metricTypeLabel(metric pmetric.Metric) string {
+	temporality, monotonic := metricTemporality(metric)
+	if temporality == "" {
+		return metric.Type().String()
+	}
+	if monotonic {
+		return fmt.Sprintf("%s (%s, monotonic)", metric.Type().String(), temporality)
 	}
+	return fmt.Sprintf("%s (%s)", metric.Type().String(), temporality)
+}
+
+// unitNames are human readable names of common UCUM units
+var unitNames = map[string]string{
+	"1":    "count",
+	"%":    "percent",
+	"bit":  "bits",
+	"By":   "bytes",
+	"kBy":  "kilobytes",
+	"MBy":  "megabytes",
+	"GBy":  "gigabytes",
+	"KiBy": "kibibytes",
+	"MiBy": "mebibytes",
+	"GiBy": "gibibytes",
+	"ns":   "nanoseconds",
+	"us":   "microseconds",
+	"ms":   "milliseconds",
+	"s":    "seconds",
+	"min":  "minutes",
+	"h":    "hours",
+	"d":    "days",
+	"Hz":   "hertz",
+	"Cel":  "degrees Celsius",
+}
 
-	fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s |\n",
-		metric.Name(), metric.Type().String(), serviceName, metric.Unit(), valueStr, attrStr)
+// unitPerNames are the names of UCUM time units as rate denominators
+var unitPerNames = map[string]string{
+	"ns":  "nanosecond",
+	"us":  "microsecond",
+	"ms":  "millisecond",
+	"s":   "second",
+	"min": "minute",
+	"h":   "hour",
+	"d":   "day",
+}
+
+// unitLabel returns a readable label for a UCUM metric unit followed by the
+// raw unit, e.g. "bytes (By)" or "bytes per second (By/s)". Annotations such
+// as "{request}" read as counts. Unknown units are returned unchanged.
+func unitLabel(unit string) string {
+	name := unitName(unit)
+	if num, per, ok := strings.Cut(unit, "/"); ok && name == "" {
+		if perName, known := unitPerNames[per]; known {
+			if numName := unitName(num); numName != "" {
+				name = numName + " per " + perName
+			} else if num == "" {
+				name = "per " + perName
+			}
+		}
+	}
+	if name == "" || name == unit {
+		return unit
+	}
+	return fmt.Sprintf("%s (%s)", name, unit)
+}
+
+// unitName returns the name of a single UCUM unit or annotation, "" if unknown
+func unitName(unit string) string {
+	if name, ok := unitNames[unit]; ok {
+		return name
+	}
+	if annotation, ok := strings.CutPrefix(unit, "{"); ok && strings.HasSuffix(annotation, "}") && len(annotation) > 1 {
+		return strings.TrimSuffix(annotation, "}") + " count"
+	}
+	return ""
 }
 
 // WriteMetricDetailed writes full details of a metric in markdown
 func (w *MetricWriter) WriteMetricDetailed(sb *strings.Builder, metric pmetric.Metric, serviceName string, resourceAttrs pcommon.Map) {
 	fmt.Fprintf(sb, "## Metric: %s\n\n", metric.Name())
 	fmt.Fprintf(sb, "**Type:** %s\n\n", metric.Type().String())
-	fmt.Fprintf(sb, "**Unit:** %s\n\n", metric.Unit())
+	fmt.Fprintf(sb, "**Unit:** %s\n\n", unitLabel(metric.Unit()))
 	fmt.Fprintf(sb, "**Service:** %s\n\n", serviceName)
 	fmt.Fprintf(sb, "**Description:** %s\n\n", metric.Description())
 
@@ -264,25 +579,18 @@ func (w *MetricWriter) WriteMetricDetailed(sb *strings.Builder, metric pmetric.M
 		w.writeGaugeDetailedDataPoints(sb, metric.Gauge())
 	case pmetric.MetricTypeHistogram:
 		w.writeHistogramDetailedDataPoints(sb, metric.Histogram())
+	case pmetric.MetricTypeExponentialHistogram:
+		w.writeExponentialHistogramDetailedDataPoints(sb, metric.ExponentialHistogram())
 	case pmetric.MetricTypeSummary:
 		w.writeSummaryDetailedDataPoints(sb, metric.Summary())
 	}
 
-	if resourceAttrs.Len() > 0 {
-		sb.WriteString("### Resource Attributes\n\n")
-		sb.WriteString("| Key | Value |\n")
-		sb.WriteString("|-----|-------|\n")
-		resourceAttrs.Range(func(k string, v pcommon.Value) bool {
-			fmt.Fprintf(sb, "| %s | %s |\n", k, v.AsString())
-			return true
-		})
-		sb.WriteString("\n")
-	}
+	writeAttributeTable(sb, "Resource Attributes", resourceAttrs, w.KeepAttribute, w.RedactAttribute)
 
 	sb.WriteString("---\n\n")
 }
 
-func (*MetricWriter) writeSumDetailedDataPoints(sb *strings.Builder, sum pmetric.Sum) {
+func (w *MetricWriter) writeSumDetailedDataPoints(sb *strings.Builder, sum pmetric.Sum) {
 	sb.WriteString("### Data Points\n\n")
 	fmt.Fprintf(sb, "**Aggregation:** %s\n\n", sum.AggregationTemporality().String())
 	fmt.Fprintf(sb, "**Is Monotonic:** %t\n\n", sum.IsMonotonic())
@@ -292,44 +600,44 @@ func (*MetricWriter) writeSumDetailedDataPoints(sb *strings.Builder, sum pmetric
 
 	for i := 0; i < sum.DataPoints().Len(); i++ {
 		dp := sum.DataPoints().At(i)
-		timestamp := time.Unix(0, int64(dp.Timestamp()))
-		attrs := formatAttributes(dp.Attributes())
+		timestamp := formatTimestamp(dp.Timestamp(), w.Location, "15:04:05.000")
+		attrs := formatAttributes(dp.Attributes(), w.KeepAttribute, w.RedactAttribute)
 		if attrs == "" {
 			attrs = "-"
 		}
 		fmt.Fprintf(sb, "| %s | %.2f | %s |\n",
-			timestamp.Format("15:04:05.000"), dp.DoubleValue(), attrs)
+			timestamp, dp.DoubleValue(), attrs)
 	}
 	sb.WriteString("\n")
 }
 
-func (*MetricWriter) writeGaugeDetailedDataPoints(sb *strings.Builder, gauge pmetric.Gauge) {
+func (w *MetricWriter) writeGaugeDetailedDataPoints(sb *strings.Builder, gauge pmetric.Gauge) {
 	sb.WriteString("### Data Points\n\n")
 	sb.WriteString("| Timestamp | Value | Attributes |\n")
 	sb.WriteString("|-----------|-------|------------|\n")
 
 	for i := 0; i < gauge.DataPoints().Len(); i++ {
 		dp := gauge.DataPoints().At(i)
-		timestamp := time.Unix(0, int64(dp.Timestamp()))
-		attrs := formatAttributes(dp.Attributes())
+		timestamp := formatTimestamp(dp.Timestamp(), w.Location, "15:04:05.000")
+		attrs := formatAttributes(dp.Attributes(), w.KeepAttribute, w.RedactAttribute)
 		if attrs == "" {
 			attrs = "-"
 		}
 		fmt.Fprintf(sb, "| %s | %.2f | %s |\n",
-			timestamp.Format("15:04:05.000"), dp.DoubleValue(), attrs)
+			timestamp, dp.DoubleValue(), attrs)
 	}
 	sb.WriteString("\n")
 }
 
-func (*MetricWriter) writeHistogramDetailedDataPoints(sb *strings.Builder, hist pmetric.Histogram) {
+func (w *MetricWriter) writeHistogramDetailedDataPoints(sb *strings.Builder, hist pmetric.Histogram) {
 	sb.WriteString("### Data Points\n\n")
 	fmt.Fprintf(sb, "**Aggregation:** %s\n\n", hist.AggregationTemporality().String())
 
 	for i := 0; i < hist.DataPoints().Len(); i++ {
 		dp := hist.DataPoints().At(i)
-		timestamp := time.Unix(0, int64(dp.Timestamp()))
+		timestamp := formatTimestamp(dp.Timestamp(), w.Location, "15:04:05.000")
 
-		fmt.Fprintf(sb, "#### Data Point %d (%s)\n\n", i+1, timestamp.Format("15:04:05.000"))
+		fmt.Fprintf(sb, "#### Data Point %d (%s)\n\n", i+1, timestamp)
 		fmt.Fprintf(sb, "**Count:** %d\n\n", dp.Count())
 		fmt.Fprintf(sb, "**Sum:** %.2f\n\n", dp.Sum())
 
@@ -353,21 +661,86 @@ func (*MetricWriter) writeHistogramDetailedDataPoints(sb *strings.Builder, hist
 			sb.WriteString("\n")
 		}
 
-		attrs := formatAttributes(dp.Attributes())
+		attrs := formatAttributes(dp.Attributes(), w.KeepAttribute, w.RedactAttribute)
+		if attrs != "" {
+			fmt.Fprintf(sb, "**Attributes:** %s\n\n", attrs)
+		}
+	}
+}
+
+func (w *MetricWriter) writeExponentialHistogramDetailedDataPoints(sb *strings.Builder, hist pmetric.ExponentialHistogram) {
+	sb.WriteString("### Data Points\n\n")
+	fmt.Fprintf(sb, "**Aggregation:** %s\n\n", hist.AggregationTemporality().String())
+
+	for i := 0; i < hist.DataPoints().Len(); i++ {
+		dp := hist.DataPoints().At(i)
+		timestamp := formatTimestamp(dp.Timestamp(), w.Location, "15:04:05.000")
+
+		fmt.Fprintf(sb, "#### Data Point %d (%s)\n\n", i+1, timestamp)
+		fmt.Fprintf(sb, "**Count:** %d\n\n", dp.Count())
+		fmt.Fprintf(sb, "**Sum:** %.2f\n\n", dp.Sum())
+
+		if dp.HasMin() {
+			fmt.Fprintf(sb, "**Min:** %.2f\n\n", dp.Min())
+		}
+		if dp.HasMax() {
+			fmt.Fprintf(sb, "**Max:** %.2f\n\n", dp.Max())
+		}
+		fmt.Fprintf(sb, "**Scale:** %d\n\n", dp.Scale())
+
+		// Only non-empty buckets are listed, exponential histograms commonly
+		// carry long runs of empty buckets
+		if dp.Negative().BucketCounts().Len() > 0 || dp.ZeroCount() > 0 || dp.Positive().BucketCounts().Len() > 0 {
+			sb.WriteString("**Buckets (approximate bounds):**\n\n")
+			sb.WriteString("| Lower Bound | Upper Bound | Count |\n")
+			sb.WriteString("|-------------|-------------|-------|\n")
+			negative := dp.Negative()
+			for j := negative.BucketCounts().Len() - 1; j >= 0; j-- {
+				if count := negative.BucketCounts().At(j); count > 0 {
+					lower, upper := exponentialBucketBounds(dp.Scale(), negative.Offset()+int32(j))
+					fmt.Fprintf(sb, "| %.4g | %.4g | %d |\n", -upper, -lower, count)
+				}
+			}
+			if dp.ZeroCount() > 0 {
+				if threshold := dp.ZeroThreshold(); threshold > 0 {
+					fmt.Fprintf(sb, "| %.4g | %.4g | %d |\n", -threshold, threshold, dp.ZeroCount())
+				} else {
+					fmt.Fprintf(sb, "| 0 | 0 | %d |\n", dp.ZeroCount())
+				}
+			}
+			positive := dp.Positive()
+			for j := 0; j < positive.BucketCounts().Len(); j++ {
+				if count := positive.BucketCounts().At(j); count > 0 {
+					lower, upper := exponentialBucketBounds(dp.Scale(), positive.Offset()+int32(j))
+					fmt.Fprintf(sb, "| %.4g | %.4g | %d |\n", lower, upper, count)
+				}
+			}
+			sb.WriteString("\n")
+		}
+
+		attrs := formatAttributes(dp.Attributes(), w.KeepAttribute, w.RedactAttribute)
 		if attrs != "" {
 			fmt.Fprintf(sb, "**Attributes:** %s\n\n", attrs)
 		}
 	}
 }
 
-func (*MetricWriter) writeSummaryDetailedDataPoints(sb *strings.Builder, summ pmetric.Summary) {
+// exponentialBucketBounds returns the (lower, upper] bounds of the bucket at
+// index for an exponential histogram scale, i.e. base^index and
+// base^(index+1) with base = 2^(2^-scale)
+func exponentialBucketBounds(scale, index int32) (float64, float64) {
+	exponent := math.Exp2(-float64(scale))
+	return math.Exp2(float64(index) * exponent), math.Exp2(float64(index+1) * exponent)
+}
+
+func (w *MetricWriter) writeSummaryDetailedDataPoints(sb *strings.Builder, summ pmetric.Summary) {
 	sb.WriteString("### Data Points\n\n")
 
 	for i := 0; i < summ.DataPoints().Len(); i++ {
 		dp := summ.DataPoints().At(i)
-		timestamp := time.Unix(0, int64(dp.Timestamp()))
+		timestamp := formatTimestamp(dp.Timestamp(), w.Location, "15:04:05.000")
 
-		fmt.Fprintf(sb, "#### Data Point %d (%s)\n\n", i+1, timestamp.Format("15:04:05.000"))
+		fmt.Fprintf(sb, "#### Data Point %d (%s)\n\n", i+1, timestamp)
 		fmt.Fprintf(sb, "**Count:** %d\n\n", dp.Count())
 		fmt.Fprintf(sb, "**Sum:** %.2f\n\n", dp.Sum())
 
@@ -382,9 +755,32 @@ func (*MetricWriter) writeSummaryDetailedDataPoints(sb *strings.Builder, summ pm
 			sb.WriteString("\n")
 		}
 
-		attrs := formatAttributes(dp.Attributes())
+		attrs := formatAttributes(dp.Attributes(), w.KeepAttribute, w.RedactAttribute)
 		if attrs != "" {
 			fmt.Fprintf(sb, "**Attributes:** %s\n\n", attrs)
 		}
 	}
 }
+
+// writeAttributeTable writes attrs as a markdown key/value table under title,
+// leaving out the keys rejected by keep and redacting the values of the keys
+// matched by redact. Nothing is written when no key is left.
+func writeAttributeTable(sb *strings.Builder, title string, attrs pcommon.Map, keep, redact func(key string) bool) {
+	wroteHeader := false
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		if keep != nil && !keep(k) {
+			return true
+		}
+		if !wroteHeader {
+			fmt.Fprintf(sb, "### %s\n\n", title)
+			sb.WriteString("| Key | Value |\n")
+			sb.WriteString("|-----|-------|\n")
+			wroteHeader = true
+		}
+		fmt.Fprintf(sb, "| %s | %s |\n", k, redactValue(k, v.AsString(), redact))
+		return true
+	})
+	if wroteHeader {
+		sb.WriteString("\n")
+	}
+}
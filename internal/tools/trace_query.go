@@ -0,0 +1,178 @@
+// Copyright 2025 Austin Parker
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// queryKeyPattern matches the key of a query term. Words whose prefix does not
+// look like a key, such as "/api/v1:list", are treated as bare words.
+var queryKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// queryTerm is a single "key<op>value" expression of a query string. Bare words
+// have an empty key and op.
+type queryTerm struct {
+	key   string
+	op    string
+	value string
+}
+
+// applyTraceQuery parses a query string such as
+// `service:checkout status:error duration>=500ms attr.http.route:/cart` and sets
+// the matching fields of input. Keys are case-insensitive, the attribute key
+// after "attr." is not. Bare words are joined into a span name substring.
+// Terms conflicting with a field that is already set are rejected.
+func applyTraceQuery(input *QueryTracesInput, query string) error {
+	terms, err := tokenizeQuery(query)
+	if err != nil {
+		return err
+	}
+
+	var bare []string
+	for _, term := range terms {
+		if term.key == "" {
+			bare = append(bare, term.value)
+			continue
+		}
+
+		key := strings.ToLower(term.key)
+		if strings.HasPrefix(key, "attr.") && len(key) > len("attr.") {
+			attrKey := term.key[len("attr."):]
+			if term.op != ":" {
+				return fmt.Errorf("invalid query term %s%s%s: attributes only support ':'", term.key, term.op, term.value)
+			}
+			if input.Attributes == nil {
+				input.Attributes = make(map[string]string)
+			}
+			value := input.Attributes[attrKey]
+			if err := setQueryField(&value, "attributes."+attrKey, term.value); err != nil {
+				return err
+			}
+			input.Attributes[attrKey] = value
+			continue
+		}
+
+		if key == "duration" {
+			if _, err := time.ParseDuration(term.value); err != nil {
+				return fmt.Errorf("invalid query term duration%s%s: %w", term.op, term.value, err)
+			}
+			// min_duration and max_duration are inclusive, so only the
+			// inclusive comparisons are accepted
+			switch term.op {
+			case ">=":
+				err = setQueryField(&input.MinDuration, "min_duration", term.value)
+			case "<=":
+				err = setQueryField(&input.MaxDuration, "max_duration", term.value)
+			default:
+				err = fmt.Errorf("invalid query term duration%s%s: use duration>=value or duration<=value", term.op, term.value)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if term.op != ":" {
+			return fmt.Errorf("invalid query term %s%s%s: only duration supports comparisons", term.key, term.op, term.value)
+		}
+		switch key {
+		case "service":
+			err = setQueryField(&input.ServiceName, "service_name", term.value)
+		case "span", "name":
+			err = setQueryField(&input.SpanName, "span_name", term.value)
+		case "trace", "trace_id":
+			err = setQueryField(&input.TraceID, "trace_id", term.value)
+		case "root":
+			err = setQueryField(&input.RootSpanName, "root_span_name", term.value)
+		case "connector":
+			err = setQueryField(&input.ConnectorID, "connector_id", term.value)
+		case "status":
+			var status string
+			switch strings.ToLower(term.value) {
+			case "ok":
+				status = "Ok"
+			case "error":
+				status = "Error"
+			case "unset":
+				status = "Unset"
+			default:
+				return fmt.Errorf("invalid query term status:%s (must be ok, error, or unset)", term.value)
+			}
+			err = setQueryField(&input.Status, "status", status)
+		default:
+			return fmt.Errorf("unknown query key %q (supported: service, span, trace, root, connector, status, duration, attr.<key>)", term.key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(bare) > 0 {
+		return setQueryField(&input.SpanName, "span_name", strings.Join(bare, " "))
+	}
+	return nil
+}
+
+// setQueryField sets a filter field from a query term unless the field already
+// holds a different value
+func setQueryField(field *string, name, value string) error {
+	if *field != "" && *field != value {
+		return fmt.Errorf("query sets %s to %q but it is already %q", name, value, *field)
+	}
+	*field = value
+	return nil
+}
+
+// tokenizeQuery splits a query string into terms. Values may be double-quoted
+// to include spaces, e.g. span:"GET /cart".
+func tokenizeQuery(query string) ([]queryTerm, error) {
+	var words []string
+	var word strings.Builder
+	inQuotes, quoted := false, false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			quoted = true
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			if word.Len() > 0 || quoted {
+				words = append(words, word.String())
+			}
+			word.Reset()
+			quoted = false
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("invalid query %q: unterminated quote", query)
+	}
+	if word.Len() > 0 || quoted {
+		words = append(words, word.String())
+	}
+
+	terms := make([]queryTerm, 0, len(words))
+	for _, w := range words {
+		idx := strings.IndexAny(w, ":<>")
+		if idx <= 0 || !queryKeyPattern.MatchString(w[:idx]) {
+			terms = append(terms, queryTerm{value: w})
+			continue
+		}
+
+		op := w[idx : idx+1]
+		if op != ":" && strings.HasPrefix(w[idx+1:], "=") {
+			op += "="
+		}
+		term := queryTerm{key: w[:idx], op: op, value: w[idx+len(op):]}
+		if term.value == "" {
+			return nil, fmt.Errorf("invalid query term %s: missing value", w)
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}